@@ -0,0 +1,169 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// EIP712 domain the makers sign their orders with (RePermit contract)
+type Eip712Domain struct {
+	Name              string
+	Version           string
+	ChainId           *big.Int
+	VerifyingContract common.Address
+}
+
+// RePermit witness types - must match the reactor's PartialOrder witness type string
+var eip712Types = apitypes.Types{
+	"EIP712Domain": {
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	},
+	"RePermitWitnessTransferFrom": {
+		{Name: "permitted", Type: "TokenPermissions"},
+		{Name: "spender", Type: "address"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+		{Name: "witness", Type: "PartialOrder"},
+	},
+	"TokenPermissions": {
+		{Name: "token", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+	},
+	"PartialOrder": {
+		{Name: "info", Type: "OrderInfo"},
+		{Name: "exclusiveFiller", Type: "address"},
+		{Name: "exclusivityOverrideBps", Type: "uint256"},
+		{Name: "input", Type: "PartialInput"},
+		{Name: "outputs", Type: "PartialOutput[]"},
+	},
+	"OrderInfo": {
+		{Name: "reactor", Type: "address"},
+		{Name: "swapper", Type: "address"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+		{Name: "additionalValidationContract", Type: "address"},
+		{Name: "additionalValidationData", Type: "bytes"},
+	},
+	"PartialInput": {
+		{Name: "token", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+	},
+	"PartialOutput": {
+		{Name: "token", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+		{Name: "recipient", Type: "address"},
+	},
+}
+
+func bigOrZero(num *big.Int) *big.Int {
+	if num == nil {
+		return big.NewInt(0)
+	}
+	return num
+}
+
+// TypedData builds the RePermit witness typed data the maker signed for this order
+// the permit is given to the reactor (spender) over the order's input token and amount
+func (o *Order) TypedData(domain Eip712Domain) apitypes.TypedData {
+	outputs := []interface{}{}
+	for _, output := range o.Outputs {
+		outputs = append(outputs, map[string]interface{}{
+			"token":     output.Token.Hex(),
+			"amount":    bigOrZero(output.Amount),
+			"recipient": output.Recipient.Hex(),
+		})
+	}
+
+	witness := map[string]interface{}{
+		"info": map[string]interface{}{
+			"reactor":                      o.Info.Reactor.Hex(),
+			"swapper":                      o.Info.Swapper.Hex(),
+			"nonce":                        bigOrZero(o.Info.Nonce),
+			"deadline":                     bigOrZero(o.Info.Deadline),
+			"additionalValidationContract": o.Info.AdditionalValidationContract.Hex(),
+			"additionalValidationData":     hexutil.Bytes(o.Info.AdditionalValidationData),
+		},
+		"exclusiveFiller":        o.ExclusiveFiller.Hex(),
+		"exclusivityOverrideBps": bigOrZero(o.ExclusivityOverrideBps),
+		"input": map[string]interface{}{
+			"token":  o.Input.Token.Hex(),
+			"amount": bigOrZero(o.Input.Amount),
+		},
+		"outputs": outputs,
+	}
+
+	return apitypes.TypedData{
+		Types:       eip712Types,
+		PrimaryType: "RePermitWitnessTransferFrom",
+		Domain: apitypes.TypedDataDomain{
+			Name:              domain.Name,
+			Version:           domain.Version,
+			ChainId:           (*math.HexOrDecimal256)(bigOrZero(domain.ChainId)),
+			VerifyingContract: domain.VerifyingContract.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"permitted": map[string]interface{}{
+				"token":  o.Input.Token.Hex(),
+				"amount": bigOrZero(o.Input.Amount),
+			},
+			"spender":  o.Info.Reactor.Hex(),
+			"nonce":    bigOrZero(o.Info.Nonce),
+			"deadline": bigOrZero(o.Info.Deadline),
+			"witness":  witness,
+		},
+	}
+}
+
+// Eip712Hash returns the EIP712 digest of the order which is signed by the maker
+func (o *Order) Eip712Hash(domain Eip712Domain) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(o.TypedData(domain))
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+	return hash, nil
+}
+
+// RecoverSigner returns the address which signed the order's EIP712 digest
+// sig is a 65 bytes hex string [R || S || V], V may be 0/1 or 27/28
+func (o *Order) RecoverSigner(domain Eip712Domain, sig string) (common.Address, error) {
+	sigBytes, err := hexutil.Decode(ensureHexPrefix(sig))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature hex: %w", err)
+	}
+	if len(sigBytes) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("invalid signature length %d", len(sigBytes))
+	}
+
+	hash, err := o.Eip712Hash(domain)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	// normalize V to 0/1 as expected by go-ethereum
+	if sigBytes[crypto.RecoveryIDOffset] >= 27 {
+		sigBytes[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pubKey, err := crypto.SigToPub(hash, sigBytes)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
+
+func ensureHexPrefix(str string) string {
+	if strings.HasPrefix(str, "0x") || strings.HasPrefix(str, "0X") {
+		return str
+	}
+	return "0x" + str
+}
@@ -0,0 +1,80 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrder_RecoverSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	domain := Eip712Domain{
+		Name:              "RePermit",
+		Version:           "1",
+		ChainId:           big.NewInt(137),
+		VerifyingContract: common.HexToAddress(reactor),
+	}
+
+	order := Order{
+		Info: Info{
+			Reactor:                      common.HexToAddress(reactor),
+			Swapper:                      crypto.PubkeyToAddress(key.PublicKey),
+			Nonce:                        big.NewInt(1000),
+			Deadline:                     big.NewInt(1709071200),
+			AdditionalValidationContract: common.Address{},
+			AdditionalValidationData:     []byte{},
+		},
+		ExclusiveFiller:        common.HexToAddress(filler),
+		ExclusivityOverrideBps: big.NewInt(0),
+		Input: Input{
+			Token:  common.HexToAddress(inToken),
+			Amount: big.NewInt(40000000000000000),
+		},
+		Outputs: []Output{
+			{
+				Token:     common.HexToAddress(outToken),
+				Amount:    big.NewInt(34600000),
+				Recipient: crypto.PubkeyToAddress(key.PublicKey),
+			},
+		},
+	}
+
+	hash, err := order.Eip712Hash(domain)
+	assert.NoError(t, err)
+	sig, _ := crypto.Sign(hash, key)
+
+	t.Run("should recover signer with V 0/1", func(t *testing.T) {
+		signer, err := order.RecoverSigner(domain, hexutil.Encode(sig))
+		assert.NoError(t, err)
+		assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer)
+	})
+
+	t.Run("should recover signer with V 27/28 and no 0x prefix", func(t *testing.T) {
+		sig27 := make([]byte, len(sig))
+		copy(sig27, sig)
+		sig27[64] += 27
+		signer, err := order.RecoverSigner(domain, common.Bytes2Hex(sig27))
+		assert.NoError(t, err)
+		assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer)
+	})
+
+	t.Run("different domain should recover a different signer", func(t *testing.T) {
+		other := domain
+		other.ChainId = big.NewInt(1)
+		signer, err := order.RecoverSigner(other, hexutil.Encode(sig))
+		assert.NoError(t, err)
+		assert.NotEqual(t, crypto.PubkeyToAddress(key.PublicKey), signer)
+	})
+
+	t.Run("invalid signature should return error", func(t *testing.T) {
+		_, err := order.RecoverSigner(domain, "mock-sig")
+		assert.Error(t, err)
+
+		_, err = order.RecoverSigner(domain, "0x1234")
+		assert.ErrorContains(t, err, "invalid signature length")
+	})
+}
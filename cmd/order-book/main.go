@@ -18,7 +18,12 @@ import (
 	"github.com/shopspring/decimal"
 
 	"github.com/orbs-network/order-book/data/evmrepo"
+	"github.com/orbs-network/order-book/data/memrepo"
 	"github.com/orbs-network/order-book/data/redisrepo"
+	"github.com/orbs-network/order-book/data/sqlrepo"
+	"github.com/orbs-network/order-book/data/store"
+	"github.com/orbs-network/order-book/data/storearchive"
+	"github.com/orbs-network/order-book/data/storeuser"
 	"github.com/orbs-network/order-book/service"
 	"github.com/orbs-network/order-book/serviceuser"
 	"github.com/orbs-network/order-book/transport/rest"
@@ -45,20 +50,6 @@ func setup() {
 	decimal.DivisionPrecision = percision
 	fmt.Println("DivisionPrecision:\t", percision)
 
-	redisAddress, found := os.LookupEnv("REDIS_URL")
-	if !found {
-		redisAddress, found = os.LookupEnv("REDISCLOUD_URL")
-		if !found {
-			panic("Neither REDIS_URL nor REDISCLOUD_URL is set")
-		}
-	}
-
-	opt, err := redis.ParseURL(redisAddress)
-	if err != nil {
-		panic(fmt.Errorf("failed to parse redis URL: %v", err))
-	}
-
-	fmt.Println("Redis address:\t", opt.Addr)
 	port, found := os.LookupEnv("PORT")
 	if !found {
 		port = "8080"
@@ -69,18 +60,12 @@ func setup() {
 		panic("RPC_URL not set")
 	}
 
-	if strings.HasPrefix(redisAddress, "rediss") {
-		opt.TLSConfig = &tls.Config{
-			InsecureSkipVerify: true,
-		}
-	}
+	repository, closeRepository := newRepository()
+	defer closeRepository()
 
-	rdb := redis.NewClient(opt)
-	defer rdb.Close()
-
-	repository, err := redisrepo.NewRedisRepository(rdb)
-	if err != nil {
-		log.Fatalf("error creating repository: %v", err)
+	archive := newArchive()
+	if archive != nil {
+		defer archive.Close()
 	}
 
 	fmt.Println("WEB3 RPC:\t", rpcUrl)
@@ -101,10 +86,31 @@ func setup() {
 		log.Fatalf("error creating evm client: %v", err)
 	}
 
+	// delivers the users' events to their webhooks
+	webhookWorker, err := service.NewWebhookWorker(repository, nil)
+	if err != nil {
+		log.Fatalf("error creating webhook worker: %v", err)
+	}
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+	webhookWorker.Start(workerCtx)
+
+	// moves resolved swaps, closed orders and fills from the store to the archive
+	if archive != nil {
+		archiver, err := service.NewArchiver(repository, archive)
+		if err != nil {
+			log.Fatalf("error creating archiver: %v", err)
+		}
+		archiver.Start(workerCtx)
+	}
+
 	service, err := service.New(repository, evmClient)
 	if err != nil {
 		log.Fatalf("error creating service: %v", err)
 	}
+	if archive != nil {
+		service.SetArchive(archive)
+	}
 
 	userSvc, err := serviceuser.New(repository)
 	if err != nil {
@@ -142,3 +148,87 @@ func setup() {
 
 	log.Printf("Server gracefully stopped\n")
 }
+
+type repository interface {
+	store.OrderBookStore
+	storeuser.UserStore
+}
+
+// newRepository creates the store selected by STORE, "redis" (default) or "memory"
+// the memory store loses its state on exit, it is meant for development and tests without Redis
+func newRepository() (repository, func()) {
+	storeType, found := os.LookupEnv("STORE")
+	if !found {
+		storeType = "redis"
+	}
+	fmt.Println("Store:\t\t", storeType)
+
+	switch storeType {
+	case "memory":
+		repository, err := memrepo.NewMemoryRepository()
+		if err != nil {
+			log.Fatalf("error creating repository: %v", err)
+		}
+		return repository, func() {}
+	case "redis":
+		return newRedisRepository()
+	default:
+		panic(fmt.Errorf("unsupported STORE %q, use redis or memory", storeType))
+	}
+}
+
+func newRedisRepository() (repository, func()) {
+	redisAddress, found := os.LookupEnv("REDIS_URL")
+	if !found {
+		redisAddress, found = os.LookupEnv("REDISCLOUD_URL")
+		if !found {
+			panic("Neither REDIS_URL nor REDISCLOUD_URL is set")
+		}
+	}
+
+	opt, err := redis.ParseURL(redisAddress)
+	if err != nil {
+		panic(fmt.Errorf("failed to parse redis URL: %v", err))
+	}
+
+	fmt.Println("Redis address:\t", opt.Addr)
+
+	if strings.HasPrefix(redisAddress, "rediss") {
+		opt.TLSConfig = &tls.Config{
+			InsecureSkipVerify: true,
+		}
+	}
+
+	rdb := redis.NewClient(opt)
+
+	repository, err := redisrepo.NewRedisRepository(rdb)
+	if err != nil {
+		log.Fatalf("error creating repository: %v", err)
+	}
+	return repository, func() { rdb.Close() }
+}
+
+// newArchive opens the archive database selected by ARCHIVE_DRIVER, "sqlite" (default) or "postgres", at ARCHIVE_DSN
+// nothing is archived unless ARCHIVE_DSN is set, as archived records are removed from the store
+func newArchive() storearchive.ArchiveStore {
+	dsn := os.Getenv("ARCHIVE_DSN")
+	if dsn == "" {
+		fmt.Println("Archive:\t off")
+		return nil
+	}
+	driver, found := os.LookupEnv("ARCHIVE_DRIVER")
+	if !found {
+		driver = "sqlite"
+	}
+	fmt.Println("Archive:\t", driver)
+
+	db, err := sqlrepo.Open(driver, dsn)
+	if err != nil {
+		log.Fatalf("error opening archive: %v", err)
+	}
+	archive, err := sqlrepo.NewSqlRepository(db)
+	if err != nil {
+		log.Fatalf("error creating archive: %v", err)
+	}
+	return archive
+}
@@ -97,6 +97,11 @@ func main() {
 		cancel()
 	}()
 
+	// fills history of the swaps resolved before it was kept, only for users who have none yet
+	if err := evmClient.BackfillFills(ctx); err != nil {
+		log.Printf("error backfilling fills history: %v\n", err)
+	}
+
 	log.Printf("Swaps tracker running with ticker duration: %s\n", tickerDuration)
 
 	for {
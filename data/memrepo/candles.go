@@ -0,0 +1,66 @@
+package memrepo
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// StoreCandles stores the candles, replacing any candle of the same symbol, interval and start
+func (r *memoryRepository) StoreCandles(ctx context.Context, candles []models.Candle) error {
+	// marshal all of them first, so either all or none are stored
+	data := make([][]byte, len(candles))
+	for i, candle := range candles {
+		var err error
+		if data[i], err = json.Marshal(candle); err != nil {
+			logctx.Error(ctx, "failed to marshal candle", logger.String("symbol", candle.Symbol.String()), logger.String("interval", candle.Interval.String()), logger.Error(err))
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, candle := range candles {
+		key := candlesKey{symbol: candle.Symbol, interval: candle.Interval}
+		starts, ok := r.candles[key]
+		if !ok {
+			starts = make(map[int64][]byte)
+			r.candles[key] = starts
+		}
+		starts[candle.Start.UnixMilli()] = data[i]
+	}
+	return nil
+}
+
+// GetCandles returns the symbol's candles of an interval which start within [startAt, endAt), oldest first
+func (r *memoryRepository) GetCandles(ctx context.Context, symbol models.Symbol, interval models.CandleInterval, startAt, endAt time.Time) ([]models.Candle, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byStart := r.candles[candlesKey{symbol: symbol, interval: interval}]
+	from, to := startAt.UnixMilli(), endAt.UnixMilli()
+	starts := []int64{}
+	for start := range byStart {
+		if start >= from && start < to {
+			starts = append(starts, start)
+		}
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+
+	candles := make([]models.Candle, 0, len(starts))
+	for _, start := range starts {
+		var candle models.Candle
+		if err := json.Unmarshal(byStart[start], &candle); err != nil {
+			logctx.Error(ctx, "failed to unmarshal candle", logger.String("symbol", symbol.String()), logger.String("interval", interval.String()), logger.Error(err))
+			continue
+		}
+		candles = append(candles, candle)
+	}
+	return candles, nil
+}
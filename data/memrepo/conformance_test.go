@@ -0,0 +1,17 @@
+package memrepo
+
+import (
+	"testing"
+
+	"github.com/orbs-network/order-book/data/store"
+	"github.com/orbs-network/order-book/data/store/storetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.OrderBookStore {
+		repository, err := NewMemoryRepository()
+		require.NoError(t, err)
+		return repository
+	})
+}
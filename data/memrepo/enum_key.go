@@ -0,0 +1,64 @@
+package memrepo
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/orbs-network/order-book/data/redisrepo"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/shopspring/decimal"
+)
+
+// EnumSubKeysOf returns the string keys which start with key
+func (r *memoryRepository) EnumSubKeysOf(ctx context.Context, key string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := []string{}
+	for k := range r.strs {
+		if strings.HasPrefix(k, key) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// ReadStrKey returns the value of a string key, or ErrNotFound
+func (r *memoryRepository) ReadStrKey(ctx context.Context, key string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	val, ok := r.strs[key]
+	if !ok {
+		return "", models.ErrNotFound
+	}
+	return val, nil
+}
+
+func (r *memoryRepository) WriteStrKey(ctx context.Context, key, val string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.strs[key] = val
+	return nil
+}
+
+// GetMakerTokenBalance returns the tracked balance of the wallet, -1 until it is first checked
+func (r *memoryRepository) GetMakerTokenBalance(ctx context.Context, token, wallet string) (decimal.Decimal, error) {
+	res := decimal.NewFromInt(-1)
+	val, err := r.ReadStrKey(ctx, redisrepo.GetMakerTokenTrackKey(token, wallet))
+	if err != nil {
+		logctx.Error(ctx, "GetMakerTokenBalance Failed to read key", logger.Error(err))
+		return res, err
+	}
+	res, err = decimal.NewFromString(val)
+	if err != nil {
+		logctx.Error(ctx, "decimal NewFromString", logger.Error(err))
+		return res, err
+	}
+	return res, nil
+}
@@ -0,0 +1,38 @@
+package memrepo
+
+import (
+	"context"
+	"sort"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+)
+
+// AddFeeTotals adds the fees to the totals of their tokens
+func (r *memoryRepository) AddFeeTotals(ctx context.Context, totals []models.FeeTotal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, total := range totals {
+		r.takerFees[total.Token] = r.takerFees[total.Token].Add(total.TakerFees)
+		r.makerRebates[total.Token] = r.makerRebates[total.Token].Add(total.MakerRebates)
+	}
+	return nil
+}
+
+// GetFeeTotals returns the fee totals of all tokens, in token name order
+func (r *memoryRepository) GetFeeTotals(ctx context.Context) ([]models.FeeTotal, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	totals := make([]models.FeeTotal, 0, len(r.takerFees))
+	for token, takerFees := range r.takerFees {
+		rebates, ok := r.makerRebates[token]
+		if !ok {
+			rebates = decimal.Zero
+		}
+		totals = append(totals, models.FeeTotal{Token: token, TakerFees: takerFees, MakerRebates: rebates})
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Token < totals[j].Token })
+	return totals, nil
+}
@@ -0,0 +1,253 @@
+package memrepo
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// default page size of the history
+const historyPageSize = 100
+
+// StoreUserFill appends the fill to the user's fills
+func (r *memoryRepository) StoreUserFill(ctx context.Context, userId uuid.UUID, fill models.Fill) error {
+	data, err := json.Marshal(fill)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal fill", logger.String("userId", userId.String()), logger.Error(err))
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.addUserFill(userId, data)
+	return nil
+}
+
+// TxStoreUserFill appends the fill to the user's fills once the tx is committed
+func (r *memoryRepository) TxStoreUserFill(ctx context.Context, txid uint, userId uuid.UUID, fill models.Fill) error {
+	tx, ok := r.getTx(txid)
+	if !ok {
+		logctx.Error(ctx, "TxStoreUserFill txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
+	}
+
+	data, err := json.Marshal(fill)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal fill", logger.String("userId", userId.String()), logger.Error(err))
+		return err
+	}
+	tx.ops = append(tx.ops, func() {
+		r.addUserFill(userId, data)
+	})
+	return nil
+}
+
+// addUserFill must be called with the write lock held
+func (r *memoryRepository) addUserFill(userId uuid.UUID, data []byte) {
+	fills, ok := r.userFills[userId]
+	if !ok {
+		fills = &stream{}
+		r.userFills[userId] = fills
+	}
+	fills.add(data)
+}
+
+// GetUserFills returns a page of the user's fills, newest first, and the cursor of the next page
+func (r *memoryRepository) GetUserFills(ctx context.Context, userId uuid.UUID, filter models.HistoryFilter) ([]models.Fill, string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fills := []models.Fill{}
+	cursor, err := readStreamPage(r.userFills[userId], filter, func(raw []byte) bool {
+		var fill models.Fill
+		if err := json.Unmarshal(raw, &fill); err != nil {
+			logctx.Error(ctx, "failed to unmarshal fill", logger.String("userId", userId.String()), logger.Error(err))
+			return false
+		}
+		if !filter.Match(fill.Symbol, fill.Side) {
+			return false
+		}
+		fills = append(fills, fill)
+		return true
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return fills, cursor, nil
+}
+
+// GetUserSwapFills returns the user's fills of the swap, in the order they were stored
+func (r *memoryRepository) GetUserSwapFills(ctx context.Context, userId uuid.UUID, swapId uuid.UUID) ([]models.Fill, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fills := []models.Fill{}
+	userFills, ok := r.userFills[userId]
+	if !ok {
+		return fills, nil
+	}
+	for _, entry := range userFills.entries {
+		var fill models.Fill
+		if err := json.Unmarshal(entry.data, &fill); err != nil {
+			logctx.Error(ctx, "failed to unmarshal fill", logger.String("userId", userId.String()), logger.Error(err))
+			continue
+		}
+		if fill.SwapId == swapId {
+			fills = append(fills, fill)
+		}
+	}
+	return fills, nil
+}
+
+// BackfillUserFills stores the fills, sorted by their resolve time, with stream IDs of their resolve time
+// returns false without storing them if the user already has fills history
+func (r *memoryRepository) BackfillUserFills(ctx context.Context, userId uuid.UUID, fills []models.Fill) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// IDs of an existing stream, even an empty one, can only grow
+	if _, ok := r.userFills[userId]; ok || len(fills) == 0 {
+		return false, nil
+	}
+
+	backfill := &stream{}
+	for _, fill := range fills {
+		data, err := json.Marshal(fill)
+		if err != nil {
+			logctx.Error(ctx, "failed to marshal fill", logger.String("userId", userId.String()), logger.Error(err))
+			return false, err
+		}
+		backfill.addAt(fill.Resolved.UnixMilli(), data)
+	}
+	r.userFills[userId] = backfill
+	return true, nil
+}
+
+// TrimUserFills drops the user's fills stored before the time
+func (r *memoryRepository) TrimUserFills(ctx context.Context, userId uuid.UUID, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if fills, ok := r.userFills[userId]; ok {
+		fills.trimBefore(before.UnixMilli())
+	}
+	return nil
+}
+
+// StoreTrade appends the trade to its symbol's trades
+func (r *memoryRepository) StoreTrade(ctx context.Context, trade models.Trade) error {
+	data, err := json.Marshal(trade)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal trade", logger.String("symbol", trade.Symbol.String()), logger.Error(err))
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.addTrade(trade.Symbol, data)
+	return nil
+}
+
+// TxStoreTrade appends the trade to its symbol's trades once the tx is committed
+func (r *memoryRepository) TxStoreTrade(ctx context.Context, txid uint, trade models.Trade) error {
+	tx, ok := r.getTx(txid)
+	if !ok {
+		logctx.Error(ctx, "TxStoreTrade txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
+	}
+
+	data, err := json.Marshal(trade)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal trade", logger.String("symbol", trade.Symbol.String()), logger.Error(err))
+		return err
+	}
+	tx.ops = append(tx.ops, func() {
+		r.addTrade(trade.Symbol, data)
+	})
+	return nil
+}
+
+// addTrade must be called with the write lock held
+func (r *memoryRepository) addTrade(symbol models.Symbol, data []byte) {
+	trades, ok := r.symbolTrades[symbol]
+	if !ok {
+		trades = &stream{}
+		r.symbolTrades[symbol] = trades
+	}
+	trades.add(data)
+}
+
+// GetTrades returns a page of the symbol's trades, newest first, and the cursor of the next page
+func (r *memoryRepository) GetTrades(ctx context.Context, symbol models.Symbol, filter models.HistoryFilter) ([]models.Trade, string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	trades := []models.Trade{}
+	cursor, err := readStreamPage(r.symbolTrades[symbol], filter, func(raw []byte) bool {
+		var trade models.Trade
+		if err := json.Unmarshal(raw, &trade); err != nil {
+			logctx.Error(ctx, "failed to unmarshal trade", logger.String("symbol", symbol.String()), logger.Error(err))
+			return false
+		}
+		if !filter.Match(trade.Symbol, trade.Side) {
+			return false
+		}
+		trades = append(trades, trade)
+		return true
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return trades, cursor, nil
+}
+
+// readStreamPage walks the stream newest first from the filter's cursor or end time until `limit` entries were added
+// add decodes a raw entry and returns true if it matches the filter
+// the returned cursor is the ID of the last added entry, empty when the stream is exhausted
+func readStreamPage(s *stream, filter models.HistoryFilter, add func(raw []byte) bool) (string, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = historyPageSize
+	}
+
+	var before *streamId
+	if !filter.EndAt.IsZero() {
+		before = &streamId{ms: filter.EndAt.UnixMilli()}
+	}
+	if filter.Cursor != "" {
+		cursor, err := parseStreamId(filter.Cursor)
+		if err != nil {
+			return "", err
+		}
+		before = &cursor
+	}
+	var from int64
+	if !filter.StartAt.IsZero() {
+		from = filter.StartAt.UnixMilli()
+	}
+
+	cursor, count := "", 0
+	s.reverse(func(entry streamEntry) bool {
+		if before != nil && !entry.id.less(*before) {
+			return true
+		}
+		if entry.id.ms < from {
+			return false
+		}
+		if add(entry.data) {
+			count++
+			if count >= filter.Limit {
+				cursor = entry.id.String()
+				return false
+			}
+		}
+		return true
+	})
+	return cursor, nil
+}
@@ -0,0 +1,99 @@
+package memrepo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_GetUserFills(t *testing.T) {
+	repo := newTestRepo(t)
+	for i := 0; i < 5; i++ {
+		side := models.BUY
+		if i%2 == 1 {
+			side = models.SELL
+		}
+		require.NoError(t, repo.StoreUserFill(ctx, userId, models.Fill{OrderId: uuid.New(), Symbol: symbol, Side: side, Size: decimal.NewFromInt(int64(i))}))
+	}
+
+	t.Run("should page through the fills newest first", func(t *testing.T) {
+		fills, cursor, err := repo.GetUserFills(ctx, userId, models.HistoryFilter{Limit: 3})
+		assert.NoError(t, err)
+		assert.Len(t, fills, 3)
+		assert.Equal(t, "4", fills[0].Size.String())
+		assert.NotEmpty(t, cursor)
+
+		fills, cursor, err = repo.GetUserFills(ctx, userId, models.HistoryFilter{Limit: 3, Cursor: cursor})
+		assert.NoError(t, err)
+		assert.Len(t, fills, 2)
+		assert.Equal(t, "1", fills[0].Size.String())
+		assert.Empty(t, cursor)
+	})
+
+	t.Run("should filter by side", func(t *testing.T) {
+		fills, _, err := repo.GetUserFills(ctx, userId, models.HistoryFilter{Side: models.SELL})
+		assert.NoError(t, err)
+		assert.Len(t, fills, 2)
+	})
+
+	t.Run("should filter by time", func(t *testing.T) {
+		fills, _, err := repo.GetUserFills(ctx, userId, models.HistoryFilter{EndAt: time.Now().Add(-time.Hour)})
+		assert.NoError(t, err)
+		assert.Empty(t, fills)
+	})
+
+	t.Run("should return error on an invalid cursor", func(t *testing.T) {
+		_, _, err := repo.GetUserFills(ctx, userId, models.HistoryFilter{Cursor: "abc"})
+		assert.Error(t, err)
+	})
+}
+
+func TestMemoryRepository_GetTrades(t *testing.T) {
+	repo := newTestRepo(t)
+	require.NoError(t, repo.StoreTrade(ctx, models.Trade{SwapId: uuid.New(), Symbol: symbol, Side: models.BUY}))
+	require.NoError(t, repo.StoreTrade(ctx, models.Trade{SwapId: uuid.New(), Symbol: "ETH-USDC", Side: models.BUY}))
+
+	trades, cursor, err := repo.GetTrades(ctx, symbol, models.HistoryFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, trades, 1)
+	assert.Empty(t, cursor)
+}
+
+func TestMemoryRepository_FeeTotals(t *testing.T) {
+	repo := newTestRepo(t)
+	require.NoError(t, repo.AddFeeTotals(ctx, []models.FeeTotal{
+		{Token: "USDC", TakerFees: decimal.NewFromFloat(1.5), MakerRebates: decimal.NewFromFloat(0.5)},
+		{Token: "MATIC", TakerFees: decimal.NewFromInt(2)},
+	}))
+	require.NoError(t, repo.AddFeeTotals(ctx, []models.FeeTotal{{Token: "USDC", TakerFees: decimal.NewFromFloat(0.5)}}))
+
+	totals, err := repo.GetFeeTotals(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, totals, 2)
+	assert.Equal(t, "MATIC", totals[0].Token)
+	assert.Equal(t, "2", totals[1].TakerFees.String())
+	assert.Equal(t, "0.5", totals[1].MakerRebates.String())
+}
+
+func TestMemoryRepository_Candles(t *testing.T) {
+	repo := newTestRepo(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.StoreCandles(ctx, []models.Candle{
+		{Symbol: symbol, Interval: models.CANDLE_1M, Start: start.Add(time.Minute), Trades: 1},
+		{Symbol: symbol, Interval: models.CANDLE_1M, Start: start, Trades: 1},
+		{Symbol: symbol, Interval: models.CANDLE_1M, Start: start.Add(2 * time.Minute), Trades: 1},
+	}))
+	// replaces the candle of the same start
+	require.NoError(t, repo.StoreCandles(ctx, []models.Candle{{Symbol: symbol, Interval: models.CANDLE_1M, Start: start, Trades: 2}}))
+
+	candles, err := repo.GetCandles(ctx, symbol, models.CANDLE_1M, start, start.Add(2*time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, candles, 2)
+	assert.Equal(t, 2, candles[0].Trades)
+	assert.True(t, candles[1].Start.Equal(start.Add(time.Minute)))
+}
@@ -0,0 +1,73 @@
+package memrepo
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+func (r *memoryRepository) StoreFirmQuote(ctx context.Context, quote models.FirmQuote) error {
+	data, err := json.Marshal(quote)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal firm quote", logger.String("quoteId", quote.Id.String()), logger.Error(err))
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.firmQuotes[quote.Id] = data
+	return nil
+}
+
+func (r *memoryRepository) GetFirmQuote(ctx context.Context, quoteId uuid.UUID) (*models.FirmQuote, error) {
+	r.mu.RLock()
+	data, ok := r.firmQuotes[quoteId]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return unmarshalFirmQuote(ctx, data)
+}
+
+// TakeFirmQuote removes the quote and returns it, returns ErrNotFound if it was already taken
+func (r *memoryRepository) TakeFirmQuote(ctx context.Context, quoteId uuid.UUID) (*models.FirmQuote, error) {
+	r.mu.Lock()
+	data, ok := r.firmQuotes[quoteId]
+	delete(r.firmQuotes, quoteId)
+	r.mu.Unlock()
+
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return unmarshalFirmQuote(ctx, data)
+}
+
+func (r *memoryRepository) GetFirmQuotes(ctx context.Context) ([]models.FirmQuote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	quotes := []models.FirmQuote{}
+	for _, data := range r.firmQuotes {
+		quote, err := unmarshalFirmQuote(ctx, data)
+		if err != nil {
+			continue
+		}
+		quotes = append(quotes, *quote)
+	}
+	return quotes, nil
+}
+
+func unmarshalFirmQuote(ctx context.Context, data []byte) (*models.FirmQuote, error) {
+	var quote models.FirmQuote
+	if err := json.Unmarshal(data, &quote); err != nil {
+		logctx.Error(ctx, "failed to unmarshal firm quote", logger.Error(err))
+		return nil, models.ErrMarshalError
+	}
+	return &quote, nil
+}
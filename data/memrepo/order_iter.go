@@ -0,0 +1,63 @@
+package memrepo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// OrderIter walks the IDs of a price ladder read at its creation, reading each order when it is reached
+type OrderIter struct {
+	index int
+	ids   []string
+	repo  *memoryRepository
+}
+
+func (i *OrderIter) Next(ctx context.Context) *models.Order {
+	// continue iterating even if error occured, next order may be healthy
+	for i.index < len(i.ids)-1 {
+		i.index = i.index + 1
+		orderId, err := uuid.Parse(i.ids[i.index])
+		if err != nil {
+			logctx.Error(ctx, "Error parsing order id", logger.Error(err))
+			continue
+		}
+		order, err := i.repo.FindOrderById(ctx, orderId, false)
+		if err != nil {
+			logctx.Warn(ctx, "Order not found, perhaps deleted, go next", logger.String("orderId", orderId.String()), logger.Error(err))
+			continue
+		}
+		return order
+	}
+	logctx.Warn(ctx, "Error iterator reached last element")
+	return nil
+}
+
+func (i *OrderIter) HasNext() bool {
+	return i.index < (len(i.ids) - 1)
+}
+
+func (r *memoryRepository) GetMinAsk(ctx context.Context, symbol models.Symbol) models.OrderIter {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return &OrderIter{
+		index: -1,
+		ids:   r.priceLadder(symbol, models.SELL, false).members(false),
+		repo:  r,
+	}
+}
+
+func (r *memoryRepository) GetMaxBid(ctx context.Context, symbol models.Symbol) models.OrderIter {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return &OrderIter{
+		index: -1,
+		ids:   r.priceLadder(symbol, models.BUY, false).members(true),
+		repo:  r,
+	}
+}
@@ -0,0 +1,245 @@
+package memrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/data/redisrepo"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/shopspring/decimal"
+)
+
+// FindOrderById finds an order by its ID or clientOId. If `isClientOId` is true, the `id` is treated as a clientOId, otherwise it is treated as an orderId.
+func (r *memoryRepository) FindOrderById(ctx context.Context, id uuid.UUID, isClientOId bool) (*models.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	orderId := id
+	if isClientOId {
+		var ok bool
+		if orderId, ok = r.clientOIds[id]; !ok {
+			return nil, models.ErrNotFound
+		}
+	}
+
+	order, err := r.getOrder(orderId)
+	if err != nil {
+		if err != models.ErrNotFound {
+			logctx.Error(ctx, "could not map order", logger.Error(err))
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+// FindOrdersByIds finds orders by their IDs. If an order is not found for any of the provided IDs, an error is returned.
+//
+// Passing onlyOpen=true will only return orders that are open (not cancelled and not filled).
+func (r *memoryRepository) FindOrdersByIds(ctx context.Context, ids []uuid.UUID, onlyOpen bool) ([]models.Order, error) {
+	if len(ids) > redisrepo.MAX_ORDER_IDS {
+		return nil, fmt.Errorf("exceeded maximum number of IDs: %d", redisrepo.MAX_ORDER_IDS)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	orders := make([]models.Order, 0, len(ids))
+	for _, id := range ids {
+		order, err := r.getOrder(id)
+		if err == models.ErrNotFound {
+			logctx.Warn(ctx, "order not found but was expected to exist", logger.String("orderId", id.String()))
+			return nil, errors.New("order not found but was expected to exist")
+		}
+		if err != nil {
+			logctx.Error(ctx, "could not map order", logger.Error(err))
+			return nil, fmt.Errorf("could not map order: %v", err)
+		}
+		if !onlyOpen || order.IsOpen() {
+			orders = append(orders, *order)
+		}
+	}
+	return orders, nil
+}
+
+// GetOrdersAtPrice returns the open orders of both sides of the symbol at the price, oldest first
+func (r *memoryRepository) GetOrdersAtPrice(ctx context.Context, symbol models.Symbol, price decimal.Decimal) ([]models.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	orders := []models.Order{}
+	f64Price, _ := price.Float64()
+	for _, side := range []models.Side{models.BUY, models.SELL} {
+		for _, idStr := range r.priceLadder(symbol, side, false).rangeByScore(f64Price, f64Price+priceScoreWindow) {
+			order, err := r.getOrderByStr(idStr)
+			if err != nil {
+				logctx.Warn(ctx, "order of price ladder not found", logger.String("orderId", idStr), logger.Error(err))
+				continue
+			}
+			// the window may hold close prices too
+			if order.Price.Equal(price) {
+				orders = append(orders, *order)
+			}
+		}
+	}
+	return orders, nil
+}
+
+// GetMarketDepth returns up to `query.Depth` price levels (L2) or orders (L3) of each side, best price first
+func (r *memoryRepository) GetMarketDepth(ctx context.Context, symbol models.Symbol, query models.DepthQuery) (models.MarketDepth, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	marketDepth := models.MarketDepth{
+		Asks:   [][]decimal.Decimal{},
+		Bids:   [][]decimal.Decimal{},
+		Symbol: symbol.String(),
+		Time:   time.Now().Unix(),
+	}
+
+	marketDepth.Asks = r.depthSide(ctx, symbol, models.SELL, query)
+	marketDepth.Bids = r.depthSide(ctx, symbol, models.BUY, query)
+	return marketDepth, nil
+}
+
+// depthSide adds the open orders of a side, best price first, until the depth is full or the side is exhausted
+func (r *memoryRepository) depthSide(ctx context.Context, symbol models.Symbol, side models.Side, query models.DepthQuery) [][]decimal.Decimal {
+	builder := models.NewDepthBuilder(side, query)
+	if query.Depth <= 0 {
+		return builder.Rows
+	}
+
+	for _, order := range r.ladderOrders(ctx, symbol, side) {
+		if !order.IsOpen() {
+			continue
+		}
+		if !builder.Add(order.Price, order.GetAvailableSize()) {
+			break
+		}
+	}
+	return builder.Rows
+}
+
+// GetOpenOrders returns the open orders of a user in a symbol, or all symbols if it is empty, sorted by creation time.
+//
+// This function is paginated, and returns the total number of open orders of the user
+func (r *memoryRepository) GetOpenOrders(ctx context.Context, userId uuid.UUID, symbol models.Symbol) ([]models.Order, int, error) {
+	start, stop := utils.PaginationBounds(ctx)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	orderIds := r.openOrdersOf(userId, false).members(false)
+	totalOrders := len(orderIds)
+
+	// the bounds are inclusive, like a Redis range
+	if start > int64(len(orderIds)) {
+		start = int64(len(orderIds))
+	}
+	if stop >= int64(len(orderIds)) {
+		stop = int64(len(orderIds)) - 1
+	}
+
+	orders := []models.Order{}
+	for _, idStr := range orderIds[start : stop+1] {
+		order, err := r.getOrderByStr(idStr)
+		if err != nil {
+			logctx.Error(ctx, "failed to find order of user", logger.String("userId", userId.String()), logger.String("orderId", idStr), logger.Error(err))
+			return []models.Order{}, 0, fmt.Errorf("failed to find orders by IDs: %v", err)
+		}
+		if symbol == "" || order.Symbol == symbol {
+			orders = append(orders, *order)
+		}
+	}
+	return orders, totalOrders, nil
+}
+
+// GetOpenOrdersForUser returns all the open orders of a user, or ErrNotFound if there are none
+func (r *memoryRepository) GetOpenOrdersForUser(ctx context.Context, userId uuid.UUID) ([]models.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	orderIds := r.openOrdersOf(userId, false).members(false)
+	if len(orderIds) == 0 {
+		logctx.Warn(ctx, "no open orders found for user", logger.String("userId", userId.String()))
+		return nil, models.ErrNotFound
+	}
+
+	orders := make([]models.Order, 0, len(orderIds))
+	for _, idStr := range orderIds {
+		order, err := r.getOrderByStr(idStr)
+		if err == models.ErrNotFound {
+			logctx.Warn(ctx, "order not found but was expected to exist", logger.String("orderId", idStr))
+			continue
+		}
+		if err != nil {
+			logctx.Error(ctx, "could not map order", logger.Error(err))
+			return nil, fmt.Errorf("could not map order: %v", err)
+		}
+		if order.IsOpen() {
+			orders = append(orders, *order)
+		}
+	}
+	return orders, nil
+}
+
+// GetExpiredOrderIds returns the IDs of orders whose signed deadline or time in force expiry is at or before `at`
+func (r *memoryRepository) GetExpiredOrderIds(ctx context.Context, at time.Time) ([]uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	strIds := r.deadlines.rangeByScore(math.Inf(-1), float64(at.Unix()))
+	ids := make([]uuid.UUID, 0, len(strIds))
+	for _, strId := range strIds {
+		id, err := uuid.Parse(strId)
+		if err != nil {
+			logctx.Error(ctx, "invalid order ID in deadlines", logger.String("orderId", strId), logger.Error(err))
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// getOrder maps the stored fields of an order, must be called with the lock held
+func (r *memoryRepository) getOrder(orderId uuid.UUID) (*models.Order, error) {
+	orderMap, ok := r.orders[orderId]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	order := &models.Order{}
+	if err := order.MapToOrder(orderMap); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (r *memoryRepository) getOrderByStr(idStr string) (*models.Order, error) {
+	orderId, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse order id: %w", err)
+	}
+	return r.getOrder(orderId)
+}
+
+// ladderOrders returns the orders of a side's price ladder, best price first, skipping ones which can't be read
+// must be called with the lock held
+func (r *memoryRepository) ladderOrders(ctx context.Context, symbol models.Symbol, side models.Side) []models.Order {
+	orderIds := r.priceLadder(symbol, side, false).members(side == models.BUY)
+	orders := make([]models.Order, 0, len(orderIds))
+	for _, idStr := range orderIds {
+		order, err := r.getOrderByStr(idStr)
+		if err != nil {
+			logctx.Warn(ctx, "order of price ladder not found", logger.String("orderId", idStr), logger.Error(err))
+			continue
+		}
+		orders = append(orders, *order)
+	}
+	return orders
+}
@@ -0,0 +1,140 @@
+package memrepo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_FindOrdersByIds(t *testing.T) {
+	repo := newTestRepo(t)
+	open := newTestOrder(models.BUY, "1", "10", 0)
+	cancelled := newTestOrder(models.BUY, "1", "10", 0)
+	cancelled.Cancelled = true
+	require.NoError(t, repo.StoreOpenOrders(ctx, []models.Order{open, cancelled}))
+
+	t.Run("should return all orders in ids order", func(t *testing.T) {
+		orders, err := repo.FindOrdersByIds(ctx, []uuid.UUID{cancelled.Id, open.Id}, false)
+		assert.NoError(t, err)
+		assert.Len(t, orders, 2)
+		assert.Equal(t, cancelled.Id, orders[0].Id)
+	})
+
+	t.Run("should only return open orders", func(t *testing.T) {
+		orders, err := repo.FindOrdersByIds(ctx, []uuid.UUID{cancelled.Id, open.Id}, true)
+		assert.NoError(t, err)
+		assert.Len(t, orders, 1)
+		assert.Equal(t, open.Id, orders[0].Id)
+	})
+
+	t.Run("should return error if an order is missing", func(t *testing.T) {
+		_, err := repo.FindOrdersByIds(ctx, []uuid.UUID{open.Id, uuid.New()}, false)
+		assert.Error(t, err)
+	})
+}
+
+func TestMemoryRepository_GetMarketDepth(t *testing.T) {
+	repo := newTestRepo(t)
+	require.NoError(t, repo.StoreOpenOrders(ctx, []models.Order{
+		newTestOrder(models.SELL, "1.2", "1", 0),
+		newTestOrder(models.SELL, "1.1", "2", 0),
+		newTestOrder(models.SELL, "1.1", "3", 0),
+		newTestOrder(models.BUY, "0.9", "4", 0),
+		newTestOrder(models.BUY, "1", "5", 0),
+	}))
+
+	t.Run("should aggregate price levels best price first", func(t *testing.T) {
+		depth, err := repo.GetMarketDepth(ctx, symbol, models.DepthQuery{Mode: models.DEPTH_L2, Depth: 10})
+		assert.NoError(t, err)
+		assert.Len(t, depth.Asks, 2)
+		assert.Equal(t, "1.1", depth.Asks[0][0].String())
+		assert.Equal(t, "5", depth.Asks[0][1].String())
+		assert.Len(t, depth.Bids, 2)
+		assert.Equal(t, "1", depth.Bids[0][0].String())
+	})
+
+	t.Run("should limit the levels to the depth", func(t *testing.T) {
+		depth, err := repo.GetMarketDepth(ctx, symbol, models.DepthQuery{Mode: models.DEPTH_L2, Depth: 1})
+		assert.NoError(t, err)
+		assert.Len(t, depth.Asks, 1)
+		assert.Len(t, depth.Bids, 1)
+	})
+}
+
+func TestMemoryRepository_GetOpenOrders(t *testing.T) {
+	repo := newTestRepo(t)
+	oldest := newTestOrder(models.BUY, "1", "10", 2*time.Minute)
+	middle := newTestOrder(models.SELL, "2", "10", time.Minute)
+	newest := newTestOrder(models.BUY, "1", "10", 0)
+	newest.Symbol = "ETH-USDC"
+	require.NoError(t, repo.StoreOpenOrders(ctx, []models.Order{newest, oldest, middle}))
+
+	t.Run("should return the orders of a symbol oldest first and the total", func(t *testing.T) {
+		orders, total, err := repo.GetOpenOrders(mocks.AddPaginationToCtx(1, 10), userId, symbol)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+		assert.Len(t, orders, 2)
+		assert.Equal(t, oldest.Id, orders[0].Id)
+		assert.Equal(t, middle.Id, orders[1].Id)
+	})
+
+	t.Run("should return ErrNotFound for a user without open orders", func(t *testing.T) {
+		_, err := repo.GetOpenOrdersForUser(ctx, uuid.New())
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should return all the open orders of a user", func(t *testing.T) {
+		orders, err := repo.GetOpenOrdersForUser(ctx, userId)
+		assert.NoError(t, err)
+		assert.Len(t, orders, 3)
+	})
+}
+
+func TestMemoryRepository_GetExpiredOrderIds(t *testing.T) {
+	repo := newTestRepo(t)
+	expired := newTestOrder(models.BUY, "1", "10", 0)
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	later := newTestOrder(models.BUY, "1", "10", 0)
+	later.ExpiresAt = time.Now().Add(time.Hour)
+	gtc := newTestOrder(models.BUY, "1", "10", 0)
+	require.NoError(t, repo.StoreOpenOrders(ctx, []models.Order{expired, later, gtc}))
+
+	ids, err := repo.GetExpiredOrderIds(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{expired.Id}, ids)
+}
+
+func TestMemoryRepository_GetOrdersAtPrice(t *testing.T) {
+	repo := newTestRepo(t)
+	newer := newTestOrder(models.BUY, "1.5", "1", 0)
+	older := newTestOrder(models.BUY, "1.5", "1", time.Minute)
+	ask := newTestOrder(models.SELL, "1.5", "1", 0)
+	nearby := newTestOrder(models.BUY, "1.501", "1", 0)
+	require.NoError(t, repo.StoreOpenOrders(ctx, []models.Order{newer, older, ask, nearby}))
+
+	orders, err := repo.GetOrdersAtPrice(ctx, symbol, decimal.RequireFromString("1.5"))
+	assert.NoError(t, err)
+	require.Len(t, orders, 3)
+	assert.Equal(t, older.Id, orders[0].Id)
+	assert.Equal(t, newer.Id, orders[1].Id)
+	assert.Equal(t, ask.Id, orders[2].Id)
+}
+
+func TestMemoryRepository_GetMinAsk(t *testing.T) {
+	repo := newTestRepo(t)
+	high := newTestOrder(models.SELL, "2", "1", 0)
+	low := newTestOrder(models.SELL, "1", "1", 0)
+	require.NoError(t, repo.StoreOpenOrders(ctx, []models.Order{high, low}))
+
+	it := repo.GetMinAsk(ctx, symbol)
+	assert.True(t, it.HasNext())
+	assert.Equal(t, low.Id, it.Next(ctx).Id)
+	assert.Equal(t, high.Id, it.Next(ctx).Id)
+	assert.False(t, it.HasNext())
+}
@@ -0,0 +1,77 @@
+package memrepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// messages buffered per subscriber before they are dropped
+const subscriberBufferSize = 100
+
+// PublishEvent sends an event to all the subscribers of the channel
+func (r *memoryRepository) PublishEvent(ctx context.Context, key string, value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = append([]byte{}, v...)
+	case string:
+		data = []byte(v)
+	default:
+		data = []byte(fmt.Sprint(v))
+	}
+
+	r.publish(ctx, key, data)
+	return nil
+}
+
+func (r *memoryRepository) SubscribeToEvents(ctx context.Context, channel string) (chan []byte, error) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	clients, ok := r.subscriptions[channel]
+	if !ok {
+		clients = make(map[chan []byte]struct{})
+		r.subscriptions[channel] = clients
+	}
+
+	clientChan := make(chan []byte, subscriberBufferSize)
+	clients[clientChan] = struct{}{}
+	return clientChan, nil
+}
+
+// UnsubscribeFromEvents unsubscribes a client from a channel and closes its channel
+func (r *memoryRepository) UnsubscribeFromEvents(ctx context.Context, channel string, clientChan chan []byte) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	clients, ok := r.subscriptions[channel]
+	if !ok {
+		return
+	}
+	if _, ok := clients[clientChan]; !ok {
+		return
+	}
+
+	delete(clients, clientChan)
+	close(clientChan)
+	if len(clients) == 0 {
+		delete(r.subscriptions, channel)
+	}
+}
+
+// publish sends the message to the channel's subscribers without blocking, a subscriber whose buffer is full misses it
+func (r *memoryRepository) publish(ctx context.Context, channel string, data []byte) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	for clientChan := range r.subscriptions[channel] {
+		select {
+		case clientChan <- data:
+		default:
+			logctx.Error(ctx, "client channel is full, dropping message", logger.String("channel", channel), logger.Int("buffer_size", subscriberBufferSize))
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package memrepo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_PubSub(t *testing.T) {
+
+	t.Run("should deliver events to all subscribers until they unsubscribe", func(t *testing.T) {
+		repo := newTestRepo(t)
+		first, err := repo.SubscribeToEvents(ctx, "channel")
+		require.NoError(t, err)
+		second, err := repo.SubscribeToEvents(ctx, "channel")
+		require.NoError(t, err)
+
+		assert.NoError(t, repo.PublishEvent(ctx, "channel", []byte("event")))
+		assert.Equal(t, "event", string(<-first))
+		assert.Equal(t, "event", string(<-second))
+
+		repo.UnsubscribeFromEvents(ctx, "channel", first)
+		_, open := <-first
+		assert.False(t, open)
+
+		assert.NoError(t, repo.PublishEvent(ctx, "channel", "other"))
+		assert.Equal(t, "other", string(<-second))
+	})
+
+	t.Run("should publish a book change once a tx is committed", func(t *testing.T) {
+		repo := newTestRepo(t)
+		events, err := repo.SubscribeToEvents(ctx, models.CreateMarketEventKey(symbol))
+		require.NoError(t, err)
+
+		failed := repo.PerformTx(ctx, func(txid uint) error {
+			if err := repo.TxStoreOpenOrder(ctx, txid, newTestOrder(models.SELL, "1", "1", 0)); err != nil {
+				return err
+			}
+			return assert.AnError
+		})
+		require.Error(t, failed)
+		assert.Empty(t, events)
+
+		require.NoError(t, repo.StoreOpenOrder(ctx, newTestOrder(models.SELL, "1", "1", 0)))
+		select {
+		case data := <-events:
+			var event models.MarketEvent
+			assert.NoError(t, json.Unmarshal(data, &event))
+			assert.Equal(t, models.MARKET_EVENT_BOOK_CHANGE, event.Event)
+			assert.Equal(t, models.SELL, event.Side)
+		case <-time.After(time.Second):
+			t.Fatal("book change was not published")
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package memrepo
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+)
+
+// memoryRepository keeps the order book in process memory, for running without Redis in development and tests
+// it mirrors how redisrepo stores each value, orders as their hash fields and the rest as JSON, so both behave the same
+type memoryRepository struct {
+	mu sync.RWMutex
+
+	// orders by ID, as the fields of their `order:<id>` hash
+	orders     map[uuid.UUID]map[string]string
+	clientOIds map[uuid.UUID]uuid.UUID
+	// price ladders by symbol and side, scored by price and time
+	prices map[priceLadderKey]*sortedSet
+	// open orders of each user, scored by time
+	userOpenOrders map[uuid.UUID]*sortedSet
+	// orders which expire, scored by their expiry time
+	deadlines *sortedSet
+	// string keys, like the tracked maker balances
+	strs map[string]string
+
+	openSwaps     map[uuid.UUID][]byte
+	resolvedSwaps map[uuid.UUID][]byte
+	// resolved swaps scored by their resolve time
+	resolvedSwapTimes *sortedSet
+	userResolvedSwaps map[uuid.UUID]map[string]struct{}
+	firmQuotes        map[uuid.UUID][]byte
+
+	userFills    map[uuid.UUID]*stream
+	symbolTrades map[models.Symbol]*stream
+	takerFees    map[string]decimal.Decimal
+	makerRebates map[string]decimal.Decimal
+	candles      map[candlesKey]map[int64][]byte
+
+	userEventSeqs map[uuid.UUID]int64
+	userEvents    map[uuid.UUID][]models.UserEvent
+	// closed and replaced when a user event is appended, to wake up blocked readers
+	userEventsAppended map[uuid.UUID]chan struct{}
+
+	webhooks          map[uuid.UUID][]byte
+	userWebhooks      map[uuid.UUID]map[uuid.UUID]struct{}
+	webhookLeases     map[uuid.UUID]webhookLease
+	webhookDeliveries map[uuid.UUID]*stream
+
+	usersById     map[uuid.UUID]models.User
+	usersByApiKey map[string]uuid.UUID
+
+	txMap   map[uint]*memoryTx
+	ixIndex uint
+
+	subMu         sync.Mutex
+	subscriptions map[string]map[chan []byte]struct{}
+}
+
+type priceLadderKey struct {
+	symbol models.Symbol
+	side   models.Side
+}
+
+type candlesKey struct {
+	symbol   models.Symbol
+	interval models.CandleInterval
+}
+
+type webhookLease struct {
+	owner     string
+	expiresAt time.Time
+}
+
+func NewMemoryRepository() (*memoryRepository, error) {
+	return &memoryRepository{
+		orders:             make(map[uuid.UUID]map[string]string),
+		clientOIds:         make(map[uuid.UUID]uuid.UUID),
+		prices:             make(map[priceLadderKey]*sortedSet),
+		userOpenOrders:     make(map[uuid.UUID]*sortedSet),
+		deadlines:          newSortedSet(),
+		strs:               make(map[string]string),
+		openSwaps:          make(map[uuid.UUID][]byte),
+		resolvedSwaps:      make(map[uuid.UUID][]byte),
+		resolvedSwapTimes:  newSortedSet(),
+		userResolvedSwaps:  make(map[uuid.UUID]map[string]struct{}),
+		firmQuotes:         make(map[uuid.UUID][]byte),
+		userFills:          make(map[uuid.UUID]*stream),
+		symbolTrades:       make(map[models.Symbol]*stream),
+		takerFees:          make(map[string]decimal.Decimal),
+		makerRebates:       make(map[string]decimal.Decimal),
+		candles:            make(map[candlesKey]map[int64][]byte),
+		userEventSeqs:      make(map[uuid.UUID]int64),
+		userEvents:         make(map[uuid.UUID][]models.UserEvent),
+		userEventsAppended: make(map[uuid.UUID]chan struct{}),
+		webhooks:           make(map[uuid.UUID][]byte),
+		userWebhooks:       make(map[uuid.UUID]map[uuid.UUID]struct{}),
+		webhookLeases:      make(map[uuid.UUID]webhookLease),
+		webhookDeliveries:  make(map[uuid.UUID]*stream),
+		usersById:          make(map[uuid.UUID]models.User),
+		usersByApiKey:      make(map[string]uuid.UUID),
+		txMap:              make(map[uint]*memoryTx),
+		subscriptions:      make(map[string]map[chan []byte]struct{}),
+	}, nil
+}
+
+// priceLadder returns the price ladder of the symbol's side, creating it if create is true
+func (r *memoryRepository) priceLadder(symbol models.Symbol, side models.Side, create bool) *sortedSet {
+	key := priceLadderKey{symbol: symbol, side: side}
+	ladder, ok := r.prices[key]
+	if !ok && create {
+		ladder = newSortedSet()
+		r.prices[key] = ladder
+	}
+	return ladder
+}
+
+// openOrdersOf returns the user's open orders set, creating it if create is true
+func (r *memoryRepository) openOrdersOf(userId uuid.UUID, create bool) *sortedSet {
+	set, ok := r.userOpenOrders[userId]
+	if !ok && create {
+		set = newSortedSet()
+		r.userOpenOrders[userId] = set
+	}
+	return set
+}
@@ -0,0 +1,73 @@
+package memrepo
+
+import (
+	"sort"
+)
+
+// sortedSet orders its members by score, and members of the same score by their value, like a Redis sorted set
+type sortedSet struct {
+	scores map[string]float64
+}
+
+func newSortedSet() *sortedSet {
+	return &sortedSet{scores: make(map[string]float64)}
+}
+
+func (s *sortedSet) add(member string, score float64) {
+	s.scores[member] = score
+}
+
+func (s *sortedSet) remove(member string) {
+	if s == nil {
+		return
+	}
+	delete(s.scores, member)
+}
+
+func (s *sortedSet) len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.scores)
+}
+
+// members returns all the members, lowest score first, or highest first if reverse is true
+func (s *sortedSet) members(reverse bool) []string {
+	if s == nil {
+		return []string{}
+	}
+
+	members := make([]string, 0, len(s.scores))
+	for member := range s.scores {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if reverse {
+			return s.less(members[j], members[i])
+		}
+		return s.less(members[i], members[j])
+	})
+	return members
+}
+
+// rangeByScore returns the members scored within [min, max], lowest score first
+func (s *sortedSet) rangeByScore(min, max float64) []string {
+	res := []string{}
+	for _, member := range s.members(false) {
+		score := s.scores[member]
+		if score > max {
+			break
+		}
+		if score >= min {
+			res = append(res, member)
+		}
+	}
+	return res
+}
+
+func (s *sortedSet) less(a, b string) bool {
+	if s.scores[a] != s.scores[b] {
+		return s.scores[a] < s.scores[b]
+	}
+	return a < b
+}
@@ -0,0 +1,101 @@
+package memrepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/orbs-network/order-book/data/redisrepo"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// the maker's balance of the order's input token is tracked under the same key as in Redis, -1 until it is first checked
+func (r *memoryRepository) txEnsureMakerTokenForBalanceTracking(ctx context.Context, txid uint, order models.Order) error {
+	tx, ok := r.getTx(txid)
+	if !ok {
+		logctx.Error(ctx, "TxModifyOrder txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
+	}
+
+	key := redisrepo.Order2MakerTokenTrackKey(order)
+	if key == "" {
+		logctx.Error(ctx, "Order2MakerTokenTrackKey failed for order", logger.String("orderId", order.Id.String()))
+		return models.ErrInvalidInput
+	}
+	tx.ops = append(tx.ops, func() {
+		if _, ok := r.strs[key]; !ok {
+			r.strs[key] = "-1"
+		}
+	})
+	return nil
+}
+
+// TxStoreOpenOrder adds an unfilled or partially filled order to all of its indexes in a single tx
+func (r *memoryRepository) TxStoreOpenOrder(ctx context.Context, txid uint, order models.Order) error {
+	if err := r.TxModifyOrder(ctx, txid, models.Add, order); err != nil {
+		logctx.Error(ctx, "StoreOpenOrders TxModifyOrder Failed adding order", logger.String("orderId", order.Id.String()), logger.String("userId", order.UserId.String()), logger.Error(err))
+		return err
+	}
+	if err := r.TxModifyClientOId(ctx, txid, models.Add, order); err != nil {
+		logctx.Error(ctx, "StoreOpenOrders TxModifyClientOId Failed adding order", logger.String("orderId", order.Id.String()), logger.String("userId", order.UserId.String()), logger.Error(err))
+		return err
+	}
+	if err := r.TxModifyPrices(ctx, txid, models.Add, order); err != nil {
+		logctx.Error(ctx, "StoreOpenOrders TxModifyPrices Failed adding order", logger.String("orderId", order.Id.String()), logger.String("userId", order.UserId.String()), logger.Error(err))
+		return err
+	}
+	if err := r.TxModifyUserOpenOrders(ctx, txid, models.Add, order); err != nil {
+		logctx.Error(ctx, "StoreOpenOrders TxModifyUserOpenOrders Failed adding order", logger.String("orderId", order.Id.String()), logger.String("userId", order.UserId.String()), logger.Error(err))
+		return err
+	}
+	if err := r.TxModifyOrderDeadlines(ctx, txid, models.Add, order); err != nil {
+		logctx.Error(ctx, "StoreOpenOrders TxModifyOrderDeadlines Failed adding order", logger.String("orderId", order.Id.String()), logger.String("userId", order.UserId.String()), logger.Error(err))
+		return err
+	}
+
+	// ensure balance is tracked for this order
+	return r.txEnsureMakerTokenForBalanceTracking(ctx, txid, order)
+}
+
+func (r *memoryRepository) StoreOpenOrders(ctx context.Context, orders []models.Order) error {
+	return r.PerformTx(ctx, func(txid uint) error {
+		for _, order := range orders {
+			if err := r.TxStoreOpenOrder(ctx, txid, order); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *memoryRepository) StoreOpenOrder(ctx context.Context, order models.Order) error {
+	return r.PerformTx(ctx, func(txid uint) error {
+		return r.TxStoreOpenOrder(ctx, txid, order)
+	})
+}
+
+// This should be used to store FILLED orders.
+//
+// Each order is only stored at the version it was read, otherwise it fails with ErrVersionConflict.
+func (r *memoryRepository) StoreFilledOrders(ctx context.Context, orders []models.Order) error {
+	err := r.PerformTx(ctx, func(txid uint) error {
+		for _, order := range orders {
+			if err := r.TxModifyUserOpenOrders(ctx, txid, models.Remove, order); err != nil {
+				return err
+			}
+			if err := r.TxModifyPrices(ctx, txid, models.Remove, order); err != nil {
+				return err
+			}
+			if err := r.TxModifyOrder(ctx, txid, models.Update, order); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logctx.Error(ctx, "failed to store filled orders", logger.Error(err), logger.Strings("orderIds", models.OrderIdsToStrings(ctx, &orders)))
+		return fmt.Errorf("failed to store filled orders: %w", err)
+	}
+	return nil
+}
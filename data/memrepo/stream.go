@@ -0,0 +1,94 @@
+package memrepo
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stream is an append-only log whose entries have increasing `<ms>-<seq>` IDs, like a Redis stream
+type stream struct {
+	entries []streamEntry
+}
+
+type streamEntry struct {
+	id   streamId
+	data []byte
+}
+
+type streamId struct {
+	ms  int64
+	seq int64
+}
+
+func (id streamId) String() string {
+	return fmt.Sprintf("%d-%d", id.ms, id.seq)
+}
+
+func (id streamId) less(other streamId) bool {
+	if id.ms != other.ms {
+		return id.ms < other.ms
+	}
+	return id.seq < other.seq
+}
+
+func parseStreamId(str string) (streamId, error) {
+	parts := strings.SplitN(str, "-", 2)
+	if len(parts) != 2 {
+		return streamId{}, fmt.Errorf("invalid stream ID %q", str)
+	}
+	ms, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return streamId{}, fmt.Errorf("invalid stream ID %q: %w", str, err)
+	}
+	seq, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return streamId{}, fmt.Errorf("invalid stream ID %q: %w", str, err)
+	}
+	return streamId{ms: ms, seq: seq}, nil
+}
+
+// add appends the data with an ID of the current time, after the last entry's ID
+func (s *stream) add(data []byte) streamId {
+	return s.addAt(time.Now().UnixMilli(), data)
+}
+
+// addAt appends the data with an ID of the time in ms, after the last entry's ID
+func (s *stream) addAt(ms int64, data []byte) streamId {
+	id := streamId{ms: ms}
+	if len(s.entries) > 0 {
+		last := s.entries[len(s.entries)-1].id
+		if !last.less(id) {
+			id = streamId{ms: last.ms, seq: last.seq + 1}
+		}
+	}
+	s.entries = append(s.entries, streamEntry{id: id, data: data})
+	return id
+}
+
+// trim drops the oldest entries so at most maxLen are kept
+func (s *stream) trim(maxLen int) {
+	if len(s.entries) > maxLen {
+		s.entries = append([]streamEntry{}, s.entries[len(s.entries)-maxLen:]...)
+	}
+}
+
+// trimBefore drops the entries added before the time
+func (s *stream) trimBefore(ms int64) {
+	i := sort.Search(len(s.entries), func(i int) bool { return s.entries[i].id.ms >= ms })
+	s.entries = append([]streamEntry{}, s.entries[i:]...)
+}
+
+// reverse calls fn on the entries newest first, until it returns false
+func (s *stream) reverse(fn func(entry streamEntry) bool) {
+	if s == nil {
+		return
+	}
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		if !fn(s.entries[i]) {
+			return
+		}
+	}
+}
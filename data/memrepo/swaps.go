@@ -0,0 +1,305 @@
+package memrepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// LockOrderFrags adds the size of each fragment to its order's pending size and increments its version, only if all the orders have it available
+// returns the locked orders in frags order, ErrNotFound if an order is missing and ErrSwapInvalid if a fragment is not available
+func (r *memoryRepository) LockOrderFrags(ctx context.Context, frags []models.OrderFrag) ([]models.Order, error) {
+	tx := &memoryTx{}
+
+	r.mu.Lock()
+	// an order with several fragments is locked for all of them
+	locked := make(map[uuid.UUID]*models.Order, len(frags))
+	for _, frag := range frags {
+		order, ok := locked[frag.OrderId]
+		if !ok {
+			var err error
+			order, err = r.getOrder(frag.OrderId)
+			if err == models.ErrNotFound {
+				r.mu.Unlock()
+				logctx.Warn(ctx, "order to lock not found", logger.String("orderId", frag.OrderId.String()))
+				return nil, models.ErrNotFound
+			}
+			if err != nil {
+				r.mu.Unlock()
+				logctx.Error(ctx, "could not map order to lock", logger.Error(err), logger.String("orderId", frag.OrderId.String()))
+				return nil, err
+			}
+			locked[frag.OrderId] = order
+		}
+
+		if order.IsFilled() {
+			r.mu.Unlock()
+			logctx.Warn(ctx, "order to lock is filled", logger.String("orderId", frag.OrderId.String()))
+			return nil, models.ErrSwapInvalid
+		}
+		if err := order.Lock(ctx, frag); err != nil {
+			r.mu.Unlock()
+			logctx.Warn(ctx, "failed to lock order frag", logger.Error(err), logger.String("orderId", frag.OrderId.String()))
+			return nil, models.ErrSwapInvalid
+		}
+	}
+
+	for id, order := range locked {
+		order.Version++
+		r.setOrderFields(id, map[string]string{
+			"sizePending": order.SizePending.String(),
+			"version":     strconv.FormatInt(order.Version, 10),
+		})
+		tx.publishBookChange(ctx, *order)
+	}
+	r.mu.Unlock()
+
+	for _, event := range tx.events {
+		r.publish(ctx, event.channel, event.data)
+	}
+
+	orders := make([]models.Order, 0, len(frags))
+	for _, frag := range frags {
+		orders = append(orders, *locked[frag.OrderId])
+	}
+	return orders, nil
+}
+
+func (r *memoryRepository) StoreSwap(ctx context.Context, swapId uuid.UUID, legs []models.RouteLeg, frags []models.OrderFrag) error {
+	swap := models.NewSwap(legs, frags)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.saveSwap(ctx, swapId, *swap, false); err != nil {
+		logctx.Error(ctx, "failed to store swap", logger.String("swapId", swapId.String()), logger.Error(err))
+		return fmt.Errorf("failed to store swap: %v", err)
+	}
+	logctx.Info(ctx, "stored swap", logger.String("swapId", swapId.String()))
+	return nil
+}
+
+func (r *memoryRepository) GetSwap(ctx context.Context, swapId uuid.UUID, open bool) (*models.Swap, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.getSwap(ctx, swapId, open)
+}
+
+func (r *memoryRepository) RemoveSwap(ctx context.Context, swapId uuid.UUID) error {
+	logctx.Debug(ctx, "RemoveSwap", logger.String("key", swapId.String()))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.openSwaps, swapId)
+	return nil
+}
+
+// GetOpenSwaps returns the swaps which are not resolved yet, oldest first
+func (r *memoryRepository) GetOpenSwaps(ctx context.Context) ([]models.Swap, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := []models.Swap{}
+	for id := range r.openSwaps {
+		swap, err := r.getSwap(ctx, id, true)
+		if err != nil {
+			logctx.Error(ctx, "failed to get swap", logger.String("swapId", id.String()))
+			continue
+		}
+		swap.Id = id
+		res = append(res, *swap)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Created.Before(res[j].Created) })
+	return res, nil
+}
+
+// StoreNewPendingSwap stores a new pending swap in order for its status (pending/complete) to be checked later
+func (r *memoryRepository) StoreNewPendingSwap(ctx context.Context, p models.SwapTx) (*models.Swap, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	swap, err := r.getSwap(ctx, p.SwapId, true)
+	if err != nil {
+		if err == models.ErrNotFound {
+			logctx.Warn(ctx, "no swap found by that ID", logger.Error(err), logger.String("swapId", p.SwapId.String()), logger.String("txHash", p.TxHash))
+			return nil, err
+		}
+		logctx.Error(ctx, "failed to get swap", logger.Error(err), logger.String("swapId", p.SwapId.String()), logger.String("txHash", p.TxHash))
+		return nil, fmt.Errorf("failed to get swap unexpectedly: %s", err)
+	}
+
+	// protect re-entry
+	if swap.IsStarted() {
+		logctx.Error(ctx, "swap is already started", logger.String("startedSwapId", p.SwapId.String()))
+		return nil, fmt.Errorf("swap is already started")
+	}
+
+	swap.Started = time.Now()
+	swap.TxHash = p.TxHash
+	swap.Id = p.SwapId
+	if err := r.saveSwap(ctx, p.SwapId, *swap, false); err != nil {
+		logctx.Error(ctx, "failed to update swap started time", logger.Error(err), logger.String("swapId", p.SwapId.String()))
+		return nil, fmt.Errorf("failed to update swap started time: %s", err)
+	}
+
+	logctx.Debug(ctx, "store pending swap", logger.String("swapId", p.SwapId.String()), logger.String("txHash", p.TxHash))
+	return swap, nil
+}
+
+// ResolveSwap moves the swap from the open swaps to the resolved ones, in a single tx
+func (r *memoryRepository) ResolveSwap(ctx context.Context, swap models.Swap) error {
+	return r.PerformTx(ctx, func(txid uint) error {
+		return r.TxResolveSwap(ctx, txid, swap)
+	})
+}
+
+// TxResolveSwap moves the swap from the open swaps to the resolved ones once the tx is committed
+func (r *memoryRepository) TxResolveSwap(ctx context.Context, txid uint, swap models.Swap) error {
+	tx, ok := r.getTx(txid)
+	if !ok {
+		logctx.Error(ctx, "TxResolveSwap txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
+	}
+
+	swapJson, err := json.Marshal(swap)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal swap", logger.String("swapId", swap.Id.String()), logger.Error(err))
+		return fmt.Errorf("failed to marshal swap: %v", err)
+	}
+	tx.ops = append(tx.ops, func() {
+		r.resolvedSwaps[swap.Id] = swapJson
+		r.resolvedSwapTimes.add(swap.Id.String(), float64(swap.Resolved.UnixMilli()))
+		delete(r.openSwaps, swap.Id)
+	})
+	return nil
+}
+
+// GetResolvedSwapsUserIds returns the users with resolved swaps in the store
+func (r *memoryRepository) GetResolvedSwapsUserIds(ctx context.Context) ([]uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	userIds := []uuid.UUID{}
+	for userId, swapIds := range r.userResolvedSwaps {
+		if len(swapIds) > 0 {
+			userIds = append(userIds, userId)
+		}
+	}
+	return userIds, nil
+}
+
+// GetResolvedSwaps returns up to limit swaps resolved before the time, oldest first
+func (r *memoryRepository) GetResolvedSwaps(ctx context.Context, resolvedBefore time.Time, limit int) ([]models.Swap, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// scores are whole ms, so this excludes the time itself
+	swaps := []models.Swap{}
+	for _, swapId := range r.resolvedSwapTimes.rangeByScore(math.Inf(-1), float64(resolvedBefore.UnixMilli()-1)) {
+		if len(swaps) >= limit {
+			break
+		}
+		id, err := uuid.Parse(swapId)
+		if err != nil {
+			logctx.Error(ctx, "invalid swap ID in resolved swaps", logger.String("swapId", swapId), logger.Error(err))
+			return nil, err
+		}
+		var swap models.Swap
+		if err := json.Unmarshal(r.resolvedSwaps[id], &swap); err != nil {
+			logctx.Error(ctx, "failed to unmarshal resolved swap", logger.String("swapId", swapId), logger.Error(err))
+			continue
+		}
+		swaps = append(swaps, swap)
+	}
+	return swaps, nil
+}
+
+// RemoveResolvedSwap removes the resolved swap, and its ID from the resolved swaps of the users
+func (r *memoryRepository) RemoveResolvedSwap(ctx context.Context, swapId uuid.UUID, userIds []uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.resolvedSwaps, swapId)
+	r.resolvedSwapTimes.remove(swapId.String())
+	for _, userId := range userIds {
+		delete(r.userResolvedSwaps[userId], swapId.String())
+	}
+	return nil
+}
+
+// save swapId in the set of the user's resolved swaps
+func (r *memoryRepository) StoreUserResolvedSwap(ctx context.Context, userId uuid.UUID, swap models.Swap) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	swapIds, ok := r.userResolvedSwaps[userId]
+	if !ok {
+		swapIds = make(map[string]struct{})
+		r.userResolvedSwaps[userId] = swapIds
+	}
+	if _, ok := swapIds[swap.Id.String()]; ok {
+		logctx.Warn(ctx, "Element already in set", logger.String("userId", userId.String()), logger.String("swapId", swap.Id.String()))
+		return models.ErrValAlreadyInSet
+	}
+	swapIds[swap.Id.String()] = struct{}{}
+	return nil
+}
+
+func (r *memoryRepository) GetUserResolvedSwapIds(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]string, 0, len(r.userResolvedSwaps[userId]))
+	for swapId := range r.userResolvedSwaps[userId] {
+		res = append(res, swapId)
+	}
+	return res, nil
+}
+
+// saveSwap must be called with the write lock held
+func (r *memoryRepository) saveSwap(ctx context.Context, swapId uuid.UUID, swap models.Swap, resolved bool) error {
+	swapJson, err := json.Marshal(swap)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal swap", logger.String("swapId", swapId.String()), logger.Error(err))
+		return fmt.Errorf("failed to marshal swap: %v", err)
+	}
+
+	if resolved {
+		r.resolvedSwaps[swapId] = swapJson
+	} else {
+		r.openSwaps[swapId] = swapJson
+	}
+	return nil
+}
+
+// getSwap must be called with the lock held
+func (r *memoryRepository) getSwap(ctx context.Context, swapId uuid.UUID, open bool) (*models.Swap, error) {
+	swaps := r.openSwaps
+	if !open {
+		swaps = r.resolvedSwaps
+	}
+
+	swapJson, ok := swaps[swapId]
+	if !ok {
+		logctx.Warn(ctx, "swap is not found", logger.String("swapId", swapId.String()))
+		return nil, models.ErrNotFound
+	}
+
+	var swap models.Swap
+	if err := json.Unmarshal(swapJson, &swap); err != nil {
+		logctx.Error(ctx, "failed to unmarshal swap", logger.String("swapId", swapId.String()), logger.Error(err))
+		return nil, models.ErrMarshalError
+	}
+	return &swap, nil
+}
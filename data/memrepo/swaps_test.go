@@ -0,0 +1,164 @@
+package memrepo
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_LockOrderFrags(t *testing.T) {
+
+	t.Run("should lock all the fragments and increment the versions", func(t *testing.T) {
+		repo := newTestRepo(t)
+		order := newTestOrder(models.SELL, "1", "10", 0)
+		require.NoError(t, repo.StoreOpenOrder(ctx, order))
+
+		frag := models.OrderFrag{OrderId: order.Id, OutSize: decimal.NewFromInt(3)}
+		orders, err := repo.LockOrderFrags(ctx, []models.OrderFrag{frag, frag})
+		assert.NoError(t, err)
+		assert.Len(t, orders, 2)
+		assert.Equal(t, "6", orders[0].SizePending.String())
+		assert.Equal(t, int64(1), orders[0].Version)
+
+		stored, err := repo.FindOrderById(ctx, order.Id, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "6", stored.SizePending.String())
+		assert.Equal(t, int64(1), stored.Version)
+	})
+
+	t.Run("should lock none if a fragment is not available", func(t *testing.T) {
+		repo := newTestRepo(t)
+		available := newTestOrder(models.SELL, "1", "10", 0)
+		small := newTestOrder(models.SELL, "1", "1", 0)
+		require.NoError(t, repo.StoreOpenOrders(ctx, []models.Order{available, small}))
+
+		_, err := repo.LockOrderFrags(ctx, []models.OrderFrag{
+			{OrderId: available.Id, OutSize: decimal.NewFromInt(3)},
+			{OrderId: small.Id, OutSize: decimal.NewFromInt(3)},
+		})
+		assert.ErrorIs(t, err, models.ErrSwapInvalid)
+
+		stored, err := repo.FindOrderById(ctx, available.Id, false)
+		assert.NoError(t, err)
+		assert.True(t, stored.SizePending.IsZero())
+	})
+
+	t.Run("should return ErrNotFound for a missing order", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		_, err := repo.LockOrderFrags(ctx, []models.OrderFrag{{OrderId: uuid.New(), OutSize: decimal.NewFromInt(1)}})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should never lock more than the available size concurrently", func(t *testing.T) {
+		repo := newTestRepo(t)
+		order := newTestOrder(models.SELL, "1", "10", 0)
+		require.NoError(t, repo.StoreOpenOrder(ctx, order))
+
+		var locked int32
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repo.LockOrderFrags(ctx, []models.OrderFrag{{OrderId: order.Id, OutSize: decimal.NewFromInt(1)}})
+				if err == nil {
+					atomic.AddInt32(&locked, 1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(10), locked)
+		stored, err := repo.FindOrderById(ctx, order.Id, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "10", stored.SizePending.String())
+	})
+}
+
+func TestMemoryRepository_Swaps(t *testing.T) {
+	repo := newTestRepo(t)
+	swapId := uuid.New()
+	frags := []models.OrderFrag{{OrderId: uuid.New(), OutSize: decimal.NewFromInt(1), InSize: decimal.NewFromInt(2)}}
+
+	t.Run("should store an open swap", func(t *testing.T) {
+		err := repo.StoreSwap(ctx, swapId, []models.RouteLeg{{Symbol: symbol, MakerSide: models.BUY}}, frags)
+		assert.NoError(t, err)
+
+		swap, err := repo.GetSwap(ctx, swapId, true)
+		assert.NoError(t, err)
+		assert.Len(t, swap.Frags, 1)
+
+		swaps, err := repo.GetOpenSwaps(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, swaps, 1)
+		assert.Equal(t, swapId, swaps[0].Id)
+	})
+
+	t.Run("should start a pending swap once", func(t *testing.T) {
+		swap, err := repo.StoreNewPendingSwap(ctx, models.SwapTx{SwapId: swapId, TxHash: "0x1"})
+		assert.NoError(t, err)
+		assert.True(t, swap.IsStarted())
+
+		_, err = repo.StoreNewPendingSwap(ctx, models.SwapTx{SwapId: swapId, TxHash: "0x1"})
+		assert.Error(t, err)
+
+		_, err = repo.StoreNewPendingSwap(ctx, models.SwapTx{SwapId: uuid.New(), TxHash: "0x2"})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should move a resolved swap out of the open swaps", func(t *testing.T) {
+		swap, err := repo.GetSwap(ctx, swapId, true)
+		require.NoError(t, err)
+		swap.Id = swapId
+		swap.Succeeded = true
+
+		err = repo.ResolveSwap(ctx, *swap)
+		assert.NoError(t, err)
+
+		_, err = repo.GetSwap(ctx, swapId, true)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		resolved, err := repo.GetSwap(ctx, swapId, false)
+		assert.NoError(t, err)
+		assert.True(t, resolved.Succeeded)
+		swaps, err := repo.GetOpenSwaps(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, swaps)
+	})
+
+	t.Run("should store a user's resolved swap once", func(t *testing.T) {
+		swap := models.Swap{Id: swapId}
+		assert.NoError(t, repo.StoreUserResolvedSwap(ctx, userId, swap))
+		assert.ErrorIs(t, repo.StoreUserResolvedSwap(ctx, userId, swap), models.ErrValAlreadyInSet)
+
+		ids, err := repo.GetUserResolvedSwapIds(ctx, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{swapId.String()}, ids)
+	})
+}
+
+func TestMemoryRepository_FirmQuotes(t *testing.T) {
+	repo := newTestRepo(t)
+	quote := models.FirmQuote{Id: uuid.New(), InAmount: decimal.NewFromInt(1)}
+	require.NoError(t, repo.StoreFirmQuote(ctx, quote))
+
+	stored, err := repo.GetFirmQuote(ctx, quote.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", stored.InAmount.String())
+
+	taken, err := repo.TakeFirmQuote(ctx, quote.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, quote.Id, taken.Id)
+
+	_, err = repo.TakeFirmQuote(ctx, quote.Id)
+	assert.ErrorIs(t, err, models.ErrNotFound)
+	quotes, err := repo.GetFirmQuotes(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, quotes)
+}
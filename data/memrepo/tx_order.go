@@ -0,0 +1,376 @@
+package memrepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// Generic Building blocks with no biz logic in a single TX
+
+// memoryTx buffers the changes of a transaction until it is committed, so a failed action leaves the store unchanged
+type memoryTx struct {
+	// applied in order, under the write lock, when the tx is committed
+	ops []func()
+	// version each order modified in the tx must still have when the tx is committed
+	versions map[uuid.UUID]int64
+	// published once the tx is committed
+	events []txEvent
+}
+
+type txEvent struct {
+	channel string
+	data    []byte
+}
+
+// Perform a transaction with a single action. This should be used for all interactions with the memory repository.
+// Handles the transaction lifecycle.
+// Changes made by the action with the Tx methods below (eg. TxModifyOrder, TxModifyPrices, etc.) are only applied if it succeeds.
+// Orders modified in the transaction are only committed at the version they were read, otherwise it fails with ErrVersionConflict.
+func (r *memoryRepository) PerformTx(ctx context.Context, action func(txid uint) error) error {
+	txid := r.txStart(ctx)
+	defer r.txDiscard(txid)
+
+	err := action(txid)
+	if err != nil {
+		logctx.Error(ctx, "PerformTx action failed", logger.Error(err), logger.Int("txid", int(txid)))
+		return fmt.Errorf("PerformTx action failed: %w", err)
+	}
+
+	err = r.txEnd(ctx, txid)
+	if err != nil {
+		logctx.Warn(ctx, "PerformTx txEnd commit failed", logger.Error(err), logger.Int("txid", int(txid)))
+		return fmt.Errorf("PerformTx txEnd commit failed: %w", err)
+	}
+
+	return nil
+}
+
+// This should be used for all write interactions with orders
+func (r *memoryRepository) TxModifyOrder(ctx context.Context, txid uint, operation models.Operation, order models.Order) error {
+	tx, ok := r.getTx(txid)
+	if !ok {
+		logctx.Error(ctx, "TxModifyOrder txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
+	}
+
+	switch operation {
+	case models.Add, models.Update:
+		// the order is updated from the version it was read at, to the next one
+		if operation == models.Update {
+			tx.expectVersion(order.Id, order.Version)
+			order.Version++
+		}
+		orderMap := order.OrderToMap()
+		tx.ops = append(tx.ops, func() {
+			r.setOrderFields(order.Id, orderMap)
+		})
+		logctx.Debug(ctx, "TxModifyOrder add/update", logger.String("orderId", order.Id.String()), logger.String("orderMap", fmt.Sprintf("%v", orderMap)))
+		// filled, locked and amended sizes change the order's price level
+		if operation == models.Update {
+			tx.publishBookChange(ctx, order)
+		}
+	case models.Remove:
+		tx.expectVersion(order.Id, order.Version)
+		tx.ops = append(tx.ops, func() {
+			delete(r.orders, order.Id)
+		})
+		logctx.Debug(ctx, "TxModifyOrder remove", logger.String("orderId", order.Id.String()))
+	default:
+		logctx.Error(ctx, "TxModifyOrder unsupported operation", logger.Int("operation", int(operation)))
+		return models.ErrUnsupportedOperation
+	}
+
+	return nil
+}
+
+// This should be used for all write interactions with the price ladders of each token pair
+func (r *memoryRepository) TxModifyPrices(ctx context.Context, txid uint, operation models.Operation, order models.Order) error {
+	tx, ok := r.getTx(txid)
+	if !ok {
+		logctx.Error(ctx, "TxModifyPrices txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
+	}
+
+	switch operation {
+	case models.Add:
+		score := priceScore(order)
+		tx.ops = append(tx.ops, func() {
+			r.priceLadder(order.Symbol, order.Side, true).add(order.Id.String(), score)
+		})
+		logctx.Debug(ctx, "TxModifyPrices add", logger.String("orderId", order.Id.String()), logger.String("symbol", order.Symbol.String()), logger.String("side", order.Side.String()))
+	case models.Remove:
+		tx.ops = append(tx.ops, func() {
+			r.priceLadder(order.Symbol, order.Side, false).remove(order.Id.String())
+		})
+		logctx.Debug(ctx, "TxModifyPrices remove", logger.String("orderId", order.Id.String()), logger.String("symbol", order.Symbol.String()), logger.String("side", order.Side.String()))
+	default:
+		logctx.Error(ctx, "TxModifyPrices unsupported operation", logger.Int("operation", int(operation)))
+		return models.ErrUnsupportedOperation
+	}
+
+	tx.publishBookChange(ctx, order)
+	return nil
+}
+
+// This should be used for all write interactions with the clientOId to order ID index
+func (r *memoryRepository) TxModifyClientOId(ctx context.Context, txid uint, operation models.Operation, order models.Order) error {
+	tx, ok := r.getTx(txid)
+	if !ok {
+		logctx.Error(ctx, "TxModifyClientOId txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
+	}
+
+	switch operation {
+	case models.Add:
+		tx.ops = append(tx.ops, func() {
+			r.clientOIds[order.ClientOId] = order.Id
+		})
+		logctx.Debug(ctx, "ModifyClientOId add", logger.String("clientOID", order.ClientOId.String()), logger.String("orderId", order.Id.String()))
+	case models.Remove:
+		tx.ops = append(tx.ops, func() {
+			delete(r.clientOIds, order.ClientOId)
+		})
+		logctx.Debug(ctx, "ModifyClientOId remove", logger.String("clientOID", order.ClientOId.String()), logger.String("orderId", order.Id.String()))
+	default:
+		logctx.Error(ctx, "ModifyClientOId unsupported operation", logger.Int("operation", int(operation)))
+		return models.ErrUnsupportedOperation
+	}
+	return nil
+}
+
+// This should be used for all write interactions with the open orders of each user
+func (r *memoryRepository) TxModifyUserOpenOrders(ctx context.Context, txid uint, operation models.Operation, order models.Order) error {
+	tx, ok := r.getTx(txid)
+	if !ok {
+		logctx.Error(ctx, "TxModifyUserOpenOrders txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
+	}
+
+	switch operation {
+	case models.Add:
+		score := float64(order.Timestamp.UTC().UnixNano())
+		tx.ops = append(tx.ops, func() {
+			r.openOrdersOf(order.UserId, true).add(order.Id.String(), score)
+		})
+		logctx.Debug(ctx, "ModifyUserOpenOrders add", logger.String("orderId", order.Id.String()), logger.String("userId", order.UserId.String()))
+	case models.Remove:
+		tx.ops = append(tx.ops, func() {
+			r.openOrdersOf(order.UserId, false).remove(order.Id.String())
+		})
+		logctx.Debug(ctx, "ModifyUserOpenOrders remove", logger.String("orderId", order.Id.String()), logger.String("userId", order.UserId.String()))
+	default:
+		logctx.Error(ctx, "ModifyUserOpenOrders unsupported operation", logger.Int("operation", int(operation)))
+		return models.ErrUnsupportedOperation
+	}
+	return nil
+}
+
+// This should be used for all write interactions with the order deadlines (used to expire orders by their signed deadline or time in force)
+// Orders which never expire are not indexed
+func (r *memoryRepository) TxModifyOrderDeadlines(ctx context.Context, txid uint, operation models.Operation, order models.Order) error {
+	tx, ok := r.getTx(txid)
+	if !ok {
+		logctx.Error(ctx, "TxModifyOrderDeadlines txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
+	}
+
+	deadline := order.ExpiryTime()
+	if deadline.IsZero() {
+		return nil
+	}
+
+	switch operation {
+	case models.Add:
+		tx.ops = append(tx.ops, func() {
+			r.deadlines.add(order.Id.String(), float64(deadline.Unix()))
+		})
+		logctx.Debug(ctx, "TxModifyOrderDeadlines add", logger.String("orderId", order.Id.String()), logger.String("deadline", deadline.String()))
+	case models.Remove:
+		tx.ops = append(tx.ops, func() {
+			r.deadlines.remove(order.Id.String())
+		})
+		logctx.Debug(ctx, "TxModifyOrderDeadlines remove", logger.String("orderId", order.Id.String()))
+	default:
+		logctx.Error(ctx, "TxModifyOrderDeadlines unsupported operation", logger.Int("operation", int(operation)))
+		return models.ErrUnsupportedOperation
+	}
+	return nil
+}
+
+func (r *memoryRepository) TxRemoveOrder(ctx context.Context, txid uint, order models.Order) error {
+	// remove from client OID
+	if err := r.TxModifyClientOId(ctx, txid, models.Remove, order); err != nil {
+		logctx.Error(ctx, "Failed removing order from ClientOID", logger.String("id", order.Id.String()), logger.String("userId", order.UserId.String()), logger.Error(err))
+		return fmt.Errorf("failed removing order from user open orders: %w", err)
+	}
+	// remove from user's open orders
+	if err := r.TxModifyUserOpenOrders(ctx, txid, models.Remove, order); err != nil {
+		logctx.Error(ctx, "Failed removing order from user open orders", logger.String("id", order.Id.String()), logger.String("userId", order.UserId.String()), logger.Error(err))
+		return fmt.Errorf("failed removing order from user open orders: %w", err)
+	}
+	// remove from deadlines
+	if err := r.TxModifyOrderDeadlines(ctx, txid, models.Remove, order); err != nil {
+		logctx.Error(ctx, "Failed removing order from deadlines", logger.String("id", order.Id.String()), logger.Error(err))
+		return fmt.Errorf("failed removing order from deadlines: %w", err)
+	}
+	// remove entirely
+	if err := r.TxModifyOrder(ctx, txid, models.Remove, order); err != nil {
+		logctx.Error(ctx, "Failed remove cancelled order", logger.Error(err), logger.String("orderId", order.Id.String()))
+		return fmt.Errorf("failed remove cancelled order: %w", err)
+	}
+	return nil
+}
+
+// close order
+// 1. remove from price (if not cancelled - as cancelled should have removed it)
+// 2. remove from the user's open orders
+func (r *memoryRepository) TxCloseOrder(ctx context.Context, txid uint, order models.Order) error {
+	// confirm not pending
+	if !order.IsPending() {
+		logctx.Error(ctx, "TxCloseOrder Unexpected, try to close a still pending order", logger.Int("txid", int(txid)), logger.String("orderId", order.Id.String()))
+	}
+	// remove from price if not cancelled already
+	if !order.Cancelled {
+		err := r.TxModifyPrices(ctx, txid, models.Remove, order)
+		if err != nil {
+			return err
+		}
+	}
+	// remove from user's open orders
+	if err := r.TxModifyUserOpenOrders(ctx, txid, models.Remove, order); err != nil {
+		logctx.Error(ctx, "TxCloseOrder Failed removing order from user open orders", logger.String("id", order.Id.String()), logger.String("userId", order.UserId.String()), logger.Error(err))
+		return err
+	}
+	// remove from deadlines
+	if err := r.TxModifyOrderDeadlines(ctx, txid, models.Remove, order); err != nil {
+		logctx.Error(ctx, "TxCloseOrder Failed removing order from deadlines", logger.String("id", order.Id.String()), logger.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Create a new transaction and return the transaction ID
+func (r *memoryRepository) txStart(ctx context.Context) uint {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ixIndex += 1
+	txid := r.ixIndex
+	r.txMap[txid] = &memoryTx{versions: make(map[uuid.UUID]int64)}
+
+	logctx.Debug(ctx, "memoryRepository txStart", logger.Int("txid", int(txid)))
+	return txid
+}
+
+// Commit a given transaction, all of its changes or none if one of its orders is no longer at its expected version
+func (r *memoryRepository) txEnd(ctx context.Context, txid uint) error {
+	tx, ok := r.getTx(txid)
+	if !ok {
+		logctx.Error(ctx, "txEnd txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
+	}
+
+	r.mu.Lock()
+	for id, expected := range tx.versions {
+		orderMap, ok := r.orders[id]
+		// removed since it was read
+		if !ok {
+			r.mu.Unlock()
+			logctx.Warn(ctx, "order was removed since it was read", logger.String("orderId", id.String()))
+			return models.ErrVersionConflict
+		}
+		version, err := orderVersion(orderMap)
+		if err != nil {
+			r.mu.Unlock()
+			return fmt.Errorf("invalid version of order %s: %w", id, err)
+		}
+		if version != expected {
+			r.mu.Unlock()
+			logctx.Warn(ctx, "order was changed since it was read", logger.String("orderId", id.String()), logger.Int("version", int(version)), logger.Int("expectedVersion", int(expected)))
+			return models.ErrVersionConflict
+		}
+	}
+	for _, op := range tx.ops {
+		op()
+	}
+	r.mu.Unlock()
+
+	for _, event := range tx.events {
+		r.publish(ctx, event.channel, event.data)
+	}
+	return nil
+}
+
+// Drop a given transaction, whether it was committed or not
+func (r *memoryRepository) txDiscard(txid uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.txMap, txid)
+}
+
+func (r *memoryRepository) getTx(txid uint) (*memoryTx, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tx, ok := r.txMap[txid]
+	return tx, ok
+}
+
+// expectVersion sets the version the order must have when the tx is committed, the version it was first read at
+func (tx *memoryTx) expectVersion(orderId uuid.UUID, version int64) {
+	if _, ok := tx.versions[orderId]; !ok {
+		tx.versions[orderId] = version
+	}
+}
+
+// publishBookChange notifies market data subscribers, once the tx is committed, that a side of the order's book may have changed
+func (tx *memoryTx) publishBookChange(ctx context.Context, order models.Order) {
+	if order.Symbol == "" {
+		return
+	}
+
+	event, err := json.Marshal(models.MarketEvent{Event: models.MARKET_EVENT_BOOK_CHANGE, Symbol: order.Symbol, Side: order.Side})
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal book change event", logger.Error(err), logger.String("orderId", order.Id.String()))
+		return
+	}
+
+	tx.events = append(tx.events, txEvent{channel: models.CreateMarketEventKey(order.Symbol), data: event})
+}
+
+// setOrderFields sets the fields of the order, keeping any other fields it has, must be called with the write lock held
+func (r *memoryRepository) setOrderFields(orderId uuid.UUID, fields map[string]string) {
+	orderMap, ok := r.orders[orderId]
+	if !ok {
+		orderMap = make(map[string]string, len(fields))
+		r.orders[orderId] = orderMap
+	}
+	for field, value := range fields {
+		orderMap[field] = value
+	}
+}
+
+// orders stored before versions were introduced are at version 0
+func orderVersion(orderMap map[string]string) (int64, error) {
+	versionStr, ok := orderMap["version"]
+	if !ok || versionStr == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(versionStr, 10, 64)
+}
+
+// scores add the scaled order timestamp to the price, which stays well below this
+const priceScoreWindow = 0.01
+
+// priceScore sorts orders by price, and orders with the same price by time. It should not be used for price comparison.
+func priceScore(order models.Order) float64 {
+	f64Price, _ := order.Price.Float64()
+	timestamp := float64(order.Timestamp.UTC().UnixNano()) / 1e9
+	return f64Price + (timestamp / 1e12)
+}
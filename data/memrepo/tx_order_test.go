@@ -0,0 +1,164 @@
+package memrepo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ctx = context.Background()
+var userId = uuid.MustParse("00000000-0000-0000-0000-000000000003")
+var symbol, _ = models.StrToSymbol("MATIC-USDC")
+
+func newTestRepo(t *testing.T) *memoryRepository {
+	repo, err := NewMemoryRepository()
+	require.NoError(t, err)
+	return repo
+}
+
+func newTestOrder(side models.Side, price string, size string, age time.Duration) models.Order {
+	return models.Order{
+		Id:        uuid.New(),
+		ClientOId: uuid.New(),
+		UserId:    userId,
+		Price:     decimal.RequireFromString(price),
+		Size:      decimal.RequireFromString(size),
+		Symbol:    symbol,
+		Side:      side,
+		Timestamp: time.Now().Add(-age).Truncate(time.Second),
+	}
+}
+
+func TestMemoryRepository_PerformTx(t *testing.T) {
+
+	t.Run("should apply the changes of a successful tx", func(t *testing.T) {
+		repo := newTestRepo(t)
+		order := newTestOrder(models.BUY, "1", "10", 0)
+
+		err := repo.StoreOpenOrder(ctx, order)
+		assert.NoError(t, err)
+
+		stored, err := repo.FindOrderById(ctx, order.Id, false)
+		assert.NoError(t, err)
+		assert.Equal(t, order.Id, stored.Id)
+		byClientOId, err := repo.FindOrderById(ctx, order.ClientOId, true)
+		assert.NoError(t, err)
+		assert.Equal(t, order.Id, byClientOId.Id)
+		assert.Equal(t, 1, repo.openOrdersOf(userId, false).len())
+		assert.Equal(t, 1, repo.priceLadder(symbol, models.BUY, false).len())
+	})
+
+	t.Run("should roll back all the changes of a failed action", func(t *testing.T) {
+		repo := newTestRepo(t)
+		order := newTestOrder(models.BUY, "1", "10", 0)
+
+		err := repo.PerformTx(ctx, func(txid uint) error {
+			if err := repo.TxStoreOpenOrder(ctx, txid, order); err != nil {
+				return err
+			}
+			return assert.AnError
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+
+		_, err = repo.FindOrderById(ctx, order.Id, false)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = repo.FindOrderById(ctx, order.ClientOId, true)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.Equal(t, 0, repo.openOrdersOf(userId, false).len())
+		assert.Equal(t, 0, repo.priceLadder(symbol, models.BUY, false).len())
+		assert.Empty(t, repo.txMap)
+	})
+
+	t.Run("should return ErrNotFound for an unknown txid", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		err := repo.TxModifyOrder(ctx, 42, models.Add, newTestOrder(models.BUY, "1", "10", 0))
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should increment the version of an updated order", func(t *testing.T) {
+		repo := newTestRepo(t)
+		order := newTestOrder(models.BUY, "1", "10", 0)
+		require.NoError(t, repo.StoreOpenOrder(ctx, order))
+
+		err := repo.PerformTx(ctx, func(txid uint) error {
+			return repo.TxModifyOrder(ctx, txid, models.Update, order)
+		})
+		assert.NoError(t, err)
+
+		stored, err := repo.FindOrderById(ctx, order.Id, false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), stored.Version)
+	})
+
+	t.Run("should fail with ErrVersionConflict and apply nothing when an order changed since it was read", func(t *testing.T) {
+		repo := newTestRepo(t)
+		order := newTestOrder(models.BUY, "1", "10", 0)
+		other := newTestOrder(models.BUY, "2", "10", 0)
+		require.NoError(t, repo.StoreOpenOrders(ctx, []models.Order{order, other}))
+
+		// changed by someone else after it was read
+		require.NoError(t, repo.PerformTx(ctx, func(txid uint) error {
+			return repo.TxModifyOrder(ctx, txid, models.Update, order)
+		}))
+
+		err := repo.PerformTx(ctx, func(txid uint) error {
+			other.Cancelled = true
+			if err := repo.TxModifyOrder(ctx, txid, models.Update, other); err != nil {
+				return err
+			}
+			return repo.TxModifyOrder(ctx, txid, models.Update, order)
+		})
+		assert.ErrorIs(t, err, models.ErrVersionConflict)
+
+		stored, err := repo.FindOrderById(ctx, other.Id, false)
+		assert.NoError(t, err)
+		assert.False(t, stored.Cancelled)
+		assert.Equal(t, int64(0), stored.Version)
+	})
+
+	t.Run("should fail with ErrVersionConflict when an order was removed since it was read", func(t *testing.T) {
+		repo := newTestRepo(t)
+		order := newTestOrder(models.BUY, "1", "10", 0)
+		require.NoError(t, repo.StoreOpenOrder(ctx, order))
+		require.NoError(t, repo.PerformTx(ctx, func(txid uint) error {
+			return repo.TxRemoveOrder(ctx, txid, order)
+		}))
+
+		err := repo.PerformTx(ctx, func(txid uint) error {
+			return repo.TxModifyOrder(ctx, txid, models.Update, order)
+		})
+		assert.ErrorIs(t, err, models.ErrVersionConflict)
+		_, err = repo.FindOrderById(ctx, order.Id, false)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should close a filled order", func(t *testing.T) {
+		repo := newTestRepo(t)
+		order := newTestOrder(models.SELL, "1", "10", 0)
+		order.ExpiresAt = time.Now().Add(time.Hour)
+		require.NoError(t, repo.StoreOpenOrder(ctx, order))
+
+		order.SizeFilled = order.Size
+		err := repo.PerformTx(ctx, func(txid uint) error {
+			if err := repo.TxModifyOrder(ctx, txid, models.Update, order); err != nil {
+				return err
+			}
+			return repo.TxCloseOrder(ctx, txid, order)
+		})
+		assert.NoError(t, err)
+
+		stored, err := repo.FindOrderById(ctx, order.Id, false)
+		assert.NoError(t, err)
+		assert.True(t, stored.IsFilled())
+		assert.Equal(t, 0, repo.openOrdersOf(userId, false).len())
+		assert.Equal(t, 0, repo.priceLadder(symbol, models.SELL, false).len())
+		assert.Equal(t, 0, repo.deadlines.len())
+	})
+}
@@ -0,0 +1,97 @@
+package memrepo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/data/redisrepo"
+	"github.com/orbs-network/order-book/models"
+)
+
+// AppendUserEvent appends the event to the user's events and returns its seq
+func (r *memoryRepository) AppendUserEvent(ctx context.Context, userId uuid.UUID, event []byte) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.userEventSeqs[userId]++
+	seq := r.userEventSeqs[userId]
+
+	events := append(r.userEvents[userId], models.UserEvent{Seq: seq, Data: append([]byte{}, event...)})
+	if maxLen := getUserEventsMaxLen(); len(events) > maxLen {
+		events = append([]models.UserEvent{}, events[len(events)-maxLen:]...)
+	}
+	r.userEvents[userId] = events
+
+	// wake up the readers waiting for it
+	if appended, ok := r.userEventsAppended[userId]; ok {
+		close(appended)
+		delete(r.userEventsAppended, userId)
+	}
+	return seq, nil
+}
+
+// GetUserEventSeq returns the seq of the user's last event, 0 if there are none
+func (r *memoryRepository) GetUserEventSeq(ctx context.Context, userId uuid.UUID) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.userEventSeqs[userId], nil
+}
+
+// ReadUserEvents returns up to count of the user's events after afterSeq, oldest first
+// if there are none it waits up to block for new ones, a block of 0 returns at once
+// events trimmed from the log are skipped, so the first seq returned may be higher than afterSeq+1
+func (r *memoryRepository) ReadUserEvents(ctx context.Context, userId uuid.UUID, afterSeq, count int64, block time.Duration) ([]models.UserEvent, error) {
+	timeout := time.NewTimer(block)
+	defer timeout.Stop()
+
+	for {
+		r.mu.Lock()
+		events := r.userEventsAfter(userId, afterSeq, count)
+		if len(events) > 0 || block <= 0 {
+			r.mu.Unlock()
+			return events, nil
+		}
+		appended, ok := r.userEventsAppended[userId]
+		if !ok {
+			appended = make(chan struct{})
+			r.userEventsAppended[userId] = appended
+		}
+		r.mu.Unlock()
+
+		select {
+		case <-appended:
+		case <-timeout.C:
+			return []models.UserEvent{}, nil
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to read user events: %w", ctx.Err())
+		}
+	}
+}
+
+// userEventsAfter must be called with the lock held
+func (r *memoryRepository) userEventsAfter(userId uuid.UUID, afterSeq, count int64) []models.UserEvent {
+	res := []models.UserEvent{}
+	for _, event := range r.userEvents[userId] {
+		if event.Seq <= afterSeq {
+			continue
+		}
+		if count > 0 && int64(len(res)) >= count {
+			break
+		}
+		res = append(res, models.UserEvent{Seq: event.Seq, Data: append([]byte{}, event.Data...)})
+	}
+	return res
+}
+
+// getUserEventsMaxLen returns the configurable number of events kept per user, shared with the Redis store
+func getUserEventsMaxLen() int {
+	maxLen, err := strconv.Atoi(redisrepo.USER_EVENTS_MAX_LEN)
+	if err != nil || maxLen <= 0 {
+		return 10000
+	}
+	return maxLen
+}
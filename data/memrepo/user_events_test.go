@@ -0,0 +1,67 @@
+package memrepo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRepository_UserEvents(t *testing.T) {
+
+	t.Run("should sequence the events of a user", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		seq, err := repo.GetUserEventSeq(ctx, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), seq)
+
+		for i := 1; i <= 3; i++ {
+			seq, err := repo.AppendUserEvent(ctx, userId, []byte("event"))
+			assert.NoError(t, err)
+			assert.Equal(t, int64(i), seq)
+		}
+
+		events, err := repo.ReadUserEvents(ctx, userId, 1, 10, 0)
+		assert.NoError(t, err)
+		assert.Len(t, events, 2)
+		assert.Equal(t, int64(2), events[0].Seq)
+
+		events, err = repo.ReadUserEvents(ctx, userId, 0, 1, 0)
+		assert.NoError(t, err)
+		assert.Len(t, events, 1)
+	})
+
+	t.Run("should wait for a new event", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			_, err := repo.AppendUserEvent(ctx, userId, []byte("event"))
+			assert.NoError(t, err)
+		}()
+
+		events, err := repo.ReadUserEvents(ctx, userId, 0, 10, time.Second)
+		assert.NoError(t, err)
+		assert.Len(t, events, 1)
+		assert.Equal(t, "event", string(events[0].Data))
+	})
+
+	t.Run("should return no events when the block times out", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		events, err := repo.ReadUserEvents(ctx, userId, 0, 10, 10*time.Millisecond)
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("should stop waiting when the context is done", func(t *testing.T) {
+		repo := newTestRepo(t)
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := repo.ReadUserEvents(cancelledCtx, userId, 0, 10, time.Second)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,85 @@
+package memrepo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/data/storeuser"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// CreateUser stores the user, indexed by ID and by API key
+func (r *memoryRepository) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, idExists := r.usersById[user.Id]
+	_, apiKeyExists := r.usersByApiKey[user.ApiKey]
+	if idExists || apiKeyExists {
+		logctx.Warn(ctx, "user already exists", logger.String("userId", user.Id.String()))
+		return models.User{}, models.ErrUserAlreadyExists
+	}
+
+	r.usersById[user.Id] = user
+	r.usersByApiKey[user.ApiKey] = user.Id
+
+	logctx.Info(ctx, "user created", logger.String("userId", user.Id.String()))
+	return user, nil
+}
+
+// GetUserByApiKey returns a user by their apiKey
+func (r *memoryRepository) GetUserByApiKey(ctx context.Context, apiKey string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	userId, ok := r.usersByApiKey[apiKey]
+	if !ok {
+		logctx.Warn(ctx, "user not found by api key")
+		return nil, models.ErrNotFound
+	}
+	user := r.usersById[userId]
+	return &user, nil
+}
+
+// GetUserById returns a user by their userId
+func (r *memoryRepository) GetUserById(ctx context.Context, userId uuid.UUID) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.usersById[userId]
+	if !ok {
+		logctx.Error(ctx, "user not found by ID", logger.String("userId", userId.String()))
+		return nil, models.ErrNotFound
+	}
+	return &user, nil
+}
+
+// UpdateUser updates a user's pubKey and apiKey, the old apiKey no longer finds the user
+//
+// Ensure that `PubKey` and `ApiKey` are correct and not empty
+func (r *memoryRepository) UpdateUser(ctx context.Context, input storeuser.UpdateUserInput) error {
+	if input.ApiKey == "" || input.PubKey == "" {
+		logctx.Error(ctx, "apiKey or pubKey is empty", logger.String("apiKey", input.ApiKey), logger.String("pubKey", input.PubKey))
+		return models.ErrInvalidInput
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.usersById[input.UserId]
+	if !ok {
+		logctx.Error(ctx, "user not found by ID", logger.String("userId", input.UserId.String()))
+		return models.ErrNotFound
+	}
+
+	delete(r.usersByApiKey, user.ApiKey)
+	user.PubKey = input.PubKey
+	user.ApiKey = input.ApiKey
+	r.usersById[user.Id] = user
+	r.usersByApiKey[user.ApiKey] = user.Id
+
+	logctx.Info(ctx, "user updated", logger.String("userId", input.UserId.String()), logger.String("pubKey", input.PubKey))
+	return nil
+}
@@ -0,0 +1,79 @@
+package memrepo
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/data/storeuser"
+	"github.com/orbs-network/order-book/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_Users(t *testing.T) {
+	repo := newTestRepo(t)
+	user := models.User{Id: uuid.New(), Type: models.MARKET_MAKER, PubKey: "pub", ApiKey: "key"}
+
+	t.Run("should create a user once", func(t *testing.T) {
+		created, err := repo.CreateUser(ctx, user)
+		assert.NoError(t, err)
+		assert.Equal(t, user, created)
+
+		_, err = repo.CreateUser(ctx, user)
+		assert.ErrorIs(t, err, models.ErrUserAlreadyExists)
+	})
+
+	t.Run("should find a user by id and api key", func(t *testing.T) {
+		byId, err := repo.GetUserById(ctx, user.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, user, *byId)
+
+		byApiKey, err := repo.GetUserByApiKey(ctx, user.ApiKey)
+		assert.NoError(t, err)
+		assert.Equal(t, user, *byApiKey)
+	})
+
+	t.Run("should replace the api key of an updated user", func(t *testing.T) {
+		err := repo.UpdateUser(ctx, storeuser.UpdateUserInput{UserId: user.Id, PubKey: "new-pub", ApiKey: "new-key"})
+		assert.NoError(t, err)
+
+		_, err = repo.GetUserByApiKey(ctx, "key")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		updated, err := repo.GetUserByApiKey(ctx, "new-key")
+		assert.NoError(t, err)
+		assert.Equal(t, "new-pub", updated.PubKey)
+	})
+
+	t.Run("should return error for an empty api key", func(t *testing.T) {
+		err := repo.UpdateUser(ctx, storeuser.UpdateUserInput{UserId: user.Id, PubKey: "pub"})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+}
+
+func TestMemoryRepository_StrKeys(t *testing.T) {
+	repo := newTestRepo(t)
+	order := newTestOrder(models.SELL, "1", "1", 0)
+	require.NoError(t, repo.StoreOpenOrder(ctx, order))
+
+	t.Run("should track the maker balance of a stored order", func(t *testing.T) {
+		keys, err := repo.EnumSubKeysOf(ctx, "balance")
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+
+		token := order.Signature.AbiFragment.Input.Token.String()
+		wallet := order.Signature.AbiFragment.Info.Swapper.String()
+		balance, err := repo.GetMakerTokenBalance(ctx, token, wallet)
+		assert.NoError(t, err)
+		assert.Equal(t, "-1", balance.String())
+
+		require.NoError(t, repo.WriteStrKey(ctx, keys[0], "12.5"))
+		balance, err = repo.GetMakerTokenBalance(ctx, token, wallet)
+		assert.NoError(t, err)
+		assert.Equal(t, "12.5", balance.String())
+	})
+
+	t.Run("should return ErrNotFound for a missing key", func(t *testing.T) {
+		_, err := repo.ReadStrKey(ctx, "missing")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+}
@@ -0,0 +1,187 @@
+package memrepo
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// delivery attempts kept per webhook
+const webhookDeliveriesMaxLen = 1000
+
+// StoreWebhook adds a new webhook of its user
+func (r *memoryRepository) StoreWebhook(ctx context.Context, webhook models.Webhook) error {
+	data, err := json.Marshal(webhook)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal webhook", logger.String("webhookId", webhook.Id.String()), logger.Error(err))
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.webhooks[webhook.Id] = data
+	webhookIds, ok := r.userWebhooks[webhook.UserId]
+	if !ok {
+		webhookIds = make(map[uuid.UUID]struct{})
+		r.userWebhooks[webhook.UserId] = webhookIds
+	}
+	webhookIds[webhook.Id] = struct{}{}
+	return nil
+}
+
+// UpdateWebhook updates an existing webhook, returns ErrNotFound if it was removed
+func (r *memoryRepository) UpdateWebhook(ctx context.Context, webhook models.Webhook) error {
+	data, err := json.Marshal(webhook)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal webhook", logger.String("webhookId", webhook.Id.String()), logger.Error(err))
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.webhooks[webhook.Id]; !ok {
+		return models.ErrNotFound
+	}
+	r.webhooks[webhook.Id] = data
+	return nil
+}
+
+// RemoveWebhook removes the webhook and its delivery log
+func (r *memoryRepository) RemoveWebhook(ctx context.Context, webhook models.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.webhooks, webhook.Id)
+	delete(r.userWebhooks[webhook.UserId], webhook.Id)
+	delete(r.webhookDeliveries, webhook.Id)
+	return nil
+}
+
+// GetWebhook returns the webhook, or ErrNotFound
+func (r *memoryRepository) GetWebhook(ctx context.Context, webhookId uuid.UUID) (*models.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	data, ok := r.webhooks[webhookId]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+
+	var webhook models.Webhook
+	if err := json.Unmarshal(data, &webhook); err != nil {
+		logctx.Error(ctx, "failed to unmarshal webhook", logger.String("webhookId", webhookId.String()), logger.Error(err))
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// GetUserWebhooks returns the webhooks of the user, oldest first
+func (r *memoryRepository) GetUserWebhooks(ctx context.Context, userId uuid.UUID) ([]models.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	values := make([][]byte, 0, len(r.userWebhooks[userId]))
+	for webhookId := range r.userWebhooks[userId] {
+		if data, ok := r.webhooks[webhookId]; ok {
+			values = append(values, data)
+		}
+	}
+	return unmarshalWebhooks(ctx, values), nil
+}
+
+// GetWebhooks returns the webhooks of all users, oldest first
+func (r *memoryRepository) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	values := make([][]byte, 0, len(r.webhooks))
+	for _, data := range r.webhooks {
+		values = append(values, data)
+	}
+	return unmarshalWebhooks(ctx, values), nil
+}
+
+// unmarshalWebhooks skips invalid webhooks
+func unmarshalWebhooks(ctx context.Context, values [][]byte) []models.Webhook {
+	webhooks := []models.Webhook{}
+	for _, data := range values {
+		var webhook models.Webhook
+		if err := json.Unmarshal(data, &webhook); err != nil {
+			logctx.Error(ctx, "failed to unmarshal webhook", logger.Error(err))
+			continue
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].Created.Before(webhooks[j].Created) })
+	return webhooks
+}
+
+// AcquireWebhookLease returns true if the owner now holds the webhook's lease for ttl
+func (r *memoryRepository) AcquireWebhookLease(ctx context.Context, webhookId uuid.UUID, owner string, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if lease, ok := r.webhookLeases[webhookId]; ok && time.Now().Before(lease.expiresAt) {
+		return false, nil
+	}
+	r.webhookLeases[webhookId] = webhookLease{owner: owner, expiresAt: time.Now().Add(ttl)}
+	return true, nil
+}
+
+// ReleaseWebhookLease releases the webhook's lease if it is still held by the owner
+func (r *memoryRepository) ReleaseWebhookLease(ctx context.Context, webhookId uuid.UUID, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if lease, ok := r.webhookLeases[webhookId]; ok && lease.owner == owner {
+		delete(r.webhookLeases, webhookId)
+	}
+	return nil
+}
+
+// StoreWebhookDelivery appends the delivery attempt to the webhook's delivery log, which keeps the latest attempts only
+func (r *memoryRepository) StoreWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal webhook delivery", logger.String("webhookId", delivery.WebhookId.String()), logger.Error(err))
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries, ok := r.webhookDeliveries[delivery.WebhookId]
+	if !ok {
+		deliveries = &stream{}
+		r.webhookDeliveries[delivery.WebhookId] = deliveries
+	}
+	deliveries.add(data)
+	deliveries.trim(webhookDeliveriesMaxLen)
+	return nil
+}
+
+// GetWebhookDeliveries returns the latest delivery attempts of the webhook, newest first
+func (r *memoryRepository) GetWebhookDeliveries(ctx context.Context, webhookId uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := []models.WebhookDelivery{}
+	r.webhookDeliveries[webhookId].reverse(func(entry streamEntry) bool {
+		var delivery models.WebhookDelivery
+		if err := json.Unmarshal(entry.data, &delivery); err != nil {
+			logctx.Error(ctx, "failed to unmarshal webhook delivery", logger.String("webhookId", webhookId.String()), logger.Error(err))
+			return true
+		}
+		deliveries = append(deliveries, delivery)
+		return limit <= 0 || len(deliveries) < limit
+	})
+	return deliveries, nil
+}
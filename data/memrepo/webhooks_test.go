@@ -0,0 +1,86 @@
+package memrepo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_Webhooks(t *testing.T) {
+	repo := newTestRepo(t)
+	webhook := models.Webhook{Id: uuid.New(), UserId: userId, Url: "https://example.com", Created: time.Now()}
+	require.NoError(t, repo.StoreWebhook(ctx, webhook))
+
+	t.Run("should get the webhooks of a user", func(t *testing.T) {
+		webhooks, err := repo.GetUserWebhooks(ctx, userId)
+		assert.NoError(t, err)
+		assert.Len(t, webhooks, 1)
+
+		webhooks, err = repo.GetUserWebhooks(ctx, uuid.New())
+		assert.NoError(t, err)
+		assert.Empty(t, webhooks)
+	})
+
+	t.Run("should update an existing webhook", func(t *testing.T) {
+		webhook.LastSeq = 3
+		assert.NoError(t, repo.UpdateWebhook(ctx, webhook))
+
+		stored, err := repo.GetWebhook(ctx, webhook.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), stored.LastSeq)
+	})
+
+	t.Run("should keep the latest deliveries newest first", func(t *testing.T) {
+		for seq := int64(1); seq <= 3; seq++ {
+			require.NoError(t, repo.StoreWebhookDelivery(ctx, models.WebhookDelivery{Id: uuid.New(), WebhookId: webhook.Id, Seq: seq}))
+		}
+
+		deliveries, err := repo.GetWebhookDeliveries(ctx, webhook.Id, 2)
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 2)
+		assert.Equal(t, int64(3), deliveries[0].Seq)
+	})
+
+	t.Run("should hold a lease for a single owner until released", func(t *testing.T) {
+		acquired, err := repo.AcquireWebhookLease(ctx, webhook.Id, "a", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, acquired)
+
+		acquired, err = repo.AcquireWebhookLease(ctx, webhook.Id, "b", time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, acquired)
+
+		assert.NoError(t, repo.ReleaseWebhookLease(ctx, webhook.Id, "b"))
+		acquired, _ = repo.AcquireWebhookLease(ctx, webhook.Id, "b", time.Minute)
+		assert.False(t, acquired)
+
+		assert.NoError(t, repo.ReleaseWebhookLease(ctx, webhook.Id, "a"))
+		acquired, _ = repo.AcquireWebhookLease(ctx, webhook.Id, "b", time.Minute)
+		assert.True(t, acquired)
+	})
+
+	t.Run("should let an expired lease be acquired", func(t *testing.T) {
+		id := uuid.New()
+		acquired, _ := repo.AcquireWebhookLease(ctx, id, "a", time.Millisecond)
+		assert.True(t, acquired)
+		time.Sleep(5 * time.Millisecond)
+
+		acquired, _ = repo.AcquireWebhookLease(ctx, id, "b", time.Minute)
+		assert.True(t, acquired)
+	})
+
+	t.Run("should not update a removed webhook", func(t *testing.T) {
+		assert.NoError(t, repo.RemoveWebhook(ctx, webhook))
+
+		assert.ErrorIs(t, repo.UpdateWebhook(ctx, webhook), models.ErrNotFound)
+		_, err := repo.GetWebhook(ctx, webhook.Id)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		deliveries, err := repo.GetWebhookDeliveries(ctx, webhook.Id, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, deliveries)
+	})
+}
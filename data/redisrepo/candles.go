@@ -0,0 +1,68 @@
+package redisrepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/redis/go-redis/v9"
+)
+
+// StoreCandles stores the candles, replacing any candle of the same symbol, interval and start
+func (r *redisRepository) StoreCandles(ctx context.Context, candles []models.Candle) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, candle := range candles {
+			data, err := json.Marshal(candle)
+			if err != nil {
+				logctx.Error(ctx, "failed to marshal candle", logger.String("symbol", candle.Symbol.String()), logger.String("interval", candle.Interval.String()), logger.Error(err))
+				return err
+			}
+
+			key := CreateCandlesKey(candle.Symbol, candle.Interval)
+			start := strconv.FormatInt(candle.Start.UnixMilli(), 10)
+			pipe.ZRemRangeByScore(ctx, key, start, start)
+			pipe.ZAdd(ctx, key, redis.Z{
+				Score:  float64(candle.Start.UnixMilli()),
+				Member: string(data),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		logctx.Error(ctx, "failed to store candles", logger.Error(err))
+		return fmt.Errorf("failed to store candles: %w", err)
+	}
+
+	return nil
+}
+
+// GetCandles returns the symbol's candles of an interval which start within [startAt, endAt), oldest first
+func (r *redisRepository) GetCandles(ctx context.Context, symbol models.Symbol, interval models.CandleInterval, startAt, endAt time.Time) ([]models.Candle, error) {
+	key := CreateCandlesKey(symbol, interval)
+
+	members, err := r.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatInt(startAt.UnixMilli(), 10),
+		Max: "(" + strconv.FormatInt(endAt.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		logctx.Error(ctx, "failed to get candles", logger.String("key", key), logger.Error(err))
+		return nil, fmt.Errorf("failed to get candles: %w", err)
+	}
+
+	candles := make([]models.Candle, 0, len(members))
+	for _, member := range members {
+		var candle models.Candle
+		if err := json.Unmarshal([]byte(member), &candle); err != nil {
+			logctx.Error(ctx, "failed to unmarshal candle", logger.String("key", key), logger.Error(err))
+			continue
+		}
+		candles = append(candles, candle)
+	}
+
+	return candles, nil
+}
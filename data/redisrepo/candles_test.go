@@ -0,0 +1,62 @@
+package redisrepo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/orbs-network/order-book/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisRepository_Candles(t *testing.T) {
+	ctx := context.Background()
+	symbol := models.Symbol("MATIC-USDC")
+	key := CreateCandlesKey(symbol, models.CANDLE_1M)
+
+	start := time.UnixMilli(60000).UTC()
+	candle := models.NewCandle(symbol, models.CANDLE_1M, start)
+	candle.AddTrade(models.Trade{Price: decimal.NewFromInt(2), Size: decimal.NewFromInt(3), Mined: start.Add(time.Second)})
+	data, _ := json.Marshal(candle)
+
+	t.Run("should replace candle of the same start", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectTxPipeline()
+		mock.ExpectZRemRangeByScore(key, "60000", "60000").SetVal(1)
+		mock.ExpectZAdd(key, redis.Z{Score: 60000, Member: string(data)}).SetVal(1)
+		mock.ExpectTxPipelineExec()
+
+		assert.NoError(t, repo.StoreCandles(ctx, []models.Candle{candle}))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return candles within range and skip invalid ones", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectZRangeByScore(key, &redis.ZRangeBy{Min: "0", Max: "(120000"}).SetVal([]string{string(data), "invalid"})
+
+		candles, err := repo.GetCandles(ctx, symbol, models.CANDLE_1M, time.UnixMilli(0), time.UnixMilli(120000))
+		assert.NoError(t, err)
+		assert.Len(t, candles, 1)
+		assert.True(t, candle.Close.Equal(candles[0].Close))
+		assert.True(t, candle.Volume.Equal(candles[0].Volume))
+		assert.Equal(t, candle.Start.UnixMilli(), candles[0].Start.UnixMilli())
+	})
+
+	t.Run("should return error on redis error", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectZRangeByScore(key, &redis.ZRangeBy{Min: "0", Max: "(120000"}).SetErr(assert.AnError)
+
+		_, err := repo.GetCandles(ctx, symbol, models.CANDLE_1M, time.UnixMilli(0), time.UnixMilli(120000))
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -0,0 +1,23 @@
+package redisrepo
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/orbs-network/order-book/data/store"
+	"github.com/orbs-network/order-book/data/store/storetest"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisRepository_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.OrderBookStore {
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { client.Close() })
+
+		repository, err := NewRedisRepository(client)
+		require.NoError(t, err)
+		return repository
+	})
+}
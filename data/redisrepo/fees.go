@@ -0,0 +1,67 @@
+package redisrepo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+)
+
+// AddFeeTotals adds the fees to the totals of their tokens
+func (r *redisRepository) AddFeeTotals(ctx context.Context, totals []models.FeeTotal) error {
+	if len(totals) == 0 {
+		return nil
+	}
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, total := range totals {
+			takerFees, _ := total.TakerFees.Float64()
+			makerRebates, _ := total.MakerRebates.Float64()
+			pipe.HIncrByFloat(ctx, CreateTakerFeesKey(), total.Token, takerFees)
+			pipe.HIncrByFloat(ctx, CreateMakerRebatesKey(), total.Token, makerRebates)
+		}
+		return nil
+	})
+	if err != nil {
+		logctx.Error(ctx, "failed to add fee totals", logger.Error(err))
+		return fmt.Errorf("failed to add fee totals: %w", err)
+	}
+	return nil
+}
+
+// GetFeeTotals returns the fee totals of all tokens, in token name order
+func (r *redisRepository) GetFeeTotals(ctx context.Context) ([]models.FeeTotal, error) {
+	takerFees, err := r.client.HGetAll(ctx, CreateTakerFeesKey()).Result()
+	if err != nil {
+		logctx.Error(ctx, "failed to get taker fees", logger.Error(err))
+		return nil, fmt.Errorf("failed to get taker fees: %w", err)
+	}
+	makerRebates, err := r.client.HGetAll(ctx, CreateMakerRebatesKey()).Result()
+	if err != nil {
+		logctx.Error(ctx, "failed to get maker rebates", logger.Error(err))
+		return nil, fmt.Errorf("failed to get maker rebates: %w", err)
+	}
+
+	totals := make([]models.FeeTotal, 0, len(takerFees))
+	for token, value := range takerFees {
+		total := models.FeeTotal{Token: token}
+		if total.TakerFees, err = decimal.NewFromString(value); err != nil {
+			logctx.Error(ctx, "invalid taker fees total", logger.String("token", token), logger.String("value", value))
+			continue
+		}
+		if rebates, ok := makerRebates[token]; ok {
+			if total.MakerRebates, err = decimal.NewFromString(rebates); err != nil {
+				logctx.Error(ctx, "invalid maker rebates total", logger.String("token", token), logger.String("value", rebates))
+				continue
+			}
+		}
+		totals = append(totals, total)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Token < totals[j].Token })
+	return totals, nil
+}
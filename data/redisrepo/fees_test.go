@@ -0,0 +1,55 @@
+package redisrepo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisRepository_FeeTotals(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should add the fees of each token", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectTxPipeline()
+		mock.ExpectHIncrByFloat(CreateTakerFeesKey(), "USDC", 1.5).SetVal(1.5)
+		mock.ExpectHIncrByFloat(CreateMakerRebatesKey(), "USDC", 0.5).SetVal(0.5)
+		mock.ExpectTxPipelineExec()
+
+		err := repo.AddFeeTotals(ctx, []models.FeeTotal{{Token: "USDC", TakerFees: decimal.NewFromFloat(1.5), MakerRebates: decimal.NewFromFloat(0.5)}})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return the totals in token order", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectHGetAll(CreateTakerFeesKey()).SetVal(map[string]string{"USDC": "1.5", "MATIC": "2"})
+		mock.ExpectHGetAll(CreateMakerRebatesKey()).SetVal(map[string]string{"USDC": "0.5"})
+
+		totals, err := repo.GetFeeTotals(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, totals, 2)
+		assert.Equal(t, "MATIC", totals[0].Token)
+		assert.True(t, totals[0].MakerRebates.IsZero())
+		assert.Equal(t, "USDC", totals[1].Token)
+		assert.Equal(t, "1", totals[1].ProtocolFees().String())
+	})
+
+	t.Run("should return error on redis error", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectHGetAll(CreateTakerFeesKey()).SetErr(assert.AnError)
+
+		_, err := repo.GetFeeTotals(ctx)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -0,0 +1,299 @@
+package redisrepo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/redis/go-redis/v9"
+)
+
+// number of stream entries read per round trip while filling a page
+const historyBatchSize = 100
+
+// StoreUserFill appends the fill to the user's fills stream and to the user's fills of its swap
+func (r *redisRepository) StoreUserFill(ctx context.Context, userId uuid.UUID, fill models.Fill) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		return storeUserFill(ctx, pipe, userId, fill)
+	})
+	if err != nil {
+		logctx.Error(ctx, "failed to store user fill", logger.Error(err), logger.String("userId", userId.String()))
+		return fmt.Errorf("failed to store user fill: %w", err)
+	}
+	return nil
+}
+
+// TxStoreUserFill appends the fill to the user's fills stream and to the user's fills of its swap once the tx is committed
+func (r *redisRepository) TxStoreUserFill(ctx context.Context, txid uint, userId uuid.UUID, fill models.Fill) error {
+	var tx redis.Pipeliner
+	var ok bool
+	if tx, ok = r.getTx(txid); !ok {
+		logctx.Error(ctx, "TxStoreUserFill txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
+	}
+	return storeUserFill(ctx, tx, userId, fill)
+}
+
+// storeUserFill queues the fill to the user's fills stream and to the user's fills of its swap
+func storeUserFill(ctx context.Context, pipe redis.Pipeliner, userId uuid.UUID, fill models.Fill) error {
+	if err := appendToStream(ctx, pipe, CreateUserFillsKey(userId), "fill", fill); err != nil {
+		return err
+	}
+	return indexSwapFill(ctx, pipe, userId, fill)
+}
+
+// indexSwapFill queues the fill to the user's fills of its swap, so they are found by swap ID rather than by time
+func indexSwapFill(ctx context.Context, pipe redis.Pipeliner, userId uuid.UUID, fill models.Fill) error {
+	data, err := json.Marshal(fill)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal fill", logger.String("userId", userId.String()), logger.Error(err))
+		return err
+	}
+	pipe.RPush(ctx, CreateUserSwapFillsKey(userId, fill.SwapId), string(data))
+	return nil
+}
+
+// GetUserFills returns a page of the user's fills, newest first, and the cursor of the next page
+func (r *redisRepository) GetUserFills(ctx context.Context, userId uuid.UUID, filter models.HistoryFilter) ([]models.Fill, string, error) {
+	fills := []models.Fill{}
+	cursor, err := r.readStreamPage(ctx, CreateUserFillsKey(userId), "fill", filter, func(raw string) bool {
+		var fill models.Fill
+		if err := json.Unmarshal([]byte(raw), &fill); err != nil {
+			logctx.Error(ctx, "failed to unmarshal fill", logger.String("userId", userId.String()), logger.Error(err))
+			return false
+		}
+		if !filter.Match(fill.Symbol, fill.Side) {
+			return false
+		}
+		fills = append(fills, fill)
+		return true
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return fills, cursor, nil
+}
+
+// GetUserSwapFills returns the user's fills of the swap, in the order they were stored
+func (r *redisRepository) GetUserSwapFills(ctx context.Context, userId uuid.UUID, swapId uuid.UUID) ([]models.Fill, error) {
+	raws, err := r.client.LRange(ctx, CreateUserSwapFillsKey(userId, swapId), 0, -1).Result()
+	if err != nil {
+		logctx.Error(ctx, "failed to get user swap fills", logger.Error(err), logger.String("userId", userId.String()), logger.String("swapId", swapId.String()))
+		return nil, fmt.Errorf("failed to get user swap fills: %w", err)
+	}
+
+	fills := make([]models.Fill, 0, len(raws))
+	for _, raw := range raws {
+		var fill models.Fill
+		if err := json.Unmarshal([]byte(raw), &fill); err != nil {
+			logctx.Error(ctx, "failed to unmarshal fill", logger.String("userId", userId.String()), logger.Error(err))
+			continue
+		}
+		fills = append(fills, fill)
+	}
+	return fills, nil
+}
+
+// BackfillUserFills stores the fills, sorted by their resolve time, with stream IDs of their resolve time
+// returns false without storing them if the user already has fills history, or gets some meanwhile
+func (r *redisRepository) BackfillUserFills(ctx context.Context, userId uuid.UUID, fills []models.Fill) (bool, error) {
+	key := CreateUserFillsKey(userId)
+	backfilled := false
+	err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+		// IDs of an existing stream, even an empty one, can only grow
+		exists, err := tx.Exists(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if exists > 0 || len(fills) == 0 {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			var ms, seq int64
+			for i, fill := range fills {
+				// fills resolved in the same ms get the next seq
+				if resolved := fill.Resolved.UnixMilli(); i == 0 || resolved > ms {
+					ms, seq = resolved, 0
+				} else {
+					seq++
+				}
+				data, err := json.Marshal(fill)
+				if err != nil {
+					return err
+				}
+				pipe.XAdd(ctx, &redis.XAddArgs{
+					Stream: key,
+					ID:     fmt.Sprintf("%d-%d", ms, seq),
+					Values: map[string]interface{}{"fill": string(data)},
+				})
+				if err := indexSwapFill(ctx, pipe, userId, fill); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		backfilled = err == nil
+		return err
+	}, key)
+	if errors.Is(err, redis.TxFailedErr) {
+		logctx.Warn(ctx, "user got fills while backfilling", logger.String("userId", userId.String()))
+		return false, nil
+	}
+	if err != nil {
+		logctx.Error(ctx, "failed to backfill user fills", logger.Error(err), logger.String("userId", userId.String()))
+		return false, fmt.Errorf("failed to backfill user fills: %w", err)
+	}
+	return backfilled, nil
+}
+
+// TrimUserFills drops the entries of the user's fills stream added before the time, and the user's fills of their swaps
+func (r *redisRepository) TrimUserFills(ctx context.Context, userId uuid.UUID, before time.Time) error {
+	key := CreateUserFillsKey(userId)
+	msgs, err := r.client.XRange(ctx, key, "-", strconv.FormatInt(before.UnixMilli()-1, 10)).Result()
+	if err != nil {
+		logctx.Error(ctx, "failed to read user fills to trim", logger.Error(err), logger.String("userId", userId.String()))
+		return fmt.Errorf("failed to read user fills to trim: %w", err)
+	}
+
+	swapIds := map[uuid.UUID]struct{}{}
+	for _, msg := range msgs {
+		raw, ok := msg.Values["fill"].(string)
+		if !ok {
+			continue
+		}
+		var fill models.Fill
+		if err := json.Unmarshal([]byte(raw), &fill); err != nil {
+			logctx.Error(ctx, "failed to unmarshal fill", logger.String("userId", userId.String()), logger.Error(err))
+			continue
+		}
+		swapIds[fill.SwapId] = struct{}{}
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for swapId := range swapIds {
+			pipe.Del(ctx, CreateUserSwapFillsKey(userId, swapId))
+		}
+		pipe.XTrimMinID(ctx, key, strconv.FormatInt(before.UnixMilli(), 10))
+		return nil
+	})
+	if err != nil {
+		logctx.Error(ctx, "failed to trim user fills", logger.Error(err), logger.String("userId", userId.String()))
+		return fmt.Errorf("failed to trim user fills: %w", err)
+	}
+	return nil
+}
+
+// StoreTrade appends the trade to its symbol's trades stream
+func (r *redisRepository) StoreTrade(ctx context.Context, trade models.Trade) error {
+	return appendToStream(ctx, r.client, CreateSymbolTradesKey(trade.Symbol), "trade", trade)
+}
+
+// TxStoreTrade appends the trade to its symbol's trades stream once the tx is committed
+func (r *redisRepository) TxStoreTrade(ctx context.Context, txid uint, trade models.Trade) error {
+	var tx redis.Pipeliner
+	var ok bool
+	if tx, ok = r.getTx(txid); !ok {
+		logctx.Error(ctx, "TxStoreTrade txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
+	}
+	return appendToStream(ctx, tx, CreateSymbolTradesKey(trade.Symbol), "trade", trade)
+}
+
+// GetTrades returns a page of the symbol's trades, newest first, and the cursor of the next page
+func (r *redisRepository) GetTrades(ctx context.Context, symbol models.Symbol, filter models.HistoryFilter) ([]models.Trade, string, error) {
+	trades := []models.Trade{}
+	cursor, err := r.readStreamPage(ctx, CreateSymbolTradesKey(symbol), "trade", filter, func(raw string) bool {
+		var trade models.Trade
+		if err := json.Unmarshal([]byte(raw), &trade); err != nil {
+			logctx.Error(ctx, "failed to unmarshal trade", logger.String("symbol", symbol.String()), logger.Error(err))
+			return false
+		}
+		if !filter.Match(trade.Symbol, trade.Side) {
+			return false
+		}
+		trades = append(trades, trade)
+		return true
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return trades, cursor, nil
+}
+
+// appendToStream adds the value to the stream, or queues it when client is a tx pipeline
+func appendToStream(ctx context.Context, client redis.Cmdable, key, field string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal stream entry", logger.String("key", key), logger.Error(err))
+		return err
+	}
+
+	if err := client.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		Values: map[string]interface{}{field: string(data)},
+	}).Err(); err != nil {
+		logctx.Error(ctx, "failed to append to stream", logger.String("key", key), logger.Error(err))
+		return fmt.Errorf("failed to append to stream: %w", err)
+	}
+	return nil
+}
+
+// readStreamPage walks the stream newest first from the filter's cursor or end time until `limit` entries were added
+// add decodes a raw entry and returns true if it matches the filter
+// the returned cursor is the id of the last added entry, empty when the stream is exhausted
+func (r *redisRepository) readStreamPage(ctx context.Context, key, field string, filter models.HistoryFilter, add func(raw string) bool) (string, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = historyBatchSize
+	}
+
+	end := "+"
+	if !filter.EndAt.IsZero() {
+		end = "(" + strconv.FormatInt(filter.EndAt.UnixMilli(), 10)
+	}
+	if filter.Cursor != "" {
+		end = "(" + filter.Cursor
+	}
+	start := "-"
+	if !filter.StartAt.IsZero() {
+		start = strconv.FormatInt(filter.StartAt.UnixMilli(), 10)
+	}
+
+	count := 0
+	for {
+		msgs, err := r.client.XRevRangeN(ctx, key, end, start, historyBatchSize).Result()
+		if err == redis.Nil {
+			return "", nil
+		}
+		if err != nil {
+			logctx.Error(ctx, "failed to read stream", logger.String("key", key), logger.Error(err))
+			return "", fmt.Errorf("failed to read stream: %w", err)
+		}
+
+		for _, msg := range msgs {
+			raw, ok := msg.Values[field].(string)
+			if !ok {
+				logctx.Warn(ctx, "stream entry has no value", logger.String("key", key), logger.String("id", msg.ID))
+				continue
+			}
+			if add(raw) {
+				count++
+				if count >= filter.Limit {
+					return msg.ID, nil
+				}
+			}
+		}
+
+		if len(msgs) < historyBatchSize {
+			return "", nil
+		}
+		end = "(" + msgs[len(msgs)-1].ID
+	}
+}
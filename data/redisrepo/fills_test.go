@@ -0,0 +1,96 @@
+package redisrepo
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisRepository_UserFills(t *testing.T) {
+	ctx := context.Background()
+	userId := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	key := CreateUserFillsKey(userId)
+
+	newFill := func(side models.Side) models.Fill {
+		return models.Fill{OrderId: uuid.New(), SwapId: uuid.New(), Symbol: "MATIC-USDC", Side: side, Price: decimal.NewFromInt(1), Size: decimal.NewFromInt(10), OrderSize: decimal.NewFromInt(100), FeeToken: "MATIC", TakerFee: decimal.NewFromInt(2), MakerRebate: decimal.NewFromInt(1)}
+	}
+	toMsg := func(id string, fill models.Fill) redis.XMessage {
+		data, _ := json.Marshal(fill)
+		return redis.XMessage{ID: id, Values: map[string]interface{}{"fill": string(data)}}
+	}
+
+	t.Run("should append fill to user stream and to the user's fills of its swap", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		fill := newFill(models.SELL)
+		data, _ := json.Marshal(fill)
+		mock.ExpectTxPipeline()
+		mock.ExpectXAdd(&redis.XAddArgs{Stream: key, Values: map[string]interface{}{"fill": string(data)}}).SetVal("1-0")
+		mock.ExpectRPush(CreateUserSwapFillsKey(userId, fill.SwapId), string(data)).SetVal(1)
+		mock.ExpectTxPipelineExec()
+
+		assert.NoError(t, repo.StoreUserFill(ctx, userId, fill))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return the user's fills of a swap", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		fill := newFill(models.BUY)
+		data, _ := json.Marshal(fill)
+		mock.ExpectLRange(CreateUserSwapFillsKey(userId, fill.SwapId), 0, -1).SetVal([]string{string(data)})
+
+		fills, err := repo.GetUserSwapFills(ctx, userId, fill.SwapId)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Fill{fill}, fills)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return filtered page and cursor of last fill", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		sell1, buy, sell2 := newFill(models.SELL), newFill(models.BUY), newFill(models.SELL)
+		mock.ExpectXRevRangeN(key, "+", "-", historyBatchSize).SetVal([]redis.XMessage{toMsg("3-0", sell1), toMsg("2-0", buy), toMsg("1-0", sell2)})
+
+		fills, cursor, err := repo.GetUserFills(ctx, userId, models.HistoryFilter{Side: models.SELL, Limit: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Fill{sell1, sell2}, fills)
+		assert.Equal(t, "1-0", cursor)
+	})
+
+	t.Run("should read from cursor within time range and return empty cursor when exhausted", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		startAt := time.UnixMilli(1000)
+		fill := newFill(models.BUY)
+		mock.ExpectXRevRangeN(key, "(5-0", strconv.FormatInt(startAt.UnixMilli(), 10), historyBatchSize).SetVal([]redis.XMessage{toMsg("4-0", fill)})
+
+		fills, cursor, err := repo.GetUserFills(ctx, userId, models.HistoryFilter{Cursor: "5-0", StartAt: startAt, EndAt: time.UnixMilli(9000), Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Fill{fill}, fills)
+		assert.Equal(t, "", cursor)
+	})
+
+	t.Run("should return error on redis error", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectXRevRangeN(key, "+", "-", historyBatchSize).SetErr(assert.AnError)
+
+		_, _, err := repo.GetUserFills(ctx, userId, models.HistoryFilter{Limit: 10})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -0,0 +1,89 @@
+package redisrepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/redis/go-redis/v9"
+)
+
+// takeFirmQuoteScript gets and deletes the quote, so only one of a swap and the expiry check gets its locked fragments
+// KEYS[1] - firm quotes key, ARGV[1] - quote ID
+var takeFirmQuoteScript = redis.NewScript(`
+local quote = redis.call('HGET', KEYS[1], ARGV[1])
+if quote then
+	redis.call('HDEL', KEYS[1], ARGV[1])
+end
+return quote
+`)
+
+func (r *redisRepository) StoreFirmQuote(ctx context.Context, quote models.FirmQuote) error {
+	data, err := json.Marshal(quote)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal firm quote", logger.String("quoteId", quote.Id.String()), logger.Error(err))
+		return err
+	}
+
+	if err := r.client.HSet(ctx, CreateFirmQuotesKey(), quote.Id.String(), string(data)).Err(); err != nil {
+		logctx.Error(ctx, "failed to store firm quote", logger.String("quoteId", quote.Id.String()), logger.Error(err))
+		return fmt.Errorf("failed to store firm quote: %w", err)
+	}
+	return nil
+}
+
+func (r *redisRepository) GetFirmQuote(ctx context.Context, quoteId uuid.UUID) (*models.FirmQuote, error) {
+	data, err := r.client.HGet(ctx, CreateFirmQuotesKey(), quoteId.String()).Result()
+	if err == redis.Nil {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logctx.Error(ctx, "failed to get firm quote", logger.String("quoteId", quoteId.String()), logger.Error(err))
+		return nil, err
+	}
+	return unmarshalFirmQuote(ctx, data)
+}
+
+// TakeFirmQuote removes the quote and returns it, returns ErrNotFound if it was already taken
+func (r *redisRepository) TakeFirmQuote(ctx context.Context, quoteId uuid.UUID) (*models.FirmQuote, error) {
+	data, err := takeFirmQuoteScript.Run(ctx, r.client, []string{CreateFirmQuotesKey()}, quoteId.String()).Text()
+	if err == redis.Nil {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logctx.Error(ctx, "failed to take firm quote", logger.String("quoteId", quoteId.String()), logger.Error(err))
+		return nil, err
+	}
+	return unmarshalFirmQuote(ctx, data)
+}
+
+func (r *redisRepository) GetFirmQuotes(ctx context.Context) ([]models.FirmQuote, error) {
+	values, err := r.client.HVals(ctx, CreateFirmQuotesKey()).Result()
+	if err != nil {
+		logctx.Error(ctx, "failed to get firm quotes", logger.Error(err))
+		return nil, err
+	}
+
+	quotes := []models.FirmQuote{}
+	for _, data := range values {
+		quote, err := unmarshalFirmQuote(ctx, data)
+		if err != nil {
+			continue
+		}
+		quotes = append(quotes, *quote)
+	}
+	return quotes, nil
+}
+
+func unmarshalFirmQuote(ctx context.Context, data string) (*models.FirmQuote, error) {
+	var quote models.FirmQuote
+	if err := json.Unmarshal([]byte(data), &quote); err != nil {
+		logctx.Error(ctx, "failed to unmarshal firm quote", logger.Error(err))
+		return nil, models.ErrMarshalError
+	}
+	return &quote, nil
+}
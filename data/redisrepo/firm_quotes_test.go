@@ -0,0 +1,84 @@
+package redisrepo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisRepository_FirmQuotes(t *testing.T) {
+	ctx := context.Background()
+	quote := models.FirmQuote{
+		Id:        uuid.MustParse("00000000-0000-0000-0000-000000000021"),
+		Legs:      []models.RouteLeg{{Symbol: "MATIC-USDC", MakerSide: models.SELL}},
+		InAmount:  decimal.NewFromInt(10),
+		OutAmount: decimal.NewFromInt(5),
+		Frags:     []models.OrderFrag{{OrderId: uuid.MustParse("00000000-0000-0000-0000-000000000001"), InSize: decimal.NewFromInt(10), OutSize: decimal.NewFromInt(5)}},
+		Created:   time.UnixMilli(1000).UTC(),
+		ExpiresAt: time.UnixMilli(11000).UTC(),
+	}
+	data, _ := json.Marshal(quote)
+
+	t.Run("should store firm quote", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectHSet(CreateFirmQuotesKey(), quote.Id.String(), string(data)).SetVal(1)
+
+		assert.NoError(t, repo.StoreFirmQuote(ctx, quote))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should take firm quote", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectEvalSha(takeFirmQuoteScript.Hash(), []string{CreateFirmQuotesKey()}, quote.Id.String()).SetVal(string(data))
+
+		taken, err := repo.TakeFirmQuote(ctx, quote.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, quote.Id, taken.Id)
+		assert.True(t, quote.OutAmount.Equal(taken.OutAmount))
+		assert.Equal(t, quote.ExpiresAt, taken.ExpiresAt)
+	})
+
+	t.Run("should return not found for a quote already taken", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectEvalSha(takeFirmQuoteScript.Hash(), []string{CreateFirmQuotesKey()}, quote.Id.String()).SetErr(redis.Nil)
+
+		_, err := repo.TakeFirmQuote(ctx, quote.Id)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should return not found for unknown quote", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectHGet(CreateFirmQuotesKey(), quote.Id.String()).RedisNil()
+
+		_, err := repo.GetFirmQuote(ctx, quote.Id)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should get all firm quotes", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectHVals(CreateFirmQuotesKey()).SetVal([]string{string(data)})
+
+		quotes, err := repo.GetFirmQuotes(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, quotes, 1)
+		assert.Equal(t, quote.Id, quotes[0].Id)
+	})
+}
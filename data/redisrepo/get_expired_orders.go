@@ -0,0 +1,37 @@
+package redisrepo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/redis/go-redis/v9"
+)
+
+// GetExpiredOrderIds returns the IDs of orders whose signed deadline or time in force expiry is at or before `at`
+func (r *redisRepository) GetExpiredOrderIds(ctx context.Context, at time.Time) ([]uuid.UUID, error) {
+	strIds, err := r.client.ZRangeByScore(ctx, CreateOrderDeadlinesKey(), &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(at.Unix(), 10),
+	}).Result()
+	if err != nil {
+		logctx.Error(ctx, "failed to get expired order IDs", logger.Error(err))
+		return nil, fmt.Errorf("failed to get expired order IDs: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(strIds))
+	for _, strId := range strIds {
+		id, err := uuid.Parse(strId)
+		if err != nil {
+			logctx.Error(ctx, "invalid order ID in deadlines", logger.String("orderId", strId), logger.Error(err))
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
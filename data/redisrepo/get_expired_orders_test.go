@@ -0,0 +1,40 @@
+package redisrepo
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisRepository_GetExpiredOrderIds(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	zRange := &redis.ZRangeBy{Min: "-inf", Max: strconv.FormatInt(at.Unix(), 10)}
+
+	t.Run("should return expired order IDs", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectZRangeByScore(CreateOrderDeadlinesKey(), zRange).SetVal([]string{orderId.String(), "not-a-uuid"})
+
+		ids, err := repo.GetExpiredOrderIds(ctx, at)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{orderId}, ids)
+	})
+
+	t.Run("should return error on redis error", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectZRangeByScore(CreateOrderDeadlinesKey(), zRange).SetErr(assert.AnError)
+
+		_, err := repo.GetExpiredOrderIds(ctx, at)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -13,7 +13,11 @@ import (
 	"github.com/shopspring/decimal"
 )
 
-func (r *redisRepository) GetMarketDepth(ctx context.Context, symbol models.Symbol, depth int) (models.MarketDepth, error) {
+// min number of order IDs read per round trip when aggregating price levels
+const minDepthBatchSize = 50
+
+// GetMarketDepth returns up to `query.Depth` price levels (L2) or orders (L3) of each side, best price first
+func (r *redisRepository) GetMarketDepth(ctx context.Context, symbol models.Symbol, query models.DepthQuery) (models.MarketDepth, error) {
 	// Create a default MarketDepth
 	marketDepth := models.MarketDepth{
 		Asks:   [][]decimal.Decimal{},
@@ -22,22 +26,22 @@ func (r *redisRepository) GetMarketDepth(ctx context.Context, symbol models.Symb
 		Time:   time.Now().Unix(),
 	}
 
-	// Fetch sell and buy side orders concurrently
-	var askOrderIds, bidOrderIds []uuid.UUID
+	// Fetch sell and buy sides concurrently
+	var asks, bids [][]decimal.Decimal
 	var asksErr, bidsErr error
 	var wg sync.WaitGroup
 	wg.Add(2)
 
-	// Fetch Asks
+	// Fetch Asks - lowest price first
 	go func() {
 		defer wg.Done()
-		askOrderIds, asksErr = r.fetchOrderIds(ctx, CreateSellSidePricesKey(symbol), depth)
+		asks, asksErr = r.fetchDepthSide(ctx, CreateSellSidePricesKey(symbol), models.SELL, query)
 	}()
 
-	// Fetch Bids
+	// Fetch Bids - highest price first
 	go func() {
 		defer wg.Done()
-		bidOrderIds, bidsErr = r.fetchOrderIds(ctx, CreateBuySidePricesKey(symbol), depth)
+		bids, bidsErr = r.fetchDepthSide(ctx, CreateBuySidePricesKey(symbol), models.BUY, query)
 	}()
 
 	wg.Wait()
@@ -50,53 +54,78 @@ func (r *redisRepository) GetMarketDepth(ctx context.Context, symbol models.Symb
 		return marketDepth, fmt.Errorf("failed to fetch bids: %v", bidsErr)
 	}
 
-	// Concatenate ask and bid order IDs
-	allOrderIds := append(askOrderIds, bidOrderIds...)
+	marketDepth.Asks = asks
+	marketDepth.Bids = bids
+	return marketDepth, nil
+}
 
-	// Fetch all orders in bulk
-	var orders []models.Order
-	var err error
-	for i := 0; i < len(allOrderIds); i += MAX_ORDER_IDS {
-		end := i + MAX_ORDER_IDS
-		if end > len(allOrderIds) {
-			end = len(allOrderIds)
+// fetchDepthSide reads the orders of a side in batches, best price first, until the depth is full or the side is exhausted
+func (r *redisRepository) fetchDepthSide(ctx context.Context, key string, side models.Side, query models.DepthQuery) ([][]decimal.Decimal, error) {
+	builder := models.NewDepthBuilder(side, query)
+	if query.Depth <= 0 {
+		return builder.Rows, nil
+	}
+
+	// L3 needs exactly `depth` orders, L2 needs at least that many
+	batchSize := query.Depth
+	if query.Mode != models.DEPTH_L3 && batchSize < minDepthBatchSize {
+		batchSize = minDepthBatchSize
+	}
+	if batchSize > MAX_ORDER_IDS {
+		batchSize = MAX_ORDER_IDS
+	}
+
+	for start := 0; ; start += batchSize {
+		orderIds, err := r.fetchOrderIds(ctx, key, side, start, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(orderIds) == 0 {
+			return builder.Rows, nil
 		}
 
-		orders, err = r.FindOrdersByIds(ctx, allOrderIds[i:end], true)
+		orders, err := r.FindOrdersByIds(ctx, orderIds, true)
 		if err != nil {
 			logctx.Error(ctx, "failed to find orders by IDs", logger.Error(err))
-			return marketDepth, fmt.Errorf("failed to find orders by IDs: %v", err)
+			return nil, fmt.Errorf("failed to find orders by IDs: %v", err)
 		}
-	}
 
-	// Map orders by their ID for quick lookup
-	orderMap := make(map[uuid.UUID]models.Order)
-	for _, order := range orders {
-		orderMap[order.Id] = order
-	}
+		// Map orders by their ID for quick lookup
+		orderMap := make(map[uuid.UUID]models.Order, len(orders))
+		for _, order := range orders {
+			orderMap[order.Id] = order
+		}
 
-	// Process asks
-	for _, orderId := range askOrderIds {
-		if order, ok := orderMap[orderId]; ok {
-			marketDepth.Asks = append(marketDepth.Asks, []decimal.Decimal{order.Price, order.GetAvailableSize()})
+		for _, orderId := range orderIds {
+			order, ok := orderMap[orderId]
+			if !ok {
+				continue
+			}
+			if !builder.Add(order.Price, order.GetAvailableSize()) {
+				return builder.Rows, nil
+			}
 		}
-	}
 
-	// Process bids
-	for _, orderId := range bidOrderIds {
-		if order, ok := orderMap[orderId]; ok {
-			marketDepth.Bids = append(marketDepth.Bids, []decimal.Decimal{order.Price, order.GetAvailableSize()})
+		// an L2 level may continue in the next batch, L3 is done once it has `depth` orders
+		if len(orderIds) < batchSize || (query.Mode == models.DEPTH_L3 && len(builder.Rows) >= query.Depth) {
+			return builder.Rows, nil
 		}
 	}
-
-	return marketDepth, nil
 }
 
-// fetchOrderIds fetches order IDs for a given key and depth
-func (r *redisRepository) fetchOrderIds(ctx context.Context, key string, depth int) ([]uuid.UUID, error) {
-	orderStrIds, err := r.client.ZRange(ctx, key, 0, int64(depth-1)).Result()
+// fetchOrderIds fetches a batch of order IDs of a side, best price first
+func (r *redisRepository) fetchOrderIds(ctx context.Context, key string, side models.Side, start, count int) ([]uuid.UUID, error) {
+	from, to := int64(start), int64(start+count-1)
+
+	var orderStrIds []string
+	var err error
+	if side == models.BUY {
+		orderStrIds, err = r.client.ZRevRange(ctx, key, from, to).Result()
+	} else {
+		orderStrIds, err = r.client.ZRange(ctx, key, from, to).Result()
+	}
 	if err != nil {
-		logctx.Error(ctx, "failed to fetch order IDs from Redis", logger.Error(err), logger.String("key", key), logger.Int("depth", depth))
+		logctx.Error(ctx, "failed to fetch order IDs from Redis", logger.Error(err), logger.String("key", key), logger.Int("start", start), logger.Int("count", count))
 		return nil, fmt.Errorf("failed to fetch order IDs from Redis: %v", err)
 	}
 
@@ -104,7 +133,7 @@ func (r *redisRepository) fetchOrderIds(ctx context.Context, key string, depth i
 	for i, strId := range orderStrIds {
 		orderId, err := uuid.Parse(strId)
 		if err != nil {
-			logctx.Error(ctx, "failed to parse order ID", logger.Error(err), logger.String("key", key), logger.Int("depth", depth), logger.String("strId", strId))
+			logctx.Error(ctx, "failed to parse order ID", logger.Error(err), logger.String("key", key), logger.String("strId", strId))
 			return nil, fmt.Errorf("failed to parse order ID: %v", err)
 		}
 		orderIds[i] = orderId
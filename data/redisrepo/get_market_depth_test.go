@@ -0,0 +1,78 @@
+package redisrepo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisRepository_FetchDepthSide(t *testing.T) {
+	ctx := context.Background()
+	symbol := models.Symbol("MATIC-USDC")
+
+	newOrder := func(side models.Side, price, size string) models.Order {
+		return models.Order{Id: uuid.New(), ClientOId: uuid.New(), UserId: uuid.New(), Symbol: symbol, Side: side, Price: decimal.RequireFromString(price), Size: decimal.RequireFromString(size)}
+	}
+	orderIds := func(orders []models.Order) []string {
+		ids := []string{}
+		for _, order := range orders {
+			ids = append(ids, order.Id.String())
+		}
+		return ids
+	}
+	expectOrders := func(mock redismock.ClientMock, orders ...models.Order) {
+		for _, order := range orders {
+			mock.ExpectHGetAll(CreateOrderIDKey(order.Id)).SetVal(order.OrderToMap())
+		}
+	}
+
+	t.Run("L2 should aggregate asks by price level", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		orders := []models.Order{newOrder(models.SELL, "1", "10"), newOrder(models.SELL, "1", "5"), newOrder(models.SELL, "2", "3")}
+		key := CreateSellSidePricesKey(symbol)
+		mock.ExpectZRange(key, 0, minDepthBatchSize-1).SetVal(orderIds(orders))
+		expectOrders(mock, orders...)
+
+		rows, err := repo.fetchDepthSide(ctx, key, models.SELL, models.DepthQuery{Mode: models.DEPTH_L2, Depth: 1})
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		assert.Equal(t, "1", rows[0][0].String())
+		assert.Equal(t, "15", rows[0][1].String())
+		assert.Equal(t, "2", rows[0][2].String())
+	})
+
+	t.Run("L3 should return a row per bid, highest first", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		orders := []models.Order{newOrder(models.BUY, "3", "1"), newOrder(models.BUY, "3", "2")}
+		key := CreateBuySidePricesKey(symbol)
+		mock.ExpectZRevRange(key, 0, 1).SetVal(orderIds(orders))
+		expectOrders(mock, orders...)
+
+		rows, err := repo.fetchDepthSide(ctx, key, models.BUY, models.DepthQuery{Mode: models.DEPTH_L3, Depth: 2})
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, "1", rows[0][1].String())
+		assert.Equal(t, "2", rows[1][1].String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return error on redis error", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		key := CreateSellSidePricesKey(symbol)
+		mock.ExpectZRange(key, 0, 4).SetErr(assert.AnError)
+
+		_, err := repo.fetchDepthSide(ctx, key, models.SELL, models.DepthQuery{Mode: models.DEPTH_L3, Depth: 5})
+		assert.ErrorContains(t, err, "failed to fetch order IDs from Redis")
+	})
+}
@@ -2,11 +2,61 @@ package redisrepo
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/redis/go-redis/v9"
 	"github.com/shopspring/decimal"
 )
 
+// scores add the scaled order timestamp to the price, which stays well below this
+const priceScoreWindow = 0.01
+
+// GetOrdersAtPrice returns the open orders of both sides of the symbol at the price, oldest first
 func (r *redisRepository) GetOrdersAtPrice(ctx context.Context, symbol models.Symbol, price decimal.Decimal) ([]models.Order, error) {
-	panic("not implemented")
+	f64Price, _ := price.Float64()
+
+	orders := []models.Order{}
+	for _, key := range []string{CreateBuySidePricesKey(symbol), CreateSellSidePricesKey(symbol)} {
+		strIds, err := r.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+			Min: fmt.Sprint(f64Price),
+			Max: fmt.Sprint(f64Price + priceScoreWindow),
+		}).Result()
+		if err != nil {
+			logctx.Error(ctx, "failed to get order IDs at price", logger.Error(err), logger.String("symbol", symbol.String()))
+			return nil, fmt.Errorf("failed to get order IDs at price: %w", err)
+		}
+
+		ids := make([]uuid.UUID, 0, len(strIds))
+		for _, strId := range strIds {
+			id, err := uuid.Parse(strId)
+			if err != nil {
+				logctx.Error(ctx, "invalid order ID in prices", logger.String("orderId", strId), logger.Error(err))
+				continue
+			}
+			ids = append(ids, id)
+		}
+
+		for start := 0; start < len(ids); start += MAX_ORDER_IDS {
+			end := start + MAX_ORDER_IDS
+			if end > len(ids) {
+				end = len(ids)
+			}
+			batch, err := r.FindOrdersByIds(ctx, ids[start:end], false)
+			if err != nil {
+				return nil, err
+			}
+			// the window may hold close prices too
+			for _, order := range batch {
+				if order.Price.Equal(price) {
+					orders = append(orders, order)
+				}
+			}
+		}
+	}
+
+	return orders, nil
 }
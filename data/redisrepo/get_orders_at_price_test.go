@@ -0,0 +1,57 @@
+package redisrepo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisRepository_GetOrdersAtPrice(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	repo, err := NewRedisRepository(client)
+	require.NoError(t, err)
+
+	newOrder := func(side models.Side, price string, age time.Duration) models.Order {
+		return models.Order{
+			Id:          uuid.New(),
+			ClientOId:   uuid.New(),
+			UserId:      uuid.New(),
+			Price:       decimal.RequireFromString(price),
+			Size:        decimal.NewFromInt(1),
+			SizePending: decimal.Zero,
+			SizeFilled:  decimal.Zero,
+			Symbol:      "MATIC-USDC",
+			Side:        side,
+			Timestamp:   time.Now().Add(-age).UTC().Truncate(time.Second),
+		}
+	}
+	newer := newOrder(models.BUY, "1.5", 0)
+	older := newOrder(models.BUY, "1.5", time.Minute)
+	ask := newOrder(models.SELL, "1.5", 0)
+	nearby := newOrder(models.BUY, "1.501", 0)
+	require.NoError(t, repo.StoreOpenOrders(ctx, []models.Order{newer, older, ask, nearby}))
+
+	t.Run("should return the orders of both sides at the price oldest first", func(t *testing.T) {
+		orders, err := repo.GetOrdersAtPrice(ctx, "MATIC-USDC", decimal.RequireFromString("1.5"))
+		assert.NoError(t, err)
+		require.Len(t, orders, 3)
+		assert.Equal(t, older.Id, orders[0].Id)
+		assert.Equal(t, newer.Id, orders[1].Id)
+		assert.Equal(t, ask.Id, orders[2].Id)
+	})
+
+	t.Run("should return no orders for a price without orders", func(t *testing.T) {
+		orders, err := repo.GetOrdersAtPrice(ctx, "MATIC-USDC", decimal.RequireFromString("3"))
+		assert.NoError(t, err)
+		assert.Empty(t, orders)
+	})
+}
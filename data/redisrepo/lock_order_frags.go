@@ -0,0 +1,99 @@
+package redisrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/redis/go-redis/v9"
+)
+
+// times the orders are read again when they changed while being locked
+const lockOrderFragsRetries = 3
+
+// LockOrderFrags adds the size of each fragment to its order's pending size and increments its version, only if all the orders have it available
+// the orders are watched from read to write, so a concurrent change of any of them retries the lock with their new sizes
+// returns the locked orders in frags order, ErrNotFound if an order is missing and ErrSwapInvalid if a fragment is not available
+func (r *redisRepository) LockOrderFrags(ctx context.Context, frags []models.OrderFrag) ([]models.Order, error) {
+	keys := make([]string, 0, len(frags))
+	for _, frag := range frags {
+		keys = append(keys, CreateOrderIDKey(frag.OrderId))
+	}
+
+	var orders []models.Order
+	for attempt := 0; attempt < lockOrderFragsRetries; attempt++ {
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			var err error
+			orders, err = lockWatchedOrderFrags(ctx, tx, frags)
+			return err
+		}, keys...)
+
+		if err == nil {
+			return orders, nil
+		}
+		if !errors.Is(err, redis.TxFailedErr) {
+			return nil, err
+		}
+		logctx.Debug(ctx, "orders changed while locking, retrying", logger.Int("attempt", attempt))
+	}
+
+	logctx.Warn(ctx, "orders kept changing while locking", logger.Int("frags", len(frags)))
+	return nil, models.ErrSwapInvalid
+}
+
+func lockWatchedOrderFrags(ctx context.Context, tx *redis.Tx, frags []models.OrderFrag) ([]models.Order, error) {
+	// an order with several fragments is locked for all of them
+	locked := make(map[uuid.UUID]*models.Order, len(frags))
+	for _, frag := range frags {
+		order, ok := locked[frag.OrderId]
+		if !ok {
+			orderMap, err := tx.HGetAll(ctx, CreateOrderIDKey(frag.OrderId)).Result()
+			if err != nil {
+				logctx.Error(ctx, "could not get order to lock", logger.Error(err), logger.String("orderId", frag.OrderId.String()))
+				return nil, fmt.Errorf("could not get order to lock: %w", err)
+			}
+			if len(orderMap) == 0 {
+				logctx.Warn(ctx, "order to lock not found", logger.String("orderId", frag.OrderId.String()))
+				return nil, models.ErrNotFound
+			}
+			order = &models.Order{}
+			if err := order.MapToOrder(orderMap); err != nil {
+				logctx.Error(ctx, "could not map order to lock", logger.Error(err), logger.String("orderId", frag.OrderId.String()))
+				return nil, err
+			}
+			locked[frag.OrderId] = order
+		}
+
+		if order.IsFilled() {
+			logctx.Warn(ctx, "order to lock is filled", logger.String("orderId", frag.OrderId.String()))
+			return nil, models.ErrSwapInvalid
+		}
+		if err := order.Lock(ctx, frag); err != nil {
+			logctx.Warn(ctx, "failed to lock order frag", logger.Error(err), logger.String("orderId", frag.OrderId.String()))
+			return nil, models.ErrSwapInvalid
+		}
+	}
+
+	_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for id, order := range locked {
+			order.Version++
+			pipe.HSet(ctx, CreateOrderIDKey(id), "sizePending", order.SizePending.String(), "version", strconv.FormatInt(order.Version, 10))
+			txPublishBookChange(ctx, pipe, *order)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]models.Order, 0, len(frags))
+	for _, frag := range frags {
+		orders = append(orders, *locked[frag.OrderId])
+	}
+	return orders, nil
+}
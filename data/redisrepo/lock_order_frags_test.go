@@ -0,0 +1,162 @@
+package redisrepo
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redismock/v9"
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisRepository_LockOrderFrags(t *testing.T) {
+	ctx := context.Background()
+
+	newOrder := func() models.Order {
+		return models.Order{Id: uuid.New(), ClientOId: uuid.New(), UserId: uuid.New(), Symbol: "MATIC-USDC", Side: models.SELL, Price: decimal.NewFromInt(2), Size: decimal.NewFromInt(10), SizePending: decimal.NewFromInt(4)}
+	}
+	fragOf := func(order models.Order, size int64) models.OrderFrag {
+		return models.OrderFrag{OrderId: order.Id, OutSize: decimal.NewFromInt(size), InSize: decimal.NewFromInt(size * 2)}
+	}
+	bookChange := func(order models.Order) string {
+		event, _ := json.Marshal(models.MarketEvent{Event: models.MARKET_EVENT_BOOK_CHANGE, Symbol: order.Symbol, Side: order.Side})
+		return string(event)
+	}
+
+	t.Run("should lock the fragments of watched orders", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+		order := newOrder()
+		key := CreateOrderIDKey(order.Id)
+
+		mock.ExpectWatch(key)
+		mock.ExpectHGetAll(key).SetVal(order.OrderToMap())
+		mock.ExpectTxPipeline()
+		mock.ExpectHSet(key, "sizePending", "10", "version", "1").SetVal(0)
+		mock.ExpectPublish(models.CreateMarketEventKey(order.Symbol), bookChange(order)).SetVal(0)
+		mock.ExpectTxPipelineExec()
+
+		orders, err := repo.LockOrderFrags(ctx, []models.OrderFrag{fragOf(order, 6)})
+		assert.NoError(t, err)
+		assert.Len(t, orders, 1)
+		assert.Equal(t, "10", orders[0].SizePending.String())
+		assert.Equal(t, int64(1), orders[0].Version)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should not lock more than the available size", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+		order := newOrder()
+		key := CreateOrderIDKey(order.Id)
+
+		mock.ExpectWatch(key)
+		mock.ExpectHGetAll(key).SetVal(order.OrderToMap())
+
+		_, err := repo.LockOrderFrags(ctx, []models.OrderFrag{fragOf(order, 7)})
+		assert.ErrorIs(t, err, models.ErrSwapInvalid)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return not found for a missing order", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+		order := newOrder()
+		key := CreateOrderIDKey(order.Id)
+
+		mock.ExpectWatch(key)
+		mock.ExpectHGetAll(key).SetVal(map[string]string{})
+
+		_, err := repo.LockOrderFrags(ctx, []models.OrderFrag{fragOf(order, 1)})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should fail once the orders keep changing", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+		order := newOrder()
+		key := CreateOrderIDKey(order.Id)
+
+		for i := 0; i < lockOrderFragsRetries; i++ {
+			mock.ExpectWatch(key)
+			mock.ExpectHGetAll(key).SetVal(order.OrderToMap())
+			mock.ExpectTxPipeline()
+			mock.ExpectHSet(key, "sizePending", "5", "version", "1").SetVal(0)
+			mock.ExpectPublish(models.CreateMarketEventKey(order.Symbol), bookChange(order)).SetVal(0)
+			mock.ExpectTxPipelineExec().SetErr(redis.TxFailedErr)
+		}
+
+		_, err := repo.LockOrderFrags(ctx, []models.OrderFrag{fragOf(order, 1)})
+		assert.ErrorIs(t, err, models.ErrSwapInvalid)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRedisRepository_LockOrderFragsConcurrent(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	repo, err := NewRedisRepository(client)
+	require.NoError(t, err)
+
+	newOrder := func() models.Order {
+		order := models.Order{Id: uuid.New(), ClientOId: uuid.New(), UserId: uuid.New(), Symbol: "MATIC-USDC", Side: models.SELL, Price: decimal.NewFromInt(2), Size: decimal.NewFromInt(10)}
+		require.NoError(t, repo.StoreOpenOrder(ctx, order))
+		return order
+	}
+	fragOf := func(order models.Order, size int64) models.OrderFrag {
+		return models.OrderFrag{OrderId: order.Id, OutSize: decimal.NewFromInt(size), InSize: decimal.NewFromInt(size * 2)}
+	}
+
+	t.Run("should lock overlapping fragments for only one of two takers", func(t *testing.T) {
+		for round := 0; round < 100; round++ {
+			shared, other := newOrder(), newOrder()
+			takers := [][]models.OrderFrag{
+				{fragOf(shared, 6)},
+				{fragOf(other, 1), fragOf(shared, 6)},
+			}
+
+			// both takers start locking together
+			start := make(chan struct{})
+			errs := make([]error, len(takers))
+			var wg sync.WaitGroup
+			for i, frags := range takers {
+				wg.Add(1)
+				go func(i int, frags []models.OrderFrag) {
+					defer wg.Done()
+					<-start
+					_, errs[i] = repo.LockOrderFrags(ctx, frags)
+				}(i, frags)
+			}
+			close(start)
+			wg.Wait()
+
+			locked := 0
+			for _, err := range errs {
+				if err == nil {
+					locked++
+				} else {
+					assert.ErrorIs(t, err, models.ErrSwapInvalid)
+				}
+			}
+			assert.Equal(t, 1, locked, "round %d", round)
+
+			stored, err := repo.FindOrderById(ctx, shared.Id, false)
+			require.NoError(t, err)
+			assert.Equal(t, "6", stored.SizePending.String(), "round %d", round)
+			if errs[1] != nil {
+				stored, err = repo.FindOrderById(ctx, other.Id, false)
+				require.NoError(t, err)
+				assert.True(t, stored.SizePending.IsZero(), "round %d: sizePending %s", round, stored.SizePending)
+			}
+		}
+	})
+}
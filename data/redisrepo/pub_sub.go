@@ -86,7 +86,13 @@ func (r *redisRepository) distributeMessages(ctx context.Context, channel string
 
 	for {
 		select {
-		case msg := <-sub.pubsub.Channel():
+		case msg, ok := <-sub.pubsub.Channel():
+			// the subscription is closed along with its client
+			if !ok {
+				logctx.Debug(ctx, "subscription closed", logger.String("channel", channel))
+				return
+			}
+
 			// Send the message to all subscribed clients
 			r.mu.Lock()
 
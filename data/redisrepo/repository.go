@@ -4,16 +4,25 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
 type redisRepository struct {
-	cmdable       redis.Cmdable
-	client        *redis.Client
-	txMap         map[uint]redis.Pipeliner
+	cmdable redis.Cmdable
+	client  *redis.Client
+	txMap   map[uint]redis.Pipeliner
+	// version each order key modified in a tx must still have when the tx is committed
+	txVersions    map[uint]map[string]int64
 	ixIndex       uint
 	subscriptions map[string]*channelSubscription
-	mu            sync.Mutex
+	// closed once an event is appended for the user, to wake up the readers waiting for it
+	userEventsAppended map[uuid.UUID]chan struct{}
+	// guards the txs in progress, the subscriptions and the user events readers
+	mu sync.Mutex
+	// serializes starting the listener of appended user events
+	listenMu  sync.Mutex
+	listening bool
 }
 
 type channelSubscription struct {
@@ -37,6 +46,9 @@ func NewRedisRepository(cmdable redis.Cmdable) (*redisRepository, error) {
 		cmdable:       cmdable,
 		client:        client,
 		txMap:         txMap,
+		txVersions:    make(map[uint]map[string]int64),
 		subscriptions: make(map[string]*channelSubscription),
+
+		userEventsAppended: make(map[uuid.UUID]chan struct{}),
 	}, nil
 }
@@ -2,6 +2,11 @@ package redisrepo
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/orbs-network/order-book/models"
@@ -10,28 +15,38 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-// ProcessCompletedSwapOrders stores the updated swap orders and removes the swap from Redis. It should be called after a swap is completed.
-//
-// `orders` should be the orders that were part of the swap (with `SizePending` and `SizeFilled` updated accordingly)
-//
-// `isSuccessful` should be `true` if the swap was successful, `false` otherwise
-
+// ResolveSwap moves the swap from the open swaps to the resolved ones, indexed by its resolve time, in a single tx
 func (r *redisRepository) ResolveSwap(ctx context.Context, swap models.Swap) error {
+	return r.PerformTx(ctx, func(txid uint) error {
+		return r.TxResolveSwap(ctx, txid, swap)
+	})
+}
 
-	// save swap in resolved key
-	err := r.saveSwap(ctx, swap.Id, swap, true)
-	if err != nil {
-		logctx.Error(ctx, "failed to save swap", logger.Error(err), logger.String("swapId", swap.Id.String()))
-		return err
+// TxResolveSwap moves the swap from the open swaps to the resolved ones, indexed by its resolve time, once the tx is committed
+func (r *redisRepository) TxResolveSwap(ctx context.Context, txid uint, swap models.Swap) error {
+	var tx redis.Pipeliner
+	var ok bool
+	if tx, ok = r.getTx(txid); !ok {
+		logctx.Error(ctx, "TxResolveSwap txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
 	}
 
-	// remove from swapId
-	err = r.RemoveSwap(ctx, swap.Id)
+	swapJson, err := json.Marshal(swap)
 	if err != nil {
-		logctx.Error(ctx, "failed to remove swap", logger.Error(err), logger.String("swapId", swap.Id.String()))
-		return err
+		logctx.Error(ctx, "failed to marshal swap", logger.String("swapId", swap.Id.String()), logger.Error(err))
+		return fmt.Errorf("failed to marshal swap: %v", err)
 	}
 
+	// save swap in resolved key
+	tx.Set(ctx, CreateResolvedSwapKey(swap.Id), swapJson, 0)
+	// index by resolve time, to be archived
+	tx.ZAdd(ctx, CreateResolvedSwapsKey(), redis.Z{
+		Score:  float64(swap.Resolved.UnixMilli()),
+		Member: swap.Id.String(),
+	})
+	// remove from swapId
+	tx.Del(ctx, CreateOpenSwapKey(swap.Id))
+	logctx.Debug(ctx, "TxResolveSwap", logger.String("swapId", swap.Id.String()))
 	return nil
 }
 
@@ -53,3 +68,92 @@ func (r *redisRepository) GetUserResolvedSwapIds(ctx context.Context, userId uui
 	}
 	return res, nil
 }
+
+// GetResolvedSwapsUserIds returns the users with resolved swaps in the store
+func (r *redisRepository) GetResolvedSwapsUserIds(ctx context.Context) ([]uuid.UUID, error) {
+	// the prefix is a glob pattern
+	keys, err := r.EnumSubKeysOf(ctx, "userId:*:resolvedSwaps")
+	if err != nil {
+		logctx.Error(ctx, "failed to enumerate user keys", logger.Error(err))
+		return nil, fmt.Errorf("failed to enumerate user keys: %w", err)
+	}
+
+	userIds := []uuid.UUID{}
+	for _, key := range keys {
+		if !strings.HasSuffix(key, ":resolvedSwaps") {
+			continue
+		}
+		userId, err := uuid.Parse(strings.TrimSuffix(strings.TrimPrefix(key, "userId:"), ":resolvedSwaps"))
+		if err != nil {
+			logctx.Warn(ctx, "invalid user ID in resolved swaps key", logger.String("key", key), logger.Error(err))
+			continue
+		}
+		userIds = append(userIds, userId)
+	}
+	return userIds, nil
+}
+
+// GetResolvedSwaps returns up to limit swaps resolved before the time, oldest first
+// swaps resolved before the index was kept are not returned
+func (r *redisRepository) GetResolvedSwaps(ctx context.Context, resolvedBefore time.Time, limit int) ([]models.Swap, error) {
+	swapIds, err := r.client.ZRangeByScore(ctx, CreateResolvedSwapsKey(), &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   "(" + strconv.FormatInt(resolvedBefore.UnixMilli(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		logctx.Error(ctx, "failed to get resolved swap IDs", logger.Error(err))
+		return nil, fmt.Errorf("failed to get resolved swap IDs: %w", err)
+	}
+	if len(swapIds) == 0 {
+		return []models.Swap{}, nil
+	}
+
+	keys := make([]string, len(swapIds))
+	for i, swapId := range swapIds {
+		id, err := uuid.Parse(swapId)
+		if err != nil {
+			logctx.Error(ctx, "invalid swap ID in resolved swaps", logger.String("swapId", swapId), logger.Error(err))
+			return nil, err
+		}
+		keys[i] = CreateResolvedSwapKey(id)
+	}
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		logctx.Error(ctx, "failed to get resolved swaps", logger.Error(err))
+		return nil, fmt.Errorf("failed to get resolved swaps: %w", err)
+	}
+
+	swaps := make([]models.Swap, 0, len(values))
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			logctx.Warn(ctx, "indexed resolved swap not found", logger.String("swapId", swapIds[i]))
+			continue
+		}
+		var swap models.Swap
+		if err := json.Unmarshal([]byte(data), &swap); err != nil {
+			logctx.Error(ctx, "failed to unmarshal resolved swap", logger.String("swapId", swapIds[i]), logger.Error(err))
+			continue
+		}
+		swaps = append(swaps, swap)
+	}
+	return swaps, nil
+}
+
+// RemoveResolvedSwap removes the resolved swap, its index entry, and its ID from the resolved swaps of the users
+func (r *redisRepository) RemoveResolvedSwap(ctx context.Context, swapId uuid.UUID, userIds []uuid.UUID) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, CreateResolvedSwapKey(swapId))
+		pipe.ZRem(ctx, CreateResolvedSwapsKey(), swapId.String())
+		for _, userId := range userIds {
+			pipe.SRem(ctx, CreateUserResolvedSwapsKey(userId), swapId.String())
+		}
+		return nil
+	})
+	if err != nil {
+		logctx.Error(ctx, "failed to remove resolved swap", logger.Error(err), logger.String("swapId", swapId.String()))
+		return fmt.Errorf("failed to remove resolved swap: %w", err)
+	}
+	return nil
+}
@@ -13,27 +13,33 @@ import (
 // This should be used to store FILLED orders in Redis.
 //
 // `StoreOpenOrder` or `StoreOpenOrders` should be used to store unfilled or partially filled orders.
+// Each order is only stored at the version it was read, otherwise it fails with ErrVersionConflict.
 func (r *redisRepository) StoreFilledOrders(ctx context.Context, orders []models.Order) error {
-	transaction := r.client.TxPipeline()
-
-	for _, order := range orders {
-		err := storeFilledOrderTx(ctx, transaction, &order)
-		if err != nil {
-			return err
+	err := r.PerformTx(ctx, func(txid uint) error {
+		for _, order := range orders {
+			if err := r.txStoreFilledOrder(ctx, txid, order); err != nil {
+				return err
+			}
 		}
-
-	}
-	_, err := transaction.Exec(ctx)
+		return nil
+	})
 	if err != nil {
 		logctx.Error(ctx, "failed to store filled orders in Redis", logger.Error(err), logger.Strings("orderIds", models.OrderIdsToStrings(ctx, &orders)))
-		return fmt.Errorf("failed to store filled orders in Redis: %v", err)
+		return fmt.Errorf("failed to store filled orders in Redis: %w", err)
 	}
 
 	logctx.Debug(ctx, "stored filled orders in Redis", logger.Strings("orderIds", models.OrderIdsToStrings(ctx, &orders)))
 	return nil
 }
 
-func storeFilledOrderTx(ctx context.Context, transaction redis.Pipeliner, order *models.Order) error {
+func (r *redisRepository) txStoreFilledOrder(ctx context.Context, txid uint, order models.Order) error {
+	var transaction redis.Pipeliner
+	var ok bool
+	if transaction, ok = r.getTx(txid); !ok {
+		logctx.Error(ctx, "txStoreFilledOrder txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
+	}
+
 	// 1. Remove the order from the user's open orders set
 	userOrdersKey := CreateUserOpenOrdersKey(order.UserId)
 	transaction.ZRem(ctx, userOrdersKey, order.Id.String())
@@ -47,8 +53,5 @@ func storeFilledOrderTx(ctx context.Context, transaction redis.Pipeliner, order
 	}
 
 	// 4. Store the order in the order ID key
-	orderIDKey := CreateOrderIDKey(order.Id)
-	transaction.HSet(ctx, orderIDKey, order.OrderToMap())
-
-	return nil
+	return r.TxModifyOrder(ctx, txid, models.Update, order)
 }
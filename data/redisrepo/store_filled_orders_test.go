@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-redis/redismock/v9"
 	"github.com/orbs-network/order-book/models"
+	"github.com/redis/go-redis/v9"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
@@ -31,12 +32,15 @@ func TestRedisRepository_StoreFilledOrders(t *testing.T) {
 
 		repo := &redisRepository{
 			client: db,
+			txMap:  make(map[uint]redis.Pipeliner),
 		}
 
+		expectOrderVersion(mock, buyOrder)
 		mock.ExpectTxPipeline()
 		mock.ExpectZRem(CreateUserOpenOrdersKey(buyOrder.UserId), buyOrder.Id.String()).SetVal(1)
 		mock.ExpectZRem(CreateBuySidePricesKey(buyOrder.Symbol), buyOrder.Id.String()).SetVal(1)
-		mock.ExpectHSet(CreateOrderIDKey(buyOrder.Id), buyOrder.OrderToMap()).SetVal(1)
+		mock.ExpectHSet(CreateOrderIDKey(buyOrder.Id), nextVersion(buyOrder).OrderToMap()).SetVal(1)
+		expectBookChange(mock, buyOrder)
 		mock.ExpectTxPipelineExec()
 
 		err := repo.StoreFilledOrders(ctx, []models.Order{buyOrder})
@@ -62,12 +66,15 @@ func TestRedisRepository_StoreFilledOrders(t *testing.T) {
 
 		repo := &redisRepository{
 			client: db,
+			txMap:  make(map[uint]redis.Pipeliner),
 		}
 
+		expectOrderVersion(mock, sellOrder)
 		mock.ExpectTxPipeline()
 		mock.ExpectZRem(CreateUserOpenOrdersKey(sellOrder.UserId), sellOrder.Id.String()).SetVal(1)
 		mock.ExpectZRem(CreateSellSidePricesKey(sellOrder.Symbol), sellOrder.Id.String()).SetVal(1)
-		mock.ExpectHSet(CreateOrderIDKey(sellOrder.Id), sellOrder.OrderToMap()).SetVal(1)
+		mock.ExpectHSet(CreateOrderIDKey(sellOrder.Id), nextVersion(sellOrder).OrderToMap()).SetVal(1)
+		expectBookChange(mock, sellOrder)
 		mock.ExpectTxPipelineExec()
 
 		err := repo.StoreFilledOrders(ctx, []models.Order{sellOrder})
@@ -84,8 +91,10 @@ func TestRedisRepository_StoreFilledOrders(t *testing.T) {
 
 		repo := &redisRepository{
 			client: db,
+			txMap:  make(map[uint]redis.Pipeliner),
 		}
 
+		expectOrderVersion(mock, sellOrder)
 		mock.ExpectTxPipeline()
 		mock.ExpectZRem(CreateUserOpenOrdersKey(sellOrder.UserId), sellOrder.Id.String()).SetErr(assert.AnError)
 
@@ -15,7 +15,7 @@ import (
 func (r *redisRepository) txEnsureMakerTokenForBalanceTracking(ctx context.Context, txid uint, order models.Order) error {
 	var tx redis.Pipeliner
 	var ok bool
-	if tx, ok = r.txMap[txid]; !ok {
+	if tx, ok = r.getTx(txid); !ok {
 		logctx.Error(ctx, "TxModifyOrder txid not found", logger.Int("txid", int(txid)))
 		return models.ErrNotFound
 	}
@@ -39,7 +39,8 @@ func (r *redisRepository) txEnsureMakerTokenForBalanceTracking(ctx context.Conte
 	return nil
 }
 
-func (r *redisRepository) txAddOpenOrder(ctx context.Context, txid uint, order models.Order) error {
+// TxStoreOpenOrder adds an unfilled or partially filled order to all of its keys in a single tx
+func (r *redisRepository) TxStoreOpenOrder(ctx context.Context, txid uint, order models.Order) error {
 	// add to order:id key
 	if err := r.TxModifyOrder(ctx, txid, models.Add, order); err != nil {
 		logctx.Error(ctx, "StoreOpenOrders TxModifyOrder Failed adding order", logger.String("orderId", order.Id.String()), logger.String("userId", order.UserId.String()), logger.Error(err))
@@ -62,6 +63,12 @@ func (r *redisRepository) txAddOpenOrder(ctx context.Context, txid uint, order m
 		return err
 	}
 
+	// add to deadlines
+	if err := r.TxModifyOrderDeadlines(ctx, txid, models.Add, order); err != nil {
+		logctx.Error(ctx, "StoreOpenOrders TxModifyOrderDeadlines Failed adding order", logger.String("orderId", order.Id.String()), logger.String("userId", order.UserId.String()), logger.Error(err))
+		return err
+	}
+
 	// ensure balance is tracked for this order
 	return r.txEnsureMakerTokenForBalanceTracking(ctx, txid, order)
 }
@@ -69,7 +76,7 @@ func (r *redisRepository) txAddOpenOrder(ctx context.Context, txid uint, order m
 func (r *redisRepository) StoreOpenOrders(ctx context.Context, orders []models.Order) error {
 	err := r.PerformTx(ctx, func(txid uint) error {
 		for _, order := range orders {
-			if err := r.txAddOpenOrder(ctx, txid, order); err != nil {
+			if err := r.TxStoreOpenOrder(ctx, txid, order); err != nil {
 				return err
 			}
 		}
@@ -80,7 +87,7 @@ func (r *redisRepository) StoreOpenOrders(ctx context.Context, orders []models.O
 
 func (r *redisRepository) StoreOpenOrder(ctx context.Context, order models.Order) error {
 	err := r.PerformTx(ctx, func(txid uint) error {
-		return r.txAddOpenOrder(ctx, txid, order)
+		return r.TxStoreOpenOrder(ctx, txid, order)
 	})
 
 	return err
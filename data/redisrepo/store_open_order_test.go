@@ -38,6 +38,7 @@ func TestRedisRepository_StoreOpenOrder(t *testing.T) {
 			Score:  10.0016969392,
 			Member: buyOrder.Id.String(),
 		}).SetVal(1)
+		expectBookChange(mock, buyOrder)
 		mock.ExpectZAdd(CreateUserOpenOrdersKey(buyOrder.UserId), redis.Z{
 			Score:  float64(timestamp.UnixNano()),
 			Member: buyOrder.Id.String(),
@@ -75,6 +76,7 @@ func TestRedisRepository_StoreOpenOrder(t *testing.T) {
 			Score:  10.0016969392,
 			Member: sellOrder.Id.String(),
 		}).SetVal(1)
+		expectBookChange(mock, sellOrder)
 		mock.ExpectZAdd(CreateUserOpenOrdersKey(sellOrder.UserId), redis.Z{
 			Score:  float64(timestamp.UnixNano()),
 			Member: sellOrder.Id.String(),
@@ -9,6 +9,7 @@ import (
 	"github.com/orbs-network/order-book/models"
 	"github.com/orbs-network/order-book/utils/logger"
 	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/redis/go-redis/v9"
 )
 
 func (r *redisRepository) saveSwap(ctx context.Context, swapId uuid.UUID, swap models.Swap, resolved bool) error {
@@ -28,8 +29,8 @@ func (r *redisRepository) saveSwap(ctx context.Context, swapId uuid.UUID, swap m
 	return err
 }
 
-func (r *redisRepository) StoreSwap(ctx context.Context, swapId uuid.UUID, symbol models.Symbol, side models.Side, frags []models.OrderFrag) error {
-	swap := models.NewSwap(symbol, side, frags)
+func (r *redisRepository) StoreSwap(ctx context.Context, swapId uuid.UUID, legs []models.RouteLeg, frags []models.OrderFrag) error {
+	swap := models.NewSwap(legs, frags)
 
 	err := r.saveSwap(ctx, swapId, *swap, false)
 	if err != nil {
@@ -47,6 +48,10 @@ func (r *redisRepository) GetSwap(ctx context.Context, swapId uuid.UUID, open bo
 	}
 
 	swapJson, err := r.client.Get(ctx, swapKey).Result()
+	if err == redis.Nil {
+		logctx.Warn(ctx, "swap is not found", logger.String("swapId", swapId.String()))
+		return nil, models.ErrNotFound
+	}
 	// Error
 	if err != nil {
 		logctx.Error(ctx, "failed to get swap", logger.String("swapId", swapId.String()), logger.Error(err))
@@ -31,6 +31,19 @@ func TestRedisRepository_GetSwap(t *testing.T) {
 		assert.Len(t, swap.Frags, 2, "Should have 2 orders in the swap")
 	})
 
+	t.Run("should return `ErrNotFound` for a missing swap", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+
+		repo := &redisRepository{
+			client: db,
+		}
+
+		mock.ExpectGet(CreateResolvedSwapKey(swapId)).RedisNil()
+
+		_, err := repo.GetSwap(ctx, swapId, false)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
 	t.Run("should return `ErrUnexpectedError` in case of a Redis error", func(t *testing.T) {
 		db, mock := redismock.NewClientMock()
 
@@ -2,6 +2,8 @@ package redisrepo
 
 import (
 	"context"
+	"encoding/json"
+	"strconv"
 	"testing"
 	"time"
 
@@ -30,6 +32,25 @@ func setupTest() (context.Context, redismock.ClientMock, *redisRepository) {
 	return ctx, mock, repo
 }
 
+// expectOrderVersion expects the version check of an order modified in the tx, before it is committed
+func expectOrderVersion(mock redismock.ClientMock, order models.Order) {
+	key := CreateOrderIDKey(order.Id)
+	mock.ExpectWatch(key)
+	mock.ExpectHMGet(key, "id", "version").SetVal([]interface{}{order.Id.String(), strconv.FormatInt(order.Version, 10)})
+}
+
+// nextVersion returns the order as it is stored by an update
+func nextVersion(order models.Order) *models.Order {
+	order.Version++
+	return &order
+}
+
+// expectBookChange expects the book change event published within the tx
+func expectBookChange(mock redismock.ClientMock, order models.Order) {
+	event, _ := json.Marshal(models.MarketEvent{Event: models.MARKET_EVENT_BOOK_CHANGE, Symbol: order.Symbol, Side: order.Side})
+	mock.ExpectPublish(models.CreateMarketEventKey(order.Symbol), string(event)).SetVal(0)
+}
+
 func TestRedisRepository_TxStartEndPerform(t *testing.T) {
 
 	t.Run("txStart initializes a transaction", func(t *testing.T) {
@@ -43,7 +64,11 @@ func TestRedisRepository_TxStartEndPerform(t *testing.T) {
 
 		mock.ExpectTxPipeline()
 
-		txid := repo.txStart(context.Background())
+		var txid uint
+		_ = db.Watch(context.Background(), func(tx *redis.Tx) error {
+			txid = repo.txStart(context.Background(), tx)
+			return nil
+		})
 
 		assert.Equal(t, uint(1), txid)
 		assert.Contains(t, repo.txMap, txid)
@@ -86,6 +111,22 @@ func TestRedisRepository_TxStartEndPerform(t *testing.T) {
 		assert.True(t, actionCalled)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("PerformTx drops the transaction once it is over", func(t *testing.T) {
+		db, _ := redismock.NewClientMock()
+
+		repo := &redisRepository{
+			client:     db,
+			txMap:      make(map[uint]redis.Pipeliner),
+			txVersions: make(map[uint]map[string]int64),
+		}
+
+		_ = repo.PerformTx(context.Background(), func(txid uint) error { return nil })
+		_ = repo.PerformTx(context.Background(), func(txid uint) error { return assert.AnError })
+
+		assert.Empty(t, repo.txMap)
+		assert.Empty(t, repo.txVersions)
+	})
 }
 
 func TestRedisRepository_TxModifyOrder(t *testing.T) {
@@ -108,8 +149,10 @@ func TestRedisRepository_TxModifyOrder(t *testing.T) {
 
 	t.Run("successfully updates order", func(t *testing.T) {
 
+		expectOrderVersion(mock, mocks.Order)
 		mock.ExpectTxPipeline()
-		mock.ExpectHSet(CreateOrderIDKey(mocks.Order.Id), mocks.Order.OrderToMap()).SetVal(1)
+		mock.ExpectHSet(CreateOrderIDKey(mocks.Order.Id), nextVersion(mocks.Order).OrderToMap()).SetVal(1)
+		expectBookChange(mock, mocks.Order)
 		mock.ExpectTxPipelineExec()
 
 		err := repo.PerformTx(ctx, func(txid uint) error {
@@ -122,6 +165,7 @@ func TestRedisRepository_TxModifyOrder(t *testing.T) {
 
 	t.Run("successfully deletes order", func(t *testing.T) {
 
+		expectOrderVersion(mock, mocks.Order)
 		mock.ExpectTxPipeline()
 		mock.ExpectDel(CreateOrderIDKey(mocks.Order.Id)).SetVal(1)
 		mock.ExpectTxPipelineExec()
@@ -142,6 +186,48 @@ func TestRedisRepository_TxModifyOrder(t *testing.T) {
 
 		assert.ErrorIs(t, err, models.ErrUnsupportedOperation)
 	})
+
+	t.Run("fails when the order was changed since it was read", func(t *testing.T) {
+
+		expectOrderVersion(mock, *nextVersion(mocks.Order))
+
+		err := repo.PerformTx(ctx, func(txid uint) error {
+			return repo.TxModifyOrder(ctx, txid, models.Update, mocks.Order)
+		})
+
+		assert.ErrorIs(t, err, models.ErrVersionConflict)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("fails when the order was removed since it was read", func(t *testing.T) {
+
+		key := CreateOrderIDKey(mocks.Order.Id)
+		mock.ExpectWatch(key)
+		mock.ExpectHMGet(key, "id", "version").SetVal([]interface{}{nil, nil})
+
+		err := repo.PerformTx(ctx, func(txid uint) error {
+			return repo.TxModifyOrder(ctx, txid, models.Update, mocks.Order)
+		})
+
+		assert.ErrorIs(t, err, models.ErrVersionConflict)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("fails when the order is changed before the tx is committed", func(t *testing.T) {
+
+		expectOrderVersion(mock, mocks.Order)
+		mock.ExpectTxPipeline()
+		mock.ExpectHSet(CreateOrderIDKey(mocks.Order.Id), nextVersion(mocks.Order).OrderToMap()).SetVal(1)
+		expectBookChange(mock, mocks.Order)
+		mock.ExpectTxPipelineExec().SetErr(redis.TxFailedErr)
+
+		err := repo.PerformTx(ctx, func(txid uint) error {
+			return repo.TxModifyOrder(ctx, txid, models.Update, mocks.Order)
+		})
+
+		assert.ErrorIs(t, err, models.ErrVersionConflict)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestRedisRepository_TxModifyPrices(t *testing.T) {
@@ -167,6 +253,7 @@ func TestRedisRepository_TxModifyPrices(t *testing.T) {
 			Score:  10.0016969392,
 			Member: buyOrder.Id.String(),
 		}).SetVal(1)
+		expectBookChange(mock, buyOrder)
 		mock.ExpectTxPipelineExec()
 
 		err := repo.PerformTx(ctx, func(txid uint) error {
@@ -194,6 +281,7 @@ func TestRedisRepository_TxModifyPrices(t *testing.T) {
 			Score:  10.0016969392,
 			Member: sellOrder.Id.String(),
 		}).SetVal(1)
+		expectBookChange(mock, sellOrder)
 		mock.ExpectTxPipelineExec()
 
 		err := repo.PerformTx(ctx, func(txid uint) error {
@@ -208,6 +296,7 @@ func TestRedisRepository_TxModifyPrices(t *testing.T) {
 
 		mock.ExpectTxPipeline()
 		mock.ExpectZRem(CreateBuySidePricesKey(mocks.Order.Symbol), mocks.Order.Id.String()).SetVal(1)
+		expectBookChange(mock, mocks.Order)
 		mock.ExpectTxPipelineExec()
 
 		err := repo.PerformTx(ctx, func(txid uint) error {
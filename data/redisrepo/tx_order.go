@@ -2,7 +2,11 @@ package redisrepo
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 
 	"github.com/orbs-network/order-book/models"
 	"github.com/orbs-network/order-book/utils/logger"
@@ -16,21 +20,97 @@ import (
 // Handles the transaction lifecycle.
 // The action function should be a single Redis command or a series of Redis commands that should be executed in a single transaction.
 // See the methods below (eg. TxModifyOrder, TxModifyPrices, etc.)
+// Orders modified in the transaction are only committed at the version they were read, otherwise it fails with ErrVersionConflict.
 func (r *redisRepository) PerformTx(ctx context.Context, action func(txid uint) error) error {
-	txid := r.txStart(ctx)
+	return r.client.Watch(ctx, func(watchTx *redis.Tx) error {
+		txid := r.txStart(ctx, watchTx)
+		// the tx is over once committed or discarded
+		defer r.txDone(txid)
 
-	err := action(txid)
-	if err != nil {
-		logctx.Error(ctx, "PerformTx action failed", logger.Error(err), logger.Int("txid", int(txid)))
-		return fmt.Errorf("PerformTx action failed: %w", err)
+		err := action(txid)
+		if err != nil {
+			logctx.Error(ctx, "PerformTx action failed", logger.Error(err), logger.Int("txid", int(txid)))
+			return fmt.Errorf("PerformTx action failed: %w", err)
+		}
+
+		err = r.txCheckVersions(ctx, watchTx, txid)
+		if err != nil {
+			logctx.Warn(ctx, "PerformTx versions check failed", logger.Error(err), logger.Int("txid", int(txid)))
+			return fmt.Errorf("PerformTx versions check failed: %w", err)
+		}
+
+		err = r.txEnd(ctx, txid)
+		if err != nil {
+			logctx.Error(ctx, "PerformTx txEnd commit failed", logger.Error(err), logger.Int("txid", int(txid)))
+			return fmt.Errorf("PerformTx txEnd commit failed: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// txExpectVersion sets the version the order key must have when the tx is committed, the version it was first read at
+func (r *redisRepository) txExpectVersion(txid uint, key string, version int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.txVersions == nil {
+		r.txVersions = make(map[uint]map[string]int64)
 	}
+	versions, ok := r.txVersions[txid]
+	if !ok {
+		versions = make(map[string]int64)
+		r.txVersions[txid] = versions
+	}
+	if _, ok := versions[key]; !ok {
+		versions[key] = version
+	}
+}
 
-	err = r.txEnd(ctx, txid)
-	if err != nil {
-		logctx.Error(ctx, "PerformTx txEnd commit failed", logger.Error(err), logger.Int("txid", int(txid)))
-		return fmt.Errorf("PerformTx txEnd commit failed: %w", err)
+// txCheckVersions watches the orders modified in the tx until it is committed, and fails with ErrVersionConflict if one of them is no longer at its expected version
+func (r *redisRepository) txCheckVersions(ctx context.Context, watchTx *redis.Tx, txid uint) error {
+	r.mu.Lock()
+	versions := make(map[string]int64, len(r.txVersions[txid]))
+	for key, version := range r.txVersions[txid] {
+		versions[key] = version
 	}
+	r.mu.Unlock()
+	if len(versions) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(versions))
+	for key := range versions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
+	if err := watchTx.Watch(ctx, keys...).Err(); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		values, err := watchTx.HMGet(ctx, key, "id", "version").Result()
+		if err != nil {
+			return err
+		}
+		// removed since it was read
+		if values[0] == nil {
+			logctx.Warn(ctx, "order was removed since it was read", logger.String("key", key))
+			return models.ErrVersionConflict
+		}
+		// orders stored before versions were introduced are at version 0
+		var version int64
+		if versionStr, ok := values[1].(string); ok {
+			if version, err = strconv.ParseInt(versionStr, 10, 64); err != nil {
+				return fmt.Errorf("invalid version of %s: %w", key, err)
+			}
+		}
+		if version != versions[key] {
+			logctx.Warn(ctx, "order was changed since it was read", logger.String("key", key), logger.Int("version", int(version)), logger.Int("expectedVersion", int(versions[key])))
+			return models.ErrVersionConflict
+		}
+	}
 	return nil
 }
 
@@ -38,7 +118,7 @@ func (r *redisRepository) PerformTx(ctx context.Context, action func(txid uint)
 func (r *redisRepository) TxModifyOrder(ctx context.Context, txid uint, operation models.Operation, order models.Order) error {
 	var tx redis.Pipeliner
 	var ok bool
-	if tx, ok = r.txMap[txid]; !ok {
+	if tx, ok = r.getTx(txid); !ok {
 		logctx.Error(ctx, "TxModifyOrder txid not found", logger.Int("txid", int(txid)))
 		return models.ErrNotFound
 	}
@@ -47,6 +127,11 @@ func (r *redisRepository) TxModifyOrder(ctx context.Context, txid uint, operatio
 	case models.Add, models.Update:
 		// Store order details by order ID
 		orderIDKey := CreateOrderIDKey(order.Id)
+		// the order is updated from the version it was read at, to the next one
+		if operation == models.Update {
+			r.txExpectVersion(txid, orderIDKey, order.Version)
+			order.Version++
+		}
 		orderMap := order.OrderToMap()
 		err := tx.HSet(ctx, orderIDKey, orderMap).Err()
 		if err != nil {
@@ -54,8 +139,13 @@ func (r *redisRepository) TxModifyOrder(ctx context.Context, txid uint, operatio
 			return err
 		}
 		logctx.Debug(ctx, "TxModifyOrder add/update", logger.String("orderId", order.Id.String()), logger.String("orderMap", fmt.Sprintf("%v", orderMap)))
+		// filled, locked and amended sizes change the order's price level
+		if operation == models.Update {
+			txPublishBookChange(ctx, tx, order)
+		}
 	case models.Remove:
 		orderIDKey := CreateOrderIDKey(order.Id)
+		r.txExpectVersion(txid, orderIDKey, order.Version)
 		tx.Del(ctx, orderIDKey)
 		logctx.Debug(ctx, "TxModifyOrder remove", logger.String("orderId", order.Id.String()))
 	default:
@@ -71,7 +161,7 @@ func (r *redisRepository) TxModifyOrder(ctx context.Context, txid uint, operatio
 func (r *redisRepository) TxModifyPrices(ctx context.Context, txid uint, operation models.Operation, order models.Order) error {
 	var tx redis.Pipeliner
 	var ok bool
-	if tx, ok = r.txMap[txid]; !ok {
+	if tx, ok = r.getTx(txid); !ok {
 		logctx.Error(ctx, "TxModifyPrices txid not found", logger.Int("txid", int(txid)))
 		return models.ErrNotFound
 	}
@@ -119,6 +209,8 @@ func (r *redisRepository) TxModifyPrices(ctx context.Context, txid uint, operati
 		logctx.Error(ctx, "TxModifyPrices unsupported operation", logger.Int("operation", int(operation)))
 		return models.ErrUnsupportedOperation
 	}
+
+	txPublishBookChange(ctx, tx, order)
 	return nil
 
 }
@@ -127,7 +219,7 @@ func (r *redisRepository) TxModifyPrices(ctx context.Context, txid uint, operati
 func (r *redisRepository) TxModifyClientOId(ctx context.Context, txid uint, operation models.Operation, order models.Order) error {
 	var tx redis.Pipeliner
 	var ok bool
-	if tx, ok = r.txMap[txid]; !ok {
+	if tx, ok = r.getTx(txid); !ok {
 		logctx.Error(ctx, "TxModifyClientOId txid not found", logger.Int("txid", int(txid)))
 		return models.ErrNotFound
 	}
@@ -152,7 +244,7 @@ func (r *redisRepository) TxModifyClientOId(ctx context.Context, txid uint, oper
 func (r *redisRepository) TxModifyUserOpenOrders(ctx context.Context, txid uint, operation models.Operation, order models.Order) error {
 	var tx redis.Pipeliner
 	var ok bool
-	if tx, ok = r.txMap[txid]; !ok {
+	if tx, ok = r.getTx(txid); !ok {
 		logctx.Error(ctx, "TxModifyUserOpenOrders txid not found", logger.Int("txid", int(txid)))
 		return models.ErrNotFound
 	}
@@ -176,6 +268,38 @@ func (r *redisRepository) TxModifyUserOpenOrders(ctx context.Context, txid uint,
 	return nil
 }
 
+// This should be used for all write interactions with the `orders:deadlines` sorted set (used to expire orders by their signed deadline or time in force)
+// Orders which never expire are not indexed
+func (r *redisRepository) TxModifyOrderDeadlines(ctx context.Context, txid uint, operation models.Operation, order models.Order) error {
+	var tx redis.Pipeliner
+	var ok bool
+	if tx, ok = r.getTx(txid); !ok {
+		logctx.Error(ctx, "TxModifyOrderDeadlines txid not found", logger.Int("txid", int(txid)))
+		return models.ErrNotFound
+	}
+
+	deadline := order.ExpiryTime()
+	if deadline.IsZero() {
+		return nil
+	}
+
+	switch operation {
+	case models.Add:
+		tx.ZAdd(ctx, CreateOrderDeadlinesKey(), redis.Z{
+			Score:  float64(deadline.Unix()),
+			Member: order.Id.String(),
+		})
+		logctx.Debug(ctx, "TxModifyOrderDeadlines add", logger.String("orderId", order.Id.String()), logger.String("deadline", deadline.String()))
+	case models.Remove:
+		tx.ZRem(ctx, CreateOrderDeadlinesKey(), order.Id.String())
+		logctx.Debug(ctx, "TxModifyOrderDeadlines remove", logger.String("orderId", order.Id.String()))
+	default:
+		logctx.Error(ctx, "TxModifyOrderDeadlines unsupported operation", logger.Int("operation", int(operation)))
+		return models.ErrUnsupportedOperation
+	}
+	return nil
+}
+
 func (r *redisRepository) TxRemoveOrder(ctx context.Context, txid uint, order models.Order) error {
 	// remove from client OID
 	if err := r.TxModifyClientOId(ctx, txid, models.Remove, order); err != nil {
@@ -187,6 +311,11 @@ func (r *redisRepository) TxRemoveOrder(ctx context.Context, txid uint, order mo
 		logctx.Error(ctx, "Failed removing order from user open orders", logger.String("id", order.Id.String()), logger.String("userId", order.UserId.String()), logger.Error(err))
 		return fmt.Errorf("failed removing order from user open orders: %w", err)
 	}
+	// remove from deadlines
+	if err := r.TxModifyOrderDeadlines(ctx, txid, models.Remove, order); err != nil {
+		logctx.Error(ctx, "Failed removing order from deadlines", logger.String("id", order.Id.String()), logger.Error(err))
+		return fmt.Errorf("failed removing order from deadlines: %w", err)
+	}
 	// remove entirely
 	if err := r.TxModifyOrder(ctx, txid, models.Remove, order); err != nil {
 		logctx.Error(ctx, "Failed remove cancelled order", logger.Error(err), logger.String("orderId", order.Id.String()))
@@ -215,25 +344,48 @@ func (r *redisRepository) TxCloseOrder(ctx context.Context, txid uint, order mod
 		logctx.Error(ctx, "TxCloseOrder Failed removing order from user open orders", logger.String("id", order.Id.String()), logger.String("userId", order.UserId.String()), logger.Error(err))
 		return err
 	}
+	// remove from deadlines
+	if err := r.TxModifyOrderDeadlines(ctx, txid, models.Remove, order); err != nil {
+		logctx.Error(ctx, "TxCloseOrder Failed removing order from deadlines", logger.String("id", order.Id.String()), logger.Error(err))
+		return err
+	}
 	return nil
 }
 
-// Create a new transaction and return the transaction ID
-func (r *redisRepository) txStart(ctx context.Context) uint {
-	tx := r.client.TxPipeline()
+// Create a new transaction on the watching connection and return the transaction ID
+func (r *redisRepository) txStart(ctx context.Context, watchTx *redis.Tx) uint {
+	tx := watchTx.TxPipeline()
+	r.mu.Lock()
 	r.ixIndex += 1
 	txid := r.ixIndex
 	r.txMap[txid] = tx
+	r.mu.Unlock()
 
 	logctx.Debug(ctx, "redisRepository txStart", logger.Int("txid", int(txid)))
 	return txid
 }
 
+// Drop a given transaction, whether it was committed or not
+func (r *redisRepository) txDone(txid uint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.txMap, txid)
+	delete(r.txVersions, txid)
+}
+
+// getTx returns the pipeline of a transaction in progress
+func (r *redisRepository) getTx(txid uint) (redis.Pipeliner, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tx, ok := r.txMap[txid]
+	return tx, ok
+}
+
 // Commit a given transaction
 func (r *redisRepository) txEnd(ctx context.Context, txid uint) error {
 	var tx redis.Pipeliner
 	var ok bool
-	if tx, ok = r.txMap[txid]; !ok {
+	if tx, ok = r.getTx(txid); !ok {
 		logctx.Error(ctx, "txEnd txid not found", logger.Int("txid", int(txid)))
 		return models.ErrNotFound
 	}
@@ -244,6 +396,11 @@ func (r *redisRepository) txEnd(ctx context.Context, txid uint) error {
 		logctx.Debug(ctx, "Command executed in transaction", logger.Int("txid", int(txid)), logger.String("command", cmder.String()))
 	}
 
+	// a watched order was changed after its version was checked
+	if errors.Is(err, redis.TxFailedErr) {
+		logctx.Warn(ctx, "txEnd transaction aborted, watched orders changed", logger.Int("txid", int(txid)))
+		return models.ErrVersionConflict
+	}
 	if err != nil {
 		logctx.Error(ctx, "txEnd transaction exec failed", logger.Error(err), logger.Int("txid", int(txid)))
 		return fmt.Errorf("txEnd transaction exec failed for txId %q: %w", txid, err)
@@ -251,3 +408,18 @@ func (r *redisRepository) txEnd(ctx context.Context, txid uint) error {
 
 	return nil
 }
+
+// txPublishBookChange notifies market data subscribers, once the tx is committed, that a side of the order's book may have changed
+func txPublishBookChange(ctx context.Context, tx redis.Pipeliner, order models.Order) {
+	if order.Symbol == "" {
+		return
+	}
+
+	event, err := json.Marshal(models.MarketEvent{Event: models.MARKET_EVENT_BOOK_CHANGE, Symbol: order.Symbol, Side: order.Side})
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal book change event", logger.Error(err), logger.String("orderId", order.Id.String()))
+		return
+	}
+
+	tx.Publish(ctx, models.CreateMarketEventKey(order.Symbol), string(event))
+}
@@ -0,0 +1,192 @@
+package redisrepo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/redis/go-redis/v9"
+)
+
+var USER_EVENTS_MAX_LEN = os.Getenv("USER_EVENTS_MAX_LEN")
+
+// appendUserEventScript gives the event the next seq of the user and adds it to the stream with the seq as id,
+// so seqs are never skipped and the stream can be read from any seq, then announces it to the waiting readers
+// KEYS[1] - seq key, KEYS[2] - stream key, ARGV[1] - event, ARGV[2] - approximate max stream length,
+// ARGV[3] - appended events channel, ARGV[4] - user ID
+var appendUserEventScript = redis.NewScript(`
+local seq = redis.call('INCR', KEYS[1])
+redis.call('XADD', KEYS[2], 'MAXLEN', '~', ARGV[2], seq .. '-0', 'event', ARGV[1])
+redis.call('PUBLISH', ARGV[3], ARGV[4])
+return seq
+`)
+
+// AppendUserEvent appends the event to the user's events stream and returns its seq
+func (r *redisRepository) AppendUserEvent(ctx context.Context, userId uuid.UUID, event []byte) (int64, error) {
+	keys := []string{CreateUserEventSeqKey(userId), CreateUserEventsKey(userId)}
+	seq, err := appendUserEventScript.Run(ctx, r.client, keys, string(event), getUserEventsMaxLen(), CreateUserEventsAppendedKey(), userId.String()).Int64()
+	if err != nil {
+		logctx.Error(ctx, "failed to append user event", logger.String("userId", userId.String()), logger.Error(err))
+		return 0, fmt.Errorf("failed to append user event: %w", err)
+	}
+	return seq, nil
+}
+
+// GetUserEventSeq returns the seq of the user's last event, 0 if there are none
+func (r *redisRepository) GetUserEventSeq(ctx context.Context, userId uuid.UUID) (int64, error) {
+	seq, err := r.client.Get(ctx, CreateUserEventSeqKey(userId)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		logctx.Error(ctx, "failed to get user event seq", logger.String("userId", userId.String()), logger.Error(err))
+		return 0, fmt.Errorf("failed to get user event seq: %w", err)
+	}
+	return seq, nil
+}
+
+// ReadUserEvents returns up to count of the user's events after afterSeq, oldest first
+// if there are none it waits up to block for new ones, a block of 0 returns at once
+// events trimmed from the stream are skipped, so the first seq returned may be higher than afterSeq+1
+// reads never block on Redis: waiting readers are woken up by the single subscription to appended events,
+// so they do not hold connections of the client pool
+func (r *redisRepository) ReadUserEvents(ctx context.Context, userId uuid.UUID, afterSeq, count int64, block time.Duration) ([]models.UserEvent, error) {
+	if block <= 0 {
+		return r.readUserEvents(ctx, userId, afterSeq, count)
+	}
+
+	timeout := time.NewTimer(block)
+	defer timeout.Stop()
+
+	for {
+		// wait before reading, so an event appended in between still wakes us up
+		appended, err := r.waitUserEvents(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+
+		events, err := r.readUserEvents(ctx, userId, afterSeq, count)
+		if err != nil || len(events) > 0 {
+			return events, err
+		}
+
+		select {
+		case <-appended:
+		case <-timeout.C:
+			return []models.UserEvent{}, nil
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to read user events: %w", ctx.Err())
+		}
+	}
+}
+
+// readUserEvents reads up to count of the user's events after afterSeq without blocking
+func (r *redisRepository) readUserEvents(ctx context.Context, userId uuid.UUID, afterSeq, count int64) ([]models.UserEvent, error) {
+	key := CreateUserEventsKey(userId)
+	streams, err := r.client.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{key, fmt.Sprintf("%d-0", afterSeq)},
+		Count:   count,
+		// go-redis blocks forever on 0
+		Block: -1,
+	}).Result()
+	if err == redis.Nil {
+		return []models.UserEvent{}, nil
+	}
+	if err != nil {
+		logctx.Error(ctx, "failed to read user events", logger.String("userId", userId.String()), logger.Error(err))
+		return nil, fmt.Errorf("failed to read user events: %w", err)
+	}
+
+	events := []models.UserEvent{}
+	for _, stream := range streams {
+		for _, msg := range stream.Messages {
+			seq, err := strconv.ParseInt(strings.SplitN(msg.ID, "-", 2)[0], 10, 64)
+			if err != nil {
+				logctx.Error(ctx, "invalid user event id", logger.String("key", key), logger.String("id", msg.ID), logger.Error(err))
+				continue
+			}
+			data, ok := msg.Values["event"].(string)
+			if !ok {
+				logctx.Error(ctx, "user event without data", logger.String("key", key), logger.String("id", msg.ID))
+				continue
+			}
+			events = append(events, models.UserEvent{Seq: seq, Data: []byte(data)})
+		}
+	}
+	return events, nil
+}
+
+// waitUserEvents returns a channel closed once the next event is appended for the user
+func (r *redisRepository) waitUserEvents(ctx context.Context, userId uuid.UUID) (chan struct{}, error) {
+	if err := r.listenUserEvents(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.userEventsAppended == nil {
+		r.userEventsAppended = make(map[uuid.UUID]chan struct{})
+	}
+	appended, ok := r.userEventsAppended[userId]
+	if !ok {
+		appended = make(chan struct{})
+		r.userEventsAppended[userId] = appended
+	}
+	return appended, nil
+}
+
+// listenUserEvents subscribes once to the appended user events, and wakes up the readers of each user announced
+// a wake-up dropped by a full subscription only delays the readers until their block is over
+func (r *redisRepository) listenUserEvents(ctx context.Context) error {
+	r.listenMu.Lock()
+	defer r.listenMu.Unlock()
+
+	if r.listening {
+		return nil
+	}
+
+	// the subscription outlives the read that started it
+	appendedChan, err := r.SubscribeToEvents(context.Background(), CreateUserEventsAppendedKey())
+	if err != nil {
+		logctx.Error(ctx, "failed to subscribe to appended user events", logger.Error(err))
+		return fmt.Errorf("failed to subscribe to appended user events: %w", err)
+	}
+	r.listening = true
+
+	go func() {
+		for msg := range appendedChan {
+			userId, err := uuid.ParseBytes(msg)
+			if err != nil {
+				continue
+			}
+
+			r.mu.Lock()
+			if appended, ok := r.userEventsAppended[userId]; ok {
+				close(appended)
+				delete(r.userEventsAppended, userId)
+			}
+			r.mu.Unlock()
+		}
+	}()
+	return nil
+}
+
+// getUserEventsMaxLen returns the configurable approximate number of events kept per user
+func getUserEventsMaxLen() int {
+	if USER_EVENTS_MAX_LEN == "" {
+		return 10000
+	}
+	maxLen, err := strconv.Atoi(USER_EVENTS_MAX_LEN)
+	if err != nil || maxLen <= 0 {
+		return 10000
+	}
+	return maxLen
+}
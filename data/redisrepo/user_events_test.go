@@ -0,0 +1,148 @@
+package redisrepo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redismock/v9"
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisRepository_UserEvents(t *testing.T) {
+	ctx := context.Background()
+	userId := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	key := CreateUserEventsKey(userId)
+	seqKey := CreateUserEventSeqKey(userId)
+
+	t.Run("should append event with next seq", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		event := `{"event":"order-changed"}`
+		mock.ExpectEvalSha(appendUserEventScript.Hash(), []string{seqKey, key}, event, 10000, CreateUserEventsAppendedKey(), userId.String()).SetVal(int64(7))
+
+		seq, err := repo.AppendUserEvent(ctx, userId, []byte(event))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), seq)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return error when append fails", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectEvalSha(appendUserEventScript.Hash(), []string{seqKey, key}, "{}", 10000, CreateUserEventsAppendedKey(), userId.String()).SetErr(assert.AnError)
+
+		_, err := repo.AppendUserEvent(ctx, userId, []byte("{}"))
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("should return 0 seq for user without events", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectGet(seqKey).RedisNil()
+
+		seq, err := repo.GetUserEventSeq(ctx, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), seq)
+	})
+
+	t.Run("should return last seq", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectGet(seqKey).SetVal("12")
+
+		seq, err := repo.GetUserEventSeq(ctx, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12), seq)
+	})
+
+	t.Run("should read events after seq", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectXRead(&redis.XReadArgs{Streams: []string{key, "3-0"}, Count: 10, Block: -1}).SetVal([]redis.XStream{{
+			Stream: key,
+			Messages: []redis.XMessage{
+				{ID: "5-0", Values: map[string]interface{}{"event": `{"event":"order-changed"}`}},
+				{ID: "6-0", Values: map[string]interface{}{"event": `{"event":"order-fill"}`}},
+			},
+		}})
+
+		events, err := repo.ReadUserEvents(ctx, userId, 3, 10, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.UserEvent{
+			{Seq: 5, Data: []byte(`{"event":"order-changed"}`)},
+			{Seq: 6, Data: []byte(`{"event":"order-fill"}`)},
+		}, events)
+	})
+
+	t.Run("should return no events without blocking", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectXRead(&redis.XReadArgs{Streams: []string{key, "3-0"}, Count: 10, Block: -1}).RedisNil()
+
+		events, err := repo.ReadUserEvents(ctx, userId, 3, 10, 0)
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("should return error when read fails", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectXRead(&redis.XReadArgs{Streams: []string{key, "3-0"}, Count: 10, Block: -1}).SetErr(assert.AnError)
+
+		_, err := repo.ReadUserEvents(ctx, userId, 3, 10, 0)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestRedisRepository_WaitingUserEventReaders(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), PoolSize: 2, PoolTimeout: time.Second})
+	t.Cleanup(func() { client.Close() })
+
+	repo, err := NewRedisRepository(client)
+	require.NoError(t, err)
+
+	t.Run("should not hold pooled connections while waiting for events", func(t *testing.T) {
+		readers := 10
+		results := make(chan []models.UserEvent, readers)
+		userIds := []uuid.UUID{}
+		for i := 0; i < readers; i++ {
+			userId := uuid.New()
+			userIds = append(userIds, userId)
+			go func() {
+				events, err := repo.ReadUserEvents(ctx, userId, 0, 10, 5*time.Second)
+				assert.NoError(t, err)
+				results <- events
+			}()
+		}
+		time.Sleep(100 * time.Millisecond)
+
+		for _, userId := range userIds {
+			_, err := repo.AppendUserEvent(ctx, userId, []byte(`{"n":1}`))
+			require.NoError(t, err)
+		}
+
+		for i := 0; i < readers; i++ {
+			select {
+			case events := <-results:
+				assert.Len(t, events, 1)
+			case <-time.After(2 * time.Second):
+				t.Fatal("reader was not woken up")
+			}
+		}
+	})
+}
@@ -29,6 +29,11 @@ func CreateClientOIDKey(clientOId uuid.UUID) string {
 	return fmt.Sprintf("clientOId:%s:order", clientOId)
 }
 
+// CreateOrderDeadlinesKey creates a Redis key for the sorted set of open orders by their expiry time
+func CreateOrderDeadlinesKey() string {
+	return "orders:deadlines"
+}
+
 // CreateBuySidePricesKey creates a Redis key for storing the buy side (bid) prices
 func CreateBuySidePricesKey(symbol models.Symbol) string {
 	return fmt.Sprintf("%s:buy:prices", symbol)
@@ -48,6 +53,16 @@ func CreateResolvedSwapKey(swapId uuid.UUID) string {
 	return fmt.Sprintf("swap:resolved:%s", swapId)
 }
 
+// CreateResolvedSwapsKey creates a Redis key for the sorted set of resolved swap IDs by their resolve time
+func CreateResolvedSwapsKey() string {
+	return "swaps:resolved"
+}
+
+// CreateFirmQuotesKey creates a Redis key for the hash of locked firm quotes by their ID
+func CreateFirmQuotesKey() string {
+	return "quotes:firm"
+}
+
 // CreateUserApiKeyKey creates a Redis key for storing the user by their API key
 func CreateUserApiKeyKey(apiKey string) string {
 	return fmt.Sprintf("userApiKey:%s:user", apiKey)
@@ -63,6 +78,71 @@ func CreateUserResolvedSwapsKey(userId uuid.UUID) string {
 	return fmt.Sprintf("userId:%s:resolvedSwaps", userId)
 }
 
+// CreateUserFillsKey creates a Redis key for the stream of a user's fills
+func CreateUserFillsKey(userId uuid.UUID) string {
+	return fmt.Sprintf("userId:%s:fills", userId)
+}
+
+// CreateUserSwapFillsKey creates a Redis key for the list of a user's fills of a swap
+func CreateUserSwapFillsKey(userId, swapId uuid.UUID) string {
+	return fmt.Sprintf("userId:%s:swapFills:%s", userId, swapId)
+}
+
+// CreateTakerFeesKey creates a Redis key for the hash of the taker fees collected per token
+func CreateTakerFeesKey() string {
+	return "fees:taker"
+}
+
+// CreateMakerRebatesKey creates a Redis key for the hash of the maker rebates paid per token
+func CreateMakerRebatesKey() string {
+	return "fees:makerRebates"
+}
+
+// CreateSymbolTradesKey creates a Redis key for the stream of a symbol's public trades
+func CreateSymbolTradesKey(symbol models.Symbol) string {
+	return fmt.Sprintf("%s:trades", symbol)
+}
+
+// CreateUserEventsKey creates a Redis key for the stream of a user's order events, with the event seq as entry id
+func CreateUserEventsKey(userId uuid.UUID) string {
+	return fmt.Sprintf("userId:%s:events", userId)
+}
+
+// CreateUserEventSeqKey creates a Redis key for the last seq given to a user's order event
+func CreateUserEventSeqKey(userId uuid.UUID) string {
+	return fmt.Sprintf("userId:%s:eventSeq", userId)
+}
+
+// CreateUserEventsAppendedKey creates a Redis channel announcing the IDs of the users an event was appended for
+func CreateUserEventsAppendedKey() string {
+	return "userEvents:appended"
+}
+
+// CreateWebhooksKey creates a Redis key for the hash of all webhooks by their ID
+func CreateWebhooksKey() string {
+	return "webhooks"
+}
+
+// CreateUserWebhooksKey creates a Redis key for the set of a user's webhook IDs
+func CreateUserWebhooksKey(userId uuid.UUID) string {
+	return fmt.Sprintf("userId:%s:webhooks", userId)
+}
+
+// CreateWebhookDeliveriesKey creates a Redis key for the stream of a webhook's delivery attempts
+func CreateWebhookDeliveriesKey(webhookId uuid.UUID) string {
+	return fmt.Sprintf("webhook:%s:deliveries", webhookId)
+}
+
+// CreateWebhookLeaseKey creates a Redis key held by the instance delivering to a webhook
+func CreateWebhookLeaseKey(webhookId uuid.UUID) string {
+	return fmt.Sprintf("webhook:%s:lease", webhookId)
+}
+
+// CreateCandlesKey creates a Redis key for the sorted set of a symbol's candles of an interval, scored by their start time
+func CreateCandlesKey(symbol models.Symbol, interval models.CandleInterval) string {
+	return fmt.Sprintf("%s:candles:%s", symbol, interval)
+}
+
 // GENERIC store funcs
 func AddVal2Set(ctx context.Context, client redis.Cmdable, key, val string) error {
 	added, err := client.SAdd(ctx, key, val).Result()
@@ -0,0 +1,224 @@
+package redisrepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/redis/go-redis/v9"
+)
+
+// delivery attempts kept per webhook
+const webhookDeliveriesMaxLen = 1000
+
+// updateWebhookScript sets the webhook only if it still exists, so a webhook removed during a delivery stays removed
+// KEYS[1] - webhooks key, ARGV[1] - webhook ID, ARGV[2] - webhook
+var updateWebhookScript = redis.NewScript(`
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+return 1
+`)
+
+// releaseLeaseScript deletes the lease only if it is still held by the owner
+// KEYS[1] - lease key, ARGV[1] - owner
+var releaseLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// StoreWebhook adds a new webhook of its user
+func (r *redisRepository) StoreWebhook(ctx context.Context, webhook models.Webhook) error {
+	data, err := json.Marshal(webhook)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal webhook", logger.String("webhookId", webhook.Id.String()), logger.Error(err))
+		return err
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, CreateWebhooksKey(), webhook.Id.String(), string(data))
+		pipe.SAdd(ctx, CreateUserWebhooksKey(webhook.UserId), webhook.Id.String())
+		return nil
+	})
+	if err != nil {
+		logctx.Error(ctx, "failed to store webhook", logger.String("webhookId", webhook.Id.String()), logger.Error(err))
+		return fmt.Errorf("failed to store webhook: %w", err)
+	}
+	return nil
+}
+
+// UpdateWebhook updates an existing webhook, returns ErrNotFound if it was removed
+func (r *redisRepository) UpdateWebhook(ctx context.Context, webhook models.Webhook) error {
+	data, err := json.Marshal(webhook)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal webhook", logger.String("webhookId", webhook.Id.String()), logger.Error(err))
+		return err
+	}
+
+	updated, err := updateWebhookScript.Run(ctx, r.client, []string{CreateWebhooksKey()}, webhook.Id.String(), string(data)).Int()
+	if err != nil {
+		logctx.Error(ctx, "failed to update webhook", logger.String("webhookId", webhook.Id.String()), logger.Error(err))
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	if updated == 0 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
+// RemoveWebhook removes the webhook and its delivery log
+func (r *redisRepository) RemoveWebhook(ctx context.Context, webhook models.Webhook) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, CreateWebhooksKey(), webhook.Id.String())
+		pipe.SRem(ctx, CreateUserWebhooksKey(webhook.UserId), webhook.Id.String())
+		pipe.Del(ctx, CreateWebhookDeliveriesKey(webhook.Id))
+		return nil
+	})
+	if err != nil {
+		logctx.Error(ctx, "failed to remove webhook", logger.String("webhookId", webhook.Id.String()), logger.Error(err))
+		return fmt.Errorf("failed to remove webhook: %w", err)
+	}
+	return nil
+}
+
+// GetWebhook returns the webhook, or ErrNotFound
+func (r *redisRepository) GetWebhook(ctx context.Context, webhookId uuid.UUID) (*models.Webhook, error) {
+	data, err := r.client.HGet(ctx, CreateWebhooksKey(), webhookId.String()).Result()
+	if err == redis.Nil {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logctx.Error(ctx, "failed to get webhook", logger.String("webhookId", webhookId.String()), logger.Error(err))
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	var webhook models.Webhook
+	if err := json.Unmarshal([]byte(data), &webhook); err != nil {
+		logctx.Error(ctx, "failed to unmarshal webhook", logger.String("webhookId", webhookId.String()), logger.Error(err))
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// GetUserWebhooks returns the webhooks of the user
+func (r *redisRepository) GetUserWebhooks(ctx context.Context, userId uuid.UUID) ([]models.Webhook, error) {
+	ids, err := r.client.SMembers(ctx, CreateUserWebhooksKey(userId)).Result()
+	if err != nil {
+		logctx.Error(ctx, "failed to get user webhook ids", logger.String("userId", userId.String()), logger.Error(err))
+		return nil, fmt.Errorf("failed to get user webhooks: %w", err)
+	}
+	if len(ids) == 0 {
+		return []models.Webhook{}, nil
+	}
+
+	values, err := r.client.HMGet(ctx, CreateWebhooksKey(), ids...).Result()
+	if err != nil {
+		logctx.Error(ctx, "failed to get user webhooks", logger.String("userId", userId.String()), logger.Error(err))
+		return nil, fmt.Errorf("failed to get user webhooks: %w", err)
+	}
+	return unmarshalWebhooks(ctx, values), nil
+}
+
+// GetWebhooks returns the webhooks of all users
+func (r *redisRepository) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	values, err := r.client.HVals(ctx, CreateWebhooksKey()).Result()
+	if err != nil {
+		logctx.Error(ctx, "failed to get webhooks", logger.Error(err))
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	res := make([]interface{}, len(values))
+	for i, value := range values {
+		res[i] = value
+	}
+	return unmarshalWebhooks(ctx, res), nil
+}
+
+// unmarshalWebhooks skips missing and invalid webhooks
+func unmarshalWebhooks(ctx context.Context, values []interface{}) []models.Webhook {
+	webhooks := []models.Webhook{}
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var webhook models.Webhook
+		if err := json.Unmarshal([]byte(data), &webhook); err != nil {
+			logctx.Error(ctx, "failed to unmarshal webhook", logger.Error(err))
+			continue
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks
+}
+
+// AcquireWebhookLease returns true if the owner now holds the webhook's lease for ttl
+func (r *redisRepository) AcquireWebhookLease(ctx context.Context, webhookId uuid.UUID, owner string, ttl time.Duration) (bool, error) {
+	acquired, err := r.client.SetNX(ctx, CreateWebhookLeaseKey(webhookId), owner, ttl).Result()
+	if err != nil {
+		logctx.Error(ctx, "failed to acquire webhook lease", logger.String("webhookId", webhookId.String()), logger.Error(err))
+		return false, fmt.Errorf("failed to acquire webhook lease: %w", err)
+	}
+	return acquired, nil
+}
+
+// ReleaseWebhookLease releases the webhook's lease if it is still held by the owner
+func (r *redisRepository) ReleaseWebhookLease(ctx context.Context, webhookId uuid.UUID, owner string) error {
+	if err := releaseLeaseScript.Run(ctx, r.client, []string{CreateWebhookLeaseKey(webhookId)}, owner).Err(); err != nil {
+		logctx.Error(ctx, "failed to release webhook lease", logger.String("webhookId", webhookId.String()), logger.Error(err))
+		return fmt.Errorf("failed to release webhook lease: %w", err)
+	}
+	return nil
+}
+
+// StoreWebhookDelivery appends the delivery attempt to the webhook's delivery log, which keeps the latest attempts only
+func (r *redisRepository) StoreWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal webhook delivery", logger.String("webhookId", delivery.WebhookId.String()), logger.Error(err))
+		return err
+	}
+
+	if err := r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: CreateWebhookDeliveriesKey(delivery.WebhookId),
+		MaxLen: webhookDeliveriesMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"delivery": string(data)},
+	}).Err(); err != nil {
+		logctx.Error(ctx, "failed to store webhook delivery", logger.String("webhookId", delivery.WebhookId.String()), logger.Error(err))
+		return fmt.Errorf("failed to store webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookDeliveries returns the latest delivery attempts of the webhook, newest first
+func (r *redisRepository) GetWebhookDeliveries(ctx context.Context, webhookId uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	msgs, err := r.client.XRevRangeN(ctx, CreateWebhookDeliveriesKey(webhookId), "+", "-", int64(limit)).Result()
+	if err != nil {
+		logctx.Error(ctx, "failed to get webhook deliveries", logger.String("webhookId", webhookId.String()), logger.Error(err))
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	deliveries := []models.WebhookDelivery{}
+	for _, msg := range msgs {
+		data, ok := msg.Values["delivery"].(string)
+		if !ok {
+			continue
+		}
+		var delivery models.WebhookDelivery
+		if err := json.Unmarshal([]byte(data), &delivery); err != nil {
+			logctx.Error(ctx, "failed to unmarshal webhook delivery", logger.String("webhookId", webhookId.String()), logger.Error(err))
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,118 @@
+package redisrepo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisRepository_Webhooks(t *testing.T) {
+	ctx := context.Background()
+	webhook := models.Webhook{
+		Id:      uuid.MustParse("00000000-0000-0000-0000-000000000011"),
+		UserId:  uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		Url:     "https://example.com/hook",
+		Secret:  "secret",
+		Created: time.UnixMilli(1000).UTC(),
+	}
+	data, _ := json.Marshal(webhook)
+
+	t.Run("should store webhook with its user", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectTxPipeline()
+		mock.ExpectHSet(CreateWebhooksKey(), webhook.Id.String(), string(data)).SetVal(1)
+		mock.ExpectSAdd(CreateUserWebhooksKey(webhook.UserId), webhook.Id.String()).SetVal(1)
+		mock.ExpectTxPipelineExec()
+
+		assert.NoError(t, repo.StoreWebhook(ctx, webhook))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("should return not found when updating a removed webhook", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectEvalSha(updateWebhookScript.Hash(), []string{CreateWebhooksKey()}, webhook.Id.String(), string(data)).SetVal(int64(0))
+
+		assert.ErrorIs(t, repo.UpdateWebhook(ctx, webhook), models.ErrNotFound)
+	})
+
+	t.Run("should update existing webhook", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectEvalSha(updateWebhookScript.Hash(), []string{CreateWebhooksKey()}, webhook.Id.String(), string(data)).SetVal(int64(1))
+
+		assert.NoError(t, repo.UpdateWebhook(ctx, webhook))
+	})
+
+	t.Run("should return not found for unknown webhook", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectHGet(CreateWebhooksKey(), webhook.Id.String()).RedisNil()
+
+		_, err := repo.GetWebhook(ctx, webhook.Id)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should get user webhooks", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectSMembers(CreateUserWebhooksKey(webhook.UserId)).SetVal([]string{webhook.Id.String(), "removed"})
+		mock.ExpectHMGet(CreateWebhooksKey(), webhook.Id.String(), "removed").SetVal([]interface{}{string(data), nil})
+
+		webhooks, err := repo.GetUserWebhooks(ctx, webhook.UserId)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Webhook{webhook}, webhooks)
+	})
+
+	t.Run("should get all webhooks", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectHVals(CreateWebhooksKey()).SetVal([]string{string(data), "invalid"})
+
+		webhooks, err := repo.GetWebhooks(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Webhook{webhook}, webhooks)
+	})
+
+	t.Run("should acquire lease only if not held", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		mock.ExpectSetNX(CreateWebhookLeaseKey(webhook.Id), "owner", time.Minute).SetVal(false)
+
+		acquired, err := repo.AcquireWebhookLease(ctx, webhook.Id, "owner", time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, acquired)
+	})
+
+	t.Run("should store and read deliveries newest first", func(t *testing.T) {
+		db, mock := redismock.NewClientMock()
+		repo := &redisRepository{client: db}
+
+		delivery := models.WebhookDelivery{Id: uuid.New(), WebhookId: webhook.Id, Seq: 3, Event: "order-fill", Attempt: 1, Success: true, StatusCode: 200, Timestamp: time.UnixMilli(2000).UTC()}
+		deliveryData, _ := json.Marshal(delivery)
+		key := CreateWebhookDeliveriesKey(webhook.Id)
+
+		mock.ExpectXAdd(&redis.XAddArgs{Stream: key, MaxLen: webhookDeliveriesMaxLen, Approx: true, Values: map[string]interface{}{"delivery": string(deliveryData)}}).SetVal("1-0")
+		mock.ExpectXRevRangeN(key, "+", "-", 10).SetVal([]redis.XMessage{{ID: "1-0", Values: map[string]interface{}{"delivery": string(deliveryData)}}})
+
+		assert.NoError(t, repo.StoreWebhookDelivery(ctx, delivery))
+		deliveries, err := repo.GetWebhookDeliveries(ctx, webhook.Id, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.WebhookDelivery{delivery}, deliveries)
+	})
+}
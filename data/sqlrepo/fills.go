@@ -0,0 +1,165 @@
+package sqlrepo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// fills returned by a page of GetUserFills without a limit
+const historyBatchSize = 100
+
+// ArchiveFills stores the fills of the user, skipping ones already archived
+func (r *sqlRepository) ArchiveFills(ctx context.Context, userId uuid.UUID, fills []models.Fill) error {
+	if len(fills) == 0 {
+		return nil
+	}
+
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		for _, fill := range fills {
+			data, err := json.Marshal(fill)
+			if err != nil {
+				logctx.Error(ctx, "failed to marshal fill", logger.String("orderId", fill.OrderId.String()), logger.Error(err))
+				return err
+			}
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO archived_fills (swap_id, order_id, user_id, symbol, side, resolved, data)
+				VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (swap_id, order_id) DO NOTHING`,
+				fill.SwapId.String(), fill.OrderId.String(), userId.String(), fill.Symbol.String(),
+				fill.Side.String(), fill.Resolved.UnixMilli(), string(data),
+			)
+			if err != nil {
+				logctx.Error(ctx, "failed to archive fill", logger.String("swapId", fill.SwapId.String()), logger.Error(err))
+				return fmt.Errorf("failed to archive fill: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetSwapFills returns the archived fills of the user's orders in the swap
+func (r *sqlRepository) GetSwapFills(ctx context.Context, userId uuid.UUID, swapId uuid.UUID) ([]models.Fill, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT data FROM archived_fills WHERE swap_id = $1 AND user_id = $2 ORDER BY order_id`,
+		swapId.String(), userId.String(),
+	)
+	if err != nil {
+		logctx.Error(ctx, "failed to get archived swap fills", logger.String("swapId", swapId.String()), logger.Error(err))
+		return nil, fmt.Errorf("failed to get archived swap fills: %w", err)
+	}
+	return scanFills(ctx, rows)
+}
+
+// GetUserFills returns a page of the user's archived fills, newest first, and the cursor of the next page
+// the cursor is the resolve time, swap ID and order ID of the last fill, empty when there are no more fills
+func (r *sqlRepository) GetUserFills(ctx context.Context, userId uuid.UUID, filter models.HistoryFilter) ([]models.Fill, string, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = historyBatchSize
+	}
+
+	conds := []string{"user_id = $1"}
+	args := []interface{}{userId.String()}
+	if filter.Symbol != "" {
+		args = append(args, filter.Symbol.String())
+		conds = append(conds, fmt.Sprintf("symbol = $%d", len(args)))
+	}
+	if filter.Side != "" {
+		args = append(args, filter.Side.String())
+		conds = append(conds, fmt.Sprintf("side = $%d", len(args)))
+	}
+	if !filter.StartAt.IsZero() {
+		args = append(args, filter.StartAt.UnixMilli())
+		conds = append(conds, fmt.Sprintf("resolved >= $%d", len(args)))
+	}
+	if !filter.EndAt.IsZero() {
+		args = append(args, filter.EndAt.UnixMilli())
+		conds = append(conds, fmt.Sprintf("resolved < $%d", len(args)))
+	}
+	if filter.Cursor != "" {
+		resolved, swapId, orderId, err := parseCursor(filter.Cursor)
+		if err != nil {
+			logctx.Warn(ctx, "invalid archived fills cursor", logger.String("cursor", filter.Cursor), logger.Error(err))
+			return nil, "", models.ErrInvalidInput
+		}
+		n := len(args)
+		args = append(args, resolved, swapId, orderId)
+		conds = append(conds, fmt.Sprintf(
+			"(resolved < $%d OR (resolved = $%d AND (swap_id < $%d OR (swap_id = $%d AND order_id < $%d))))",
+			n+1, n+1, n+2, n+2, n+3,
+		))
+	}
+	args = append(args, filter.Limit)
+
+	rows, err := r.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT data FROM archived_fills WHERE %s ORDER BY resolved DESC, swap_id DESC, order_id DESC LIMIT $%d`,
+			strings.Join(conds, " AND "), len(args)),
+		args...,
+	)
+	if err != nil {
+		logctx.Error(ctx, "failed to get archived user fills", logger.String("userId", userId.String()), logger.Error(err))
+		return nil, "", fmt.Errorf("failed to get archived user fills: %w", err)
+	}
+	fills, err := scanFills(ctx, rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cursor := ""
+	if len(fills) == filter.Limit {
+		last := fills[len(fills)-1]
+		cursor = fmt.Sprintf("%d:%s:%s", last.Resolved.UnixMilli(), last.SwapId, last.OrderId)
+	}
+	return fills, cursor, nil
+}
+
+func parseCursor(cursor string) (int64, string, string, error) {
+	parts := strings.Split(cursor, ":")
+	if len(parts) != 3 {
+		return 0, "", "", fmt.Errorf("expected 3 parts, got %d", len(parts))
+	}
+	resolved, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, "", "", err
+	}
+	swapId, err := uuid.Parse(parts[1])
+	if err != nil {
+		return 0, "", "", err
+	}
+	orderId, err := uuid.Parse(parts[2])
+	if err != nil {
+		return 0, "", "", err
+	}
+	return resolved, swapId.String(), orderId.String(), nil
+}
+
+func scanFills(ctx context.Context, rows *sql.Rows) ([]models.Fill, error) {
+	defer rows.Close()
+
+	fills := []models.Fill{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			logctx.Error(ctx, "failed to scan archived fill", logger.Error(err))
+			return nil, fmt.Errorf("failed to scan archived fill: %w", err)
+		}
+		var fill models.Fill
+		if err := json.Unmarshal([]byte(data), &fill); err != nil {
+			logctx.Error(ctx, "failed to unmarshal archived fill", logger.Error(err))
+			return nil, models.ErrMarshalError
+		}
+		fills = append(fills, fill)
+	}
+	if err := rows.Err(); err != nil {
+		logctx.Error(ctx, "failed to read archived fills", logger.Error(err))
+		return nil, fmt.Errorf("failed to read archived fills: %w", err)
+	}
+	return fills, nil
+}
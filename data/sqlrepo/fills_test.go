@@ -0,0 +1,86 @@
+package sqlrepo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSqlRepository_ArchiveFills(t *testing.T) {
+	resolved := time.Now().UTC().Truncate(time.Millisecond)
+	fill := func(swapId uuid.UUID, side models.Side, age time.Duration) models.Fill {
+		return models.Fill{OrderId: uuid.New(), SwapId: swapId, Symbol: "MATIC-USDC", Side: side, Resolved: resolved.Add(-age)}
+	}
+
+	t.Run("should get the fills of a user in a swap", func(t *testing.T) {
+		repo := newTestRepo(t)
+		userId, swapId := uuid.New(), uuid.New()
+		fills := []models.Fill{fill(swapId, models.BUY, 0), fill(swapId, models.BUY, 0)}
+		require.NoError(t, repo.ArchiveFills(ctx, userId, fills))
+		require.NoError(t, repo.ArchiveFills(ctx, userId, fills))
+		require.NoError(t, repo.ArchiveFills(ctx, uuid.New(), []models.Fill{fill(swapId, models.SELL, 0)}))
+
+		archived, err := repo.GetSwapFills(ctx, userId, swapId)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{fills[0].OrderId, fills[1].OrderId}, fillOrderIds(archived))
+
+		archived, err = repo.GetSwapFills(ctx, userId, uuid.New())
+		assert.NoError(t, err)
+		assert.Empty(t, archived)
+	})
+
+	t.Run("should page the fills of a user newest first", func(t *testing.T) {
+		repo := newTestRepo(t)
+		userId := uuid.New()
+		fills := []models.Fill{}
+		for i := 0; i < 5; i++ {
+			side := models.BUY
+			if i == 2 {
+				side = models.SELL
+			}
+			fills = append(fills, fill(uuid.New(), side, time.Duration(5-i)*time.Minute))
+		}
+		require.NoError(t, repo.ArchiveFills(ctx, userId, fills))
+
+		page, cursor, err := repo.GetUserFills(ctx, userId, models.HistoryFilter{Limit: 3})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fills[4].OrderId, fills[3].OrderId, fills[2].OrderId}, fillOrderIds(page))
+		assert.NotEmpty(t, cursor)
+
+		page, cursor, err = repo.GetUserFills(ctx, userId, models.HistoryFilter{Limit: 3, Cursor: cursor})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fills[1].OrderId, fills[0].OrderId}, fillOrderIds(page))
+		assert.Empty(t, cursor)
+
+		page, _, err = repo.GetUserFills(ctx, userId, models.HistoryFilter{Side: models.BUY, EndAt: resolved.Add(-time.Minute)})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fills[3].OrderId, fills[1].OrderId, fills[0].OrderId}, fillOrderIds(page))
+
+		page, _, err = repo.GetUserFills(ctx, userId, models.HistoryFilter{StartAt: resolved.Add(-time.Minute)})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fills[4].OrderId}, fillOrderIds(page))
+
+		page, _, err = repo.GetUserFills(ctx, userId, models.HistoryFilter{Symbol: "BTC-USDC"})
+		assert.NoError(t, err)
+		assert.Empty(t, page)
+	})
+
+	t.Run("should reject an invalid cursor", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		_, _, err := repo.GetUserFills(ctx, uuid.New(), models.HistoryFilter{Cursor: "1-0"})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+}
+
+func fillOrderIds(fills []models.Fill) []uuid.UUID {
+	ids := make([]uuid.UUID, len(fills))
+	for i, fill := range fills {
+		ids[i] = fill.OrderId
+	}
+	return ids
+}
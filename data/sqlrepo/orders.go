@@ -0,0 +1,90 @@
+package sqlrepo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// ArchiveOrders stores the closed orders, as the fields they were stored with, skipping ones already archived
+func (r *sqlRepository) ArchiveOrders(ctx context.Context, orders []models.Order, closedAt time.Time) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		for _, order := range orders {
+			data, err := json.Marshal(order.OrderToMap())
+			if err != nil {
+				logctx.Error(ctx, "failed to marshal order", logger.String("orderId", order.Id.String()), logger.Error(err))
+				return err
+			}
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO archived_orders (id, client_oid, user_id, symbol, created, closed, data)
+				VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (id) DO NOTHING`,
+				order.Id.String(), order.ClientOId.String(), order.UserId.String(), order.Symbol.String(),
+				order.Timestamp.UnixMilli(), closedAt.UnixMilli(), string(data),
+			)
+			if err != nil {
+				logctx.Error(ctx, "failed to archive order", logger.String("orderId", order.Id.String()), logger.Error(err))
+				return fmt.Errorf("failed to archive order: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// FindOrderById finds an archived order by its ID or clientOId. If `isClientOId` is true, the `id` is treated as a clientOId, otherwise it is treated as an orderId.
+func (r *sqlRepository) FindOrderById(ctx context.Context, id uuid.UUID, isClientOId bool) (*models.Order, error) {
+	query := `SELECT data FROM archived_orders WHERE id = $1`
+	if isClientOId {
+		query = `SELECT data FROM archived_orders WHERE client_oid = $1`
+	}
+
+	var data string
+	err := r.db.QueryRowContext(ctx, query, id.String()).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logctx.Error(ctx, "failed to find archived order", logger.String("id", id.String()), logger.Error(err))
+		return nil, fmt.Errorf("failed to find archived order: %w", err)
+	}
+
+	orderMap := map[string]string{}
+	if err := json.Unmarshal([]byte(data), &orderMap); err != nil {
+		logctx.Error(ctx, "failed to unmarshal archived order", logger.String("id", id.String()), logger.Error(err))
+		return nil, models.ErrMarshalError
+	}
+	order := &models.Order{}
+	if err := order.MapToOrder(orderMap); err != nil {
+		logctx.Error(ctx, "could not map archived order", logger.String("id", id.String()), logger.Error(err))
+		return nil, err
+	}
+	return order, nil
+}
+
+// inTx runs fn in a transaction, which is committed if fn succeeds and rolled back otherwise
+func (r *sqlRepository) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logctx.Error(ctx, "failed to begin archive transaction", logger.Error(err))
+		return fmt.Errorf("failed to begin archive transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		logctx.Error(ctx, "failed to commit archive transaction", logger.Error(err))
+		return fmt.Errorf("failed to commit archive transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package sqlrepo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSqlRepository_ArchiveOrders(t *testing.T) {
+	order := models.Order{
+		Id:          uuid.New(),
+		ClientOId:   uuid.New(),
+		UserId:      uuid.New(),
+		Price:       decimal.RequireFromString("1.5"),
+		Size:        decimal.NewFromInt(10),
+		SizePending: decimal.NewFromInt(0),
+		SizeFilled:  decimal.NewFromInt(10),
+		Symbol:      "MATIC-USDC",
+		Side:        models.SELL,
+		Timestamp:   time.Now().UTC().Truncate(time.Second),
+	}
+
+	t.Run("should find an archived order by ID and clientOId", func(t *testing.T) {
+		repo := newTestRepo(t)
+		require.NoError(t, repo.ArchiveOrders(ctx, []models.Order{order}, time.Now()))
+
+		found, err := repo.FindOrderById(ctx, order.Id, false)
+		assert.NoError(t, err)
+		assert.Equal(t, order.Id, found.Id)
+		assert.Equal(t, "10", found.SizeFilled.String())
+		assert.True(t, order.Timestamp.Equal(found.Timestamp))
+
+		found, err = repo.FindOrderById(ctx, order.ClientOId, true)
+		assert.NoError(t, err)
+		assert.Equal(t, order.Id, found.Id)
+	})
+
+	t.Run("should skip orders already archived", func(t *testing.T) {
+		repo := newTestRepo(t)
+		require.NoError(t, repo.ArchiveOrders(ctx, []models.Order{order}, time.Now()))
+
+		changed := order
+		changed.SizeFilled = decimal.NewFromInt(1)
+		assert.NoError(t, repo.ArchiveOrders(ctx, []models.Order{changed}, time.Now()))
+
+		found, err := repo.FindOrderById(ctx, order.Id, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "10", found.SizeFilled.String())
+	})
+
+	t.Run("should return ErrNotFound for an order not archived", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		_, err := repo.FindOrderById(ctx, uuid.New(), false)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = repo.FindOrderById(ctx, uuid.New(), true)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+}
@@ -0,0 +1,89 @@
+package sqlrepo
+
+import (
+	"database/sql"
+	"fmt"
+
+	// drivers of the supported archive databases
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// schema of the archive, each record keeps its full JSON next to the columns it is looked up by
+// times are unix milliseconds, so the same statements run on SQLite and Postgres
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS archived_orders (
+		id TEXT PRIMARY KEY,
+		client_oid TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		symbol TEXT NOT NULL,
+		created BIGINT NOT NULL,
+		closed BIGINT NOT NULL,
+		data TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS archived_orders_client_oid ON archived_orders (client_oid)`,
+	`CREATE INDEX IF NOT EXISTS archived_orders_user_created ON archived_orders (user_id, created)`,
+	`CREATE INDEX IF NOT EXISTS archived_orders_symbol_created ON archived_orders (symbol, created)`,
+	`CREATE TABLE IF NOT EXISTS archived_swaps (
+		id TEXT PRIMARY KEY,
+		symbol TEXT NOT NULL,
+		created BIGINT NOT NULL,
+		resolved BIGINT NOT NULL,
+		data TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS archived_swaps_symbol_resolved ON archived_swaps (symbol, resolved)`,
+	`CREATE INDEX IF NOT EXISTS archived_swaps_resolved ON archived_swaps (resolved)`,
+	`CREATE TABLE IF NOT EXISTS archived_fills (
+		swap_id TEXT NOT NULL,
+		order_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		symbol TEXT NOT NULL,
+		side TEXT NOT NULL,
+		resolved BIGINT NOT NULL,
+		data TEXT NOT NULL,
+		PRIMARY KEY (swap_id, order_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS archived_fills_user_resolved ON archived_fills (user_id, resolved)`,
+	`CREATE INDEX IF NOT EXISTS archived_fills_symbol_resolved ON archived_fills (symbol, resolved)`,
+}
+
+type sqlRepository struct {
+	db *sql.DB
+}
+
+// Open opens the archive database of the driver, "sqlite" or "postgres"
+func Open(driver, dsn string) (*sql.DB, error) {
+	switch driver {
+	case "sqlite", "postgres":
+	default:
+		return nil, fmt.Errorf("unsupported archive driver %q, use sqlite or postgres", driver)
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive database: %w", err)
+	}
+	if driver == "sqlite" {
+		// SQLite allows a single writer
+		db.SetMaxOpenConns(1)
+	}
+	return db, nil
+}
+
+// NewSqlRepository creates the archive tables and indexes if they don't exist yet
+func NewSqlRepository(db *sql.DB) (*sqlRepository, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db cannot be nil")
+	}
+
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, fmt.Errorf("failed to create archive schema: %w", err)
+		}
+	}
+	return &sqlRepository{db: db}, nil
+}
+
+func (r *sqlRepository) Close() error {
+	return r.db.Close()
+}
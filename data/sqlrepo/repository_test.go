@@ -0,0 +1,33 @@
+package sqlrepo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var ctx = context.Background()
+
+func newTestRepo(t *testing.T) *sqlRepository {
+	db, err := Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	repo, err := NewSqlRepository(db)
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func TestSqlRepository_Open(t *testing.T) {
+
+	t.Run("should reject an unsupported driver", func(t *testing.T) {
+		_, err := Open("mysql", "")
+		require.Error(t, err)
+	})
+
+	t.Run("should create the schema only once", func(t *testing.T) {
+		repo := newTestRepo(t)
+		_, err := NewSqlRepository(repo.db)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,53 @@
+package sqlrepo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// ArchiveSwap stores the resolved swap, unless it was already archived
+func (r *sqlRepository) ArchiveSwap(ctx context.Context, swap models.Swap) error {
+	data, err := json.Marshal(swap)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal swap", logger.String("swapId", swap.Id.String()), logger.Error(err))
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx,
+		`INSERT INTO archived_swaps (id, symbol, created, resolved, data)
+		VALUES ($1, $2, $3, $4, $5) ON CONFLICT (id) DO NOTHING`,
+		swap.Id.String(), swap.Symbol, swap.Created.UnixMilli(), swap.Resolved.UnixMilli(), string(data),
+	)
+	if err != nil {
+		logctx.Error(ctx, "failed to archive swap", logger.String("swapId", swap.Id.String()), logger.Error(err))
+		return fmt.Errorf("failed to archive swap: %w", err)
+	}
+	return nil
+}
+
+// GetSwap returns an archived swap, or ErrNotFound
+func (r *sqlRepository) GetSwap(ctx context.Context, swapId uuid.UUID) (*models.Swap, error) {
+	var data string
+	err := r.db.QueryRowContext(ctx, `SELECT data FROM archived_swaps WHERE id = $1`, swapId.String()).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logctx.Error(ctx, "failed to get archived swap", logger.String("swapId", swapId.String()), logger.Error(err))
+		return nil, fmt.Errorf("failed to get archived swap: %w", err)
+	}
+
+	var swap models.Swap
+	if err := json.Unmarshal([]byte(data), &swap); err != nil {
+		logctx.Error(ctx, "failed to unmarshal archived swap", logger.String("swapId", swapId.String()), logger.Error(err))
+		return nil, models.ErrMarshalError
+	}
+	return &swap, nil
+}
@@ -0,0 +1,43 @@
+package sqlrepo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSqlRepository_ArchiveSwap(t *testing.T) {
+
+	t.Run("should get an archived swap", func(t *testing.T) {
+		repo := newTestRepo(t)
+		swap := models.Swap{
+			Id:        uuid.New(),
+			Symbol:    "MATIC-USDC",
+			Created:   time.Now().Add(-time.Minute).UTC(),
+			Resolved:  time.Now().UTC(),
+			Succeeded: true,
+			Frags:     []models.OrderFrag{{OrderId: uuid.New(), OutSize: decimal.NewFromInt(2)}},
+		}
+		require.NoError(t, repo.ArchiveSwap(ctx, swap))
+		assert.NoError(t, repo.ArchiveSwap(ctx, swap))
+
+		archived, err := repo.GetSwap(ctx, swap.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, swap.Id, archived.Id)
+		assert.True(t, archived.Succeeded)
+		require.Len(t, archived.Frags, 1)
+		assert.Equal(t, "2", archived.Frags[0].OutSize.String())
+	})
+
+	t.Run("should return ErrNotFound for a swap not archived", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		_, err := repo.GetSwap(ctx, uuid.New())
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/orbs-network/order-book/models"
@@ -22,10 +23,12 @@ type OrderBookStore interface {
 	FindOrderById(ctx context.Context, id uuid.UUID, isClientOId bool) (*models.Order, error)
 	FindOrdersByIds(ctx context.Context, ids []uuid.UUID, onlyOpen bool) ([]models.Order, error)
 	GetOrdersAtPrice(ctx context.Context, symbol models.Symbol, price decimal.Decimal) ([]models.Order, error)
-	GetMarketDepth(ctx context.Context, symbol models.Symbol, depth int) (models.MarketDepth, error)
+	GetMarketDepth(ctx context.Context, symbol models.Symbol, query models.DepthQuery) (models.MarketDepth, error)
 	GetOpenOrders(ctx context.Context, userId uuid.UUID, symbol models.Symbol) (orders []models.Order, totalOrders int, err error)
 	// Fetches order IDs and their respective orders in one call
 	GetOpenOrdersForUser(ctx context.Context, userId uuid.UUID) ([]models.Order, error)
+	// Fetches IDs of orders whose signed deadline or time in force expiry has passed at the given time
+	GetExpiredOrderIds(ctx context.Context, at time.Time) ([]uuid.UUID, error)
 	// ------------------------------
 	// Generic Building blocks with no biz logic in a single tx
 
@@ -37,23 +40,76 @@ type OrderBookStore interface {
 	TxModifyUserOpenOrders(ctx context.Context, txid uint, operation models.Operation, order models.Order) error
 	TxCloseOrder(ctx context.Context, txid uint, order models.Order) error
 	TxRemoveOrder(ctx context.Context, txid uint, order models.Order) error
+	TxModifyOrderDeadlines(ctx context.Context, txid uint, operation models.Operation, order models.Order) error
+	TxStoreOpenOrder(ctx context.Context, txid uint, order models.Order) error
+	TxStoreUserFill(ctx context.Context, txid uint, userId uuid.UUID, fill models.Fill) error
+	TxStoreTrade(ctx context.Context, txid uint, trade models.Trade) error
 	// ------------------------------
 	// LH side
 	GetMinAsk(ctx context.Context, symbol models.Symbol) models.OrderIter
 	GetMaxBid(ctx context.Context, symbol models.Symbol) models.OrderIter
 	// taker side
+	// atomically lock the fragments as pending on their orders, all or none
+	LockOrderFrags(ctx context.Context, frags []models.OrderFrag) ([]models.Order, error)
 	GetSwap(ctx context.Context, swapId uuid.UUID, open bool) (*models.Swap, error)
-	StoreSwap(ctx context.Context, swapId uuid.UUID, symbol models.Symbol, side models.Side, frags []models.OrderFrag) error
+	StoreSwap(ctx context.Context, swapId uuid.UUID, legs []models.RouteLeg, frags []models.OrderFrag) error
 	RemoveSwap(ctx context.Context, swapId uuid.UUID) error
 	GetOpenSwaps(ctx context.Context) ([]models.Swap, error)
+	// Firm quotes, locked until taken by a swap or the expiry check
+	StoreFirmQuote(ctx context.Context, quote models.FirmQuote) error
+	GetFirmQuote(ctx context.Context, quoteId uuid.UUID) (*models.FirmQuote, error)
+	TakeFirmQuote(ctx context.Context, quoteId uuid.UUID) (*models.FirmQuote, error)
+	GetFirmQuotes(ctx context.Context) ([]models.FirmQuote, error)
 	// Pending Swap+Transaction (TODO: rename)
 	StoreNewPendingSwap(ctx context.Context, pendingSwap models.SwapTx) (*models.Swap, error)
 	// removes from "swapid" key
 	// adds to "swapResolve" key
 	ResolveSwap(ctx context.Context, swap models.Swap) error
+	TxResolveSwap(ctx context.Context, txid uint, swap models.Swap) error
 	// save swapId in a set of the userId:resolvedSwap key
 	StoreUserResolvedSwap(ctx context.Context, userId uuid.UUID, swap models.Swap) error
 	GetUserResolvedSwapIds(ctx context.Context, userId uuid.UUID) ([]string, error)
+	GetResolvedSwapsUserIds(ctx context.Context) ([]uuid.UUID, error)
+	// resolved swaps are kept until they are archived, oldest first
+	GetResolvedSwaps(ctx context.Context, resolvedBefore time.Time, limit int) ([]models.Swap, error)
+	// removes the resolved swap, and its ID from the resolved swaps of the users
+	RemoveResolvedSwap(ctx context.Context, swapId uuid.UUID, userIds []uuid.UUID) error
+	// append-only fills and trades history, read newest first
+	StoreUserFill(ctx context.Context, userId uuid.UUID, fill models.Fill) error
+	GetUserFills(ctx context.Context, userId uuid.UUID, filter models.HistoryFilter) (fills []models.Fill, nextCursor string, err error)
+	// the user's fills of a swap, indexed by its ID when they are stored
+	GetUserSwapFills(ctx context.Context, userId uuid.UUID, swapId uuid.UUID) ([]models.Fill, error)
+	// stores fills sorted by resolve time at their resolve time, only if the user has no fills history yet
+	BackfillUserFills(ctx context.Context, userId uuid.UUID, fills []models.Fill) (bool, error)
+	// drops the user's fills stored before the time, once they are archived
+	TrimUserFills(ctx context.Context, userId uuid.UUID, before time.Time) error
+	StoreTrade(ctx context.Context, trade models.Trade) error
+	GetTrades(ctx context.Context, symbol models.Symbol, filter models.HistoryFilter) (trades []models.Trade, nextCursor string, err error)
+	// fees of resolved fills summed per token
+	AddFeeTotals(ctx context.Context, totals []models.FeeTotal) error
+	GetFeeTotals(ctx context.Context) ([]models.FeeTotal, error)
+	// OHLCV candles, one per symbol, interval and start
+	StoreCandles(ctx context.Context, candles []models.Candle) error
+	GetCandles(ctx context.Context, symbol models.Symbol, interval models.CandleInterval, startAt, endAt time.Time) ([]models.Candle, error)
+
+	// sequenced order events per user, kept for resuming a subscription
+	AppendUserEvent(ctx context.Context, userId uuid.UUID, event []byte) (seq int64, err error)
+	GetUserEventSeq(ctx context.Context, userId uuid.UUID) (int64, error)
+	ReadUserEvents(ctx context.Context, userId uuid.UUID, afterSeq, count int64, block time.Duration) ([]models.UserEvent, error)
+
+	// webhooks of users and their delivery log
+	StoreWebhook(ctx context.Context, webhook models.Webhook) error
+	// returns ErrNotFound if the webhook was removed
+	UpdateWebhook(ctx context.Context, webhook models.Webhook) error
+	RemoveWebhook(ctx context.Context, webhook models.Webhook) error
+	GetWebhook(ctx context.Context, webhookId uuid.UUID) (*models.Webhook, error)
+	GetUserWebhooks(ctx context.Context, userId uuid.UUID) ([]models.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	// a lease makes sure a single instance delivers to a webhook at a time
+	AcquireWebhookLease(ctx context.Context, webhookId uuid.UUID, owner string, ttl time.Duration) (bool, error)
+	ReleaseWebhookLease(ctx context.Context, webhookId uuid.UUID, owner string) error
+	StoreWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error
+	GetWebhookDeliveries(ctx context.Context, webhookId uuid.UUID, limit int) ([]models.WebhookDelivery, error)
 
 	// utils
 	EnumSubKeysOf(ctx context.Context, key string) ([]string, error)
@@ -0,0 +1,133 @@
+package storetest
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testUserEvents(t *testing.T, newStore NewStore) {
+
+	t.Run("should sequence the events of each user", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+
+		seq, err := s.GetUserEventSeq(ctx, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), seq)
+
+		for i := int64(1); i <= 3; i++ {
+			seq, err := s.AppendUserEvent(ctx, userId, []byte(`{"event":"order"}`))
+			assert.NoError(t, err)
+			assert.Equal(t, i, seq)
+		}
+		seq, err = s.AppendUserEvent(ctx, uuid.New(), []byte(`{}`))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), seq)
+
+		seq, err = s.GetUserEventSeq(ctx, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), seq)
+	})
+
+	t.Run("should read the events after a seq oldest first", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		for _, event := range []string{`{"n":1}`, `{"n":2}`, `{"n":3}`} {
+			_, err := s.AppendUserEvent(ctx, userId, []byte(event))
+			require.NoError(t, err)
+		}
+
+		events, err := s.ReadUserEvents(ctx, userId, 1, 10, 0)
+		assert.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, int64(2), events[0].Seq)
+		assert.Equal(t, `{"n":2}`, string(events[0].Data))
+		assert.Equal(t, int64(3), events[1].Seq)
+
+		events, err = s.ReadUserEvents(ctx, userId, 0, 1, 0)
+		assert.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, int64(1), events[0].Seq)
+
+		events, err = s.ReadUserEvents(ctx, userId, 3, 10, 0)
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("should wait for a new event", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			_, err := s.AppendUserEvent(ctx, userId, []byte(`{"n":1}`))
+			assert.NoError(t, err)
+		}()
+
+		events, err := s.ReadUserEvents(ctx, userId, 0, 10, 5*time.Second)
+		assert.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, int64(1), events[0].Seq)
+	})
+
+	t.Run("should return no events once the block is over", func(t *testing.T) {
+		s := newStore(t)
+
+		events, err := s.ReadUserEvents(ctx, uuid.New(), 0, 10, 50*time.Millisecond)
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+}
+
+func testPubSub(t *testing.T, newStore NewStore) {
+
+	t.Run("should deliver published events to every subscriber of the channel", func(t *testing.T) {
+		s := newStore(t)
+
+		first, err := s.SubscribeToEvents(ctx, "channel")
+		require.NoError(t, err)
+		second, err := s.SubscribeToEvents(ctx, "channel")
+		require.NoError(t, err)
+		other, err := s.SubscribeToEvents(ctx, "other")
+		require.NoError(t, err)
+
+		require.NoError(t, s.PublishEvent(ctx, "channel", "message"))
+		assert.Equal(t, "message", string(receive(t, first)))
+		assert.Equal(t, "message", string(receive(t, second)))
+		assert.Empty(t, other)
+
+		s.UnsubscribeFromEvents(ctx, "channel", first)
+		_, open := <-first
+		assert.False(t, open)
+
+		require.NoError(t, s.PublishEvent(ctx, "channel", "again"))
+		assert.Equal(t, "again", string(receive(t, second)))
+
+		s.UnsubscribeFromEvents(ctx, "channel", second)
+		s.UnsubscribeFromEvents(ctx, "other", other)
+	})
+
+	t.Run("should publish a book change once a tx is committed", func(t *testing.T) {
+		s := newStore(t)
+		changes, err := s.SubscribeToEvents(ctx, models.CreateMarketEventKey(symbol))
+		require.NoError(t, err)
+		defer s.UnsubscribeFromEvents(ctx, models.CreateMarketEventKey(symbol), changes)
+
+		order := newOrder(uuid.New(), models.SELL, "1", "10", 0)
+		require.NoError(t, s.PerformTx(ctx, func(txid uint) error {
+			return s.TxStoreOpenOrder(ctx, txid, order)
+		}))
+
+		var event models.MarketEvent
+		require.NoError(t, json.Unmarshal(receive(t, changes), &event))
+		assert.Equal(t, models.MARKET_EVENT_BOOK_CHANGE, event.Event)
+		assert.Equal(t, symbol, event.Symbol)
+		assert.Equal(t, models.SELL, event.Side)
+	})
+}
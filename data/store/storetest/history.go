@@ -0,0 +1,286 @@
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testHistory(t *testing.T, newStore NewStore) {
+
+	t.Run("should page the fills of a user newest first", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		ids := []uuid.UUID{}
+		for i := 0; i < 5; i++ {
+			fill := models.Fill{OrderId: uuid.New(), Symbol: symbol, Side: models.BUY, Size: decimal.NewFromInt(int64(i))}
+			if i == 2 {
+				fill.Side = models.SELL
+			}
+			require.NoError(t, s.StoreUserFill(ctx, userId, fill))
+			ids = append(ids, fill.OrderId)
+		}
+		require.NoError(t, s.StoreUserFill(ctx, uuid.New(), models.Fill{OrderId: uuid.New(), Symbol: symbol, Side: models.BUY}))
+
+		fills, cursor, err := s.GetUserFills(ctx, userId, models.HistoryFilter{Limit: 3})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{ids[4], ids[3], ids[2]}, fillOrderIds(fills))
+		assert.NotEmpty(t, cursor)
+
+		fills, _, err = s.GetUserFills(ctx, userId, models.HistoryFilter{Limit: 3, Cursor: cursor})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{ids[1], ids[0]}, fillOrderIds(fills))
+
+		fills, _, err = s.GetUserFills(ctx, userId, models.HistoryFilter{Side: models.BUY})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{ids[4], ids[3], ids[1], ids[0]}, fillOrderIds(fills))
+
+		fills, cursor, err = s.GetUserFills(ctx, userId, models.HistoryFilter{Symbol: otherSymbol})
+		assert.NoError(t, err)
+		assert.Empty(t, fills)
+		assert.Empty(t, cursor)
+	})
+
+	t.Run("should filter the fills by the time they were stored", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		require.NoError(t, s.StoreUserFill(ctx, userId, models.Fill{OrderId: uuid.New(), Symbol: symbol}))
+
+		fills, _, err := s.GetUserFills(ctx, userId, models.HistoryFilter{StartAt: time.Now().Add(-time.Minute), EndAt: time.Now().Add(time.Minute)})
+		assert.NoError(t, err)
+		assert.Len(t, fills, 1)
+
+		fills, _, err = s.GetUserFills(ctx, userId, models.HistoryFilter{EndAt: time.Now().Add(-time.Minute)})
+		assert.NoError(t, err)
+		assert.Empty(t, fills)
+
+		fills, _, err = s.GetUserFills(ctx, userId, models.HistoryFilter{StartAt: time.Now().Add(time.Minute)})
+		assert.NoError(t, err)
+		assert.Empty(t, fills)
+	})
+
+	t.Run("should trim the fills of a user stored before a time", func(t *testing.T) {
+		s := newStore(t)
+		userId, otherUserId := uuid.New(), uuid.New()
+		swapId := uuid.New()
+		require.NoError(t, s.StoreUserFill(ctx, userId, models.Fill{OrderId: uuid.New(), SwapId: swapId, Symbol: symbol}))
+		require.NoError(t, s.StoreUserFill(ctx, otherUserId, models.Fill{OrderId: uuid.New(), SwapId: swapId, Symbol: symbol}))
+
+		require.NoError(t, s.TrimUserFills(ctx, userId, time.Now().Add(-time.Minute)))
+		fills, _, err := s.GetUserFills(ctx, userId, models.HistoryFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, fills, 1)
+
+		require.NoError(t, s.TrimUserFills(ctx, userId, time.Now().Add(time.Minute)))
+		fills, _, err = s.GetUserFills(ctx, userId, models.HistoryFilter{})
+		assert.NoError(t, err)
+		assert.Empty(t, fills)
+		fills, err = s.GetUserSwapFills(ctx, userId, swapId)
+		assert.NoError(t, err)
+		assert.Empty(t, fills)
+		fills, _, err = s.GetUserFills(ctx, otherUserId, models.HistoryFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, fills, 1)
+		fills, err = s.GetUserSwapFills(ctx, otherUserId, swapId)
+		assert.NoError(t, err)
+		assert.Len(t, fills, 1)
+
+		assert.NoError(t, s.TrimUserFills(ctx, uuid.New(), time.Now()))
+	})
+
+	t.Run("should backfill the fills of a user without fills history at their resolve time", func(t *testing.T) {
+		s := newStore(t)
+		userId, otherUserId := uuid.New(), uuid.New()
+		resolved := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+		fills := []models.Fill{
+			{OrderId: uuid.New(), Symbol: symbol, Resolved: resolved},
+			{OrderId: uuid.New(), Symbol: symbol, Resolved: resolved},
+			{OrderId: uuid.New(), Symbol: symbol, Resolved: resolved.Add(time.Minute)},
+		}
+
+		backfilled, err := s.BackfillUserFills(ctx, userId, fills)
+		assert.NoError(t, err)
+		assert.True(t, backfilled)
+		require.NoError(t, s.StoreUserFill(ctx, userId, models.Fill{OrderId: uuid.New(), Symbol: symbol}))
+
+		stored, _, err := s.GetUserFills(ctx, userId, models.HistoryFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, stored, 4)
+		stored, _, err = s.GetUserFills(ctx, userId, models.HistoryFilter{StartAt: resolved, EndAt: resolved.Add(time.Second)})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fills[1].OrderId, fills[0].OrderId}, fillOrderIds(stored))
+
+		backfilled, err = s.BackfillUserFills(ctx, userId, fills)
+		assert.NoError(t, err)
+		assert.False(t, backfilled)
+
+		require.NoError(t, s.StoreUserFill(ctx, otherUserId, models.Fill{OrderId: uuid.New(), Symbol: symbol}))
+		backfilled, err = s.BackfillUserFills(ctx, otherUserId, fills)
+		assert.NoError(t, err)
+		assert.False(t, backfilled)
+		stored, _, err = s.GetUserFills(ctx, otherUserId, models.HistoryFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, stored, 1)
+	})
+
+	t.Run("should page the trades of a symbol newest first", func(t *testing.T) {
+		s := newStore(t)
+		ids := []uuid.UUID{}
+		for i := 0; i < 3; i++ {
+			trade := models.Trade{SwapId: uuid.New(), Symbol: symbol, Side: models.SELL, Price: decimal.NewFromInt(int64(i))}
+			require.NoError(t, s.StoreTrade(ctx, trade))
+			ids = append(ids, trade.SwapId)
+		}
+		require.NoError(t, s.StoreTrade(ctx, models.Trade{SwapId: uuid.New(), Symbol: otherSymbol, Side: models.SELL}))
+
+		trades, cursor, err := s.GetTrades(ctx, symbol, models.HistoryFilter{Limit: 2})
+		assert.NoError(t, err)
+		require.Len(t, trades, 2)
+		assert.Equal(t, ids[2], trades[0].SwapId)
+		assert.Equal(t, "2", trades[0].Price.String())
+		assert.Equal(t, ids[1], trades[1].SwapId)
+
+		trades, _, err = s.GetTrades(ctx, symbol, models.HistoryFilter{Limit: 2, Cursor: cursor})
+		assert.NoError(t, err)
+		require.Len(t, trades, 1)
+		assert.Equal(t, ids[0], trades[0].SwapId)
+
+		trades, _, err = s.GetTrades(ctx, "BTC-USDC", models.HistoryFilter{})
+		assert.NoError(t, err)
+		assert.Empty(t, trades)
+	})
+
+	t.Run("should store the fills and trades of a tx only once it is committed", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		fill := models.Fill{OrderId: uuid.New(), SwapId: uuid.New(), Symbol: symbol, Side: models.BUY}
+
+		err := s.PerformTx(ctx, func(txid uint) error {
+			require.NoError(t, s.TxStoreUserFill(ctx, txid, userId, fill))
+			require.NoError(t, s.TxStoreTrade(ctx, txid, models.NewTrade(fill)))
+			return assert.AnError
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+
+		fills, _, err := s.GetUserFills(ctx, userId, models.HistoryFilter{})
+		assert.NoError(t, err)
+		assert.Empty(t, fills)
+		trades, _, err := s.GetTrades(ctx, symbol, models.HistoryFilter{})
+		assert.NoError(t, err)
+		assert.Empty(t, trades)
+
+		err = s.PerformTx(ctx, func(txid uint) error {
+			if err := s.TxStoreUserFill(ctx, txid, userId, fill); err != nil {
+				return err
+			}
+			return s.TxStoreTrade(ctx, txid, models.NewTrade(fill))
+		})
+		assert.NoError(t, err)
+
+		fills, _, err = s.GetUserFills(ctx, userId, models.HistoryFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fill.OrderId}, fillOrderIds(fills))
+		trades, _, err = s.GetTrades(ctx, symbol, models.HistoryFilter{})
+		assert.NoError(t, err)
+		require.Len(t, trades, 1)
+		assert.Equal(t, fill.SwapId, trades[0].SwapId)
+	})
+
+	t.Run("should find the fills of a user's swap by its ID", func(t *testing.T) {
+		s := newStore(t)
+		userId, otherUserId := uuid.New(), uuid.New()
+		swapId, otherSwapId := uuid.New(), uuid.New()
+		fills := []models.Fill{
+			{OrderId: uuid.New(), SwapId: swapId, Symbol: symbol},
+			{OrderId: uuid.New(), SwapId: swapId, Symbol: symbol},
+		}
+		require.NoError(t, s.StoreUserFill(ctx, userId, fills[0]))
+		require.NoError(t, s.PerformTx(ctx, func(txid uint) error {
+			return s.TxStoreUserFill(ctx, txid, userId, fills[1])
+		}))
+		require.NoError(t, s.StoreUserFill(ctx, userId, models.Fill{OrderId: uuid.New(), SwapId: otherSwapId, Symbol: symbol}))
+		require.NoError(t, s.StoreUserFill(ctx, otherUserId, models.Fill{OrderId: uuid.New(), SwapId: swapId, Symbol: symbol}))
+
+		stored, err := s.GetUserSwapFills(ctx, userId, swapId)
+		assert.NoError(t, err)
+		assert.Equal(t, fillOrderIds(fills), fillOrderIds(stored))
+
+		stored, err = s.GetUserSwapFills(ctx, uuid.New(), swapId)
+		assert.NoError(t, err)
+		assert.Empty(t, stored)
+
+		backfillUserId := uuid.New()
+		backfill := models.Fill{OrderId: uuid.New(), SwapId: swapId, Symbol: symbol, Resolved: time.Now().Add(-time.Hour)}
+		_, err = s.BackfillUserFills(ctx, backfillUserId, []models.Fill{backfill})
+		require.NoError(t, err)
+		stored, err = s.GetUserSwapFills(ctx, backfillUserId, swapId)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{backfill.OrderId}, fillOrderIds(stored))
+	})
+}
+
+func testFeesAndCandles(t *testing.T, newStore NewStore) {
+
+	t.Run("should sum the fee totals per token", func(t *testing.T) {
+		s := newStore(t)
+
+		totals, err := s.GetFeeTotals(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, totals)
+
+		require.NoError(t, s.AddFeeTotals(ctx, []models.FeeTotal{
+			{Token: "USDC", TakerFees: decimal.RequireFromString("1.5"), MakerRebates: decimal.RequireFromString("0.5")},
+			{Token: "MATIC", TakerFees: decimal.RequireFromString("2"), MakerRebates: decimal.Zero},
+		}))
+		require.NoError(t, s.AddFeeTotals(ctx, []models.FeeTotal{
+			{Token: "USDC", TakerFees: decimal.RequireFromString("0.25"), MakerRebates: decimal.RequireFromString("0.25")},
+		}))
+
+		totals, err = s.GetFeeTotals(ctx)
+		assert.NoError(t, err)
+		require.Len(t, totals, 2)
+		assert.Equal(t, "MATIC", totals[0].Token)
+		assert.Equal(t, "2", totals[0].TakerFees.String())
+		assert.Equal(t, "USDC", totals[1].Token)
+		assert.Equal(t, "1.75", totals[1].TakerFees.String())
+		assert.Equal(t, "0.75", totals[1].MakerRebates.String())
+	})
+
+	t.Run("should replace candles of the same start and return them oldest first", func(t *testing.T) {
+		s := newStore(t)
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		candle := func(minute int, trades int) models.Candle {
+			return models.Candle{Symbol: symbol, Interval: models.CANDLE_1M, Start: start.Add(time.Duration(minute) * time.Minute), Trades: trades}
+		}
+
+		require.NoError(t, s.StoreCandles(ctx, []models.Candle{candle(1, 1), candle(0, 1), candle(2, 1)}))
+		require.NoError(t, s.StoreCandles(ctx, []models.Candle{candle(1, 2)}))
+		other := candle(0, 5)
+		other.Interval = models.CANDLE_5M
+		require.NoError(t, s.StoreCandles(ctx, []models.Candle{other}))
+
+		candles, err := s.GetCandles(ctx, symbol, models.CANDLE_1M, start, start.Add(2*time.Minute))
+		assert.NoError(t, err)
+		require.Len(t, candles, 2)
+		assert.True(t, start.Equal(candles[0].Start))
+		assert.Equal(t, 1, candles[0].Trades)
+		assert.Equal(t, 2, candles[1].Trades)
+
+		candles, err = s.GetCandles(ctx, otherSymbol, models.CANDLE_1M, start, start.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Empty(t, candles)
+	})
+}
+
+func fillOrderIds(fills []models.Fill) []uuid.UUID {
+	ids := make([]uuid.UUID, len(fills))
+	for i, fill := range fills {
+		ids[i] = fill.OrderId
+	}
+	return ids
+}
@@ -0,0 +1,48 @@
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStrKeys(t *testing.T, newStore NewStore) {
+
+	t.Run("should read the written string keys", func(t *testing.T) {
+		s := newStore(t)
+
+		require.NoError(t, s.WriteStrKey(ctx, "key:a", "1"))
+		require.NoError(t, s.WriteStrKey(ctx, "key:a", "2"))
+		val, err := s.ReadStrKey(ctx, "key:a")
+		assert.NoError(t, err)
+		assert.Equal(t, "2", val)
+
+		// backends return their own missing key error
+		_, err = s.ReadStrKey(ctx, "key:missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("should enumerate the keys of a prefix", func(t *testing.T) {
+		s := newStore(t)
+		for _, key := range []string{"prefix:a", "prefix:b", "other:a"} {
+			require.NoError(t, s.WriteStrKey(ctx, key, "1"))
+		}
+
+		keys, err := s.EnumSubKeysOf(ctx, "prefix:")
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"prefix:a", "prefix:b"}, keys)
+
+		keys, err = s.EnumSubKeysOf(ctx, "none:")
+		assert.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+
+	t.Run("should fail to get the balance of an untracked maker token", func(t *testing.T) {
+		s := newStore(t)
+
+		balance, err := s.GetMakerTokenBalance(ctx, "USDC", "0x0")
+		assert.Error(t, err)
+		assert.Equal(t, "-1", balance.String())
+	})
+}
@@ -0,0 +1,205 @@
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testOrders(t *testing.T, newStore NewStore) {
+
+	t.Run("should return ErrNotFound for a missing order", func(t *testing.T) {
+		s := newStore(t)
+
+		_, err := s.FindOrderById(ctx, uuid.New(), false)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = s.FindOrderById(ctx, uuid.New(), true)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should find orders by ids in ids order", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		open := newOrder(userId, models.BUY, "1", "10", 0)
+		cancelled := newOrder(userId, models.BUY, "1", "10", 0)
+		cancelled.Cancelled = true
+		require.NoError(t, s.StoreOpenOrders(ctx, []models.Order{open, cancelled}))
+
+		orders, err := s.FindOrdersByIds(ctx, []uuid.UUID{cancelled.Id, open.Id}, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{cancelled.Id, open.Id}, orderIds(orders))
+
+		orders, err = s.FindOrdersByIds(ctx, []uuid.UUID{cancelled.Id, open.Id}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{open.Id}, orderIds(orders))
+
+		_, err = s.FindOrdersByIds(ctx, []uuid.UUID{open.Id, uuid.New()}, false)
+		assert.Error(t, err)
+	})
+
+	t.Run("should return the open orders of a user by creation time", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		oldest := newOrder(userId, models.BUY, "1", "10", 2*time.Minute)
+		middle := newOrder(userId, models.SELL, "2", "10", time.Minute)
+		newest := newOrder(userId, models.BUY, "1", "10", 0)
+		newest.Symbol = otherSymbol
+		require.NoError(t, s.StoreOpenOrders(ctx, []models.Order{newest, oldest, middle}))
+		require.NoError(t, s.StoreOpenOrder(ctx, newOrder(uuid.New(), models.BUY, "1", "10", 0)))
+
+		paginated := utils.WithPaginationCtx(ctx, &utils.Paginator{Page: 1, PageSize: 10})
+		orders, total, err := s.GetOpenOrders(paginated, userId, symbol)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+		assert.Equal(t, []uuid.UUID{oldest.Id, middle.Id}, orderIds(orders))
+
+		orders, total, err = s.GetOpenOrders(paginated, userId, "")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+		assert.Equal(t, []uuid.UUID{oldest.Id, middle.Id, newest.Id}, orderIds(orders))
+
+		orders, err = s.GetOpenOrdersForUser(ctx, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{oldest.Id, middle.Id, newest.Id}, orderIds(orders))
+
+		_, err = s.GetOpenOrdersForUser(ctx, uuid.New())
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should return the orders expired at a time", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		expired := newOrder(userId, models.BUY, "1", "10", 0)
+		expired.ExpiresAt = time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+		later := newOrder(userId, models.BUY, "1", "10", 0)
+		later.ExpiresAt = time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		goodTillCancelled := newOrder(userId, models.BUY, "1", "10", 0)
+		require.NoError(t, s.StoreOpenOrders(ctx, []models.Order{expired, later, goodTillCancelled}))
+
+		ids, err := s.GetExpiredOrderIds(ctx, time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{expired.Id}, ids)
+
+		ids, err = s.GetExpiredOrderIds(ctx, time.Now().Add(2*time.Hour))
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{expired.Id, later.Id}, ids)
+	})
+
+	t.Run("should return the orders of both sides at a price oldest first", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		newer := newOrder(userId, models.BUY, "1.5", "1", 0)
+		older := newOrder(userId, models.BUY, "1.5", "1", time.Minute)
+		ask := newOrder(userId, models.SELL, "1.5", "1", 0)
+		nearby := newOrder(userId, models.BUY, "1.501", "1", 0)
+		require.NoError(t, s.StoreOpenOrders(ctx, []models.Order{newer, older, ask, nearby, newOrder(userId, models.SELL, "2", "1", 0)}))
+
+		orders, err := s.GetOrdersAtPrice(ctx, symbol, decimal.RequireFromString("1.5"))
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{older.Id, newer.Id, ask.Id}, orderIds(orders))
+
+		orders, err = s.GetOrdersAtPrice(ctx, symbol, decimal.RequireFromString("3"))
+		assert.NoError(t, err)
+		assert.Empty(t, orders)
+	})
+
+	t.Run("should return the open price levels best price first", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		cancelled := newOrder(userId, models.SELL, "1", "7", 0)
+		cancelled.Cancelled = true
+		require.NoError(t, s.StoreOpenOrders(ctx, []models.Order{
+			newOrder(userId, models.SELL, "1.2", "1", 0),
+			newOrder(userId, models.SELL, "1.1", "2", 0),
+			newOrder(userId, models.SELL, "1.1", "3", 0),
+			cancelled,
+			newOrder(userId, models.BUY, "0.9", "4", 0),
+			newOrder(userId, models.BUY, "1", "5", 0),
+		}))
+
+		depth, err := s.GetMarketDepth(ctx, symbol, models.DepthQuery{Mode: models.DEPTH_L2, Depth: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, symbol.String(), depth.Symbol)
+		require.Len(t, depth.Asks, 2)
+		assert.Equal(t, "1.1", depth.Asks[0][0].String())
+		assert.Equal(t, "5", depth.Asks[0][1].String())
+		assert.Equal(t, "1.2", depth.Asks[1][0].String())
+		require.Len(t, depth.Bids, 2)
+		assert.Equal(t, "1", depth.Bids[0][0].String())
+		assert.Equal(t, "0.9", depth.Bids[1][0].String())
+
+		depth, err = s.GetMarketDepth(ctx, symbol, models.DepthQuery{Mode: models.DEPTH_L3, Depth: 2})
+		assert.NoError(t, err)
+		assert.Len(t, depth.Asks, 2)
+		assert.Equal(t, "1.1", depth.Asks[1][0].String())
+
+		depth, err = s.GetMarketDepth(ctx, otherSymbol, models.DepthQuery{Mode: models.DEPTH_L2, Depth: 10})
+		assert.NoError(t, err)
+		assert.Empty(t, depth.Asks)
+		assert.Empty(t, depth.Bids)
+	})
+}
+
+func testOrderIter(t *testing.T, newStore NewStore) {
+
+	t.Run("should iterate asks lowest price first, then oldest first", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		high := newOrder(userId, models.SELL, "2", "1", 2*time.Minute)
+		lowNewer := newOrder(userId, models.SELL, "1", "1", 0)
+		lowOlder := newOrder(userId, models.SELL, "1", "1", time.Minute)
+		require.NoError(t, s.StoreOpenOrders(ctx, []models.Order{high, lowNewer, lowOlder}))
+
+		it := s.GetMinAsk(ctx, symbol)
+		ids := []uuid.UUID{}
+		for it.HasNext() {
+			ids = append(ids, it.Next(ctx).Id)
+		}
+		assert.Equal(t, []uuid.UUID{lowOlder.Id, lowNewer.Id, high.Id}, ids)
+		assert.Nil(t, it.Next(ctx))
+	})
+
+	t.Run("should iterate bids highest price first", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		low := newOrder(userId, models.BUY, "1", "1", 0)
+		high := newOrder(userId, models.BUY, "2", "1", 0)
+		other := newOrder(userId, models.BUY, "3", "1", 0)
+		other.Symbol = otherSymbol
+		require.NoError(t, s.StoreOpenOrders(ctx, []models.Order{low, high, other}))
+
+		it := s.GetMaxBid(ctx, symbol)
+		assert.Equal(t, high.Id, it.Next(ctx).Id)
+		assert.Equal(t, low.Id, it.Next(ctx).Id)
+		assert.False(t, it.HasNext())
+	})
+
+	t.Run("should skip orders removed while iterating", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		removed := newOrder(userId, models.SELL, "1", "1", 0)
+		kept := newOrder(userId, models.SELL, "2", "1", 0)
+		require.NoError(t, s.StoreOpenOrders(ctx, []models.Order{removed, kept}))
+
+		it := s.GetMinAsk(ctx, symbol)
+		require.NoError(t, s.PerformTx(ctx, func(txid uint) error {
+			return s.TxRemoveOrder(ctx, txid, removed)
+		}))
+
+		assert.Equal(t, kept.Id, it.Next(ctx).Id)
+		assert.False(t, it.HasNext())
+	})
+
+	t.Run("should be empty for a symbol without orders", func(t *testing.T) {
+		s := newStore(t)
+
+		assert.False(t, s.GetMinAsk(ctx, symbol).HasNext())
+		assert.False(t, s.GetMaxBid(ctx, symbol).HasNext())
+	})
+}
@@ -0,0 +1,75 @@
+// Package storetest is a conformance suite of store.OrderBookStore, run by the tests of each backend.
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/data/store"
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+)
+
+// NewStore returns a new empty store for a single test
+type NewStore func(t *testing.T) store.OrderBookStore
+
+var ctx = context.Background()
+
+var symbol, _ = models.StrToSymbol("MATIC-USDC")
+var otherSymbol, _ = models.StrToSymbol("ETH-USDC")
+
+// Run runs every test of the suite, each on a new store
+func Run(t *testing.T, newStore NewStore) {
+	t.Run("Tx", func(t *testing.T) { testTx(t, newStore) })
+	t.Run("Orders", func(t *testing.T) { testOrders(t, newStore) })
+	t.Run("OrderIter", func(t *testing.T) { testOrderIter(t, newStore) })
+	t.Run("LockOrderFrags", func(t *testing.T) { testLockOrderFrags(t, newStore) })
+	t.Run("Swaps", func(t *testing.T) { testSwaps(t, newStore) })
+	t.Run("FirmQuotes", func(t *testing.T) { testFirmQuotes(t, newStore) })
+	t.Run("History", func(t *testing.T) { testHistory(t, newStore) })
+	t.Run("FeesAndCandles", func(t *testing.T) { testFeesAndCandles(t, newStore) })
+	t.Run("UserEvents", func(t *testing.T) { testUserEvents(t, newStore) })
+	t.Run("Webhooks", func(t *testing.T) { testWebhooks(t, newStore) })
+	t.Run("PubSub", func(t *testing.T) { testPubSub(t, newStore) })
+	t.Run("StrKeys", func(t *testing.T) { testStrKeys(t, newStore) })
+}
+
+// newOrder returns an open order of the user, created `age` ago
+// stores keep timestamps to the second, so it is truncated to compare with the stored order
+func newOrder(userId uuid.UUID, side models.Side, price, size string, age time.Duration) models.Order {
+	return models.Order{
+		Id:        uuid.New(),
+		ClientOId: uuid.New(),
+		UserId:    userId,
+		Price:     decimal.RequireFromString(price),
+		Size:      decimal.RequireFromString(size),
+		// as read back from a store
+		SizePending: decimal.NewFromInt(0),
+		SizeFilled:  decimal.NewFromInt(0),
+		Symbol:      symbol,
+		Side:        side,
+		Timestamp:   time.Now().Add(-age).UTC().Truncate(time.Second),
+	}
+}
+
+func orderIds(orders []models.Order) []uuid.UUID {
+	ids := make([]uuid.UUID, len(orders))
+	for i, order := range orders {
+		ids[i] = order.Id
+	}
+	return ids
+}
+
+// receive waits for a published message, messages are delivered asynchronously by some stores
+func receive(t *testing.T, messages chan []byte) []byte {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatal("no message was received")
+		return nil
+	}
+}
@@ -0,0 +1,309 @@
+package storetest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLockOrderFrags(t *testing.T, newStore NewStore) {
+
+	t.Run("should lock all the fragments and increment the versions", func(t *testing.T) {
+		s := newStore(t)
+		order := newOrder(uuid.New(), models.SELL, "1", "10", 0)
+		require.NoError(t, s.StoreOpenOrder(ctx, order))
+
+		frag := models.OrderFrag{OrderId: order.Id, OutSize: decimal.NewFromInt(3), InSize: decimal.NewFromInt(3)}
+		orders, err := s.LockOrderFrags(ctx, []models.OrderFrag{frag, frag})
+		assert.NoError(t, err)
+		require.Len(t, orders, 2)
+		assert.Equal(t, "6", orders[0].SizePending.String())
+		assert.Equal(t, int64(1), orders[0].Version)
+
+		stored, err := s.FindOrderById(ctx, order.Id, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "6", stored.SizePending.String())
+		assert.Equal(t, int64(1), stored.Version)
+	})
+
+	t.Run("should lock none of the fragments if one is not available", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		available := newOrder(userId, models.SELL, "1", "10", 0)
+		small := newOrder(userId, models.SELL, "1", "1", 0)
+		cancelled := newOrder(userId, models.SELL, "1", "10", 0)
+		cancelled.Cancelled = true
+		require.NoError(t, s.StoreOpenOrders(ctx, []models.Order{available, small, cancelled}))
+
+		for _, unavailable := range []models.Order{small, cancelled} {
+			_, err := s.LockOrderFrags(ctx, []models.OrderFrag{
+				{OrderId: available.Id, OutSize: decimal.NewFromInt(3)},
+				{OrderId: unavailable.Id, OutSize: decimal.NewFromInt(3)},
+			})
+			assert.ErrorIs(t, err, models.ErrSwapInvalid)
+		}
+
+		stored, err := s.FindOrderById(ctx, available.Id, false)
+		assert.NoError(t, err)
+		assert.True(t, stored.SizePending.IsZero())
+		assert.Equal(t, int64(0), stored.Version)
+	})
+
+	t.Run("should return ErrNotFound for a missing order", func(t *testing.T) {
+		s := newStore(t)
+
+		_, err := s.LockOrderFrags(ctx, []models.OrderFrag{{OrderId: uuid.New(), OutSize: decimal.NewFromInt(1)}})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should never lock more than the available size concurrently", func(t *testing.T) {
+		s := newStore(t)
+		order := newOrder(uuid.New(), models.SELL, "1", "10", 0)
+		require.NoError(t, s.StoreOpenOrder(ctx, order))
+		frags := []models.OrderFrag{{OrderId: order.Id, OutSize: decimal.NewFromInt(1)}}
+
+		const swaps = 30
+		errs := make(chan error, swaps)
+		var wg sync.WaitGroup
+		for i := 0; i < swaps; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := s.LockOrderFrags(ctx, frags)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		locked := int64(0)
+		for err := range errs {
+			if err == nil {
+				locked++
+			} else {
+				assert.ErrorIs(t, err, models.ErrSwapInvalid)
+			}
+		}
+		assert.LessOrEqual(t, locked, int64(10))
+
+		// a store may give up on a busy order, the rest of its size is still available
+		for locked < 10 {
+			_, err := s.LockOrderFrags(ctx, frags)
+			require.NoError(t, err)
+			locked++
+		}
+		_, err := s.LockOrderFrags(ctx, frags)
+		assert.ErrorIs(t, err, models.ErrSwapInvalid)
+
+		stored, err := s.FindOrderById(ctx, order.Id, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "10", stored.SizePending.String())
+		assert.Equal(t, int64(10), stored.Version)
+	})
+}
+
+func testSwaps(t *testing.T, newStore NewStore) {
+
+	t.Run("should keep every leg of a routed swap", func(t *testing.T) {
+		s := newStore(t)
+		swapId := uuid.New()
+		legs := []models.RouteLeg{{Symbol: "MATIC-ETH", MakerSide: models.BUY}, {Symbol: "BTC-ETH", MakerSide: models.SELL}}
+
+		require.NoError(t, s.StoreSwap(ctx, swapId, legs, []models.OrderFrag{}))
+		swap, err := s.GetSwap(ctx, swapId, true)
+		require.NoError(t, err)
+		assert.Equal(t, legs, swap.Legs)
+		assert.Equal(t, "MATIC-ETH", swap.Symbol)
+		assert.Equal(t, models.BUY.String(), swap.Side)
+	})
+
+	t.Run("should move a swap from open to started to resolved", func(t *testing.T) {
+		s := newStore(t)
+		swapId := uuid.New()
+		frags := []models.OrderFrag{{OrderId: uuid.New(), OutSize: decimal.NewFromInt(1), InSize: decimal.NewFromInt(2)}}
+
+		require.NoError(t, s.StoreSwap(ctx, swapId, []models.RouteLeg{{Symbol: symbol, MakerSide: models.BUY}}, frags))
+		swap, err := s.GetSwap(ctx, swapId, true)
+		assert.NoError(t, err)
+		assert.Equal(t, frags[0].OrderId, swap.Frags[0].OrderId)
+		assert.True(t, frags[0].InSize.Equal(swap.Frags[0].InSize))
+		assert.False(t, swap.IsStarted())
+		open, err := s.GetOpenSwaps(ctx)
+		assert.NoError(t, err)
+		require.Len(t, open, 1)
+		assert.Equal(t, swapId, open[0].Id)
+
+		started, err := s.StoreNewPendingSwap(ctx, models.SwapTx{SwapId: swapId, TxHash: "0x1"})
+		assert.NoError(t, err)
+		assert.True(t, started.IsStarted())
+		assert.Equal(t, "0x1", started.TxHash)
+		_, err = s.StoreNewPendingSwap(ctx, models.SwapTx{SwapId: swapId, TxHash: "0x2"})
+		assert.Error(t, err)
+		swap, err = s.GetSwap(ctx, swapId, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "0x1", swap.TxHash)
+
+		swap.Id = swapId
+		swap.Succeeded = true
+		swap.Resolved = time.Now()
+		require.NoError(t, s.ResolveSwap(ctx, *swap))
+
+		_, err = s.GetSwap(ctx, swapId, true)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		resolved, err := s.GetSwap(ctx, swapId, false)
+		assert.NoError(t, err)
+		assert.True(t, resolved.Succeeded)
+		open, err = s.GetOpenSwaps(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, open)
+	})
+
+	t.Run("should return ErrNotFound for a missing swap", func(t *testing.T) {
+		s := newStore(t)
+
+		_, err := s.GetSwap(ctx, uuid.New(), true)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = s.StoreNewPendingSwap(ctx, models.SwapTx{SwapId: uuid.New(), TxHash: "0x1"})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should remove an open swap", func(t *testing.T) {
+		s := newStore(t)
+		swapId := uuid.New()
+		require.NoError(t, s.StoreSwap(ctx, swapId, []models.RouteLeg{{Symbol: symbol, MakerSide: models.SELL}}, []models.OrderFrag{}))
+
+		assert.NoError(t, s.RemoveSwap(ctx, swapId))
+		_, err := s.GetSwap(ctx, swapId, true)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should return the swaps resolved before a time oldest first, until removed", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		resolved := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+		swapIds := []uuid.UUID{}
+		for i := 0; i < 3; i++ {
+			swapId := uuid.New()
+			require.NoError(t, s.StoreSwap(ctx, swapId, []models.RouteLeg{{Symbol: symbol, MakerSide: models.BUY}}, []models.OrderFrag{}))
+			require.NoError(t, s.ResolveSwap(ctx, models.Swap{Id: swapId, Resolved: resolved.Add(time.Duration(2-i) * time.Minute)}))
+			require.NoError(t, s.StoreUserResolvedSwap(ctx, userId, models.Swap{Id: swapId}))
+			swapIds = append(swapIds, swapId)
+		}
+
+		swaps, err := s.GetResolvedSwaps(ctx, time.Now(), 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{swapIds[2], swapIds[1], swapIds[0]}, swapIdsOf(swaps))
+		swaps, err = s.GetResolvedSwaps(ctx, time.Now(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{swapIds[2]}, swapIdsOf(swaps))
+		// the end is exclusive
+		swaps, err = s.GetResolvedSwaps(ctx, resolved.Add(time.Minute), 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{swapIds[2]}, swapIdsOf(swaps))
+
+		require.NoError(t, s.RemoveResolvedSwap(ctx, swapIds[2], []uuid.UUID{userId}))
+		_, err = s.GetSwap(ctx, swapIds[2], false)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		swaps, err = s.GetResolvedSwaps(ctx, time.Now(), 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{swapIds[1], swapIds[0]}, swapIdsOf(swaps))
+		ids, err := s.GetUserResolvedSwapIds(ctx, userId)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{swapIds[0].String(), swapIds[1].String()}, ids)
+	})
+
+	t.Run("should keep the resolved swaps of a user once", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		first, second := models.Swap{Id: uuid.New()}, models.Swap{Id: uuid.New()}
+
+		assert.NoError(t, s.StoreUserResolvedSwap(ctx, userId, first))
+		assert.NoError(t, s.StoreUserResolvedSwap(ctx, userId, second))
+		assert.ErrorIs(t, s.StoreUserResolvedSwap(ctx, userId, first), models.ErrValAlreadyInSet)
+
+		ids, err := s.GetUserResolvedSwapIds(ctx, userId)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{first.Id.String(), second.Id.String()}, ids)
+
+		ids, err = s.GetUserResolvedSwapIds(ctx, uuid.New())
+		assert.NoError(t, err)
+		assert.Empty(t, ids)
+
+		userIds, err := s.GetResolvedSwapsUserIds(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{userId}, userIds)
+	})
+}
+
+func testFirmQuotes(t *testing.T, newStore NewStore) {
+
+	t.Run("should take a firm quote once", func(t *testing.T) {
+		s := newStore(t)
+		quote := models.FirmQuote{Id: uuid.New(), InAmount: decimal.NewFromInt(1), ExpiresAt: time.Now().Add(time.Minute).UTC()}
+		other := models.FirmQuote{Id: uuid.New(), InAmount: decimal.NewFromInt(2)}
+		require.NoError(t, s.StoreFirmQuote(ctx, quote))
+		require.NoError(t, s.StoreFirmQuote(ctx, other))
+
+		stored, err := s.GetFirmQuote(ctx, quote.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", stored.InAmount.String())
+		quotes, err := s.GetFirmQuotes(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, quotes, 2)
+
+		taken, err := s.TakeFirmQuote(ctx, quote.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, quote.Id, taken.Id)
+		assert.True(t, quote.ExpiresAt.Equal(taken.ExpiresAt))
+
+		_, err = s.TakeFirmQuote(ctx, quote.Id)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = s.GetFirmQuote(ctx, quote.Id)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		quotes, err = s.GetFirmQuotes(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, quotes, 1)
+	})
+
+	t.Run("should take a firm quote once concurrently", func(t *testing.T) {
+		s := newStore(t)
+		quote := models.FirmQuote{Id: uuid.New()}
+		require.NoError(t, s.StoreFirmQuote(ctx, quote))
+
+		const takers = 10
+		taken := make(chan bool, takers)
+		var wg sync.WaitGroup
+		for i := 0; i < takers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := s.TakeFirmQuote(ctx, quote.Id)
+				taken <- err == nil
+			}()
+		}
+		wg.Wait()
+		close(taken)
+
+		count := 0
+		for ok := range taken {
+			if ok {
+				count++
+			}
+		}
+		assert.Equal(t, 1, count)
+	})
+}
+
+func swapIdsOf(swaps []models.Swap) []uuid.UUID {
+	ids := make([]uuid.UUID, len(swaps))
+	for i, swap := range swaps {
+		ids[i] = swap.Id
+	}
+	return ids
+}
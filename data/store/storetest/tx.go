@@ -0,0 +1,256 @@
+package storetest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTx(t *testing.T, newStore NewStore) {
+
+	t.Run("should store an open order in all of its indexes", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		order := newOrder(userId, models.BUY, "1", "10", 0)
+
+		require.NoError(t, s.StoreOpenOrder(ctx, order))
+
+		stored, err := s.FindOrderById(ctx, order.Id, false)
+		assert.NoError(t, err)
+		assert.Equal(t, order, *stored)
+		byClientOId, err := s.FindOrderById(ctx, order.ClientOId, true)
+		assert.NoError(t, err)
+		assert.Equal(t, order.Id, byClientOId.Id)
+		open, err := s.GetOpenOrdersForUser(ctx, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{order.Id}, orderIds(open))
+		assert.True(t, s.GetMaxBid(ctx, symbol).HasNext())
+	})
+
+	t.Run("should apply none of the changes of a failed action", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		stored := newOrder(userId, models.SELL, "1", "10", 0)
+		require.NoError(t, s.StoreOpenOrder(ctx, stored))
+		order := newOrder(userId, models.BUY, "1", "10", 0)
+
+		err := s.PerformTx(ctx, func(txid uint) error {
+			if err := s.TxStoreOpenOrder(ctx, txid, order); err != nil {
+				return err
+			}
+			if err := s.TxRemoveOrder(ctx, txid, stored); err != nil {
+				return err
+			}
+			return assert.AnError
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+
+		_, err = s.FindOrderById(ctx, order.Id, false)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = s.FindOrderById(ctx, order.ClientOId, true)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.False(t, s.GetMaxBid(ctx, symbol).HasNext())
+		_, err = s.FindOrderById(ctx, stored.Id, false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return ErrNotFound for an unknown txid", func(t *testing.T) {
+		s := newStore(t)
+		order := newOrder(uuid.New(), models.BUY, "1", "10", 0)
+
+		var txid uint
+		require.NoError(t, s.PerformTx(ctx, func(id uint) error {
+			txid = id
+			return nil
+		}))
+
+		// the tx is over
+		assert.ErrorIs(t, s.TxModifyOrder(ctx, txid, models.Add, order), models.ErrNotFound)
+		assert.ErrorIs(t, s.TxModifyPrices(ctx, txid, models.Add, order), models.ErrNotFound)
+		assert.ErrorIs(t, s.TxModifyClientOId(ctx, txid, models.Add, order), models.ErrNotFound)
+		assert.ErrorIs(t, s.TxModifyUserOpenOrders(ctx, txid, models.Add, order), models.ErrNotFound)
+		assert.ErrorIs(t, s.TxStoreOpenOrder(ctx, txid, order), models.ErrNotFound)
+	})
+
+	t.Run("should return ErrUnsupportedOperation", func(t *testing.T) {
+		s := newStore(t)
+		order := newOrder(uuid.New(), models.BUY, "1", "10", 0)
+
+		err := s.PerformTx(ctx, func(txid uint) error {
+			return s.TxModifyPrices(ctx, txid, models.Update, order)
+		})
+		assert.ErrorIs(t, err, models.ErrUnsupportedOperation)
+	})
+
+	t.Run("should increment the version of an updated order", func(t *testing.T) {
+		s := newStore(t)
+		order := newOrder(uuid.New(), models.BUY, "1", "10", 0)
+		require.NoError(t, s.StoreOpenOrder(ctx, order))
+
+		for version := int64(1); version <= 2; version++ {
+			stored, err := s.FindOrderById(ctx, order.Id, false)
+			require.NoError(t, err)
+			require.NoError(t, s.PerformTx(ctx, func(txid uint) error {
+				return s.TxModifyOrder(ctx, txid, models.Update, *stored)
+			}))
+
+			updated, err := s.FindOrderById(ctx, order.Id, false)
+			assert.NoError(t, err)
+			assert.Equal(t, version, updated.Version)
+		}
+	})
+
+	t.Run("should fail with ErrVersionConflict and apply nothing when an order changed since it was read", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		order := newOrder(userId, models.BUY, "1", "10", 0)
+		other := newOrder(userId, models.BUY, "2", "10", 0)
+		require.NoError(t, s.StoreOpenOrders(ctx, []models.Order{order, other}))
+
+		// read, then changed by someone else
+		read, err := s.FindOrderById(ctx, order.Id, false)
+		require.NoError(t, err)
+		require.NoError(t, s.PerformTx(ctx, func(txid uint) error {
+			return s.TxModifyOrder(ctx, txid, models.Update, *read)
+		}))
+
+		err = s.PerformTx(ctx, func(txid uint) error {
+			cancelled := other
+			cancelled.Cancelled = true
+			if err := s.TxModifyOrder(ctx, txid, models.Update, cancelled); err != nil {
+				return err
+			}
+			return s.TxModifyOrder(ctx, txid, models.Update, *read)
+		})
+		assert.ErrorIs(t, err, models.ErrVersionConflict)
+
+		stored, err := s.FindOrderById(ctx, other.Id, false)
+		assert.NoError(t, err)
+		assert.False(t, stored.Cancelled)
+		assert.Equal(t, int64(0), stored.Version)
+	})
+
+	t.Run("should fail with ErrVersionConflict when an order was removed since it was read", func(t *testing.T) {
+		s := newStore(t)
+		order := newOrder(uuid.New(), models.BUY, "1", "10", 0)
+		require.NoError(t, s.StoreOpenOrder(ctx, order))
+		require.NoError(t, s.PerformTx(ctx, func(txid uint) error {
+			return s.TxRemoveOrder(ctx, txid, order)
+		}))
+
+		err := s.PerformTx(ctx, func(txid uint) error {
+			return s.TxModifyOrder(ctx, txid, models.Update, order)
+		})
+		assert.ErrorIs(t, err, models.ErrVersionConflict)
+		_, err = s.FindOrderById(ctx, order.Id, false)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should remove an order from all of its indexes", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		order := newOrder(userId, models.BUY, "1", "10", 0)
+		order.ExpiresAt = time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+		require.NoError(t, s.StoreOpenOrder(ctx, order))
+
+		require.NoError(t, s.PerformTx(ctx, func(txid uint) error {
+			if err := s.TxModifyPrices(ctx, txid, models.Remove, order); err != nil {
+				return err
+			}
+			return s.TxRemoveOrder(ctx, txid, order)
+		}))
+
+		_, err := s.FindOrderById(ctx, order.Id, false)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = s.FindOrderById(ctx, order.ClientOId, true)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = s.GetOpenOrdersForUser(ctx, userId)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		expired, err := s.GetExpiredOrderIds(ctx, time.Now())
+		assert.NoError(t, err)
+		assert.Empty(t, expired)
+		assert.False(t, s.GetMaxBid(ctx, symbol).HasNext())
+	})
+
+	t.Run("should close a filled order and keep it", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		order := newOrder(userId, models.SELL, "1", "10", 0)
+		require.NoError(t, s.StoreOpenOrder(ctx, order))
+
+		order.SizeFilled = order.Size
+		require.NoError(t, s.PerformTx(ctx, func(txid uint) error {
+			if err := s.TxModifyOrder(ctx, txid, models.Update, order); err != nil {
+				return err
+			}
+			return s.TxCloseOrder(ctx, txid, order)
+		}))
+
+		stored, err := s.FindOrderById(ctx, order.Id, false)
+		assert.NoError(t, err)
+		assert.True(t, stored.IsFilled())
+		_, err = s.GetOpenOrdersForUser(ctx, userId)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.False(t, s.GetMinAsk(ctx, symbol).HasNext())
+	})
+
+	t.Run("should store filled orders at the version they were read", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		order := newOrder(userId, models.SELL, "1", "10", 0)
+		require.NoError(t, s.StoreOpenOrder(ctx, order))
+
+		order.SizeFilled = order.Size
+		require.NoError(t, s.StoreFilledOrders(ctx, []models.Order{order}))
+
+		stored, err := s.FindOrderById(ctx, order.Id, false)
+		assert.NoError(t, err)
+		assert.True(t, stored.IsFilled())
+		assert.False(t, s.GetMinAsk(ctx, symbol).HasNext())
+
+		// still at the version it was read before
+		assert.ErrorIs(t, s.StoreFilledOrders(ctx, []models.Order{order}), models.ErrVersionConflict)
+	})
+
+	t.Run("should perform concurrent txs of different orders", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		orders := []models.Order{}
+		for i := 0; i < 20; i++ {
+			order := newOrder(userId, models.SELL, "1", "10", 0)
+			require.NoError(t, s.StoreOpenOrder(ctx, order))
+			orders = append(orders, order)
+		}
+
+		errs := make(chan error, len(orders))
+		var wg sync.WaitGroup
+		for _, order := range orders {
+			wg.Add(1)
+			go func(order models.Order) {
+				defer wg.Done()
+				order.SizePending = decimal.NewFromInt(1)
+				errs <- s.PerformTx(ctx, func(txid uint) error {
+					return s.TxModifyOrder(ctx, txid, models.Update, order)
+				})
+			}(order)
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+		for _, order := range orders {
+			stored, err := s.FindOrderById(ctx, order.Id, false)
+			require.NoError(t, err)
+			assert.Equal(t, order.Version+1, stored.Version)
+			assert.True(t, stored.SizePending.Equal(decimal.NewFromInt(1)))
+		}
+	})
+}
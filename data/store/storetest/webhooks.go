@@ -0,0 +1,104 @@
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testWebhooks(t *testing.T, newStore NewStore) {
+
+	t.Run("should store, update and remove the webhooks of users", func(t *testing.T) {
+		s := newStore(t)
+		userId := uuid.New()
+		first := models.Webhook{Id: uuid.New(), UserId: userId, Url: "https://example.com/1", Created: time.Now().UTC()}
+		second := models.Webhook{Id: uuid.New(), UserId: userId, Url: "https://example.com/2", Created: time.Now().UTC()}
+		other := models.Webhook{Id: uuid.New(), UserId: uuid.New(), Url: "https://example.com/3", Created: time.Now().UTC()}
+		for _, webhook := range []models.Webhook{first, second, other} {
+			require.NoError(t, s.StoreWebhook(ctx, webhook))
+		}
+
+		stored, err := s.GetWebhook(ctx, first.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, first.Url, stored.Url)
+		userWebhooks, err := s.GetUserWebhooks(ctx, userId)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{first.Id, second.Id}, webhookIds(userWebhooks))
+		all, err := s.GetWebhooks(ctx)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{first.Id, second.Id, other.Id}, webhookIds(all))
+
+		first.LastSeq = 7
+		assert.NoError(t, s.UpdateWebhook(ctx, first))
+		stored, err = s.GetWebhook(ctx, first.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), stored.LastSeq)
+
+		assert.NoError(t, s.RemoveWebhook(ctx, first))
+		_, err = s.GetWebhook(ctx, first.Id)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.ErrorIs(t, s.UpdateWebhook(ctx, first), models.ErrNotFound)
+		userWebhooks, err = s.GetUserWebhooks(ctx, userId)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{second.Id}, webhookIds(userWebhooks))
+
+		userWebhooks, err = s.GetUserWebhooks(ctx, uuid.New())
+		assert.NoError(t, err)
+		assert.Empty(t, userWebhooks)
+	})
+
+	t.Run("should hold a webhook lease by a single owner", func(t *testing.T) {
+		s := newStore(t)
+		webhookId := uuid.New()
+
+		acquired, err := s.AcquireWebhookLease(ctx, webhookId, "a", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, acquired)
+		acquired, err = s.AcquireWebhookLease(ctx, webhookId, "b", time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, acquired)
+
+		// only released by its owner
+		assert.NoError(t, s.ReleaseWebhookLease(ctx, webhookId, "b"))
+		acquired, err = s.AcquireWebhookLease(ctx, webhookId, "b", time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, acquired)
+
+		assert.NoError(t, s.ReleaseWebhookLease(ctx, webhookId, "a"))
+		acquired, err = s.AcquireWebhookLease(ctx, webhookId, "b", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("should return the latest deliveries newest first", func(t *testing.T) {
+		s := newStore(t)
+		webhook := models.Webhook{Id: uuid.New(), UserId: uuid.New()}
+		require.NoError(t, s.StoreWebhook(ctx, webhook))
+		for seq := int64(1); seq <= 3; seq++ {
+			require.NoError(t, s.StoreWebhookDelivery(ctx, models.WebhookDelivery{Id: uuid.New(), WebhookId: webhook.Id, Seq: seq}))
+		}
+
+		deliveries, err := s.GetWebhookDeliveries(ctx, webhook.Id, 2)
+		assert.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		assert.Equal(t, int64(3), deliveries[0].Seq)
+		assert.Equal(t, int64(2), deliveries[1].Seq)
+
+		require.NoError(t, s.RemoveWebhook(ctx, webhook))
+		deliveries, err = s.GetWebhookDeliveries(ctx, webhook.Id, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, deliveries)
+	})
+}
+
+func webhookIds(webhooks []models.Webhook) []uuid.UUID {
+	ids := make([]uuid.UUID, len(webhooks))
+	for i, webhook := range webhooks {
+		ids[i] = webhook.Id
+	}
+	return ids
+}
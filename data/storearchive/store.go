@@ -0,0 +1,25 @@
+package storearchive
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+)
+
+// ArchiveStore keeps the closed orders, resolved swaps and fills moved out of the order book store
+// archiving the same records again is a no-op, so an interrupted archive run can be repeated
+type ArchiveStore interface {
+	ArchiveOrders(ctx context.Context, orders []models.Order, closedAt time.Time) error
+	ArchiveSwap(ctx context.Context, swap models.Swap) error
+	ArchiveFills(ctx context.Context, userId uuid.UUID, fills []models.Fill) error
+	// finds an archived order by its ID or clientOId, or returns ErrNotFound
+	FindOrderById(ctx context.Context, id uuid.UUID, isClientOId bool) (*models.Order, error)
+	// returns an archived swap, or ErrNotFound
+	GetSwap(ctx context.Context, swapId uuid.UUID) (*models.Swap, error)
+	GetSwapFills(ctx context.Context, userId uuid.UUID, swapId uuid.UUID) ([]models.Fill, error)
+	// archived fills history of the user, newest first
+	GetUserFills(ctx context.Context, userId uuid.UUID, filter models.HistoryFilter) (fills []models.Fill, nextCursor string, err error)
+	Close() error
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/orbs-network/order-book/models"
 )
@@ -19,3 +20,22 @@ func (m *MockBcClient) CheckPendingTxs(ctx context.Context) error {
 func (m *MockBcClient) UpdateMakerBalances(ctx context.Context) error {
 	return m.Error
 }
+
+type MockBlockchainStore struct {
+	Error    error
+	Tx       *models.Tx
+	Balance  *big.Int
+	Decimals int64
+}
+
+func (m *MockBlockchainStore) GetTx(ctx context.Context, id string) (*models.Tx, error) {
+	return m.Tx, m.Error
+}
+
+func (m *MockBlockchainStore) BalanceOf(ctx context.Context, token, adrs string) (*big.Int, error) {
+	return m.Balance, m.Error
+}
+
+func (m *MockBlockchainStore) TokenDecimals(ctx context.Context, token, adrs string) (int64, error) {
+	return m.Decimals, m.Error
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/orbs-network/order-book/abi"
+)
+
+var RePermitAddress = "0x4d415B58EA43988FfF7f50A3475718b0858fA0f0"
+
+var Eip712Domain = abi.Eip712Domain{
+	Name:              "RePermit",
+	Version:           "1",
+	ChainId:           big.NewInt(137),
+	VerifyingContract: common.HexToAddress(RePermitAddress),
+}
+
+// SignAbiOrder signs the order's EIP712 digest the same way a maker's wallet does (V is 27/28)
+func SignAbiOrder(key *ecdsa.PrivateKey, order abi.Order) string {
+	hash, err := order.Eip712Hash(Eip712Domain)
+	if err != nil {
+		panic(err)
+	}
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		panic(err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(sig)
+}
@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/orbs-network/order-book/models"
@@ -27,8 +29,29 @@ type MockOrderBookStore struct {
 	Sets map[string]map[string]struct{}
 	// Pending swaps
 	PendingSwaps []models.SwapTx
+	// on-chain balance of every maker, zero by default
+	MakerBalance decimal.Decimal
+	// locked firm quotes
+	FirmQuotes []models.FirmQuote
 	// PubSub
 	EventsChan chan []byte
+	// fills and trades history
+	Fills   []models.Fill
+	Trades  []models.Trade
+	Candles []models.Candle
+	// fees summed per token
+	FeeTotals []models.FeeTotal
+	// sequenced user events, the seq of each is its position
+	UserEvents   []models.UserEvent
+	userEventsMu sync.Mutex
+	// serializes LockOrderFrags, as the store does
+	locksMu sync.Mutex
+	// number of txs to fail with ErrVersionConflict before they are performed, and txs performed or failed
+	VersionConflicts int
+	Txs              int
+	// webhooks and their delivery attempts
+	Webhooks          []models.Webhook
+	WebhookDeliveries []models.WebhookDelivery
 }
 
 func (m *MockOrderBookStore) StoreOpenOrder(ctx context.Context, order models.Order) error {
@@ -63,6 +86,31 @@ func (m *MockOrderBookStore) FindOrderById(ctx context.Context, id uuid.UUID, is
 	return m.Order, nil
 }
 
+// LockOrderFrags locks each fragment on a copy of Order, then keeps the locked size on Order if all were locked
+func (m *MockOrderBookStore) LockOrderFrags(ctx context.Context, frags []models.OrderFrag) ([]models.Order, error) {
+	m.locksMu.Lock()
+	defer m.locksMu.Unlock()
+
+	orders := []models.Order{}
+	for _, frag := range frags {
+		order, err := m.FindOrderById(ctx, frag.OrderId, false)
+		if err != nil {
+			return nil, models.ErrNotFound
+		}
+		locked := *order
+		if locked.IsFilled() || locked.Lock(ctx, frag) != nil {
+			return nil, models.ErrSwapInvalid
+		}
+		orders = append(orders, locked)
+	}
+	for _, order := range orders {
+		if m.Order != nil && m.Order.Id == order.Id {
+			*m.Order = order
+		}
+	}
+	return orders, nil
+}
+
 func (m *MockOrderBookStore) GetOrdersAtPrice(ctx context.Context, symbol models.Symbol, price decimal.Decimal) ([]models.Order, error) {
 	if m.Error != nil {
 		return nil, m.Error
@@ -70,14 +118,14 @@ func (m *MockOrderBookStore) GetOrdersAtPrice(ctx context.Context, symbol models
 	return m.Orders, nil
 }
 
-func (m *MockOrderBookStore) GetMarketDepth(ctx context.Context, symbol models.Symbol, depth int) (models.MarketDepth, error) {
+func (m *MockOrderBookStore) GetMarketDepth(ctx context.Context, symbol models.Symbol, query models.DepthQuery) (models.MarketDepth, error) {
 	if m.Error != nil {
 		return models.MarketDepth{}, m.Error
 	}
 	return m.MarketDepth, nil
 }
 
-func (m *MockOrderBookStore) StoreSwap(ctx context.Context, swapId uuid.UUID, symbol models.Symbol, side models.Side, frags []models.OrderFrag) error {
+func (m *MockOrderBookStore) StoreSwap(ctx context.Context, swapId uuid.UUID, legs []models.RouteLeg, frags []models.OrderFrag) error {
 	if m.Error != nil {
 		return m.Error
 	}
@@ -98,6 +146,11 @@ func (m *MockOrderBookStore) GetOpenOrdersForUser(ctx context.Context, userId uu
 
 // Generic Building blocks with no biz logic in a single TX
 func (m *MockOrderBookStore) PerformTx(ctx context.Context, action func(txid uint) error) error {
+	m.Txs++
+	if m.VersionConflicts > 0 {
+		m.VersionConflicts--
+		return models.ErrVersionConflict
+	}
 	return m.Error
 }
 
@@ -121,6 +174,27 @@ func (m *MockOrderBookStore) TxRemoveOrder(ctx context.Context, txid uint, order
 	return m.Error
 }
 
+func (m *MockOrderBookStore) TxModifyOrderDeadlines(ctx context.Context, txid uint, operation models.Operation, order models.Order) error {
+	return m.Error
+}
+
+func (m *MockOrderBookStore) TxStoreOpenOrder(ctx context.Context, txid uint, order models.Order) error {
+	return m.Error
+}
+
+func (m *MockOrderBookStore) GetExpiredOrderIds(ctx context.Context, at time.Time) ([]uuid.UUID, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	ids := []uuid.UUID{}
+	for _, order := range m.Orders {
+		if order.IsExpired(at) {
+			ids = append(ids, order.Id)
+		}
+	}
+	return ids, nil
+}
+
 func (m *MockOrderBookStore) TxCloseOrder(ctx context.Context, txid uint, order models.Order) error {
 	return m.Error
 }
@@ -140,7 +214,7 @@ func (m *MockOrderBookStore) GetSwap(ctx context.Context, swapId uuid.UUID, open
 	if m.Error != nil {
 		return nil, m.Error
 	}
-	return models.NewSwap(models.Symbol("MATIC_USDC"), models.BUY, m.Frags), nil
+	return models.NewSwap([]models.RouteLeg{{Symbol: "MATIC_USDC", MakerSide: models.BUY}}, m.Frags), nil
 }
 
 func (m *MockOrderBookStore) RemoveSwap(ctx context.Context, swapId uuid.UUID) error {
@@ -152,6 +226,43 @@ func (m *MockOrderBookStore) GetOpenSwaps(ctx context.Context) ([]models.Swap, e
 	return []models.Swap{}, m.Error
 }
 
+func (m *MockOrderBookStore) StoreFirmQuote(ctx context.Context, quote models.FirmQuote) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	m.FirmQuotes = append(m.FirmQuotes, quote)
+	return nil
+}
+
+func (m *MockOrderBookStore) GetFirmQuote(ctx context.Context, quoteId uuid.UUID) (*models.FirmQuote, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	for _, quote := range m.FirmQuotes {
+		if quote.Id == quoteId {
+			return &quote, nil
+		}
+	}
+	return nil, models.ErrNotFound
+}
+
+func (m *MockOrderBookStore) TakeFirmQuote(ctx context.Context, quoteId uuid.UUID) (*models.FirmQuote, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	for i, quote := range m.FirmQuotes {
+		if quote.Id == quoteId {
+			m.FirmQuotes = append(m.FirmQuotes[:i], m.FirmQuotes[i+1:]...)
+			return &quote, nil
+		}
+	}
+	return nil, models.ErrNotFound
+}
+
+func (m *MockOrderBookStore) GetFirmQuotes(ctx context.Context) ([]models.FirmQuote, error) {
+	return append([]models.FirmQuote{}, m.FirmQuotes...), m.Error
+}
+
 func (m *MockOrderBookStore) GetMinAsk(ctx context.Context, symbol models.Symbol) models.OrderIter {
 	return m.AskOrderIter
 }
@@ -161,7 +272,7 @@ func (m *MockOrderBookStore) GetMaxBid(ctx context.Context, symbol models.Symbol
 }
 
 func (m *MockOrderBookStore) StoreNewPendingSwap(ctx context.Context, pendingSwap models.SwapTx) (*models.Swap, error) {
-	swap := models.NewSwap(m.Order.Symbol, m.Order.Side, m.Frags)
+	swap := models.NewSwap([]models.RouteLeg{{Symbol: m.Order.Symbol, MakerSide: m.Order.Side}}, m.Frags)
 	return swap, m.Error
 }
 
@@ -169,6 +280,10 @@ func (m *MockOrderBookStore) ResolveSwap(ctx context.Context, swap models.Swap)
 	return m.Error
 }
 
+func (m *MockOrderBookStore) TxResolveSwap(ctx context.Context, txid uint, swap models.Swap) error {
+	return m.Error
+}
+
 func (m *MockOrderBookStore) StoreUserResolvedSwap(ctx context.Context, userId uuid.UUID, swap models.Swap) error {
 	return m.Error
 }
@@ -177,6 +292,264 @@ func (m *MockOrderBookStore) GetUserResolvedSwapIds(ctx context.Context, userId
 	return []string{"111", "222"}, m.Error
 }
 
+func (m *MockOrderBookStore) GetResolvedSwapsUserIds(ctx context.Context) ([]uuid.UUID, error) {
+	return []uuid.UUID{}, m.Error
+}
+
+func (m *MockOrderBookStore) GetResolvedSwaps(ctx context.Context, resolvedBefore time.Time, limit int) ([]models.Swap, error) {
+	return []models.Swap{}, m.Error
+}
+
+func (m *MockOrderBookStore) RemoveResolvedSwap(ctx context.Context, swapId uuid.UUID, userIds []uuid.UUID) error {
+	return m.Error
+}
+
+func (m *MockOrderBookStore) StoreUserFill(ctx context.Context, userId uuid.UUID, fill models.Fill) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	m.Fills = append(m.Fills, fill)
+	return nil
+}
+
+func (m *MockOrderBookStore) TxStoreUserFill(ctx context.Context, txid uint, userId uuid.UUID, fill models.Fill) error {
+	return m.StoreUserFill(ctx, userId, fill)
+}
+
+func (m *MockOrderBookStore) GetUserFills(ctx context.Context, userId uuid.UUID, filter models.HistoryFilter) ([]models.Fill, string, error) {
+	if m.Error != nil {
+		return nil, "", m.Error
+	}
+	return m.Fills, "", nil
+}
+
+func (m *MockOrderBookStore) GetUserSwapFills(ctx context.Context, userId uuid.UUID, swapId uuid.UUID) ([]models.Fill, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	fills := []models.Fill{}
+	for _, fill := range m.Fills {
+		if fill.SwapId == swapId {
+			fills = append(fills, fill)
+		}
+	}
+	return fills, nil
+}
+
+func (m *MockOrderBookStore) BackfillUserFills(ctx context.Context, userId uuid.UUID, fills []models.Fill) (bool, error) {
+	if m.Error != nil {
+		return false, m.Error
+	}
+	if len(m.Fills) > 0 || len(fills) == 0 {
+		return false, nil
+	}
+	m.Fills = append(m.Fills, fills...)
+	return true, nil
+}
+
+func (m *MockOrderBookStore) TrimUserFills(ctx context.Context, userId uuid.UUID, before time.Time) error {
+	return m.Error
+}
+
+func (m *MockOrderBookStore) StoreTrade(ctx context.Context, trade models.Trade) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	m.Trades = append(m.Trades, trade)
+	return nil
+}
+
+func (m *MockOrderBookStore) TxStoreTrade(ctx context.Context, txid uint, trade models.Trade) error {
+	return m.StoreTrade(ctx, trade)
+}
+
+func (m *MockOrderBookStore) GetTrades(ctx context.Context, symbol models.Symbol, filter models.HistoryFilter) ([]models.Trade, string, error) {
+	if m.Error != nil {
+		return nil, "", m.Error
+	}
+	return m.Trades, "", nil
+}
+
+func (m *MockOrderBookStore) AddFeeTotals(ctx context.Context, totals []models.FeeTotal) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	for _, total := range totals {
+		found := false
+		for i := range m.FeeTotals {
+			if m.FeeTotals[i].Token == total.Token {
+				m.FeeTotals[i].TakerFees = m.FeeTotals[i].TakerFees.Add(total.TakerFees)
+				m.FeeTotals[i].MakerRebates = m.FeeTotals[i].MakerRebates.Add(total.MakerRebates)
+				found = true
+			}
+		}
+		if !found {
+			m.FeeTotals = append(m.FeeTotals, total)
+		}
+	}
+	return nil
+}
+
+func (m *MockOrderBookStore) GetFeeTotals(ctx context.Context) ([]models.FeeTotal, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	return m.FeeTotals, nil
+}
+
+func (m *MockOrderBookStore) StoreCandles(ctx context.Context, candles []models.Candle) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	m.Candles = candles
+	return nil
+}
+
+func (m *MockOrderBookStore) GetCandles(ctx context.Context, symbol models.Symbol, interval models.CandleInterval, startAt, endAt time.Time) ([]models.Candle, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	return m.Candles, nil
+}
+
+func (m *MockOrderBookStore) AppendUserEvent(ctx context.Context, userId uuid.UUID, event []byte) (int64, error) {
+	if m.Error != nil {
+		return 0, m.Error
+	}
+	m.userEventsMu.Lock()
+	defer m.userEventsMu.Unlock()
+	seq := int64(len(m.UserEvents) + 1)
+	m.UserEvents = append(m.UserEvents, models.UserEvent{Seq: seq, Data: event})
+	return seq, nil
+}
+
+func (m *MockOrderBookStore) GetUserEventSeq(ctx context.Context, userId uuid.UUID) (int64, error) {
+	if m.Error != nil {
+		return 0, m.Error
+	}
+	m.userEventsMu.Lock()
+	defer m.userEventsMu.Unlock()
+	if len(m.UserEvents) == 0 {
+		return 0, nil
+	}
+	return m.UserEvents[len(m.UserEvents)-1].Seq, nil
+}
+
+// ReadUserEvents waits briefly (not the full block) when there are no events, so subscriptions poll the mock
+func (m *MockOrderBookStore) ReadUserEvents(ctx context.Context, userId uuid.UUID, afterSeq, count int64, block time.Duration) ([]models.UserEvent, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	m.userEventsMu.Lock()
+	events := []models.UserEvent{}
+	for _, event := range m.UserEvents {
+		if event.Seq > afterSeq && int64(len(events)) < count {
+			events = append(events, event)
+		}
+	}
+	m.userEventsMu.Unlock()
+
+	if len(events) == 0 && block > 0 {
+		select {
+		case <-ctx.Done():
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	return events, nil
+}
+
+func (m *MockOrderBookStore) StoreWebhook(ctx context.Context, webhook models.Webhook) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	m.Webhooks = append(m.Webhooks, webhook)
+	return nil
+}
+
+func (m *MockOrderBookStore) UpdateWebhook(ctx context.Context, webhook models.Webhook) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	for i := range m.Webhooks {
+		if m.Webhooks[i].Id == webhook.Id {
+			m.Webhooks[i] = webhook
+			return nil
+		}
+	}
+	return models.ErrNotFound
+}
+
+func (m *MockOrderBookStore) RemoveWebhook(ctx context.Context, webhook models.Webhook) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	for i := range m.Webhooks {
+		if m.Webhooks[i].Id == webhook.Id {
+			m.Webhooks = append(m.Webhooks[:i], m.Webhooks[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *MockOrderBookStore) GetWebhook(ctx context.Context, webhookId uuid.UUID) (*models.Webhook, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	for _, webhook := range m.Webhooks {
+		if webhook.Id == webhookId {
+			return &webhook, nil
+		}
+	}
+	return nil, models.ErrNotFound
+}
+
+func (m *MockOrderBookStore) GetUserWebhooks(ctx context.Context, userId uuid.UUID) ([]models.Webhook, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	webhooks := []models.Webhook{}
+	for _, webhook := range m.Webhooks {
+		if webhook.UserId == userId {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *MockOrderBookStore) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return append([]models.Webhook{}, m.Webhooks...), m.Error
+}
+
+func (m *MockOrderBookStore) AcquireWebhookLease(ctx context.Context, webhookId uuid.UUID, owner string, ttl time.Duration) (bool, error) {
+	return m.Error == nil, m.Error
+}
+
+func (m *MockOrderBookStore) ReleaseWebhookLease(ctx context.Context, webhookId uuid.UUID, owner string) error {
+	return m.Error
+}
+
+func (m *MockOrderBookStore) StoreWebhookDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	if m.Error != nil {
+		return m.Error
+	}
+	m.WebhookDeliveries = append(m.WebhookDeliveries, delivery)
+	return nil
+}
+
+func (m *MockOrderBookStore) GetWebhookDeliveries(ctx context.Context, webhookId uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	deliveries := []models.WebhookDelivery{}
+	for i := len(m.WebhookDeliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if m.WebhookDeliveries[i].WebhookId == webhookId {
+			deliveries = append(deliveries, m.WebhookDeliveries[i])
+		}
+	}
+	return deliveries, nil
+}
+
 func (m *MockOrderBookStore) EnumSubKeysOf(tx context.Context, key string) ([]string, error) {
 	return []string{key + "111", key + "222"}, m.Error
 }
@@ -200,5 +573,5 @@ func (r *MockOrderBookStore) WriteStrKey(ctx context.Context, key, val string) e
 }
 
 func (r *MockOrderBookStore) GetMakerTokenBalance(ctx context.Context, token, wallet string) (decimal.Decimal, error) {
-	return decimal.Zero, nil
+	return r.MakerBalance, nil
 }
@@ -21,6 +21,23 @@ type MockOrderBookService struct {
 	User         *models.User
 	BeginSwapRes models.BeginSwapRes
 	OrderEvents  chan []byte
+	MarketEvents chan []byte
+	// replace orders
+	ReplaceOrdersRes service.ReplaceOrdersRes
+	// fills and trades history
+	Fills      []models.Fill
+	Trades     []models.Trade
+	NextCursor string
+	// candles
+	Candles []models.Candle
+	// fees summed per token
+	FeeTotals []models.FeeTotal
+	// firm quotes
+	FirmQuote models.FirmQuote
+	// webhooks
+	Webhook           models.Webhook
+	Webhooks          []models.Webhook
+	WebhookDeliveries []models.WebhookDelivery
 }
 
 func (m *MockOrderBookService) GetUserByPublicKey(ctx context.Context, publicKey string) (*models.User, error) {
@@ -43,6 +60,17 @@ func (m *MockOrderBookService) CancelOrder(ctx context.Context, input service.Ca
 	return &m.Order.Id, m.Error
 }
 
+func (m *MockOrderBookService) AmendOrder(ctx context.Context, input service.AmendOrderInput) (models.Order, error) {
+	if m.Error != nil {
+		return models.Order{}, m.Error
+	}
+	return *m.Order, nil
+}
+
+func (m *MockOrderBookService) ReplaceOrders(ctx context.Context, input service.ReplaceOrdersInput) (service.ReplaceOrdersRes, error) {
+	return m.ReplaceOrdersRes, m.Error
+}
+
 func (m *MockOrderBookService) GetOrderById(ctx context.Context, orderId uuid.UUID) (*models.Order, error) {
 	return m.Order, m.Error
 }
@@ -51,7 +79,7 @@ func (m *MockOrderBookService) GetOrderByClientOId(ctx context.Context, clientOI
 	return m.Order, m.Error
 }
 
-func (m *MockOrderBookService) GetMarketDepth(ctx context.Context, symbol models.Symbol, depth int) (models.MarketDepth, error) {
+func (m *MockOrderBookService) GetMarketDepth(ctx context.Context, symbol models.Symbol, query models.DepthQuery) (models.MarketDepth, error) {
 	return m.MarketDepth, m.Error
 }
 
@@ -63,8 +91,20 @@ func (m *MockOrderBookService) GetOpenOrders(ctx context.Context, userId uuid.UU
 	return m.Orders, len(m.Orders), m.Error
 }
 
-func (m *MockOrderBookService) GetSwapFills(ctx context.Context, userId uuid.UUID, symbol models.Symbol, startAt, endAt time.Time) ([]models.Fill, error) {
-	return nil, m.Error
+func (m *MockOrderBookService) GetFills(ctx context.Context, userId uuid.UUID, filter models.HistoryFilter) ([]models.Fill, string, error) {
+	return m.Fills, m.NextCursor, m.Error
+}
+
+func (m *MockOrderBookService) GetSwapFills(ctx context.Context, userId uuid.UUID, swapId uuid.UUID) ([]models.Fill, error) {
+	return m.Fills, m.Error
+}
+
+func (m *MockOrderBookService) GetTrades(ctx context.Context, symbol models.Symbol, filter models.HistoryFilter) ([]models.Trade, string, error) {
+	return m.Trades, m.NextCursor, m.Error
+}
+
+func (m *MockOrderBookService) GetCandles(ctx context.Context, symbol models.Symbol, interval models.CandleInterval, startAt, endAt time.Time) ([]models.Candle, error) {
+	return m.Candles, m.Error
 }
 
 func (m *MockOrderBookService) CancelOrdersForUser(ctx context.Context, userId uuid.UUID, symbol models.Symbol) (orderIds []uuid.UUID, err error) {
@@ -75,7 +115,7 @@ func (m *MockOrderBookService) CancelOrdersForUser(ctx context.Context, userId u
 	return ids, m.Error
 }
 
-func (m *MockOrderBookService) SubscribeUserOrders(ctx context.Context, userId uuid.UUID) (chan []byte, error) {
+func (m *MockOrderBookService) SubscribeUserOrders(ctx context.Context, userId uuid.UUID, fromSeq int64) (chan []byte, error) {
 	return m.OrderEvents, m.Error
 }
 
@@ -83,15 +123,59 @@ func (m *MockOrderBookService) UnsubscribeUserOrders(ctx context.Context, userId
 	return m.Error
 }
 
+func (m *MockOrderBookService) SubscribeMarketEvents(ctx context.Context, symbol models.Symbol) (chan []byte, error) {
+	return m.MarketEvents, m.Error
+}
+
+func (m *MockOrderBookService) UnsubscribeMarketEvents(ctx context.Context, symbol models.Symbol, clientChan chan []byte) error {
+	return m.Error
+}
+
+func (m *MockOrderBookService) CreateWebhook(ctx context.Context, userId uuid.UUID, url string) (models.Webhook, error) {
+	return m.Webhook, m.Error
+}
+
+func (m *MockOrderBookService) GetWebhooks(ctx context.Context, userId uuid.UUID) ([]models.Webhook, error) {
+	return m.Webhooks, m.Error
+}
+
+func (m *MockOrderBookService) DeleteWebhook(ctx context.Context, userId uuid.UUID, webhookId uuid.UUID) error {
+	return m.Error
+}
+
+func (m *MockOrderBookService) GetWebhookDeliveries(ctx context.Context, userId uuid.UUID, webhookId uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	return m.WebhookDeliveries, m.Error
+}
+
 func (m *MockOrderBookService) GetQuote(ctx context.Context, symbol models.Symbol, makerSide models.Side, inAmount decimal.Decimal, minOutAmount *decimal.Decimal, makerInToken string) (models.QuoteRes, error) {
 	return m.QuoteRes, m.Error
 }
 
+func (m *MockOrderBookService) GetQuoteForOut(ctx context.Context, symbol models.Symbol, makerSide models.Side, outAmount decimal.Decimal, maxInAmount *decimal.Decimal, makerInToken string) (models.QuoteRes, error) {
+	return m.QuoteRes, m.Error
+}
+
 // taker api instead of swap
 func (m *MockOrderBookService) BeginSwap(ctx context.Context, data models.QuoteRes) (models.BeginSwapRes, error) {
 	return m.BeginSwapRes, m.Error
 }
 
+func (m *MockOrderBookService) LockQuote(ctx context.Context, quote models.QuoteRes, ttl time.Duration) (models.FirmQuote, error) {
+	return m.FirmQuote, m.Error
+}
+
+func (m *MockOrderBookService) BeginSwapFromQuote(ctx context.Context, quoteId uuid.UUID, inToken, outToken string) (models.BeginSwapRes, error) {
+	return m.BeginSwapRes, m.Error
+}
+
+func (m *MockOrderBookService) GetFeeTotals(ctx context.Context) ([]models.FeeTotal, error) {
+	return m.FeeTotals, m.Error
+}
+
+func (m *MockOrderBookService) GetRouteQuote(ctx context.Context, routes [][]models.RouteLeg, inAmount decimal.Decimal, minOutAmount *decimal.Decimal) (models.QuoteRes, error) {
+	return m.QuoteRes, m.Error
+}
+
 func (m *MockOrderBookService) AbortSwap(ctx context.Context, swapId uuid.UUID) error {
 	return m.Error
 }
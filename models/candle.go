@@ -0,0 +1,111 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type CandleInterval string
+
+const (
+	CANDLE_1M CandleInterval = "1m"
+	CANDLE_5M CandleInterval = "5m"
+	CANDLE_1H CandleInterval = "1h"
+	CANDLE_1D CandleInterval = "1d"
+)
+
+// all intervals maintained for every symbol
+var CandleIntervals = []CandleInterval{CANDLE_1M, CANDLE_5M, CANDLE_1H, CANDLE_1D}
+
+var ErrInvalidCandleInterval = errors.New("invalid candle interval")
+
+func StrToCandleInterval(s string) (CandleInterval, error) {
+	switch s {
+	case "1m":
+		return CANDLE_1M, nil
+	case "5m":
+		return CANDLE_5M, nil
+	case "1h":
+		return CANDLE_1H, nil
+	case "1d":
+		return CANDLE_1D, nil
+	default:
+		return "", ErrInvalidCandleInterval
+	}
+}
+
+func (i CandleInterval) String() string {
+	return string(i)
+}
+
+func (i CandleInterval) Duration() time.Duration {
+	switch i {
+	case CANDLE_1M:
+		return time.Minute
+	case CANDLE_5M:
+		return 5 * time.Minute
+	case CANDLE_1H:
+		return time.Hour
+	case CANDLE_1D:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// BucketStart returns the start of the UTC bucket the given time falls in
+func (i CandleInterval) BucketStart(t time.Time) time.Time {
+	return t.UTC().Truncate(i.Duration())
+}
+
+// Candle is the OHLCV summary of a symbol's trades within a single interval bucket
+type Candle struct {
+	Symbol   Symbol          `json:"symbol"`
+	Interval CandleInterval  `json:"interval"`
+	Start    time.Time       `json:"start"`
+	Open     decimal.Decimal `json:"open"`
+	High     decimal.Decimal `json:"high"`
+	Low      decimal.Decimal `json:"low"`
+	Close    decimal.Decimal `json:"close"`
+	// in base token (A)
+	Volume decimal.Decimal `json:"volume"`
+	// in quote token (B)
+	QuoteVolume decimal.Decimal `json:"quoteVolume"`
+	Trades      int             `json:"trades"`
+	// mined time of the trades which set open and close, swaps may be resolved out of order
+	FirstTradeAt time.Time `json:"firstTradeAt"`
+	LastTradeAt  time.Time `json:"lastTradeAt"`
+}
+
+func NewCandle(symbol Symbol, interval CandleInterval, start time.Time) Candle {
+	return Candle{Symbol: symbol, Interval: interval, Start: start}
+}
+
+// AddTrade updates the candle with a trade of its bucket
+func (c *Candle) AddTrade(trade Trade) {
+	if c.Trades == 0 {
+		c.Open, c.High, c.Low, c.Close = trade.Price, trade.Price, trade.Price, trade.Price
+		c.FirstTradeAt, c.LastTradeAt = trade.Mined, trade.Mined
+	}
+
+	if trade.Price.GreaterThan(c.High) {
+		c.High = trade.Price
+	}
+	if trade.Price.LessThan(c.Low) {
+		c.Low = trade.Price
+	}
+	if trade.Mined.Before(c.FirstTradeAt) {
+		c.Open = trade.Price
+		c.FirstTradeAt = trade.Mined
+	}
+	if !trade.Mined.Before(c.LastTradeAt) {
+		c.Close = trade.Price
+		c.LastTradeAt = trade.Mined
+	}
+
+	c.Volume = c.Volume.Add(trade.Size)
+	c.QuoteVolume = c.QuoteVolume.Add(trade.Size.Mul(trade.Price))
+	c.Trades++
+}
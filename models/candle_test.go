@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCandleInterval_BucketStart(t *testing.T) {
+	at := time.Date(2024, 3, 1, 13, 47, 31, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 3, 1, 13, 47, 0, 0, time.UTC), CANDLE_1M.BucketStart(at))
+	assert.Equal(t, time.Date(2024, 3, 1, 13, 45, 0, 0, time.UTC), CANDLE_5M.BucketStart(at))
+	assert.Equal(t, time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC), CANDLE_1H.BucketStart(at))
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), CANDLE_1D.BucketStart(at))
+}
+
+func TestStrToCandleInterval(t *testing.T) {
+	interval, err := StrToCandleInterval("5m")
+	assert.NoError(t, err)
+	assert.Equal(t, CANDLE_5M, interval)
+
+	_, err = StrToCandleInterval("2m")
+	assert.ErrorIs(t, err, ErrInvalidCandleInterval)
+}
+
+func TestCandle_AddTrade(t *testing.T) {
+	start := time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC)
+	candle := NewCandle("MATIC-USDC", CANDLE_1H, start)
+
+	candle.AddTrade(Trade{Price: decimal.NewFromInt(10), Size: decimal.NewFromInt(1), Mined: start.Add(10 * time.Minute)})
+	candle.AddTrade(Trade{Price: decimal.NewFromInt(12), Size: decimal.NewFromInt(2), Mined: start.Add(20 * time.Minute)})
+	// resolved after the previous trade but mined before it
+	candle.AddTrade(Trade{Price: decimal.NewFromInt(8), Size: decimal.NewFromInt(1), Mined: start.Add(5 * time.Minute)})
+	candle.AddTrade(Trade{Price: decimal.NewFromInt(11), Size: decimal.NewFromInt(1), Mined: start.Add(30 * time.Minute)})
+
+	assert.Equal(t, "8", candle.Open.String())
+	assert.Equal(t, "12", candle.High.String())
+	assert.Equal(t, "8", candle.Low.String())
+	assert.Equal(t, "11", candle.Close.String())
+	assert.Equal(t, "5", candle.Volume.String())
+	assert.Equal(t, "53", candle.QuoteVolume.String())
+	assert.Equal(t, 4, candle.Trades)
+}
@@ -24,12 +24,20 @@ var ErrOrderNotUnfilled = errors.New("order should be completely unfilled")
 var ErrOrderNotPartialFilled = errors.New("order should be partially filled")
 var ErrOrderCancelled = errors.New("order is cancelled")
 var ErrInvalidInput = errors.New("invalid input")
+var ErrInvalidTimeInForce = errors.New("invalid time in force")
 var ErrUnexpectedSizeFilled = errors.New("unexpected sizeFilled")
 var ErrUnexpectedSizePending = errors.New("unexpected sizePending")
 var ErrIterFail = errors.New("failed to get bid/ask iterator from store")
 var ErrTokenNotsupported = errors.New("token is not supported")
 var ErrMinOutAmount = errors.New("OutAmount is less than MinOutAmount")
+var ErrOutAmount = errors.New("outAmount should be positive")
+var ErrMaxInAmount = errors.New("InAmount is more than MaxInAmount")
+var ErrInvalidSignature = errors.New("order signature is invalid or not signed by the user")
+var ErrSignedOrderMismatch = errors.New("signed order does not match order symbol, side, size or price")
 var ErrMaxRecExceeded = errors.New("max number of records exceeded, narrow down the range")
+var ErrQuoteExpired = errors.New("firm quote expired")
+var ErrQuoteMismatch = errors.New("firm quote does not match the requested tokens")
+var ErrVersionConflict = errors.New("order was changed since it was read, try again")
 
 // store generic errors
 var ErrValAlreadyInSet = errors.New("the value is already a member of the set")
@@ -3,11 +3,122 @@
 package models
 
 import (
+	"bytes"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
-func CreateUserOrdersEventKey(userId uuid.UUID) string {
-	return fmt.Sprintf("user_orders:%s", userId)
+const (
+	USER_EVENT_ORDER_CHANGED = "order-changed"
+	USER_EVENT_ORDER_FILL    = "order-fill"
+	USER_EVENT_ORDER_AMENDED = "order-amended"
+	USER_EVENT_ORDER_EXPIRED = "order-expired"
+	USER_EVENT_SWAP_LOCKED   = "swap-locked"
+	USER_EVENT_SWAP_STARTED  = "swap-started"
+	USER_EVENT_SWAP_ABORTED  = "swap-aborted"
+	USER_EVENT_SWAP_FAILED   = "swap-failed"
+	// sent in place of events no longer kept, not filtered
+	USER_EVENT_GAP = "events-gap"
+)
+
+// UserEventTypes are the user events a subscriber can filter by
+var UserEventTypes = []string{
+	USER_EVENT_ORDER_CHANGED, USER_EVENT_ORDER_FILL, USER_EVENT_ORDER_AMENDED, USER_EVENT_ORDER_EXPIRED,
+	USER_EVENT_SWAP_LOCKED, USER_EVENT_SWAP_STARTED, USER_EVENT_SWAP_ABORTED, USER_EVENT_SWAP_FAILED,
+}
+
+const (
+	// the taker aborted the swap
+	SWAP_ABORT_REASON_TAKER = "taker"
+	// the swap was not started in time and was aborted automatically
+	SWAP_ABORT_REASON_NOT_STARTED = "not-started"
+)
+
+// SwapEvent tells a maker about a swap their orders are locked in, with the fragments of their own orders only
+type SwapEvent struct {
+	Event  string    `json:"event"`
+	SwapId uuid.UUID `json:"swapId"`
+	Symbol Symbol    `json:"symbol"`
+	// swap-started and swap-failed only
+	TxHash string `json:"txHash,omitempty"`
+	// swap-aborted only
+	Reason    string          `json:"reason,omitempty"`
+	Frags     []SwapEventFrag `json:"frags"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// SwapEventFrag is the part of a maker's order in a swap
+type SwapEventFrag struct {
+	OrderId   uuid.UUID `json:"orderId"`
+	ClientOId uuid.UUID `json:"clientOrderId"`
+	// of the order, a routed swap has orders of several symbols
+	Symbol Symbol          `json:"symbol"`
+	Side   Side            `json:"side"`
+	Price  decimal.Decimal `json:"price"`
+	// in the order's size token
+	Size decimal.Decimal `json:"size"`
+}
+
+func NewSwapEventFrag(frag OrderFrag, order *Order) SwapEventFrag {
+	return SwapEventFrag{
+		OrderId:   order.Id,
+		ClientOId: order.ClientOId,
+		Symbol:    order.Symbol,
+		Side:      order.Side,
+		Price:     order.Price,
+		Size:      order.FragAtokenSize(frag),
+	}
+}
+
+// UserEvent is an order, fill or swap event of a user, numbered by its position in the user's event stream
+type UserEvent struct {
+	Seq  int64
+	Data []byte
+}
+
+// Payload returns the event JSON object with its "seq" added
+func (e UserEvent) Payload() []byte {
+	data := bytes.TrimSpace(e.Data)
+	if len(data) < 2 || data[0] != '{' {
+		return []byte(fmt.Sprintf(`{"seq":%d}`, e.Seq))
+	}
+	fields := bytes.TrimSpace(data[1:])
+	if fields[0] == '}' {
+		return []byte(fmt.Sprintf(`{"seq":%d}`, e.Seq))
+	}
+	return append([]byte(fmt.Sprintf(`{"seq":%d,`, e.Seq)), fields...)
+}
+
+// EventsGap tells a subscriber that events fromSeq to toSeq are no longer kept and were skipped
+type EventsGap struct {
+	Event   string `json:"event"`
+	FromSeq int64  `json:"fromSeq"`
+	ToSeq   int64  `json:"toSeq"`
+}
+
+func NewEventsGap(fromSeq, toSeq int64) EventsGap {
+	return EventsGap{Event: USER_EVENT_GAP, FromSeq: fromSeq, ToSeq: toSeq}
+}
+
+// CreateMarketEventKey creates the channel of a symbol's public book changes and trades
+func CreateMarketEventKey(symbol Symbol) string {
+	return fmt.Sprintf("market:%s", symbol)
+}
+
+const (
+	MARKET_EVENT_BOOK_CHANGE = "book-change"
+	MARKET_EVENT_TRADE       = "trade"
+)
+
+// MarketEvent is published to a symbol's market channel
+type MarketEvent struct {
+	Event  string `json:"event"`
+	Symbol Symbol `json:"symbol"`
+	// book-change only, the side whose levels may have changed
+	Side Side `json:"side,omitempty"`
+	// trade only
+	Trade *Trade `json:"trade,omitempty"`
 }
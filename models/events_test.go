@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserEvent_Payload(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{name: "adds seq as first field", data: `{"event":"order-fill","size":"1"}`, expected: `{"seq":3,"event":"order-fill","size":"1"}`},
+		{name: "empty object", data: `{ }`, expected: `{"seq":3}`},
+		{name: "not an object", data: `[]`, expected: `{"seq":3}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, string(UserEvent{Seq: 3, Data: []byte(test.data)}.Payload()))
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+var ErrInvalidFeeSchedule = errors.New("invalid fee schedule")
+
+var bps = decimal.NewFromInt(10000)
+
+// FeeRate in bps of the taker's out amount
+type FeeRate struct {
+	// taken from the taker's out amount
+	TakerFeeBps decimal.Decimal `json:"takerFeeBps"`
+	// paid to the maker out of the taker fee
+	MakerRebateBps decimal.Decimal `json:"makerRebateBps"`
+}
+
+// FeeSchedule is the fee rate of each symbol, and the maker rebate of each maker's tier
+type FeeSchedule struct {
+	Default FeeRate `json:"default"`
+	// instead of the default
+	Symbols map[Symbol]FeeRate `json:"symbols"`
+	// maker rebate bps of each tier, instead of the symbol's
+	Tiers map[string]decimal.Decimal `json:"tiers"`
+	// tier of each maker
+	Users map[uuid.UUID]string `json:"users"`
+}
+
+// Validate returns ErrInvalidFeeSchedule if a rate is out of [0, 10000) bps, a rebate is above its taker fee, or a user's tier is missing
+func (s *FeeSchedule) Validate() error {
+	rates := []FeeRate{s.Default}
+	for _, rate := range s.Symbols {
+		rates = append(rates, rate)
+	}
+	for _, rate := range rates {
+		if !isValidBps(rate.TakerFeeBps) || !isValidBps(rate.MakerRebateBps) || rate.MakerRebateBps.GreaterThan(rate.TakerFeeBps) {
+			return ErrInvalidFeeSchedule
+		}
+		for _, rebateBps := range s.Tiers {
+			if !isValidBps(rebateBps) || rebateBps.GreaterThan(rate.TakerFeeBps) {
+				return ErrInvalidFeeSchedule
+			}
+		}
+	}
+	for _, tier := range s.Users {
+		if _, ok := s.Tiers[tier]; !ok {
+			return ErrInvalidFeeSchedule
+		}
+	}
+	return nil
+}
+
+func isValidBps(value decimal.Decimal) bool {
+	return !value.IsNegative() && value.LessThan(bps)
+}
+
+// Rate returns the fee rate of the symbol, no fees for a nil schedule
+func (s *FeeSchedule) Rate(symbol Symbol) FeeRate {
+	if s == nil {
+		return FeeRate{}
+	}
+	if rate, ok := s.Symbols[symbol]; ok {
+		return rate
+	}
+	return s.Default
+}
+
+// MakerRebateBps returns the maker's rebate on the symbol, of their tier if they have one
+func (s *FeeSchedule) MakerRebateBps(symbol Symbol, userId uuid.UUID) decimal.Decimal {
+	if s == nil {
+		return decimal.Zero
+	}
+	if tier, ok := s.Users[userId]; ok {
+		return s.Tiers[tier]
+	}
+	return s.Rate(symbol).MakerRebateBps
+}
+
+// TakerFee returns the taker fee of the symbol on a gross out amount
+func (s *FeeSchedule) TakerFee(symbol Symbol, grossOutAmount decimal.Decimal) decimal.Decimal {
+	return FeeOf(grossOutAmount, s.Rate(symbol).TakerFeeBps)
+}
+
+// GrossOutAmount returns the out amount the taker fee of the symbol is taken from for the taker to get netOutAmount
+func (s *FeeSchedule) GrossOutAmount(symbol Symbol, netOutAmount decimal.Decimal) decimal.Decimal {
+	feeBps := s.Rate(symbol).TakerFeeBps
+	if feeBps.IsZero() {
+		return netOutAmount
+	}
+	return netOutAmount.Mul(bps).Div(bps.Sub(feeBps))
+}
+
+// ApplyToFill sets the fees of the fill of the maker's fragment, in the taker's out token
+func (s *FeeSchedule) ApplyToFill(fill *Fill, frag OrderFrag, makerUserId uuid.UUID) {
+	fill.TakerFee = s.TakerFee(fill.Symbol, frag.OutSize)
+	fill.MakerRebate = FeeOf(frag.OutSize, s.MakerRebateBps(fill.Symbol, makerUserId))
+	if fill.TakerFee.IsZero() && fill.MakerRebate.IsZero() {
+		return
+	}
+	// the maker gives the taker's out token, A when selling and B when buying
+	aToken, bToken := fill.Symbol.Tokens()
+	fill.FeeToken = bToken
+	if fill.Side == SELL {
+		fill.FeeToken = aToken
+	}
+}
+
+// FeeOf returns feeBps of amount
+func FeeOf(amount, feeBps decimal.Decimal) decimal.Decimal {
+	if feeBps.IsZero() {
+		return decimal.Zero
+	}
+	return amount.Mul(feeBps).Div(bps)
+}
+
+// FeeTotal is the sum of the fees of fills in a token, the protocol keeps the taker fees less the maker rebates
+type FeeTotal struct {
+	Token        string          `json:"token"`
+	TakerFees    decimal.Decimal `json:"takerFees"`
+	MakerRebates decimal.Decimal `json:"makerRebates"`
+}
+
+func (t FeeTotal) ProtocolFees() decimal.Decimal {
+	return t.TakerFees.Sub(t.MakerRebates)
+}
+
+// FeeTotalsOf sums the fees of the fills per token, in fills order
+func FeeTotalsOf(fills []Fill) []FeeTotal {
+	totals := []FeeTotal{}
+	index := map[string]int{}
+	for _, fill := range fills {
+		if fill.FeeToken == "" {
+			continue
+		}
+		i, ok := index[fill.FeeToken]
+		if !ok {
+			i = len(totals)
+			index[fill.FeeToken] = i
+			totals = append(totals, FeeTotal{Token: fill.FeeToken})
+		}
+		totals[i].TakerFees = totals[i].TakerFees.Add(fill.TakerFee)
+		totals[i].MakerRebates = totals[i].MakerRebates.Add(fill.MakerRebate)
+	}
+	return totals
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeSchedule(t *testing.T) {
+	tieredMaker := uuid.New()
+	schedule := &FeeSchedule{
+		Default: FeeRate{TakerFeeBps: decimal.NewFromInt(10), MakerRebateBps: decimal.NewFromInt(2)},
+		Symbols: map[Symbol]FeeRate{"ETH-USDC": {TakerFeeBps: decimal.NewFromInt(20), MakerRebateBps: decimal.NewFromInt(5)}},
+		Tiers:   map[string]decimal.Decimal{"vip": decimal.NewFromInt(8)},
+		Users:   map[uuid.UUID]string{tieredMaker: "vip"},
+	}
+
+	t.Run("should validate", func(t *testing.T) {
+		assert.NoError(t, schedule.Validate())
+
+		invalid := *schedule
+		invalid.Default = FeeRate{TakerFeeBps: decimal.NewFromInt(1), MakerRebateBps: decimal.NewFromInt(2)}
+		assert.ErrorIs(t, invalid.Validate(), ErrInvalidFeeSchedule)
+
+		// a tier rebate above a taker fee
+		invalid = *schedule
+		invalid.Tiers = map[string]decimal.Decimal{"vip": decimal.NewFromInt(15)}
+		assert.ErrorIs(t, invalid.Validate(), ErrInvalidFeeSchedule)
+
+		invalid = *schedule
+		invalid.Users = map[uuid.UUID]string{tieredMaker: "gold"}
+		assert.ErrorIs(t, invalid.Validate(), ErrInvalidFeeSchedule)
+	})
+
+	t.Run("should use the symbol rate, or the default", func(t *testing.T) {
+		assert.Equal(t, "20", schedule.Rate("ETH-USDC").TakerFeeBps.String())
+		assert.Equal(t, "10", schedule.Rate("MATIC-USDC").TakerFeeBps.String())
+		assert.Equal(t, "5", schedule.MakerRebateBps("ETH-USDC", uuid.New()).String())
+		assert.Equal(t, "8", schedule.MakerRebateBps("ETH-USDC", tieredMaker).String())
+	})
+
+	t.Run("should charge no fees without a schedule", func(t *testing.T) {
+		var none *FeeSchedule
+		assert.True(t, none.TakerFee("ETH-USDC", decimal.NewFromInt(100)).IsZero())
+		assert.Equal(t, "100", none.GrossOutAmount("ETH-USDC", decimal.NewFromInt(100)).String())
+
+		fill := Fill{Symbol: "ETH-USDC", Side: SELL}
+		none.ApplyToFill(&fill, OrderFrag{OutSize: decimal.NewFromInt(1)}, tieredMaker)
+		assert.Empty(t, fill.FeeToken)
+	})
+
+	t.Run("should gross up the out amount by the taker fee", func(t *testing.T) {
+		gross := schedule.GrossOutAmount("MATIC-USDC", decimal.NewFromInt(999))
+		assert.Equal(t, "1000", gross.String())
+		assert.Equal(t, "1", schedule.TakerFee("MATIC-USDC", gross).String())
+	})
+
+	t.Run("should set the fees of a fill in the taker's out token", func(t *testing.T) {
+		fill := Fill{Symbol: "ETH-USDC", Side: SELL}
+		schedule.ApplyToFill(&fill, OrderFrag{OutSize: decimal.NewFromInt(10), InSize: decimal.NewFromInt(20000)}, tieredMaker)
+		assert.Equal(t, "ETH", fill.FeeToken)
+		assert.Equal(t, "0.02", fill.TakerFee.String())
+		assert.Equal(t, "0.008", fill.MakerRebate.String())
+
+		fill = Fill{Symbol: "ETH-USDC", Side: BUY}
+		schedule.ApplyToFill(&fill, OrderFrag{OutSize: decimal.NewFromInt(2000), InSize: decimal.NewFromInt(1)}, uuid.New())
+		assert.Equal(t, "USDC", fill.FeeToken)
+		assert.Equal(t, "4", fill.TakerFee.String())
+		assert.Equal(t, "1", fill.MakerRebate.String())
+	})
+
+	t.Run("should sum the fees of fills per token", func(t *testing.T) {
+		totals := FeeTotalsOf([]Fill{
+			{FeeToken: "USDC", TakerFee: decimal.NewFromInt(4), MakerRebate: decimal.NewFromInt(1)},
+			{},
+			{FeeToken: "ETH", TakerFee: decimal.NewFromInt(1)},
+			{FeeToken: "USDC", TakerFee: decimal.NewFromInt(2), MakerRebate: decimal.NewFromInt(1)},
+		})
+		assert.Len(t, totals, 2)
+		assert.Equal(t, "USDC", totals[0].Token)
+		assert.Equal(t, "6", totals[0].TakerFees.String())
+		assert.Equal(t, "4", totals[0].ProtocolFees().String())
+		assert.Equal(t, "ETH", totals[1].Token)
+	})
+}
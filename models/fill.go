@@ -20,6 +20,10 @@ type Fill struct {
 	Price     decimal.Decimal `json:"price"`
 	Size      decimal.Decimal `json:"size"`
 	OrderSize decimal.Decimal `json:"orderSize"`
+	// fees in the taker's out token, the maker gets the rebate
+	FeeToken    string          `json:"feeToken,omitempty"`
+	TakerFee    decimal.Decimal `json:"takerFee"`
+	MakerRebate decimal.Decimal `json:"makerRebate"`
 }
 
 func NewFill(symbol Symbol, swap Swap, frag OrderFrag, order *Order) *Fill {
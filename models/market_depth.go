@@ -1,10 +1,145 @@
 package models
 
-import "github.com/shopspring/decimal"
+import (
+	"errors"
 
+	"github.com/shopspring/decimal"
+)
+
+// MarketDepth rows are best price first
+// L2 rows are [price, size, orderCount] per price level, L3 rows are [price, availableSize] per order
 type MarketDepth struct {
 	Asks   [][]decimal.Decimal `json:"asks"`
 	Bids   [][]decimal.Decimal `json:"bids"`
 	Symbol string              `json:"symbol"`
 	Time   int64               `json:"time"`
 }
+
+type DepthMode string
+
+const (
+	// aggregated by price level
+	DEPTH_L2 DepthMode = "L2"
+	// one row per order
+	DEPTH_L3 DepthMode = "L3"
+)
+
+var ErrInvalidDepthMode = errors.New("invalid depth mode")
+
+// StrToDepthMode parses a depth mode, empty string defaults to L2
+func StrToDepthMode(s string) (DepthMode, error) {
+	switch s {
+	case "", "L2":
+		return DEPTH_L2, nil
+	case "L3":
+		return DEPTH_L3, nil
+	default:
+		return "", ErrInvalidDepthMode
+	}
+}
+
+func (m DepthMode) String() string {
+	return string(m)
+}
+
+type DepthQuery struct {
+	Mode DepthMode
+	// max price levels (L2) or orders (L3) per side
+	Depth int
+	// optional L2 tick to group price levels by, zero for exact prices
+	Step decimal.Decimal
+}
+
+// DepthBuilder collects the orders of one side of the book, best price first, into depth rows
+type DepthBuilder struct {
+	side  Side
+	query DepthQuery
+	Rows  [][]decimal.Decimal
+}
+
+func NewDepthBuilder(side Side, query DepthQuery) *DepthBuilder {
+	return &DepthBuilder{side: side, query: query, Rows: [][]decimal.Decimal{}}
+}
+
+// Add adds the next order of the side, returns false once the depth is full and the order was not added
+func (b *DepthBuilder) Add(price, availableSize decimal.Decimal) bool {
+	if b.query.Mode == DEPTH_L3 {
+		if len(b.Rows) >= b.query.Depth {
+			return false
+		}
+		b.Rows = append(b.Rows, []decimal.Decimal{price, availableSize})
+		return true
+	}
+
+	// fully locked orders are not shown as liquidity
+	if !availableSize.IsPositive() {
+		return true
+	}
+
+	levelPrice := b.levelPrice(price)
+	if n := len(b.Rows); n > 0 && b.Rows[n-1][0].Equal(levelPrice) {
+		b.Rows[n-1][1] = b.Rows[n-1][1].Add(availableSize)
+		b.Rows[n-1][2] = b.Rows[n-1][2].Add(decimal.NewFromInt(1))
+		return true
+	}
+
+	if len(b.Rows) >= b.query.Depth {
+		return false
+	}
+	b.Rows = append(b.Rows, []decimal.Decimal{levelPrice, availableSize, decimal.NewFromInt(1)})
+	return true
+}
+
+// levelPrice rounds the price to the step away from the spread - asks up and bids down
+func (b *DepthBuilder) levelPrice(price decimal.Decimal) decimal.Decimal {
+	if !b.query.Step.IsPositive() {
+		return price
+	}
+	ticks := price.Div(b.query.Step)
+	if b.side == SELL {
+		return ticks.Ceil().Mul(b.query.Step)
+	}
+	return ticks.Floor().Mul(b.query.Step)
+}
+
+// DepthChange is the new size and order count of an L2 price level, zero when the level was removed
+type DepthChange struct {
+	Side  Side            `json:"side"`
+	Price decimal.Decimal `json:"price"`
+	Size  decimal.Decimal `json:"size"`
+	Count decimal.Decimal `json:"count"`
+}
+
+// DiffDepth returns the L2 levels which differ between two depths of the same symbol, asks first
+func DiffDepth(prev, next MarketDepth) []DepthChange {
+	changes := diffDepthSide(SELL, prev.Asks, next.Asks)
+	return append(changes, diffDepthSide(BUY, prev.Bids, next.Bids)...)
+}
+
+func diffDepthSide(side Side, prev, next [][]decimal.Decimal) []DepthChange {
+	changes := []DepthChange{}
+
+	prevLevels := make(map[string][]decimal.Decimal, len(prev))
+	for _, level := range prev {
+		prevLevels[level[0].String()] = level
+	}
+
+	for _, level := range next {
+		key := level[0].String()
+		prevLevel, ok := prevLevels[key]
+		delete(prevLevels, key)
+		if ok && prevLevel[1].Equal(level[1]) && prevLevel[2].Equal(level[2]) {
+			continue
+		}
+		changes = append(changes, DepthChange{Side: side, Price: level[0], Size: level[1], Count: level[2]})
+	}
+
+	// removed levels, in their previous order
+	for _, level := range prev {
+		if _, ok := prevLevels[level[0].String()]; ok {
+			changes = append(changes, DepthChange{Side: side, Price: level[0], Size: decimal.Zero, Count: decimal.Zero})
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDepthBuilder(t *testing.T) {
+	d := decimal.RequireFromString
+
+	t.Run("L3 should add a row per order up to depth", func(t *testing.T) {
+		b := NewDepthBuilder(SELL, DepthQuery{Mode: DEPTH_L3, Depth: 2})
+
+		assert.True(t, b.Add(d("1"), d("10")))
+		assert.True(t, b.Add(d("1"), d("0")))
+		assert.False(t, b.Add(d("2"), d("5")))
+		assert.Equal(t, [][]decimal.Decimal{{d("1"), d("10")}, {d("1"), d("0")}}, b.Rows)
+	})
+
+	t.Run("L2 should aggregate orders of a price level", func(t *testing.T) {
+		b := NewDepthBuilder(SELL, DepthQuery{Mode: DEPTH_L2, Depth: 2})
+
+		assert.True(t, b.Add(d("1"), d("10")))
+		assert.True(t, b.Add(d("1"), d("5")))
+		assert.True(t, b.Add(d("1"), d("0")), "locked order is skipped")
+		assert.True(t, b.Add(d("2"), d("3")))
+		assert.True(t, b.Add(d("2"), d("1")), "last level is completed")
+		assert.False(t, b.Add(d("3"), d("1")))
+
+		assert.Len(t, b.Rows, 2)
+		assert.Equal(t, "1 15 2", b.Rows[0][0].String()+" "+b.Rows[0][1].String()+" "+b.Rows[0][2].String())
+		assert.Equal(t, "2 4 2", b.Rows[1][0].String()+" "+b.Rows[1][1].String()+" "+b.Rows[1][2].String())
+	})
+
+	t.Run("L2 should group by step away from the spread", func(t *testing.T) {
+		asks := NewDepthBuilder(SELL, DepthQuery{Mode: DEPTH_L2, Depth: 10, Step: d("0.5")})
+		asks.Add(d("1.1"), d("1"))
+		asks.Add(d("1.5"), d("1"))
+		asks.Add(d("1.6"), d("1"))
+		assert.Len(t, asks.Rows, 2)
+		assert.Equal(t, "1.5", asks.Rows[0][0].String())
+		assert.Equal(t, "2", asks.Rows[0][2].String())
+		assert.Equal(t, "2", asks.Rows[1][0].String())
+
+		bids := NewDepthBuilder(BUY, DepthQuery{Mode: DEPTH_L2, Depth: 10, Step: d("0.5")})
+		bids.Add(d("1.9"), d("1"))
+		bids.Add(d("1.5"), d("1"))
+		bids.Add(d("1.4"), d("1"))
+		assert.Len(t, bids.Rows, 2)
+		assert.Equal(t, "1.5", bids.Rows[0][0].String())
+		assert.Equal(t, "2", bids.Rows[0][1].String())
+		assert.Equal(t, "1", bids.Rows[1][0].String())
+	})
+}
+
+func TestStrToDepthMode(t *testing.T) {
+	mode, err := StrToDepthMode("")
+	assert.NoError(t, err)
+	assert.Equal(t, DEPTH_L2, mode)
+
+	mode, err = StrToDepthMode("L3")
+	assert.NoError(t, err)
+	assert.Equal(t, DEPTH_L3, mode)
+
+	_, err = StrToDepthMode("L1")
+	assert.ErrorIs(t, err, ErrInvalidDepthMode)
+}
+
+func TestDiffDepth(t *testing.T) {
+	d := decimal.RequireFromString
+	level := func(price, size, count string) []decimal.Decimal {
+		return []decimal.Decimal{d(price), d(size), d(count)}
+	}
+
+	prev := MarketDepth{
+		Asks: [][]decimal.Decimal{level("2", "10", "1"), level("3", "5", "1")},
+		Bids: [][]decimal.Decimal{level("1", "4", "2")},
+	}
+	next := MarketDepth{
+		Asks: [][]decimal.Decimal{level("2", "10", "1"), level("2.5", "1", "1")},
+		Bids: [][]decimal.Decimal{level("1", "6", "3")},
+	}
+
+	changes := DiffDepth(prev, next)
+
+	assert.Len(t, changes, 3)
+	assert.Equal(t, SELL, changes[0].Side)
+	assert.Equal(t, "2.5", changes[0].Price.String())
+	assert.Equal(t, "1", changes[0].Size.String())
+	assert.Equal(t, SELL, changes[1].Side)
+	assert.Equal(t, "3", changes[1].Price.String())
+	assert.True(t, changes[1].Size.IsZero(), "removed level has zero size")
+	assert.Equal(t, BUY, changes[2].Side)
+	assert.Equal(t, "6", changes[2].Size.String())
+	assert.Equal(t, "3", changes[2].Count.String())
+
+	assert.Empty(t, DiffDepth(next, next))
+}
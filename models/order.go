@@ -34,6 +34,11 @@ type Order struct {
 	Timestamp   time.Time       `json:"timestamp"`
 	Signature   Signature       `json:"-" `
 	Cancelled   bool            `json:"cancelled"`
+	TimeInForce TimeInForce     `json:"timeInForce"`
+	// time in force expiry, zero if the order is good till cancelled
+	ExpiresAt time.Time `json:"expiresAt"`
+	// incremented by the store on every change, an order is only changed at the version it was read
+	Version int64 `json:"version"`
 }
 
 func (o *Order) OrderToMap() map[string]string {
@@ -41,6 +46,11 @@ func (o *Order) OrderToMap() map[string]string {
 	abiFragmentBytes, _ := json.Marshal(o.Signature.AbiFragment)
 	abiFragmentStr := string(abiFragmentBytes)
 
+	expiresAt := ""
+	if !o.ExpiresAt.IsZero() {
+		expiresAt = o.ExpiresAt.Format(time.RFC3339)
+	}
+
 	return map[string]string{
 		"id":          o.Id.String(),
 		"clientOId":   o.ClientOId.String(),
@@ -55,6 +65,9 @@ func (o *Order) OrderToMap() map[string]string {
 		"eip712Sig":   o.Signature.Eip712Sig,
 		"abiFragment": abiFragmentStr,
 		"cancelled":   fmt.Sprintf("%t", o.Cancelled),
+		"timeInForce": o.TimeInForce.String(),
+		"expiresAt":   expiresAt,
+		"version":     strconv.FormatInt(o.Version, 10),
 	}
 }
 
@@ -189,6 +202,32 @@ func (o *Order) MapToOrder(data map[string]string) error {
 		return fmt.Errorf("invalid cancelled value: %v", err)
 	}
 
+	// time in force fields are optional for orders stored before they were introduced
+	var timeInForce TimeInForce
+	if timeInForceStr := data["timeInForce"]; timeInForceStr != "" {
+		timeInForce, err = StrToTimeInForce(timeInForceStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	var expiresAt time.Time
+	if expiresAtStr := data["expiresAt"]; expiresAtStr != "" {
+		expiresAt, err = time.Parse(time.RFC3339, expiresAtStr)
+		if err != nil {
+			return fmt.Errorf("invalid expiresAt: %v", err)
+		}
+	}
+
+	// version is optional for orders stored before it was introduced
+	var version int64
+	if versionStr := data["version"]; versionStr != "" {
+		version, err = strconv.ParseInt(versionStr, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version: %v", err)
+		}
+	}
+
 	o.Id = id
 	o.ClientOId = clientOId
 	o.UserId = userId
@@ -204,6 +243,9 @@ func (o *Order) MapToOrder(data map[string]string) error {
 	o.Side = side
 	o.Timestamp = timestamp
 	o.Cancelled = cancelled
+	o.TimeInForce = timeInForce
+	o.ExpiresAt = expiresAt
+	o.Version = version
 
 	return nil
 }
@@ -236,6 +278,36 @@ func (o *Order) IsPending() bool {
 	return o.SizePending.GreaterThan(decimal.Zero)
 }
 
+// Deadline returns the signed expiry of the order, zero time if the order has no deadline
+func (o *Order) Deadline() time.Time {
+	deadline := o.Signature.AbiFragment.Info.Deadline
+	if deadline == nil || deadline.Sign() <= 0 || !deadline.IsInt64() {
+		return time.Time{}
+	}
+	return time.Unix(deadline.Int64(), 0).UTC()
+}
+
+// ExpiryTime returns the earliest of the signed deadline and the time in force expiry, zero time if the order never expires
+func (o *Order) ExpiryTime() time.Time {
+	deadline := o.Deadline()
+	if deadline.IsZero() || (!o.ExpiresAt.IsZero() && o.ExpiresAt.Before(deadline)) {
+		return o.ExpiresAt
+	}
+	return deadline
+}
+
+// IsExpired returns true if the order's signed deadline or time in force expiry has passed at the given time
+func (o *Order) IsExpired(at time.Time) bool {
+	expiry := o.ExpiryTime()
+	return !expiry.IsZero() && !expiry.After(at)
+}
+
+// IsDeadlinePassed returns true if the order's signed deadline has passed at the given time
+func (o *Order) IsDeadlinePassed(at time.Time) bool {
+	deadline := o.Deadline()
+	return !deadline.IsZero() && !deadline.After(at)
+}
+
 func (o *Order) IsOpen() bool {
 	return !o.Cancelled && !o.IsFilled()
 }
@@ -258,7 +330,7 @@ func BigInt2Dcml(num *big.Int, dcmls int64) decimal.Decimal {
 	return decimalValue.Div(divisor)
 }
 
-// Status returns the status of the order
+// OnchainPrice returns the price implied by the signed input and output amounts
 func (o *Order) OnchainPrice(inDec, outDec int) (decimal.Decimal, error) {
 	// patch for tests with no onchain data
 	if o.Signature.AbiFragment.Input.Amount == nil {
@@ -273,10 +345,7 @@ func (o *Order) OnchainPrice(inDec, outDec int) (decimal.Decimal, error) {
 	} else {
 		result = out.Div(in)
 	}
-	fmt.Println("onchain price: ", result.String())
-	fmt.Println("origin  price: ", o.Price.String())
-
-	return decimal.NewFromString(result.String())
+	return result, nil
 }
 
 func (o *Order) FragAtokenSize(frag OrderFrag) decimal.Decimal {
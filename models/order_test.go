@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"math/big"
 	"testing"
 	"time"
 
@@ -35,6 +36,7 @@ func TestOrder_OrderToMap(t *testing.T) {
 		Side:      BUY,
 		Timestamp: timestamp,
 		Cancelled: false,
+		Version:   3,
 	}
 
 	expectedMap := map[string]string{
@@ -51,6 +53,9 @@ func TestOrder_OrderToMap(t *testing.T) {
 		"eip712Sig":   order.Signature.Eip712Sig,
 		"abiFragment": "{\"Info\":{\"Reactor\":\"0x0000000000000000000000000000000000000000\",\"Swapper\":\"0x0000000000000000000000000000000000000000\",\"Nonce\":null,\"Deadline\":null,\"AdditionalValidationContract\":\"0x0000000000000000000000000000000000000000\",\"AdditionalValidationData\":null},\"ExclusiveFiller\":\"0x0000000000000000000000000000000000000000\",\"ExclusivityOverrideBps\":null,\"Input\":{\"Token\":\"0x0000000000000000000000000000000000000000\",\"Amount\":null},\"Outputs\":null}",
 		"cancelled":   "false",
+		"timeInForce": "",
+		"expiresAt":   "",
+		"version":     "3",
 	}
 
 	actualMap := order.OrderToMap()
@@ -77,6 +82,7 @@ func TestOrder_MapToOrder(t *testing.T) {
 			"eip712Sig":     "signature",
 			"abiFragment":   "{\"Info\":{\"Reactor\":\"0x0000000000000000000000000000000000000000\",\"Swapper\":\"0x0000000000000000000000000000000000000000\",\"Nonce\":null,\"Deadline\":null,\"AdditionalValidationContract\":\"0x0000000000000000000000000000000000000000\",\"AdditionalValidationData\":null},\"ExclusiveFiller\":\"0x0000000000000000000000000000000000000000\",\"ExclusivityOverrideBps\":null,\"Input\":{\"Token\":\"0x0000000000000000000000000000000000000000\",\"Amount\":null},\"Outputs\":null}",
 			"cancelled":     "false",
+			"version":       "7",
 		}
 
 		err := order.MapToOrder(data)
@@ -96,6 +102,7 @@ func TestOrder_MapToOrder(t *testing.T) {
 		assert.Equal(t, "buy", order.Side.String())
 		assert.Equal(t, "2021-01-01 00:00:00 +0000 UTC", order.Timestamp.String())
 		assert.Equal(t, false, order.Cancelled)
+		assert.Equal(t, int64(7), order.Version)
 	})
 
 	t.Run("when some data is missing", func(t *testing.T) {
@@ -416,3 +423,121 @@ func TestOrder_LockSide(t *testing.T) {
 		})
 	}
 }
+
+func TestOrder_IsExpired(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	newOrder := func(deadline *big.Int) Order {
+		return Order{Signature: Signature{AbiFragment: abi.Order{Info: abi.Info{Deadline: deadline}}}}
+	}
+
+	tests := []struct {
+		name     string
+		order    Order
+		expected bool
+	}{
+		{
+			name:     "no deadline",
+			order:    newOrder(nil),
+			expected: false,
+		},
+		{
+			name:     "zero deadline",
+			order:    newOrder(big.NewInt(0)),
+			expected: false,
+		},
+		{
+			name:     "deadline in the future",
+			order:    newOrder(big.NewInt(now.Add(time.Minute).Unix())),
+			expected: false,
+		},
+		{
+			name:     "deadline now",
+			order:    newOrder(big.NewInt(now.Unix())),
+			expected: true,
+		},
+		{
+			name:     "deadline in the past",
+			order:    newOrder(big.NewInt(now.Add(-time.Minute).Unix())),
+			expected: true,
+		},
+	}
+
+	withExpiry := func(order Order, expiresAt time.Time) Order {
+		order.ExpiresAt = expiresAt
+		return order
+	}
+
+	tests = append(tests, []struct {
+		name     string
+		order    Order
+		expected bool
+	}{
+		{
+			name:     "no deadline, time in force expired",
+			order:    withExpiry(newOrder(nil), now.Add(-time.Second)),
+			expected: true,
+		},
+		{
+			name:     "deadline in the future, time in force expired",
+			order:    withExpiry(newOrder(big.NewInt(now.Add(time.Hour).Unix())), now.Add(-time.Second)),
+			expected: true,
+		},
+		{
+			name:     "deadline in the past, time in force in the future",
+			order:    withExpiry(newOrder(big.NewInt(now.Add(-time.Minute).Unix())), now.Add(time.Hour)),
+			expected: true,
+		},
+		{
+			name:     "deadline and time in force in the future",
+			order:    withExpiry(newOrder(big.NewInt(now.Add(time.Hour).Unix())), now.Add(time.Minute)),
+			expected: false,
+		},
+	}...)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.order.IsExpired(now))
+		})
+	}
+}
+
+func TestOrder_TimeInForceRoundTrip(t *testing.T) {
+	timestamp, _ := time.Parse(time.RFC3339, "2021-01-01T00:00:00Z")
+	order := Order{
+		Id:          id,
+		ClientOId:   clientOId,
+		UserId:      userId,
+		Price:       decimal.NewFromFloat(10.99),
+		Symbol:      "MATIC-USDC",
+		Size:        decimal.NewFromInt(1000),
+		Side:        BUY,
+		Timestamp:   timestamp,
+		TimeInForce: GTT,
+		ExpiresAt:   timestamp.Add(time.Hour),
+	}
+
+	data := order.OrderToMap()
+	assert.Equal(t, "GTT", data["timeInForce"])
+	assert.Equal(t, "2021-01-01T01:00:00Z", data["expiresAt"])
+
+	actual := Order{}
+	assert.NoError(t, actual.MapToOrder(data))
+	assert.Equal(t, GTT, actual.TimeInForce)
+	assert.Equal(t, order.ExpiresAt, actual.ExpiresAt)
+
+	t.Run("orders stored without time in force fields", func(t *testing.T) {
+		delete(data, "timeInForce")
+		delete(data, "expiresAt")
+		actual := Order{}
+		assert.NoError(t, actual.MapToOrder(data))
+		assert.Equal(t, TimeInForce(""), actual.TimeInForce)
+		assert.True(t, actual.ExpiresAt.IsZero())
+	})
+
+	t.Run("invalid time in force", func(t *testing.T) {
+		data["timeInForce"] = "FOK"
+		actual := Order{}
+		assert.ErrorIs(t, actual.MapToOrder(data), ErrInvalidTimeInForce)
+	})
+}
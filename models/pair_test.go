@@ -55,3 +55,47 @@ func TestOrder_PairMngr(t *testing.T) {
 		}
 	})
 }
+
+func TestPairMngr_Routes(t *testing.T) {
+	m := NewPairMngr()
+
+	t.Run("should route through intermediate tokens when there is no direct pair", func(t *testing.T) {
+		routes := m.Routes("MATIC", "BTC")
+
+		assert.Equal(t, [][]RouteLeg{
+			{{Symbol: "MATIC-ETH", MakerSide: BUY}, {Symbol: "ETH-BTC", MakerSide: BUY}},
+			{{Symbol: "MATIC-USDCE", MakerSide: BUY}, {Symbol: "BTC-USDCE", MakerSide: SELL}},
+		}, routes)
+		in, out := routes[1][1].TakerTokens()
+		assert.Equal(t, "USDCE", in)
+		assert.Equal(t, "BTC", out)
+	})
+
+	t.Run("should return the direct pair first", func(t *testing.T) {
+		routes := m.Routes("USDC", "MATIC")
+
+		assert.Equal(t, []RouteLeg{{Symbol: "MATIC-USDC", MakerSide: SELL}}, routes[0])
+		for _, route := range routes[1:] {
+			assert.Len(t, route, 2)
+			in, _ := route[0].TakerTokens()
+			_, out := route[1].TakerTokens()
+			assert.Equal(t, "USDC", in)
+			assert.Equal(t, "MATIC", out)
+		}
+	})
+
+	t.Run("should not route a token to itself or to an unknown token", func(t *testing.T) {
+		assert.Empty(t, m.Routes("ETH", "ETH"))
+		assert.Empty(t, m.Routes("ETH", "XXX"))
+	})
+}
+
+func TestRouteLegsOf(t *testing.T) {
+	orders := []Order{
+		{Symbol: "MATIC-USDCE", Side: BUY},
+		{Symbol: "MATIC-USDCE", Side: BUY},
+		{Symbol: "BTC-USDCE", Side: SELL},
+	}
+
+	assert.Equal(t, []RouteLeg{{Symbol: "MATIC-USDCE", MakerSide: BUY}, {Symbol: "BTC-USDCE", MakerSide: SELL}}, RouteLegsOf(orders))
+}
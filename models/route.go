@@ -0,0 +1,70 @@
+package models
+
+import "sort"
+
+// RouteLeg is a quote on the book of a symbol, one of the legs of a route from the taker's in token to their out token
+type RouteLeg struct {
+	Symbol    Symbol `json:"symbol"`
+	MakerSide Side   `json:"makerSide"`
+	// address of the maker's in token (the taker's out token of the leg), to verify the makers' balance
+	MakerInToken string `json:"-"`
+}
+
+func NewRouteLeg(pair *Pair, takerInToken string) RouteLeg {
+	return RouteLeg{Symbol: pair.Symbol(), MakerSide: pair.GetMakerSide(takerInToken)}
+}
+
+// TakerTokens returns the taker's in and out token names of the leg
+func (l RouteLeg) TakerTokens() (inToken, outToken string) {
+	aToken, bToken := l.Symbol.Tokens()
+	if l.MakerSide == BUY {
+		return aToken, bToken
+	}
+	return bToken, aToken
+}
+
+// RouteLegsOf returns the legs of orders sorted by leg, as fragments of a routed quote are
+func RouteLegsOf(orders []Order) []RouteLeg {
+	legs := []RouteLeg{}
+	for _, order := range orders {
+		if len(legs) > 0 && legs[len(legs)-1].Symbol == order.Symbol && legs[len(legs)-1].MakerSide == order.Side {
+			continue
+		}
+		legs = append(legs, RouteLeg{Symbol: order.Symbol, MakerSide: order.Side})
+	}
+	return legs
+}
+
+// Routes returns the routes from inToken to outToken of one leg, the direct pair, and of two legs through an intermediate token
+func (m *PairMngr) Routes(inToken, outToken string) [][]RouteLeg {
+	routes := [][]RouteLeg{}
+	if inToken == outToken {
+		return routes
+	}
+
+	if pair := m.Resolve(inToken, outToken); pair != nil {
+		routes = append(routes, []RouteLeg{NewRouteLeg(pair, inToken)})
+	}
+
+	// intermediate tokens in name order, so routes are always returned in the same order
+	intermediates := []string{}
+	for _, pair := range m.token2PairArr[inToken] {
+		midToken := pair.aToken
+		if midToken == inToken {
+			midToken = pair.bToken
+		}
+		if midToken != outToken {
+			intermediates = append(intermediates, midToken)
+		}
+	}
+	sort.Strings(intermediates)
+
+	for _, midToken := range intermediates {
+		outPair := m.Resolve(midToken, outToken)
+		if outPair == nil {
+			continue
+		}
+		routes = append(routes, []RouteLeg{NewRouteLeg(m.Resolve(inToken, midToken), inToken), NewRouteLeg(outPair, midToken)})
+	}
+	return routes
+}
@@ -25,3 +25,11 @@ func StrToSide(s string) (Side, error) {
 func (s Side) String() string {
 	return string(s)
 }
+
+// Opposite returns the other side of the book, e.g. the taker side of a maker order
+func (s Side) Opposite() Side {
+	if s == BUY {
+		return SELL
+	}
+	return BUY
+}
@@ -21,8 +21,33 @@ func (a SwapStatus) String() string {
 }
 
 type QuoteRes struct {
-	Size       decimal.Decimal
+	// taker's out amount, after the taker fee
+	Size decimal.Decimal
+	// taker fee taken from the out amount of a direct quote
+	Fee decimal.Decimal
+	// taker's in amount
+	InAmount   decimal.Decimal
 	OrderFrags []OrderFrag
+	// legs of a routed quote, in order, the fragments of each leg follow those of the previous one. Empty for a direct quote
+	Legs []RouteLeg
+	// direct quotes only
+	Analytics QuoteAnalytics
+}
+
+// QuoteAnalytics describes the fill of a quote on the book, prices are in the B token per A token like order prices
+type QuoteAnalytics struct {
+	// price of the first order filled
+	BestPrice decimal.Decimal
+	// volume weighted average price of the fill
+	Vwap decimal.Decimal
+	// mid of the best bid and ask, zero when a side of the book is empty
+	MidPrice decimal.Decimal
+	// how much worse for the taker the vwap is than the mid, zero without a mid
+	PriceImpactBps decimal.Decimal
+	// price levels the fill took from
+	Levels int
+	// available size (A token) left on the top price levels of the side of the book after the fill
+	RemainingDepth decimal.Decimal
 }
 
 type OrderFrag struct {
@@ -50,9 +75,11 @@ func MarshalOrderFrags(orderFrags []OrderFrag) ([]byte, error) {
 }
 
 type Swap struct {
-	Id        uuid.UUID   `json:"id"`
+	Id uuid.UUID `json:"id"`
+	// symbol and maker side of the first leg, Legs has every leg of a routed swap
 	Symbol    string      `json:"symbol"`
 	Side      string      `json:"side"`
+	Legs      []RouteLeg  `json:"legs,omitempty"`
 	Created   time.Time   `json:"created"`
 	Started   time.Time   `json:"started"`
 	Mined     time.Time   `json:"mined"`
@@ -62,13 +89,26 @@ type Swap struct {
 	Frags     []OrderFrag `json:"frags"`
 }
 
-func NewSwap(symbol Symbol, side Side, frags []OrderFrag) *Swap {
-	return &Swap{
+func NewSwap(legs []RouteLeg, frags []OrderFrag) *Swap {
+	swap := &Swap{
 		Created: time.Now(),
 		Frags:   frags,
-		Symbol:  symbol.String(),
-		Side:    side.String(),
+		Legs:    legs,
+	}
+	if len(legs) > 0 {
+		swap.Symbol = legs[0].Symbol.String()
+		swap.Side = legs[0].MakerSide.String()
+	}
+	return swap
+}
+
+// IsFinalLeg tells if orders of the symbol and side are of the swap's last leg, the one its taker fee is taken from
+func (s *Swap) IsFinalLeg(symbol Symbol, side Side) bool {
+	if len(s.Legs) == 0 {
+		return true
 	}
+	last := s.Legs[len(s.Legs)-1]
+	return last.Symbol == symbol && last.MakerSide == side
 }
 
 func (s *Swap) IsStarted() bool {
@@ -28,4 +28,12 @@ func Test_Swap(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, string(res), expected)
 	})
+
+	t.Run("only the last leg of a routed swap is final", func(t *testing.T) {
+		swap := NewSwap([]RouteLeg{{Symbol: "MATIC-ETH", MakerSide: BUY}, {Symbol: "ETH-USDC", MakerSide: SELL}}, frags)
+
+		assert.False(t, swap.IsFinalLeg("MATIC-ETH", BUY))
+		assert.True(t, swap.IsFinalLeg("ETH-USDC", SELL))
+		assert.True(t, (&Swap{}).IsFinalLeg("MATIC-USDC", SELL))
+	})
 }
@@ -1,6 +1,9 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 type Symbol string
 
@@ -40,6 +43,15 @@ func (s Symbol) String() string {
 	return string(s)
 }
 
+// Tokens returns the A (base) and B (quote) token names of the symbol
+func (s Symbol) Tokens() (aToken, bToken string) {
+	arr := strings.SplitN(s.String(), "-", 2)
+	if len(arr) != 2 {
+		return "", ""
+	}
+	return arr[0], arr[1]
+}
+
 func GetAllSymbols() []Symbol {
 	symbols := make([]Symbol, 0, len(symbolsMap))
 	for key := range symbolsMap {
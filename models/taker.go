@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
@@ -11,3 +13,29 @@ type BeginSwapRes struct {
 	Fragments []OrderFrag
 	SwapId    uuid.UUID
 }
+
+// FirmQuote is a quote whose fragments are locked until it is converted to a swap or expires
+type FirmQuote struct {
+	Id uuid.UUID `json:"id"`
+	// a single leg unless the quote is routed
+	Legs      []RouteLeg      `json:"legs"`
+	InAmount  decimal.Decimal `json:"inAmount"`
+	OutAmount decimal.Decimal `json:"outAmount"`
+	Frags     []OrderFrag     `json:"frags"`
+	Created   time.Time       `json:"created"`
+	ExpiresAt time.Time       `json:"expiresAt"`
+}
+
+func (q *FirmQuote) IsExpired(now time.Time) bool {
+	return !now.Before(q.ExpiresAt)
+}
+
+// TakerTokens returns the taker's in token of the first leg and out token of the last leg
+func (q *FirmQuote) TakerTokens() (inToken, outToken string) {
+	if len(q.Legs) == 0 {
+		return "", ""
+	}
+	inToken, _ = q.Legs[0].TakerTokens()
+	_, outToken = q.Legs[len(q.Legs)-1].TakerTokens()
+	return inToken, outToken
+}
@@ -0,0 +1,30 @@
+package models
+
+type TimeInForce string
+
+const (
+	// good till cancelled (or till the signed deadline)
+	GTC TimeInForce = "GTC"
+	// good till an explicit expiry time, no later than the signed deadline
+	GTT TimeInForce = "GTT"
+	// rests as maker only and is auto cancelled after N seconds
+	POST_ONLY TimeInForce = "POST_ONLY"
+)
+
+// StrToTimeInForce parses a time in force, empty string defaults to GTC
+func StrToTimeInForce(s string) (TimeInForce, error) {
+	switch s {
+	case "", "GTC":
+		return GTC, nil
+	case "GTT":
+		return GTT, nil
+	case "POST_ONLY":
+		return POST_ONLY, nil
+	default:
+		return "", ErrInvalidTimeInForce
+	}
+}
+
+func (t TimeInForce) String() string {
+	return string(t)
+}
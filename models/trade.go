@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Trade is the public, anonymized view of a fill
+type Trade struct {
+	SwapId uuid.UUID `json:"swapId"`
+	Symbol Symbol    `json:"symbol"`
+	// side of the taker (aggressor)
+	Side     Side            `json:"side"`
+	Price    decimal.Decimal `json:"price"`
+	Size     decimal.Decimal `json:"size"`
+	Mined    time.Time       `json:"mined"`
+	Resolved time.Time       `json:"resolved"`
+}
+
+func NewTrade(fill Fill) Trade {
+	return Trade{
+		SwapId:   fill.SwapId,
+		Symbol:   fill.Symbol,
+		Side:     fill.Side.Opposite(),
+		Price:    fill.Price,
+		Size:     fill.Size,
+		Mined:    fill.Mined,
+		Resolved: fill.Resolved,
+	}
+}
+
+// HistoryFilter selects a page of fills or trades, newest first
+type HistoryFilter struct {
+	// optional
+	Symbol Symbol
+	Side   Side
+	// optional, StartAt is inclusive and EndAt is exclusive
+	StartAt time.Time
+	EndAt   time.Time
+	// returned with the previous page, empty for the first page
+	Cursor string
+	Limit  int
+}
+
+// Match checks the optional symbol and side of the filter
+func (f HistoryFilter) Match(symbol Symbol, side Side) bool {
+	return (f.Symbol == "" || f.Symbol == symbol) && (f.Side == "" || f.Side == side)
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTrade(t *testing.T) {
+	fill := Fill{OrderId: uuid.New(), ClientOId: uuid.New(), SwapId: uuid.New(), Symbol: "MATIC-USDC", Side: SELL, Price: decimal.NewFromInt(2), Size: decimal.NewFromInt(5)}
+
+	trade := NewTrade(fill)
+
+	assert.Equal(t, fill.SwapId, trade.SwapId)
+	assert.Equal(t, BUY, trade.Side, "trade side is the taker side")
+	assert.True(t, fill.Price.Equal(trade.Price))
+	assert.True(t, fill.Size.Equal(trade.Size))
+}
+
+func TestHistoryFilter_Match(t *testing.T) {
+	assert.True(t, HistoryFilter{}.Match("MATIC-USDC", BUY))
+	assert.True(t, HistoryFilter{Symbol: "MATIC-USDC", Side: BUY}.Match("MATIC-USDC", BUY))
+	assert.False(t, HistoryFilter{Symbol: "ETH-USDC"}.Match("MATIC-USDC", BUY))
+	assert.False(t, HistoryFilter{Side: SELL}.Match("MATIC-USDC", BUY))
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// max webhooks a user can register
+const MAX_USER_WEBHOOKS = 5
+
+var ErrInvalidWebhookUrl = errors.New("webhook url must be a valid https url")
+var ErrTooManyWebhooks = errors.New("max number of webhooks reached")
+
+// Webhook receives the user's order, fill and swap events, in seq order
+type Webhook struct {
+	Id     uuid.UUID `json:"id"`
+	UserId uuid.UUID `json:"userId"`
+	Url    string    `json:"url"`
+	// signs the payloads, only returned when the webhook is created
+	Secret  string    `json:"secret"`
+	Created time.Time `json:"created"`
+
+	// delivery state
+	// seq of the last user event delivered or given up on
+	LastSeq int64 `json:"lastSeq"`
+	// failed attempts to deliver the event after LastSeq
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"nextAttemptAt"`
+}
+
+// WebhookDelivery is a single attempt to deliver an event to a webhook
+type WebhookDelivery struct {
+	Id        uuid.UUID `json:"id"`
+	WebhookId uuid.UUID `json:"webhookId"`
+	Seq       int64     `json:"seq"`
+	Event     string    `json:"event"`
+	Attempt   int       `json:"attempt"`
+	Success   bool      `json:"success"`
+	// the event is no longer retried after this attempt
+	GaveUp     bool          `json:"gaveUp,omitempty"`
+	StatusCode int           `json:"statusCode,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Timestamp  time.Time     `json:"timestamp"`
+	Duration   time.Duration `json:"durationNs"`
+}
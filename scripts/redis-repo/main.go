@@ -236,7 +236,7 @@ func createSwaps() {
 
 	ctx := context.Background()
 
-	err = repository.StoreSwap(ctx, swapId, models.Symbol("MATIC_USDC"), models.BUY, fillOrders)
+	err = repository.StoreSwap(ctx, swapId, []models.RouteLeg{{Symbol: models.Symbol("MATIC_USDC"), MakerSide: models.BUY}}, fillOrders)
 	if err != nil {
 		log.Fatalf("error storing swap: %v", err)
 	}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/abi"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/shopspring/decimal"
+)
+
+type AmendOrderInput struct {
+	Id          uuid.UUID
+	IsClientOId bool
+	UserId      uuid.UUID
+	UserPubKey  string
+	// new total size, nil to keep the current size
+	Size *decimal.Decimal
+	// new price, nil to keep the current price. Requires a new signed order
+	Price       *decimal.Decimal
+	Eip712Sig   string
+	AbiFragment abi.Order
+	// version the order must be at, nil to amend the current version
+	Version *int64
+}
+
+// AmendOrder reduces the size of an open order and/or replaces its price in place, keeping its orderId and clientOId
+// size can only be reduced, and not below the filled and pending size of the order
+// a price replacement requires a new signed order and is not allowed while part of the order is pending
+// an amend of a given version fails with ErrVersionConflict once the order changed, otherwise the order is read and amended again
+func (s *Service) AmendOrder(ctx context.Context, input AmendOrderInput) (models.Order, error) {
+
+	if input.Size == nil && input.Price == nil {
+		logctx.Warn(ctx, "nothing to amend", logger.String("id", input.Id.String()))
+		return models.Order{}, models.ErrInvalidInput
+	}
+
+	var amended models.Order
+	amend := func() (err error) {
+		amended, err = s.amendOrder(ctx, input)
+		return err
+	}
+
+	var err error
+	if input.Version != nil {
+		if err = amend(); errors.Is(err, models.ErrVersionConflict) {
+			err = models.ErrVersionConflict
+		}
+	} else {
+		err = retryOnVersionConflict(ctx, "AmendOrder", amend)
+	}
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	logctx.Info(ctx, "order amended", logger.String("orderId", amended.Id.String()), logger.String("userId", amended.UserId.String()), logger.String("price", amended.Price.String()), logger.String("size", amended.Size.String()))
+
+	s.publishOrderAmendedEvent(ctx, &amended)
+
+	return amended, nil
+}
+
+func (s *Service) amendOrder(ctx context.Context, input AmendOrderInput) (models.Order, error) {
+	order, err := s.getOrder(ctx, input.IsClientOId, input.Id)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	if order == nil {
+		logctx.Warn(ctx, "order not found", logger.String("id", input.Id.String()), logger.Bool("isClientOId", input.IsClientOId))
+		return models.Order{}, models.ErrNotFound
+	}
+
+	if order.UserId != input.UserId {
+		logctx.Warn(ctx, "user not allowed to amend order", logger.String("orderId", order.Id.String()), logger.String("userId", input.UserId.String()))
+		return models.Order{}, models.ErrUnauthorized
+	}
+
+	if order.Cancelled {
+		logctx.Warn(ctx, "order is cancelled", logger.String("orderId", order.Id.String()))
+		return models.Order{}, models.ErrOrderCancelled
+	}
+
+	if order.IsFilled() {
+		logctx.Warn(ctx, "order already filled", logger.String("orderId", order.Id.String()))
+		return models.Order{}, models.ErrOrderFilled
+	}
+
+	if input.Version != nil && *input.Version != order.Version {
+		logctx.Warn(ctx, "order is not at the version to amend", logger.String("orderId", order.Id.String()), logger.Int("version", int(order.Version)), logger.Int("expectedVersion", int(*input.Version)))
+		return models.Order{}, models.ErrVersionConflict
+	}
+
+	amended := *order
+
+	// validate size
+	if input.Size != nil {
+		locked := order.SizeFilled.Add(order.SizePending)
+		if input.Size.IsZero() || input.Size.IsNegative() || input.Size.GreaterThanOrEqual(order.Size) {
+			logctx.Warn(ctx, "amended size must be positive and lower than the current size", logger.String("orderId", order.Id.String()), logger.String("size", order.Size.String()), logger.String("newSize", input.Size.String()))
+			return models.Order{}, models.ErrInvalidInput
+		}
+		if input.Size.LessThan(locked) || input.Size.Equal(order.SizeFilled) {
+			logctx.Warn(ctx, "amended size must be higher than the filled size and not lower than the filled and pending size", logger.String("orderId", order.Id.String()), logger.String("newSize", input.Size.String()), logger.String("sizeFilled", order.SizeFilled.String()), logger.String("sizePending", order.SizePending.String()))
+			return models.Order{}, models.ErrInvalidInput
+		}
+		amended.Size = *input.Size
+	}
+
+	// validate price
+	priceChanged := input.Price != nil && !input.Price.Equal(order.Price)
+	if input.Price != nil {
+		if input.Price.IsZero() || input.Price.IsNegative() {
+			logctx.Warn(ctx, "price has to be positive", logger.String("orderId", order.Id.String()), logger.String("price", input.Price.String()))
+			return models.Order{}, models.ErrInvalidInput
+		}
+		if order.IsPending() {
+			logctx.Warn(ctx, "price can not be amended while order is pending", logger.String("orderId", order.Id.String()), logger.String("sizePending", order.SizePending.String()))
+			return models.Order{}, models.ErrOrderPending
+		}
+		if priceChanged {
+			if err := s.validateCrossTrade(ctx, order.Symbol, order.Side, *input.Price); err != nil {
+				return models.Order{}, err
+			}
+		}
+		amended.Price = *input.Price
+		amended.Signature = models.Signature{
+			Eip712Sig:   input.Eip712Sig,
+			AbiFragment: input.AbiFragment,
+		}
+
+		// validate the new signature
+		if err := s.verifyOrderSignature(ctx, &amended, input.UserPubKey); err != nil {
+			return models.Order{}, err
+		}
+
+		// the new signed amounts must match the amended price and size
+		// a size reduction alone keeps the old signed order, which covers more than the amended size
+		if err := s.verifyOrderAmounts(ctx, &amended); err != nil {
+			return models.Order{}, err
+		}
+	}
+
+	err = s.orderBookStore.PerformTx(ctx, func(txid uint) error {
+		if priceChanged {
+			if err := s.orderBookStore.TxModifyPrices(ctx, txid, models.Remove, *order); err != nil {
+				return fmt.Errorf("failed removing order from prices: %w", err)
+			}
+			if err := s.orderBookStore.TxModifyPrices(ctx, txid, models.Add, amended); err != nil {
+				return fmt.Errorf("failed adding amended order to prices: %w", err)
+			}
+		}
+
+		// new signed deadline
+		if !amended.ExpiryTime().Equal(order.ExpiryTime()) {
+			if err := s.orderBookStore.TxModifyOrderDeadlines(ctx, txid, models.Remove, *order); err != nil {
+				return fmt.Errorf("failed removing order from deadlines: %w", err)
+			}
+			if err := s.orderBookStore.TxModifyOrderDeadlines(ctx, txid, models.Add, amended); err != nil {
+				return fmt.Errorf("failed adding amended order to deadlines: %w", err)
+			}
+		}
+
+		if err := s.orderBookStore.TxModifyOrder(ctx, txid, models.Update, amended); err != nil {
+			return fmt.Errorf("failed updating amended order: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		logctx.Error(ctx, "failed to amend order", logger.String("orderId", order.Id.String()), logger.Error(err))
+		return models.Order{}, err
+	}
+
+	// as stored by the update
+	amended.Version++
+	return amended, nil
+}
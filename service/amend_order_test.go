@@ -0,0 +1,71 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_AmendOrder(t *testing.T) {
+
+	userId := uuid.MustParse("a577273e-12de-4acc-a4f8-de7fb5b86e37")
+	orderId := uuid.MustParse("e577273e-12de-4acc-a4f8-de7fb5b86e37")
+
+	mockBcClient := &mocks.MockBcClient{IsVerified: true}
+
+	dec := func(f float64) *decimal.Decimal {
+		d := decimal.NewFromFloat(f)
+		return &d
+	}
+	openOrder := func(size, filled, pending float64) *models.Order {
+		return &models.Order{Id: orderId, UserId: userId, Price: decimal.NewFromFloat(10), Size: decimal.NewFromFloat(size), SizeFilled: decimal.NewFromFloat(filled), SizePending: decimal.NewFromFloat(pending)}
+	}
+
+	testCases := []struct {
+		name          string
+		order         *models.Order
+		err           error
+		input         service.AmendOrderInput
+		expectedSize  decimal.Decimal
+		expectedPrice decimal.Decimal
+		expectedErr   error
+	}{
+		{name: "nothing to amend - returns `ErrInvalidInput`", order: openOrder(50, 0, 0), input: service.AmendOrderInput{}, expectedErr: models.ErrInvalidInput},
+		{name: "unexpected error when finding order - returns error", err: assert.AnError, input: service.AmendOrderInput{Size: dec(10)}, expectedErr: assert.AnError},
+		{name: "order not found - returns `ErrNotFound`", input: service.AmendOrderInput{Size: dec(10)}, expectedErr: models.ErrNotFound},
+		{name: "order of other user - returns `ErrUnauthorized`", order: &models.Order{Id: orderId, UserId: uuid.New(), Size: decimal.NewFromFloat(50)}, input: service.AmendOrderInput{Size: dec(10)}, expectedErr: models.ErrUnauthorized},
+		{name: "order cancelled - returns `ErrOrderCancelled`", order: &models.Order{Id: orderId, UserId: userId, Size: decimal.NewFromFloat(50), Cancelled: true}, input: service.AmendOrderInput{Size: dec(10)}, expectedErr: models.ErrOrderCancelled},
+		{name: "order filled - returns `ErrOrderFilled`", order: openOrder(50, 50, 0), input: service.AmendOrderInput{Size: dec(10)}, expectedErr: models.ErrOrderFilled},
+		{name: "size increase - returns `ErrInvalidInput`", order: openOrder(50, 0, 0), input: service.AmendOrderInput{Size: dec(60)}, expectedErr: models.ErrInvalidInput},
+		{name: "size below filled and pending - returns `ErrInvalidInput`", order: openOrder(50, 10, 20), input: service.AmendOrderInput{Size: dec(25)}, expectedErr: models.ErrInvalidInput},
+		{name: "size equals filled - returns `ErrInvalidInput`", order: openOrder(50, 10, 0), input: service.AmendOrderInput{Size: dec(10)}, expectedErr: models.ErrInvalidInput},
+		{name: "size reduced to filled and pending", order: openOrder(50, 10, 20), input: service.AmendOrderInput{Size: dec(30)}, expectedSize: decimal.NewFromFloat(30), expectedPrice: decimal.NewFromFloat(10)},
+		{name: "size reduced", order: openOrder(50, 0, 0), input: service.AmendOrderInput{Size: dec(20)}, expectedSize: decimal.NewFromFloat(20), expectedPrice: decimal.NewFromFloat(10)},
+		{name: "price amended while pending - returns `ErrOrderPending`", order: openOrder(50, 0, 10), input: service.AmendOrderInput{Price: dec(11)}, expectedErr: models.ErrOrderPending},
+		{name: "negative price - returns `ErrInvalidInput`", order: openOrder(50, 0, 0), input: service.AmendOrderInput{Price: dec(-1)}, expectedErr: models.ErrInvalidInput},
+		{name: "price and size amended", order: openOrder(50, 10, 0), input: service.AmendOrderInput{Price: dec(11), Size: dec(40)}, expectedSize: decimal.NewFromFloat(40), expectedPrice: decimal.NewFromFloat(11)},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, _ := service.New(&mocks.MockOrderBookStore{Order: c.order, Error: c.err}, mockBcClient)
+
+			c.input.Id = orderId
+			c.input.UserId = userId
+
+			order, err := svc.AmendOrder(context.Background(), c.input)
+			assert.Equal(t, c.expectedErr, err)
+			if c.expectedErr == nil {
+				assert.Equal(t, orderId, order.Id)
+				assert.True(t, c.expectedSize.Equal(order.Size))
+				assert.True(t, c.expectedPrice.Equal(order.Price))
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/shopspring/decimal"
+)
+
+// GetQuoteForOut quotes the taker's in amount required to get exactly outAmount
+func (s *Service) GetQuoteForOut(ctx context.Context, symbol models.Symbol, makerSide models.Side, outAmount decimal.Decimal, maxInAmount *decimal.Decimal, makerInToken string) (models.QuoteRes, error) {
+	logctx.Info(ctx, "GetQuoteForOut started", logger.String("symbol", symbol.String()), logger.String("makerSide", makerSide.String()), logger.String("outAmount", outAmount.String()))
+	if maxInAmount != nil {
+		logctx.Info(ctx, "GetQuoteForOut maxInAmount requested", logger.String("symbol", symbol.String()), logger.String("makerSide", makerSide.String()), logger.String("maxInAmount", maxInAmount.String()))
+	}
+
+	if !outAmount.IsPositive() {
+		return models.QuoteRes{}, models.ErrOutAmount
+	}
+
+	// to verify onchain balance
+	walletVerifier := NewWalletVerifier(makerInToken)
+
+	// skip orders which expire before the swap can be mined
+	minDeadline := time.Now().UTC().Add(s.quoteExpiryMargin)
+
+	it, err := s.bookIter(ctx, symbol, makerSide)
+	if err != nil {
+		return models.QuoteRes{}, err
+	}
+
+	// the orders give the out amount and the taker fee
+	grossOutAmount := s.fees.GrossOutAmount(symbol, outAmount)
+
+	var res models.QuoteRes
+	stats := &quoteStats{}
+	if makerSide == models.SELL {
+		res, err = getInAmountOutAToken(ctx, it, grossOutAmount, walletVerifier, stats, minDeadline)
+	} else { // BUY
+		res, err = getInAmountOutBToken(ctx, it, grossOutAmount, walletVerifier, stats, minDeadline)
+	}
+	if err != nil {
+		logctx.Warn(ctx, "getQuoteResOut failed", logger.Error(err))
+		return models.QuoteRes{}, err
+	}
+	res.Fee = grossOutAmount.Sub(outAmount)
+	res.Size = outAmount
+	res.Analytics = stats.analytics(makerSide, s.quoteDepth(ctx, symbol))
+
+	// apply max amount in threshold
+	if maxInAmount != nil && maxInAmount.LessThan(res.InAmount) {
+		logctx.Info(ctx, "maxInAmount was applied", logger.String("symbol", symbol.String()), logger.String("maxInAmount", maxInAmount.String()), logger.String("amountIn", res.InAmount.String()))
+		return models.QuoteRes{}, models.ErrMaxInAmount
+	}
+
+	if !walletVerifier.CheckAll(ctx, s.orderBookStore) {
+		logctx.Error(ctx, "walletVerifier CheckAll return false", logger.String("makerInToken", makerInToken), logger.String("makerInAmount", res.Size.String()))
+		return models.QuoteRes{}, models.ErrInsufficientBalance
+	}
+
+	logctx.Info(ctx, "GetQuoteForOut Finished OK", logger.String("symbol", symbol.String()), logger.String("makerSide", makerSide.String()), logger.String("inAmount", res.InAmount.String()))
+	return res, nil
+}
+
+// PAIR/SYMBOL A-B (ETH-USDC)
+// amount out A token (ETH)
+// amount in B token (USD)
+func getInAmountOutAToken(ctx context.Context, it models.OrderIter, outAmountA decimal.Decimal, verifier *WalletVerifier, stats *quoteStats, minDeadline time.Time) (models.QuoteRes, error) {
+	res := models.QuoteRes{Size: outAmountA, InAmount: decimal.Zero}
+	var order *models.Order
+
+	for it.HasNext() && outAmountA.IsPositive() {
+		order = it.Next(ctx)
+		if validateOrder(ctx, order, minDeadline) {
+			// user gains A, the min of the order's available size and what is left to gain
+			takerGainA := decimal.Min(order.GetAvailableSize(), outAmountA)
+
+			// user spends B
+			takerSpendB := order.Price.Mul(takerGainA)
+
+			// to verify onChain the maker can spend A token the taker gains
+			verifier.Add(order.Signature.AbiFragment.Info.Swapper.String(), takerGainA)
+			stats.add(order, takerGainA)
+
+			// sub - add
+			outAmountA = outAmountA.Sub(takerGainA)
+			res.InAmount = res.InAmount.Add(takerSpendB)
+
+			res.OrderFrags = append(res.OrderFrags, models.OrderFrag{OrderId: order.Id, OutSize: takerGainA, InSize: takerSpendB})
+			logctx.Debug(ctx, "getInAmountOutAToken - append order frag", logger.String("takerGainA", takerGainA.String()), logger.String("takerSpendB", takerSpendB.String()))
+		}
+	}
+	// not all is gained - error
+	if outAmountA.IsPositive() {
+		logctx.Warn(ctx, models.ErrInsufficientLiquity.Error())
+		return models.QuoteRes{}, models.ErrInsufficientLiquity
+	}
+	logctx.Debug(ctx, "getInAmountOutAToken total", logger.String("inAmountB", res.InAmount.String()), logger.String("outAmountA", res.Size.String()))
+	return res, nil
+}
+
+// PAIR/SYMBOL A-B (ETH-USDC)
+// amount out B token (USD)
+// amount in A token (ETH)
+func getInAmountOutBToken(ctx context.Context, it models.OrderIter, outAmountB decimal.Decimal, verifier *WalletVerifier, stats *quoteStats, minDeadline time.Time) (models.QuoteRes, error) {
+	res := models.QuoteRes{Size: outAmountB, InAmount: decimal.Zero}
+	var order *models.Order
+
+	for it.HasNext() && outAmountB.IsPositive() {
+		order = it.Next(ctx)
+		if validateOrder(ctx, order, minDeadline) {
+			// max gain in B token for this order
+			orderSizeB := order.Price.Mul(order.GetAvailableSize())
+			// user gains the min of orderSizeB / outAmountB
+			takerGainB := decimal.Min(orderSizeB, outAmountB)
+
+			// user spends A
+			takerSpendA := takerGainB.Div(order.Price)
+
+			// to verify onChain maker has the B funds for the user to gain
+			verifier.Add(order.Signature.AbiFragment.Info.Swapper.String(), takerGainB)
+			stats.add(order, takerSpendA)
+
+			// sub - add
+			outAmountB = outAmountB.Sub(takerGainB)
+			res.InAmount = res.InAmount.Add(takerSpendA)
+
+			res.OrderFrags = append(res.OrderFrags, models.OrderFrag{OrderId: order.Id, OutSize: takerGainB, InSize: takerSpendA})
+			logctx.Debug(ctx, "getInAmountOutBToken - append order frag", logger.String("takerGainB", takerGainB.String()), logger.String("takerSpendA", takerSpendA.String()))
+		}
+	}
+	if outAmountB.IsPositive() {
+		logctx.Warn(ctx, models.ErrInsufficientLiquity.Error())
+		return models.QuoteRes{}, models.ErrInsufficientLiquity
+	}
+	logctx.Debug(ctx, "getInAmountOutBToken total", logger.String("inAmountA", res.InAmount.String()), logger.String("outAmountB", res.Size.String()))
+	return res, nil
+}
@@ -0,0 +1,90 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_GetQuoteForOut(t *testing.T) {
+	ctx := context.Background()
+	evmClient := &service.EvmClient{}
+
+	newOrder := func(side models.Side, price, size int64) models.Order {
+		return models.Order{Id: uuid.New(), Side: side, Price: decimal.NewFromInt(price), Size: decimal.NewFromInt(size), Signature: models.Signature{AbiFragment: mocks.AbiFragment}}
+	}
+	// asks best first
+	asks := func() models.OrderIter {
+		return &mocks.OrderIterMock{Orders: []models.Order{newOrder(models.SELL, 1000, 1), newOrder(models.SELL, 1001, 2), newOrder(models.SELL, 1002, 3)}, Index: -1}
+	}
+	// bids best first
+	bids := func() models.OrderIter {
+		return &mocks.OrderIterMock{Orders: []models.Order{newOrder(models.BUY, 900, 1), newOrder(models.BUY, 800, 2)}, Index: -1}
+	}
+	newStore := func() *mocks.MockOrderBookStore {
+		return &mocks.MockOrderBookStore{AskOrderIter: asks(), BidOrderIter: bids(), MakerBalance: decimal.NewFromInt(1000000)}
+	}
+
+	t.Run("should quote the B token required to get exactly the A token", func(t *testing.T) {
+		svc, _ := service.New(newStore(), evmClient)
+
+		res, err := svc.GetQuoteForOut(ctx, symbol, models.SELL, decimal.NewFromFloat(2.5), nil, "0xTOKEN")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2.5", res.Size.String())
+		// 1 * 1000 + 1.5 * 1001
+		assert.Equal(t, "2501.5", res.InAmount.String())
+		assert.Len(t, res.OrderFrags, 2)
+		assert.Equal(t, "1.5", res.OrderFrags[1].OutSize.String())
+		assert.Equal(t, "1501.5", res.OrderFrags[1].InSize.String())
+	})
+
+	t.Run("should quote the A token required to get exactly the B token", func(t *testing.T) {
+		svc, _ := service.New(newStore(), evmClient)
+
+		res, err := svc.GetQuoteForOut(ctx, symbol, models.BUY, decimal.NewFromInt(1300), nil, "0xTOKEN")
+
+		assert.NoError(t, err)
+		// 900 / 900 + 400 / 800
+		assert.Equal(t, "1.5", res.InAmount.String())
+		assert.Len(t, res.OrderFrags, 2)
+		assert.Equal(t, "400", res.OrderFrags[1].OutSize.String())
+	})
+
+	t.Run("should fail above maxInAmount", func(t *testing.T) {
+		svc, _ := service.New(newStore(), evmClient)
+		maxInAmount := decimal.NewFromInt(2500)
+
+		_, err := svc.GetQuoteForOut(ctx, symbol, models.SELL, decimal.NewFromFloat(2.5), &maxInAmount, "0xTOKEN")
+		assert.ErrorIs(t, err, models.ErrMaxInAmount)
+	})
+
+	t.Run("should fail when the book can't fill the out amount", func(t *testing.T) {
+		svc, _ := service.New(newStore(), evmClient)
+
+		_, err := svc.GetQuoteForOut(ctx, symbol, models.SELL, decimal.NewFromInt(7), nil, "0xTOKEN")
+		assert.ErrorIs(t, err, models.ErrInsufficientLiquity)
+	})
+
+	t.Run("should fail on zero out amount", func(t *testing.T) {
+		svc, _ := service.New(newStore(), evmClient)
+
+		_, err := svc.GetQuoteForOut(ctx, symbol, models.SELL, decimal.Zero, nil, "0xTOKEN")
+		assert.ErrorIs(t, err, models.ErrOutAmount)
+	})
+
+	t.Run("should fail when the makers' balance is too low", func(t *testing.T) {
+		store := newStore()
+		store.MakerBalance = decimal.NewFromInt(1)
+		svc, _ := service.New(store, evmClient)
+
+		_, err := svc.GetQuoteForOut(ctx, symbol, models.SELL, decimal.NewFromFloat(2.5), nil, "0xTOKEN")
+		assert.ErrorIs(t, err, models.ErrInsufficientBalance)
+	})
+}
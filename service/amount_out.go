@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/orbs-network/order-book/models"
 	"github.com/orbs-network/order-book/utils/logger"
@@ -15,6 +16,31 @@ func (s *Service) GetQuote(ctx context.Context, symbol models.Symbol, makerSide
 		logctx.Info(ctx, "GetQuote minOutAmount requested", logger.String("symbol", symbol.String()), logger.String("makerSide", makerSide.String()), logger.String("minOutAmount", minOutAmount.String()))
 	}
 
+	res, err := s.getGrossQuote(ctx, symbol, makerSide, inAmount, makerInToken)
+	if err != nil {
+		return models.QuoteRes{}, err
+	}
+
+	// the taker gets the out amount less the fee, the fragments keep the amounts of the orders
+	res.Fee = s.fees.TakerFee(symbol, res.Size)
+	res.Size = res.Size.Sub(res.Fee)
+
+	// apply min amount out threshold
+	if minOutAmount != nil {
+		logctx.Info(ctx, "minOutAmount check", logger.String("symbol", symbol.String()), logger.String("minOutAmount", minOutAmount.String()), logger.String("amountOut", res.Size.String()))
+		if minOutAmount.GreaterThan(res.Size) {
+			logctx.Info(ctx, "minOutAmount was applied", logger.String("symbol", symbol.String()))
+			return models.QuoteRes{}, models.ErrMinOutAmount
+		}
+	}
+
+	logctx.Info(ctx, "GetQuote Finished OK", logger.String("symbol", symbol.String()), logger.String("makerSide", makerSide.String()), logger.String("inAmount", inAmount.String()))
+	return res, nil
+}
+
+// getGrossQuote quotes the out amount of the orders for inAmount, before the taker fee
+// the makers' balance of their in token is verified
+func (s *Service) getGrossQuote(ctx context.Context, symbol models.Symbol, makerSide models.Side, inAmount decimal.Decimal, makerInToken string) (models.QuoteRes, error) {
 	// make sure inAmount is positivr
 	if !inAmount.IsPositive() {
 		return models.QuoteRes{}, models.ErrInAmount
@@ -23,59 +49,60 @@ func (s *Service) GetQuote(ctx context.Context, symbol models.Symbol, makerSide
 	// to verify onchain balance
 	walletVerifier := NewWalletVerifier(makerInToken)
 
-	var it models.OrderIter
+	// skip orders which expire before the swap can be mined
+	minDeadline := time.Now().UTC().Add(s.quoteExpiryMargin)
+
+	it, err := s.bookIter(ctx, symbol, makerSide)
+	if err != nil {
+		return models.QuoteRes{}, err
+	}
+
 	var res models.QuoteRes
-	var err error
+	stats := &quoteStats{}
 	if makerSide == models.SELL {
-		it = s.orderBookStore.GetMinAsk(ctx, symbol)
-		if it == nil {
-			logctx.Error(ctx, "GetMinAsk failed")
-			return models.QuoteRes{}, models.ErrIterFail
-		}
-		if !it.HasNext() {
-			logctx.Warn(ctx, "insufficient liquidity", logger.String("symbol", symbol.String()), logger.String("makerSide", makerSide.String()), logger.String("inAmount", inAmount.String()))
-			return models.QuoteRes{}, models.ErrInsufficientLiquity
-		}
-		res, err = getOutAmountInAToken(ctx, it, inAmount, walletVerifier)
-
+		res, err = getOutAmountInAToken(ctx, it, inAmount, walletVerifier, stats, minDeadline)
 	} else { // BUY
-		it = s.orderBookStore.GetMaxBid(ctx, symbol)
-		if it == nil {
-			logctx.Warn(ctx, "GetMaxBid failed no orders in iterator")
-			return models.QuoteRes{}, models.ErrIterFail
-		}
-		if !it.HasNext() {
-			logctx.Warn(ctx, "GetMaxBid failed no orders in iterator")
-			return models.QuoteRes{}, models.ErrInsufficientLiquity
-		}
-		res, err = getOutAmountInBToken(ctx, it, inAmount, walletVerifier)
+		res, err = getOutAmountInBToken(ctx, it, inAmount, walletVerifier, stats, minDeadline)
 	}
 	if err != nil {
 		logctx.Warn(ctx, "getQuoteResIn failed", logger.Error(err))
 		return models.QuoteRes{}, err
 	}
+	res.InAmount = inAmount
+	res.Analytics = stats.analytics(makerSide, s.quoteDepth(ctx, symbol))
 
-	// apply min amount out threshold
-	if minOutAmount != nil {
-		logctx.Info(ctx, "minOutAmount check", logger.String("symbol", symbol.String()), logger.String("minOutAmount", minOutAmount.String()), logger.String("amountOut", res.Size.String()))
-		if minOutAmount.GreaterThan(res.Size) {
-			logctx.Info(ctx, "minOutAmount was applied", logger.String("symbol", symbol.String()))
-			return models.QuoteRes{}, models.ErrMinOutAmount
-		}
-	}
-
+	// apply on-chain balance verification on maker's InToken (which is out amount)
 	if !walletVerifier.CheckAll(ctx, s.orderBookStore) {
 		logctx.Error(ctx, "walletVerifier CheckAll return false", logger.String("makerInToken", makerInToken), logger.String("makerInAmount", res.Size.String()))
 		return models.QuoteRes{}, models.ErrInsufficientBalance
 	}
-
-	// apply on-chain balance verification on maker's InToken (which is out amount)
-	logctx.Info(ctx, "GetQuote Finished OK", logger.String("symbol", symbol.String()), logger.String("makerSide", makerSide.String()), logger.String("inAmount", inAmount.String()))
-
 	return res, nil
 }
 
-func validateOrder(ctx context.Context, order *models.Order) bool {
+// bookIter returns the orders of the maker side best price first, asks for SELL and bids for BUY
+func (s *Service) bookIter(ctx context.Context, symbol models.Symbol, makerSide models.Side) (models.OrderIter, error) {
+	var it models.OrderIter
+	if makerSide == models.SELL {
+		it = s.orderBookStore.GetMinAsk(ctx, symbol)
+		if it == nil {
+			logctx.Error(ctx, "GetMinAsk failed")
+			return nil, models.ErrIterFail
+		}
+	} else { // BUY
+		it = s.orderBookStore.GetMaxBid(ctx, symbol)
+		if it == nil {
+			logctx.Warn(ctx, "GetMaxBid failed no orders in iterator")
+			return nil, models.ErrIterFail
+		}
+	}
+	if !it.HasNext() {
+		logctx.Warn(ctx, "insufficient liquidity", logger.String("symbol", symbol.String()), logger.String("makerSide", makerSide.String()))
+		return nil, models.ErrInsufficientLiquity
+	}
+	return it, nil
+}
+
+func validateOrder(ctx context.Context, order *models.Order, minDeadline time.Time) bool {
 	if order == nil {
 		logctx.Error(ctx, "iter_Next returned nil")
 		return false
@@ -85,6 +112,16 @@ func validateOrder(ctx context.Context, order *models.Order) bool {
 		logctx.Error(ctx, "cancelled order exists in the price list", logger.String("orderId", order.Id.String()))
 		return false
 	}
+	// skip orders past their time in force, not yet swept
+	if order.IsExpired(time.Now().UTC()) {
+		logctx.Debug(ctx, "skipping expired order", logger.String("orderId", order.Id.String()), logger.String("expiry", order.ExpiryTime().String()))
+		return false
+	}
+	// skip orders whose signed deadline is too close for the swap to be mined
+	if order.IsDeadlinePassed(minDeadline) {
+		logctx.Debug(ctx, "skipping order near its deadline", logger.String("orderId", order.Id.String()), logger.String("deadline", order.Deadline().String()))
+		return false
+	}
 	// skip orders with locked funds
 	return order.GetAvailableSize().IsPositive()
 }
@@ -92,14 +129,14 @@ func validateOrder(ctx context.Context, order *models.Order) bool {
 // PAIR/SYMBOL A-B (ETH-USDC)
 // amount in B token (USD)
 // amount out A token (ETH)
-func getOutAmountInAToken(ctx context.Context, it models.OrderIter, inAmountB decimal.Decimal, verifier *WalletVerifier) (models.QuoteRes, error) {
+func getOutAmountInAToken(ctx context.Context, it models.OrderIter, inAmountB decimal.Decimal, verifier *WalletVerifier, stats *quoteStats, minDeadline time.Time) (models.QuoteRes, error) {
 	outAmountA := decimal.NewFromInt(0)
 	var frags []models.OrderFrag
 	var order *models.Order
 
 	for it.HasNext() && inAmountB.IsPositive() {
 		order = it.Next(ctx)
-		if validateOrder(ctx, order) {
+		if validateOrder(ctx, order, minDeadline) {
 			// max Spend in B token for this order
 			orderSizeB := order.Price.Mul(order.GetAvailableSize())
 			// user spends B the min of orderSizeB / inAmountB
@@ -110,6 +147,7 @@ func getOutAmountInAToken(ctx context.Context, it models.OrderIter, inAmountB de
 
 			// to verify onChain the maker can spend A token the taker gains
 			verifier.Add(order.Signature.AbiFragment.Info.Swapper.String(), takerGainA)
+			stats.add(order, takerGainA)
 
 			//sub - add
 			inAmountB = inAmountB.Sub(takerSpendB)
@@ -132,13 +170,13 @@ func getOutAmountInAToken(ctx context.Context, it models.OrderIter, inAmountB de
 // PAIR/SYMBOL A-B (ETH-USDC)
 // amount in A token (ETH)
 // amount out B token (USD)
-func getOutAmountInBToken(ctx context.Context, it models.OrderIter, inAmountA decimal.Decimal, verifier *WalletVerifier) (models.QuoteRes, error) {
+func getOutAmountInBToken(ctx context.Context, it models.OrderIter, inAmountA decimal.Decimal, verifier *WalletVerifier, stats *quoteStats, minDeadline time.Time) (models.QuoteRes, error) {
 	outAmountB := decimal.NewFromInt(0)
 	var order *models.Order
 	var frags []models.OrderFrag
 	for it.HasNext() && inAmountA.IsPositive() {
 		order = it.Next(ctx)
-		if validateOrder(ctx, order) {
+		if validateOrder(ctx, order, minDeadline) {
 			// user Spends A
 			takerSpendA := decimal.Min(order.GetAvailableSize(), inAmountA)
 
@@ -147,6 +185,7 @@ func getOutAmountInBToken(ctx context.Context, it models.OrderIter, inAmountA de
 
 			// to verify onChain maker has the B funds for the user to gain
 			verifier.Add(order.Signature.AbiFragment.Info.Swapper.String(), takerGainB)
+			stats.add(order, takerSpendA)
 
 			// sub-add
 			inAmountA = inAmountA.Sub(takerSpendA)
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/data/store"
+	"github.com/orbs-network/order-book/data/storearchive"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// resolved swaps archived per run
+const archiveBatchSize = 100
+
+// Archiver moves resolved swaps, the orders they closed and the fills of their users out of the store into the archive
+// records are written to the archive before they are removed from the store, so a failed run is repeated by the next one
+type Archiver struct {
+	store   store.OrderBookStore
+	archive storearchive.ArchiveStore
+	// records are archived once they are older than this, zero turns archiving off
+	after time.Duration
+}
+
+// NewArchiver creates an archiver of the records older than SEC_ARCHIVE_AFTER
+func NewArchiver(store store.OrderBookStore, archive storearchive.ArchiveStore) (*Archiver, error) {
+	if store == nil {
+		return nil, errors.New("store cannot be nil")
+	}
+
+	if archive == nil {
+		return nil, errors.New("archive cannot be nil")
+	}
+
+	secAfter := utils.GetEnv("SEC_ARCHIVE_AFTER", "86400")
+	afterSec, err := strconv.Atoi(secAfter)
+	if err != nil || afterSec < 0 {
+		return nil, fmt.Errorf("SEC_ARCHIVE_AFTER is not a valid number of seconds: %q", secAfter)
+	}
+
+	return &Archiver{store: store, archive: archive, after: time.Duration(afterSec) * time.Second}, nil
+}
+
+// Start runs the archiver every ARCHIVE_POLL_SEC until ctx is done
+func (a *Archiver) Start(ctx context.Context) {
+	if a.after == 0 { // USE ZERO as Turn Off feature flag
+		logctx.Debug(ctx, "archiver is turned off")
+		return
+	}
+	pollSec, _ := strconv.Atoi(utils.GetEnv("ARCHIVE_POLL_SEC", "60"))
+	if pollSec <= 0 {
+		pollSec = 60
+	}
+	logctx.Debug(ctx, "starting archiver", logger.Int("pollSec", pollSec))
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(pollSec) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				a.RunOnce(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// RunOnce archives a batch of the swaps resolved before the cutoff, then the fills of their users stored before it
+func (a *Archiver) RunOnce(ctx context.Context) {
+	cutoff := time.Now().Add(-a.after)
+
+	swaps, err := a.store.GetResolvedSwaps(ctx, cutoff, archiveBatchSize)
+	if err != nil {
+		logctx.Error(ctx, "failed to get resolved swaps to archive", logger.Error(err))
+		return
+	}
+
+	userIds := map[uuid.UUID]struct{}{}
+	for _, swap := range swaps {
+		swapUserIds, err := a.archiveSwap(ctx, swap)
+		if err != nil {
+			logctx.Warn(ctx, "failed to archive swap", logger.String("swapId", swap.Id.String()), logger.Error(err))
+			continue
+		}
+		for _, userId := range swapUserIds {
+			userIds[userId] = struct{}{}
+		}
+	}
+
+	for userId := range userIds {
+		if err := a.archiveFills(ctx, userId, cutoff); err != nil {
+			logctx.Warn(ctx, "failed to archive user fills", logger.String("userId", userId.String()), logger.Error(err))
+		}
+	}
+}
+
+// archiveSwap archives the swap and the orders it closed, removes them from the store and returns the users of its orders
+func (a *Archiver) archiveSwap(ctx context.Context, swap models.Swap) ([]uuid.UUID, error) {
+	userIds := []uuid.UUID{}
+	closed := []models.Order{}
+	for _, frag := range swap.Frags {
+		order, err := a.store.FindOrderById(ctx, frag.OrderId, false)
+		if err == models.ErrNotFound {
+			// closed and archived with an earlier swap
+			if order, err = a.archive.FindOrderById(ctx, frag.OrderId, false); err == models.ErrNotFound {
+				logctx.Warn(ctx, "order of resolved swap not found", logger.String("swapId", swap.Id.String()), logger.String("orderId", frag.OrderId.String()))
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			userIds = append(userIds, order.UserId)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		userIds = append(userIds, order.UserId)
+		if !order.IsOpen() && !order.IsPending() {
+			closed = append(closed, *order)
+		}
+	}
+
+	if err := a.archive.ArchiveSwap(ctx, swap); err != nil {
+		return nil, err
+	}
+	if err := a.archive.ArchiveOrders(ctx, closed, swap.Resolved); err != nil {
+		return nil, err
+	}
+
+	if len(closed) > 0 {
+		err := a.store.PerformTx(ctx, func(txid uint) error {
+			for _, order := range closed {
+				if err := a.store.TxRemoveOrder(ctx, txid, order); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := a.store.RemoveResolvedSwap(ctx, swap.Id, userIds); err != nil {
+		return nil, err
+	}
+	logctx.Debug(ctx, "archived swap", logger.String("swapId", swap.Id.String()), logger.Int("closedOrders", len(closed)))
+	return userIds, nil
+}
+
+// archiveFills archives the fills of the user stored before the cutoff, then drops them from the store
+func (a *Archiver) archiveFills(ctx context.Context, userId uuid.UUID, cutoff time.Time) error {
+	filter := models.HistoryFilter{EndAt: cutoff, Limit: archiveBatchSize}
+	for {
+		fills, cursor, err := a.store.GetUserFills(ctx, userId, filter)
+		if err != nil {
+			return err
+		}
+		if err := a.archive.ArchiveFills(ctx, userId, fills); err != nil {
+			return err
+		}
+		if cursor == "" {
+			break
+		}
+		filter.Cursor = cursor
+	}
+	return a.store.TrimUserFills(ctx, userId, cutoff)
+}
@@ -0,0 +1,173 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/data/memrepo"
+	"github.com/orbs-network/order-book/data/sqlrepo"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiver_RunOnce(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should move the resolved swap, its closed orders and fills to the archive", func(t *testing.T) {
+		t.Setenv("SEC_ARCHIVE_AFTER", "0")
+		store, err := memrepo.NewMemoryRepository()
+		require.NoError(t, err)
+		db, err := sqlrepo.Open("sqlite", ":memory:")
+		require.NoError(t, err)
+		archive, err := sqlrepo.NewSqlRepository(db)
+		require.NoError(t, err)
+		defer archive.Close()
+
+		svc, err := service.New(store, &service.EvmClient{})
+		require.NoError(t, err)
+		svc.SetArchive(archive)
+		archiver, err := service.NewArchiver(store, archive)
+		require.NoError(t, err)
+
+		newOrder := func(filled int64) models.Order {
+			return models.Order{Id: uuid.New(), ClientOId: uuid.New(), UserId: uuid.New(), Symbol: "MATIC-USDC", Side: models.SELL, Price: decimal.NewFromInt(2), Size: decimal.NewFromInt(10), SizePending: decimal.Zero, SizeFilled: decimal.NewFromInt(filled), Timestamp: time.Now()}
+		}
+		filled, partial := newOrder(10), newOrder(5)
+		require.NoError(t, store.StoreOpenOrders(ctx, []models.Order{filled, partial}))
+		require.NoError(t, store.PerformTx(ctx, func(txid uint) error {
+			return store.TxCloseOrder(ctx, txid, filled)
+		}))
+
+		frags := []models.OrderFrag{{OrderId: filled.Id, OutSize: decimal.NewFromInt(10)}, {OrderId: partial.Id, OutSize: decimal.NewFromInt(5)}}
+		swap := models.Swap{Id: uuid.New(), Symbol: "MATIC-USDC", Frags: frags, Resolved: time.Now(), Succeeded: true}
+		require.NoError(t, store.StoreSwap(ctx, swap.Id, []models.RouteLeg{{Symbol: "MATIC-USDC", MakerSide: models.BUY}}, frags))
+		require.NoError(t, store.ResolveSwap(ctx, swap))
+		for _, order := range []models.Order{filled, partial} {
+			require.NoError(t, store.StoreUserResolvedSwap(ctx, order.UserId, swap))
+			require.NoError(t, store.StoreUserFill(ctx, order.UserId, models.Fill{OrderId: order.Id, SwapId: swap.Id, Symbol: "MATIC-USDC", Resolved: swap.Resolved}))
+		}
+		// fills older than the cutoff
+		time.Sleep(5 * time.Millisecond)
+
+		archiver.RunOnce(ctx)
+		archiver.RunOnce(ctx)
+
+		_, err = store.GetSwap(ctx, swap.Id, false)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		ids, err := store.GetUserResolvedSwapIds(ctx, filled.UserId)
+		assert.NoError(t, err)
+		assert.Empty(t, ids)
+		_, err = store.FindOrderById(ctx, filled.Id, false)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		_, err = store.FindOrderById(ctx, partial.Id, false)
+		assert.NoError(t, err)
+		storeFills, _, err := store.GetUserFills(ctx, filled.UserId, models.HistoryFilter{})
+		assert.NoError(t, err)
+		assert.Empty(t, storeFills)
+
+		order, err := svc.GetOrderById(ctx, filled.Id)
+		assert.NoError(t, err)
+		require.NotNil(t, order)
+		assert.Equal(t, "10", order.SizeFilled.String())
+		order, err = svc.GetOrderByClientOId(ctx, filled.ClientOId)
+		assert.NoError(t, err)
+		require.NotNil(t, order)
+		assert.Equal(t, filled.Id, order.Id)
+		// the clientOId of an archived order can't be reused
+		_, err = svc.CreateOrder(ctx, service.CreateOrderInput{UserId: filled.UserId, ClientOrderID: filled.ClientOId, Symbol: "MATIC-USDC", Side: models.SELL, Price: decimal.NewFromInt(2), Size: decimal.NewFromInt(1)})
+		assert.ErrorIs(t, err, models.ErrClashingClientOrderId)
+
+		fills, err := svc.GetSwapFills(ctx, filled.UserId, swap.Id)
+		assert.NoError(t, err)
+		require.Len(t, fills, 1)
+		assert.Equal(t, filled.Id, fills[0].OrderId)
+		fills, cursor, err := svc.GetFills(ctx, partial.UserId, models.HistoryFilter{})
+		assert.NoError(t, err)
+		require.Len(t, fills, 1)
+		assert.Equal(t, partial.Id, fills[0].OrderId)
+		assert.Empty(t, cursor)
+	})
+
+	t.Run("should not archive swaps resolved after the cutoff", func(t *testing.T) {
+		t.Setenv("SEC_ARCHIVE_AFTER", "3600")
+		store, err := memrepo.NewMemoryRepository()
+		require.NoError(t, err)
+		db, err := sqlrepo.Open("sqlite", ":memory:")
+		require.NoError(t, err)
+		archive, err := sqlrepo.NewSqlRepository(db)
+		require.NoError(t, err)
+		defer archive.Close()
+		archiver, err := service.NewArchiver(store, archive)
+		require.NoError(t, err)
+
+		swap := models.Swap{Id: uuid.New(), Symbol: "MATIC-USDC", Frags: []models.OrderFrag{}, Resolved: time.Now()}
+		require.NoError(t, store.StoreSwap(ctx, swap.Id, []models.RouteLeg{{Symbol: "MATIC-USDC", MakerSide: models.BUY}}, swap.Frags))
+		require.NoError(t, store.ResolveSwap(ctx, swap))
+
+		archiver.RunOnce(ctx)
+
+		_, err = store.GetSwap(ctx, swap.Id, false)
+		assert.NoError(t, err)
+		_, err = archive.GetSwap(ctx, swap.Id)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+}
+
+func TestService_GetSwapFills(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should return ErrNotFound for a swap which is not resolved", func(t *testing.T) {
+		store, err := memrepo.NewMemoryRepository()
+		require.NoError(t, err)
+		svc, err := service.New(store, &service.EvmClient{})
+		require.NoError(t, err)
+
+		_, err = svc.GetSwapFills(ctx, uuid.New(), uuid.New())
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should return the user's fills of a swap in the store", func(t *testing.T) {
+		store, err := memrepo.NewMemoryRepository()
+		require.NoError(t, err)
+		svc, err := service.New(store, &service.EvmClient{})
+		require.NoError(t, err)
+
+		userId := uuid.New()
+		// found by the swap ID, however long after the swap was resolved they were stored
+		swap := models.Swap{Id: uuid.New(), Frags: []models.OrderFrag{}, Resolved: time.Now().Add(-time.Hour)}
+		require.NoError(t, store.StoreSwap(ctx, swap.Id, []models.RouteLeg{{Symbol: "MATIC-USDC", MakerSide: models.BUY}}, swap.Frags))
+		require.NoError(t, store.ResolveSwap(ctx, swap))
+		fill := models.Fill{OrderId: uuid.New(), SwapId: swap.Id, Symbol: "MATIC-USDC"}
+		require.NoError(t, store.StoreUserFill(ctx, userId, fill))
+		require.NoError(t, store.StoreUserFill(ctx, userId, models.Fill{OrderId: uuid.New(), SwapId: uuid.New(), Symbol: "MATIC-USDC"}))
+
+		fills, err := svc.GetSwapFills(ctx, userId, swap.Id)
+		assert.NoError(t, err)
+		require.Len(t, fills, 1)
+		assert.Equal(t, fill.OrderId, fills[0].OrderId)
+	})
+}
+
+func TestNewArchiver(t *testing.T) {
+
+	t.Run("invalid SEC_ARCHIVE_AFTER - should fail", func(t *testing.T) {
+		store, err := memrepo.NewMemoryRepository()
+		require.NoError(t, err)
+		db, err := sqlrepo.Open("sqlite", ":memory:")
+		require.NoError(t, err)
+		archive, err := sqlrepo.NewSqlRepository(db)
+		require.NoError(t, err)
+		defer archive.Close()
+
+		for _, after := range []string{"1d", "-1", ""} {
+			t.Setenv("SEC_ARCHIVE_AFTER", after)
+			_, err := service.NewArchiver(store, archive)
+			assert.ErrorContains(t, err, "SEC_ARCHIVE_AFTER", after)
+		}
+	})
+}
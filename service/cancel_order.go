@@ -19,8 +19,23 @@ type CancelOrderInput struct {
 // Flow chart - https://miro.com/welcomeonboard/Umt0YnpDN3BEcUh1U0JZaHNpejJNUHV3QmpBTGpTNFdybXVlemk2QlV4RHAwc2xVSXR5VzM0NzJwUlhGZEFRMnwzMDc0NDU3MzU4MzEyODA0NjQ2fDI=?share_link_id=23847173917
 
 // CancelOrder cancels an order by its ID or clientOId. If `isClientOId` is true, the `id` is treated as a clientOinput.Id, otherwise it is treated as an orderId
+// the order is read again and cancelled again if it changed in between
 func (s *Service) CancelOrder(ctx context.Context, input CancelOrderInput) (*uuid.UUID, error) {
+	var order *models.Order
+	err := retryOnVersionConflict(ctx, "CancelOrder", func() (err error) {
+		order, err = s.cancelOrder(ctx, input)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishOrderEvent(ctx, order)
+
+	return &order.Id, nil
+}
 
+func (s *Service) cancelOrder(ctx context.Context, input CancelOrderInput) (*models.Order, error) {
 	order, err := s.getOrder(ctx, input.IsClientOId, input.Id)
 	if err != nil {
 		return nil, err
@@ -42,66 +57,80 @@ func (s *Service) CancelOrder(ctx context.Context, input CancelOrderInput) (*uui
 	}
 
 	err = s.orderBookStore.PerformTx(ctx, func(txid uint) error {
-		order.Cancelled = true
+		return s.txCancelOrder(ctx, txid, order)
+	})
+	if err != nil {
+		logctx.Warn(ctx, "failed to cancel order", logger.String("orderId", order.Id.String()), logger.Error(err))
+		return nil, err
+	}
 
-		// remove from prices
-		if err := s.orderBookStore.TxModifyPrices(ctx, txid, models.Remove, *order); err != nil {
-			logctx.Error(ctx, "Failed removing order from prices", logger.String("id", input.Id.String()), logger.String("side", order.Side.String()), logger.Error(err))
-			return fmt.Errorf("failed removing order from prices: %w", err)
-		}
+	logctx.Debug(ctx, "order cancelled", logger.String("orderId", order.Id.String()), logger.String("userId", order.UserId.String()), logger.String("size", order.Size.String()), logger.String("sizeFilled", order.SizeFilled.String()), logger.String("sizePending", order.SizePending.String()))
 
-		// remove from user's open orders
-		if err = s.orderBookStore.TxModifyUserOpenOrders(ctx, txid, models.Remove, *order); err != nil {
-			logctx.Error(ctx, "Failed removing order from user open orders", logger.String("id", input.Id.String()), logger.String("userId", order.UserId.String()), logger.Error(err))
-			return fmt.Errorf("failed removing order from user open orders: %w", err)
-		}
+	return order, nil
+}
 
-		switch {
-		// ORDER IS PARTIALLY FILLED AND NOT PENDING
-		case !order.IsUnfilled() && !order.IsPending():
-			logctx.Debug(ctx, "cancelling partially filled and not pending order", logger.String("orderId", order.Id.String()))
-			if err = s.orderBookStore.TxModifyOrder(ctx, txid, models.Update, *order); err != nil {
-				logctx.Error(ctx, "Failed updating order to cancelled", logger.String("id", input.Id.String()), logger.Error(err))
-				return fmt.Errorf("failed updating order to cancelled: %w", err)
-			}
-		// ORDER IS PARTIALLY FILLED AND PENDING
-		case !order.IsUnfilled() && order.IsPending():
-			logctx.Debug(ctx, "cancelling partially filled and pending order", logger.String("orderId", order.Id.String()))
-			if err = s.orderBookStore.TxModifyOrder(ctx, txid, models.Update, *order); err != nil {
-				logctx.Error(ctx, "Failed updating order", logger.String("id", input.Id.String()), logger.Error(err))
-				return fmt.Errorf("failed updating order: %w", err)
-			}
-		// ORDER IS UNFILLED AND NOT PENDING
-		case order.IsUnfilled() && !order.IsPending():
-			logctx.Debug(ctx, "cancelling unfilled and not pending order", logger.String("orderId", order.Id.String()))
-			if err := s.orderBookStore.TxModifyClientOId(ctx, txid, models.Remove, *order); err != nil {
-				logctx.Error(ctx, "Failed removing order from clientOId", logger.String("id", input.Id.String()), logger.Error(err))
-				return fmt.Errorf("failed removing unfilled order: %w", err)
-			}
-			if err = s.orderBookStore.TxModifyOrder(ctx, txid, models.Remove, *order); err != nil {
-				logctx.Error(ctx, "Failed removing order", logger.String("id", input.Id.String()), logger.Error(err))
-				return fmt.Errorf("failed removing unfilled order: %w", err)
-			}
-		// ORDER IS UNFILLED AND PENDING
-		case order.IsUnfilled() && order.IsPending():
-			logctx.Debug(ctx, "cancelling unfilled and pending order", logger.String("orderId", order.Id.String()))
-			if err = s.orderBookStore.TxModifyOrder(ctx, txid, models.Update, *order); err != nil {
-				logctx.Error(ctx, "Failed updating order", logger.String("id", input.Id.String()), logger.Error(err))
-				return fmt.Errorf("failed updating order: %w", err)
-			}
-		default:
-			logctx.Error(ctx, "unexpected order state", logger.String("orderId", order.Id.String()), logger.String("size", order.Size.String()), logger.String("sizeFilled", order.SizeFilled.String()), logger.String("sizePending", order.SizePending.String()))
-			return models.ErrUnexpectedError
-		}
+// txCancelOrder takes the order off the book within the given tx
+// unfilled orders which are not pending are removed entirely, others are kept as cancelled
+func (s *Service) txCancelOrder(ctx context.Context, txid uint, order *models.Order) error {
+	order.Cancelled = true
 
-		return nil
-	})
+	// remove from prices
+	if err := s.orderBookStore.TxModifyPrices(ctx, txid, models.Remove, *order); err != nil {
+		logctx.Error(ctx, "Failed removing order from prices", logger.String("id", order.Id.String()), logger.String("side", order.Side.String()), logger.Error(err))
+		return fmt.Errorf("failed removing order from prices: %w", err)
+	}
 
-	logctx.Debug(ctx, "order cancelled", logger.String("orderId", order.Id.String()), logger.String("userId", order.UserId.String()), logger.String("size", order.Size.String()), logger.String("sizeFilled", order.SizeFilled.String()), logger.String("sizePending", order.SizePending.String()))
+	// remove from user's open orders
+	if err := s.orderBookStore.TxModifyUserOpenOrders(ctx, txid, models.Remove, *order); err != nil {
+		logctx.Error(ctx, "Failed removing order from user open orders", logger.String("id", order.Id.String()), logger.String("userId", order.UserId.String()), logger.Error(err))
+		return fmt.Errorf("failed removing order from user open orders: %w", err)
+	}
 
-	s.publishOrderEvent(ctx, order)
+	// remove from deadlines
+	if err := s.orderBookStore.TxModifyOrderDeadlines(ctx, txid, models.Remove, *order); err != nil {
+		logctx.Error(ctx, "Failed removing order from deadlines", logger.String("id", order.Id.String()), logger.Error(err))
+		return fmt.Errorf("failed removing order from deadlines: %w", err)
+	}
 
-	return &order.Id, nil
+	switch {
+	// ORDER IS PARTIALLY FILLED AND NOT PENDING
+	case !order.IsUnfilled() && !order.IsPending():
+		logctx.Debug(ctx, "cancelling partially filled and not pending order", logger.String("orderId", order.Id.String()))
+		if err := s.orderBookStore.TxModifyOrder(ctx, txid, models.Update, *order); err != nil {
+			logctx.Error(ctx, "Failed updating order to cancelled", logger.String("id", order.Id.String()), logger.Error(err))
+			return fmt.Errorf("failed updating order to cancelled: %w", err)
+		}
+	// ORDER IS PARTIALLY FILLED AND PENDING
+	case !order.IsUnfilled() && order.IsPending():
+		logctx.Debug(ctx, "cancelling partially filled and pending order", logger.String("orderId", order.Id.String()))
+		if err := s.orderBookStore.TxModifyOrder(ctx, txid, models.Update, *order); err != nil {
+			logctx.Error(ctx, "Failed updating order", logger.String("id", order.Id.String()), logger.Error(err))
+			return fmt.Errorf("failed updating order: %w", err)
+		}
+	// ORDER IS UNFILLED AND NOT PENDING
+	case order.IsUnfilled() && !order.IsPending():
+		logctx.Debug(ctx, "cancelling unfilled and not pending order", logger.String("orderId", order.Id.String()))
+		if err := s.orderBookStore.TxModifyClientOId(ctx, txid, models.Remove, *order); err != nil {
+			logctx.Error(ctx, "Failed removing order from clientOId", logger.String("id", order.Id.String()), logger.Error(err))
+			return fmt.Errorf("failed removing unfilled order: %w", err)
+		}
+		if err := s.orderBookStore.TxModifyOrder(ctx, txid, models.Remove, *order); err != nil {
+			logctx.Error(ctx, "Failed removing order", logger.String("id", order.Id.String()), logger.Error(err))
+			return fmt.Errorf("failed removing unfilled order: %w", err)
+		}
+	// ORDER IS UNFILLED AND PENDING
+	case order.IsUnfilled() && order.IsPending():
+		logctx.Debug(ctx, "cancelling unfilled and pending order", logger.String("orderId", order.Id.String()))
+		if err := s.orderBookStore.TxModifyOrder(ctx, txid, models.Update, *order); err != nil {
+			logctx.Error(ctx, "Failed updating order", logger.String("id", order.Id.String()), logger.Error(err))
+			return fmt.Errorf("failed updating order: %w", err)
+		}
+	default:
+		logctx.Error(ctx, "unexpected order state", logger.String("orderId", order.Id.String()), logger.String("size", order.Size.String()), logger.String("sizeFilled", order.SizeFilled.String()), logger.String("sizePending", order.SizePending.String()))
+		return models.ErrUnexpectedError
+	}
+
+	return nil
 }
 
 func (s *Service) getOrder(ctx context.Context, isClientOId bool, orderId uuid.UUID) (order *models.Order, err error) {
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/orbs-network/order-book/data/store"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+type candleKey struct {
+	symbol   models.Symbol
+	interval models.CandleInterval
+	start    time.Time
+}
+
+// updateCandles adds the trades of a resolved swap to the candles of every interval
+func updateCandles(ctx context.Context, obStore store.OrderBookStore, trades []models.Trade) error {
+	candles := map[candleKey]*models.Candle{}
+	keys := []candleKey{}
+
+	for _, trade := range trades {
+		for _, interval := range models.CandleIntervals {
+			key := candleKey{symbol: trade.Symbol, interval: interval, start: interval.BucketStart(trade.Mined)}
+			candle, ok := candles[key]
+			if !ok {
+				existing, err := obStore.GetCandles(ctx, key.symbol, key.interval, key.start, key.start.Add(interval.Duration()))
+				if err != nil {
+					logctx.Error(ctx, "failed to get candle", logger.String("symbol", key.symbol.String()), logger.String("interval", interval.String()), logger.Error(err))
+					return err
+				}
+				newCandle := models.NewCandle(key.symbol, key.interval, key.start)
+				if len(existing) > 0 {
+					newCandle = existing[0]
+				}
+				candle = &newCandle
+				candles[key] = candle
+				keys = append(keys, key)
+			}
+			candle.AddTrade(trade)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	updated := make([]models.Candle, 0, len(keys))
+	for _, key := range keys {
+		updated = append(updated, *candles[key])
+	}
+
+	return obStore.StoreCandles(ctx, updated)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/orbs-network/order-book/data/store"
+	"github.com/orbs-network/order-book/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// candlesStore keeps candles in memory, other store methods are not used by updateCandles
+type candlesStore struct {
+	store.OrderBookStore
+	candles []models.Candle
+	err     error
+}
+
+func (c *candlesStore) GetCandles(ctx context.Context, symbol models.Symbol, interval models.CandleInterval, startAt, endAt time.Time) ([]models.Candle, error) {
+	res := []models.Candle{}
+	for _, candle := range c.candles {
+		if candle.Symbol == symbol && candle.Interval == interval && !candle.Start.Before(startAt) && candle.Start.Before(endAt) {
+			res = append(res, candle)
+		}
+	}
+	return res, c.err
+}
+
+func (c *candlesStore) StoreCandles(ctx context.Context, candles []models.Candle) error {
+	c.candles = candles
+	return c.err
+}
+
+func TestUpdateCandles(t *testing.T) {
+	ctx := context.Background()
+	mined := time.Date(2024, 3, 1, 13, 47, 31, 0, time.UTC)
+
+	trades := []models.Trade{
+		{Symbol: "MATIC-USDC", Side: models.BUY, Price: decimal.NewFromInt(2), Size: decimal.NewFromInt(10), Mined: mined},
+		{Symbol: "MATIC-USDC", Side: models.BUY, Price: decimal.NewFromInt(3), Size: decimal.NewFromInt(5), Mined: mined},
+	}
+
+	t.Run("should add trades to a candle of every interval", func(t *testing.T) {
+		store := &candlesStore{}
+
+		assert.NoError(t, updateCandles(ctx, store, trades))
+		assert.Len(t, store.candles, len(models.CandleIntervals))
+		for i, candle := range store.candles {
+			assert.Equal(t, models.CandleIntervals[i], candle.Interval)
+			assert.Equal(t, models.CandleIntervals[i].BucketStart(mined), candle.Start)
+			assert.Equal(t, "2", candle.Open.String())
+			assert.Equal(t, "3", candle.Close.String())
+			assert.Equal(t, "15", candle.Volume.String())
+			assert.Equal(t, 2, candle.Trades)
+		}
+	})
+
+	t.Run("should merge trades into the stored candle", func(t *testing.T) {
+		existing := models.NewCandle("MATIC-USDC", models.CANDLE_1M, models.CANDLE_1M.BucketStart(mined))
+		existing.AddTrade(models.Trade{Price: decimal.NewFromInt(1), Size: decimal.NewFromInt(1), Mined: mined.Add(-time.Second)})
+		store := &candlesStore{candles: []models.Candle{existing}}
+
+		assert.NoError(t, updateCandles(ctx, store, trades[:1]))
+		assert.Equal(t, "1", store.candles[0].Open.String())
+		assert.Equal(t, "2", store.candles[0].Close.String())
+		assert.Equal(t, "11", store.candles[0].Volume.String())
+	})
+
+	t.Run("should return store error", func(t *testing.T) {
+		store := &candlesStore{err: assert.AnError}
+
+		assert.ErrorIs(t, updateCandles(ctx, store, trades), assert.AnError)
+	})
+}
@@ -22,11 +22,20 @@ type CreateOrderInput struct {
 	ClientOrderID uuid.UUID
 	Eip712Sig     string
 	AbiFragment   abi.Order
+	// PubKey of the user, the order must be signed by its wallet
+	UserPubKey string
+	// defaults to GTC
+	TimeInForce models.TimeInForce
+	// GTT only
+	ExpiresAt time.Time
+	// POST_ONLY only
+	CancelAfter time.Duration
 }
 
 func (s *Service) CreateOrder(ctx context.Context, input CreateOrderInput) (models.Order, error) {
 
-	existingOrder, err := s.orderBookStore.FindOrderById(ctx, input.ClientOrderID, true)
+	// clientOIds of archived orders are still taken
+	existingOrder, err := s.findOrderByClientOId(ctx, input.ClientOrderID)
 
 	if err != nil && err != models.ErrNotFound {
 		logctx.Error(ctx, "unexpected error when finding order by clientOrderId", logger.Error(err))
@@ -63,33 +72,38 @@ func (s *Service) createNewOrder(ctx context.Context, input CreateOrderInput, us
 		return models.Order{}, models.ErrInvalidInput
 	}
 	// validate cross trade
-	depth, err := s.GetMarketDepth(ctx, input.Symbol, 1)
-	if err != nil {
-		logctx.Warn(ctx, "market depth failed", logger.String("orderId", orderId.String()), logger.String("price", input.Price.String()))
+	if err := s.validateCrossTrade(ctx, input.Symbol, input.Side, input.Price); err != nil {
+		return models.Order{}, err
 	}
-	if len(depth.Asks) > 0 {
-		minAsk := depth.Asks[0][0]
-		if input.Side == models.BUY && input.Price.GreaterThanOrEqual(minAsk) {
-			logctx.Warn(ctx, "CrossTrade order rejected. bid price is higher than minAsk", logger.String("orderId", orderId.String()), logger.String("price", input.Price.String()), logger.String("min_ask", minAsk.String()))
-			return models.Order{}, models.ErrCrossTrade
-		}
+
+	order, err := s.buildOrder(ctx, orderId, input, userId)
+	if err != nil {
+		return models.Order{}, err
 	}
 
-	szBids := len(depth.Bids)
-	if szBids > 0 {
-		maxBid := depth.Bids[0][szBids-1]
-		if input.Side == models.SELL && input.Price.LessThanOrEqual(maxBid) {
-			logctx.Warn(ctx, "CrossTrade order rejected. bid price is higher than minAsk", logger.String("orderId", orderId.String()), logger.String("price", input.Price.String()), logger.String("maxBid", maxBid.String()))
-			return models.Order{}, models.ErrCrossTrade
-		}
+	if err := s.orderBookStore.StoreOpenOrder(ctx, order); err != nil {
+		logctx.Error(ctx, "failed to add order", logger.Error(err))
+		return models.Order{}, err
 	}
 
+	s.publishOrderEvent(ctx, &order)
+
+	return order, nil
+}
+
+// buildOrder validates the size, signature and time in force of a new order and builds it, without storing it
+func (s *Service) buildOrder(ctx context.Context, orderId uuid.UUID, input CreateOrderInput, userId uuid.UUID) (models.Order, error) {
 	// validate size
 	if input.Size.IsZero() || input.Size.IsNegative() {
 		logctx.Warn(ctx, "size has to be positive", logger.String("orderId", orderId.String()), logger.String("size", input.Size.String()))
 		return models.Order{}, models.ErrInvalidInput
 	}
 
+	timeInForce := input.TimeInForce
+	if timeInForce == "" {
+		timeInForce = models.GTC
+	}
+
 	order := models.Order{
 		Id:        orderId,
 		ClientOId: input.ClientOrderID,
@@ -101,16 +115,53 @@ func (s *Service) createNewOrder(ctx context.Context, input CreateOrderInput, us
 			Eip712Sig:   input.Eip712Sig,
 			AbiFragment: input.AbiFragment,
 		},
-		Side:      input.Side,
-		Timestamp: time.Now().UTC(),
+		Side:        input.Side,
+		Timestamp:   time.Now().UTC(),
+		TimeInForce: timeInForce,
 	}
 
-	if err := s.orderBookStore.StoreOpenOrder(ctx, order); err != nil {
-		logctx.Error(ctx, "failed to add order", logger.Error(err))
+	// validate signature
+	if err := s.verifyOrderSignature(ctx, &order, input.UserPubKey); err != nil {
 		return models.Order{}, err
 	}
 
-	s.publishOrderEvent(ctx, &order)
+	// validate time in force
+	expiresAt, err := timeInForceExpiry(ctx, input, &order)
+	if err != nil {
+		return models.Order{}, err
+	}
+	order.ExpiresAt = expiresAt
+
+	// validate signed amounts
+	if err := s.verifyOrderAmounts(ctx, &order); err != nil {
+		return models.Order{}, err
+	}
 
 	return order, nil
 }
+
+// validateCrossTrade rejects prices that would cross the opposite side of the book
+func (s *Service) validateCrossTrade(ctx context.Context, symbol models.Symbol, side models.Side, price decimal.Decimal) error {
+	// best order of each side is enough
+	depth, err := s.GetMarketDepth(ctx, symbol, models.DepthQuery{Mode: models.DEPTH_L3, Depth: 1})
+	if err != nil {
+		logctx.Warn(ctx, "market depth failed", logger.String("symbol", symbol.String()), logger.String("price", price.String()))
+	}
+	if len(depth.Asks) > 0 {
+		minAsk := depth.Asks[0][0]
+		if side == models.BUY && price.GreaterThanOrEqual(minAsk) {
+			logctx.Warn(ctx, "CrossTrade order rejected. bid price is higher than minAsk", logger.String("price", price.String()), logger.String("min_ask", minAsk.String()))
+			return models.ErrCrossTrade
+		}
+	}
+
+	szBids := len(depth.Bids)
+	if szBids > 0 {
+		maxBid := depth.Bids[0][0]
+		if side == models.SELL && price.LessThanOrEqual(maxBid) {
+			logctx.Warn(ctx, "CrossTrade order rejected. bid price is higher than minAsk", logger.String("price", price.String()), logger.String("maxBid", maxBid.String()))
+			return models.ErrCrossTrade
+		}
+	}
+	return nil
+}
@@ -1,9 +1,14 @@
 package service_test
 
 import (
+	"math/big"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/abi"
 	"github.com/orbs-network/order-book/mocks"
 	"github.com/orbs-network/order-book/models"
 	"github.com/orbs-network/order-book/service"
@@ -156,3 +161,285 @@ func TestService_CreateOrder(t *testing.T) {
 
 	})
 }
+
+func TestService_CreateOrder_VerifySignature(t *testing.T) {
+	t.Setenv("REPERMIT_ADDRESS", mocks.RePermitAddress)
+	t.Setenv("CHAIN_ID", "137")
+	t.Setenv("SUPPORTED_TOKENS_JSON_FILE_PATH", "../supportedTokens.json")
+
+	ctx := mocks.AddUserToCtx(nil)
+	mockBcClient := &mocks.MockBcClient{IsVerified: true}
+
+	key, _ := crypto.GenerateKey()
+	wallet := crypto.PubkeyToAddress(key.PublicKey)
+
+	// 1000 MATIC for 10000 USDC
+	maticAmount, _ := new(big.Int).SetString("1000000000000000000000", 10)
+	abiFragment := mocks.AbiFragment
+	abiFragment.Info.Swapper = wallet
+	abiFragment.Input = abi.Input{Token: common.HexToAddress("0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270"), Amount: maticAmount}
+	abiFragment.Outputs = []abi.Output{{Token: common.HexToAddress("0x3c499c542cef5e3811e1192ce70d8cc03d5c3359"), Amount: big.NewInt(10000000000)}}
+
+	symbol, _ := models.StrToSymbol("MATIC-USDC")
+	input := service.CreateOrderInput{
+		UserId:        uuid.MustParse("a577273e-12de-4acc-a4f8-de7fb5b86e37"),
+		Price:         decimal.NewFromFloat(10.0),
+		Symbol:        symbol,
+		Size:          decimal.NewFromFloat(1000.00),
+		Side:          models.SELL,
+		ClientOrderID: uuid.MustParse("e577273e-12de-4acc-a4f8-de7fb5b86e37"),
+		Eip712Sig:     mocks.SignAbiOrder(key, abiFragment),
+		AbiFragment:   abiFragment,
+		UserPubKey:    wallet.Hex(),
+	}
+
+	svc, _ := service.New(&mocks.MockOrderBookStore{}, mockBcClient)
+
+	t.Run("signed by user and swapper - should create order", func(t *testing.T) {
+		_, err := svc.CreateOrder(ctx, input)
+		assert.NoError(t, err)
+	})
+
+	t.Run("user pubKey as hex public key - should create order", func(t *testing.T) {
+		input := input
+		input.UserPubKey = common.Bytes2Hex(crypto.FromECDSAPub(&key.PublicKey)[1:])
+		_, err := svc.CreateOrder(ctx, input)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid signature - should return `ErrInvalidSignature` error", func(t *testing.T) {
+		input := input
+		input.Eip712Sig = "mock-sig"
+		_, err := svc.CreateOrder(ctx, input)
+		assert.ErrorIs(t, err, models.ErrInvalidSignature)
+	})
+
+	t.Run("signed by another wallet - should return `ErrInvalidSignature` error", func(t *testing.T) {
+		otherKey, _ := crypto.GenerateKey()
+		input := input
+		input.Eip712Sig = mocks.SignAbiOrder(otherKey, abiFragment)
+		_, err := svc.CreateOrder(ctx, input)
+		assert.ErrorIs(t, err, models.ErrInvalidSignature)
+	})
+
+	t.Run("signer is not the swapper - should return `ErrInvalidSignature` error", func(t *testing.T) {
+		otherFragment := abiFragment
+		otherFragment.Info.Swapper = common.HexToAddress("0x8fd379246834eac74B8419FfdA202CF8051F7A03")
+		input := input
+		input.AbiFragment = otherFragment
+		input.Eip712Sig = mocks.SignAbiOrder(key, otherFragment)
+		_, err := svc.CreateOrder(ctx, input)
+		assert.ErrorIs(t, err, models.ErrInvalidSignature)
+	})
+
+	t.Run("signer does not match user pubKey - should return `ErrInvalidSignature` error", func(t *testing.T) {
+		input := input
+		input.UserPubKey = "0x8fd379246834eac74B8419FfdA202CF8051F7A03"
+		_, err := svc.CreateOrder(ctx, input)
+		assert.ErrorIs(t, err, models.ErrInvalidSignature)
+	})
+}
+
+func TestService_New_VerifySignatureConfig(t *testing.T) {
+
+	t.Run("invalid REPERMIT_ADDRESS - should fail", func(t *testing.T) {
+		t.Setenv("REPERMIT_ADDRESS", "0x123")
+		_, err := service.New(&mocks.MockOrderBookStore{}, &mocks.MockBcClient{})
+		assert.ErrorContains(t, err, "REPERMIT_ADDRESS")
+	})
+
+	t.Run("invalid CHAIN_ID - should fail", func(t *testing.T) {
+		t.Setenv("REPERMIT_ADDRESS", mocks.RePermitAddress)
+		t.Setenv("CHAIN_ID", "polygon")
+		_, err := service.New(&mocks.MockOrderBookStore{}, &mocks.MockBcClient{})
+		assert.ErrorContains(t, err, "CHAIN_ID")
+	})
+
+	t.Run("no supported tokens - should fail", func(t *testing.T) {
+		t.Setenv("REPERMIT_ADDRESS", mocks.RePermitAddress)
+		t.Setenv("SUPPORTED_TOKENS_JSON_FILE_PATH", "missing.json")
+		_, err := service.New(&mocks.MockOrderBookStore{}, &mocks.MockBcClient{})
+		assert.ErrorContains(t, err, "supported tokens")
+	})
+
+	t.Run("no REPERMIT_ADDRESS without opting out - should fail", func(t *testing.T) {
+		t.Setenv("SKIP_SIGNATURE_VERIFICATION", "")
+		_, err := service.New(&mocks.MockOrderBookStore{}, &mocks.MockBcClient{})
+		assert.ErrorContains(t, err, "SKIP_SIGNATURE_VERIFICATION")
+	})
+}
+
+func TestService_CreateOrder_VerifyAmounts(t *testing.T) {
+	t.Setenv("SUPPORTED_TOKENS_JSON_FILE_PATH", "../supportedTokens.json")
+	t.Setenv("SIGNED_ORDER_TOLERANCE", "0.001")
+
+	ctx := mocks.AddUserToCtx(nil)
+	mockBcClient := &mocks.MockBcClient{IsVerified: true}
+
+	matic := common.HexToAddress("0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270")
+	usdc := common.HexToAddress("0x3c499c542cef5e3811e1192ce70d8cc03d5c3359")
+	maticAmount, _ := new(big.Int).SetString("40000000000000000000", 10)
+	usdcAmount := big.NewInt(34600000)
+
+	newFragment := func(inToken common.Address, inAmount *big.Int, outToken common.Address, outAmount *big.Int) abi.Order {
+		fragment := mocks.AbiFragment
+		fragment.Input = abi.Input{Token: inToken, Amount: inAmount}
+		fragment.Outputs = []abi.Output{{Token: outToken, Amount: outAmount}}
+		return fragment
+	}
+
+	symbol, _ := models.StrToSymbol("MATIC-USDC")
+	sellInput := service.CreateOrderInput{
+		UserId:        uuid.MustParse("a577273e-12de-4acc-a4f8-de7fb5b86e37"),
+		Price:         decimal.NewFromFloat(0.865),
+		Symbol:        symbol,
+		Size:          decimal.NewFromInt(40),
+		Side:          models.SELL,
+		ClientOrderID: uuid.MustParse("e577273e-12de-4acc-a4f8-de7fb5b86e37"),
+		Eip712Sig:     "mock-sig",
+		AbiFragment:   newFragment(matic, maticAmount, usdc, usdcAmount),
+	}
+	buyInput := sellInput
+	buyInput.Side = models.BUY
+	buyInput.AbiFragment = newFragment(usdc, usdcAmount, matic, maticAmount)
+
+	svc, _ := service.New(&mocks.MockOrderBookStore{}, mockBcClient)
+
+	t.Run("signed amounts match sell order - should create order", func(t *testing.T) {
+		_, err := svc.CreateOrder(ctx, sellInput)
+		assert.NoError(t, err)
+	})
+
+	t.Run("signed amounts match buy order - should create order", func(t *testing.T) {
+		_, err := svc.CreateOrder(ctx, buyInput)
+		assert.NoError(t, err)
+	})
+
+	t.Run("price within tolerance - should create order", func(t *testing.T) {
+		input := sellInput
+		input.Price = decimal.NewFromFloat(0.8655)
+		_, err := svc.CreateOrder(ctx, input)
+		assert.NoError(t, err)
+	})
+
+	t.Run("price beyond tolerance - should return `ErrSignedOrderMismatch` error", func(t *testing.T) {
+		input := sellInput
+		input.Price = decimal.NewFromFloat(0.85)
+		_, err := svc.CreateOrder(ctx, input)
+		assert.ErrorIs(t, err, models.ErrSignedOrderMismatch)
+	})
+
+	t.Run("size beyond tolerance - should return `ErrSignedOrderMismatch` error", func(t *testing.T) {
+		input := buyInput
+		input.Size = decimal.NewFromInt(50)
+		_, err := svc.CreateOrder(ctx, input)
+		assert.ErrorIs(t, err, models.ErrSignedOrderMismatch)
+	})
+
+	t.Run("signed direction does not match side - should return `ErrSignedOrderMismatch` error", func(t *testing.T) {
+		input := sellInput
+		input.Side = models.BUY
+		_, err := svc.CreateOrder(ctx, input)
+		assert.ErrorIs(t, err, models.ErrSignedOrderMismatch)
+	})
+
+	t.Run("signed token pair does not match symbol - should return `ErrSignedOrderMismatch` error", func(t *testing.T) {
+		input := sellInput
+		input.AbiFragment = newFragment(matic, maticAmount, common.HexToAddress("0xc2132D05D31c914a87C6611C10748AEb04B58e8F"), usdcAmount)
+		_, err := svc.CreateOrder(ctx, input)
+		assert.ErrorIs(t, err, models.ErrSignedOrderMismatch)
+	})
+}
+
+func TestService_New_SignedOrderTolerance(t *testing.T) {
+
+	t.Run("invalid SIGNED_ORDER_TOLERANCE - should fail", func(t *testing.T) {
+		for _, tolerance := range []string{"0.1%", "-0.001", ""} {
+			t.Setenv("SIGNED_ORDER_TOLERANCE", tolerance)
+			_, err := service.New(&mocks.MockOrderBookStore{}, &service.EvmClient{})
+			assert.ErrorContains(t, err, "SIGNED_ORDER_TOLERANCE", tolerance)
+		}
+	})
+}
+
+func TestService_CreateOrder_TimeInForce(t *testing.T) {
+	ctx := mocks.AddUserToCtx(nil)
+	mockBcClient := &mocks.MockBcClient{IsVerified: true}
+
+	deadline := time.Now().Add(time.Hour).Truncate(time.Second)
+	abiFragment := mocks.AbiFragment
+	abiFragment.Info.Deadline = big.NewInt(deadline.Unix())
+
+	symbol, _ := models.StrToSymbol("MATIC-USDC")
+	input := service.CreateOrderInput{
+		UserId:        uuid.MustParse("a577273e-12de-4acc-a4f8-de7fb5b86e37"),
+		Price:         decimal.NewFromFloat(10.0),
+		Symbol:        symbol,
+		Size:          decimal.NewFromFloat(1000.00),
+		Side:          models.SELL,
+		ClientOrderID: uuid.MustParse("e577273e-12de-4acc-a4f8-de7fb5b86e37"),
+		Eip712Sig:     "mock-sig",
+		AbiFragment:   abiFragment,
+	}
+
+	svc, _ := service.New(&mocks.MockOrderBookStore{}, mockBcClient)
+
+	t.Run("no time in force - should default to GTC", func(t *testing.T) {
+		order, err := svc.CreateOrder(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, models.GTC, order.TimeInForce)
+		assert.True(t, order.ExpiresAt.IsZero())
+	})
+
+	t.Run("GTT before the deadline - should set expiry", func(t *testing.T) {
+		input := input
+		input.TimeInForce = models.GTT
+		input.ExpiresAt = time.Now().Add(time.Minute)
+		order, err := svc.CreateOrder(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, models.GTT, order.TimeInForce)
+		assert.Equal(t, input.ExpiresAt.UTC(), order.ExpiresAt)
+	})
+
+	t.Run("GTT after the deadline - should return `ErrInvalidTimeInForce` error", func(t *testing.T) {
+		input := input
+		input.TimeInForce = models.GTT
+		input.ExpiresAt = deadline.Add(time.Minute)
+		_, err := svc.CreateOrder(ctx, input)
+		assert.ErrorIs(t, err, models.ErrInvalidTimeInForce)
+	})
+
+	t.Run("GTT in the past - should return `ErrInvalidTimeInForce` error", func(t *testing.T) {
+		input := input
+		input.TimeInForce = models.GTT
+		input.ExpiresAt = time.Now().Add(-time.Minute)
+		_, err := svc.CreateOrder(ctx, input)
+		assert.ErrorIs(t, err, models.ErrInvalidTimeInForce)
+	})
+
+	t.Run("POST_ONLY - should expire after cancelAfter", func(t *testing.T) {
+		input := input
+		input.TimeInForce = models.POST_ONLY
+		input.CancelAfter = 30 * time.Second
+		order, err := svc.CreateOrder(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, models.POST_ONLY, order.TimeInForce)
+		assert.Equal(t, order.Timestamp.Add(30*time.Second), order.ExpiresAt)
+	})
+
+	t.Run("POST_ONLY past the deadline - should be capped by the deadline", func(t *testing.T) {
+		input := input
+		input.TimeInForce = models.POST_ONLY
+		input.CancelAfter = 2 * time.Hour
+		order, err := svc.CreateOrder(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, deadline.UTC(), order.ExpiresAt)
+	})
+
+	t.Run("POST_ONLY with no cancelAfter - should return `ErrInvalidTimeInForce` error", func(t *testing.T) {
+		input := input
+		input.TimeInForce = models.POST_ONLY
+		_, err := svc.CreateOrder(ctx, input)
+		assert.ErrorIs(t, err, models.ErrInvalidTimeInForce)
+	})
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// BackfillFills stores the fills of the successful resolved swaps in the store in the fills history of users who have none yet
+// it must run before swaps are resolved, as a user with new fills is not backfilled
+func (e *EvmClient) BackfillFills(ctx context.Context) error {
+	userIds, err := e.orderBookStore.GetResolvedSwapsUserIds(ctx)
+	if err != nil {
+		logctx.Error(ctx, "failed to get users with resolved swaps", logger.Error(err))
+		return fmt.Errorf("failed to get users with resolved swaps: %w", err)
+	}
+
+	backfilledUsers := 0
+	for _, userId := range userIds {
+		fills, err := e.resolvedSwapsFills(ctx, userId)
+		if err != nil {
+			return err
+		}
+		backfilled, err := e.orderBookStore.BackfillUserFills(ctx, userId, fills)
+		if err != nil {
+			return err
+		}
+		if backfilled {
+			backfilledUsers++
+			logctx.Info(ctx, "backfilled user fills", logger.String("userId", userId.String()), logger.Int("fills", len(fills)))
+		}
+	}
+
+	logctx.Info(ctx, "backfilled fills history", logger.Int("users", backfilledUsers))
+	return nil
+}
+
+// resolvedSwapsFills returns the fills of the user's orders in the user's successful resolved swaps, oldest first
+func (e *EvmClient) resolvedSwapsFills(ctx context.Context, userId uuid.UUID) ([]models.Fill, error) {
+	swapIds, err := e.orderBookStore.GetUserResolvedSwapIds(ctx, userId)
+	if err != nil {
+		logctx.Error(ctx, "error getting user resolve swapIds", logger.Error(err), logger.String("user_id", userId.String()))
+		return nil, err
+	}
+
+	fills := []models.Fill{}
+	for _, id := range swapIds {
+		swapId, err := uuid.Parse(id)
+		if err != nil {
+			logctx.Warn(ctx, "failed to parse swapID", logger.Error(err), logger.String("user_id", userId.String()), logger.String("swap_id", id))
+			continue
+		}
+		swap, err := e.orderBookStore.GetSwap(ctx, swapId, false)
+		if err == models.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			logctx.Error(ctx, "error getting a swap", logger.Error(err), logger.String("user_id", userId.String()), logger.String("swap_id", id))
+			return nil, err
+		}
+		if !swap.Succeeded || swap.Resolved.IsZero() {
+			continue
+		}
+		swap.Id = swapId
+
+		for _, frag := range swap.Frags {
+			order, err := e.orderBookStore.FindOrderById(ctx, frag.OrderId, false)
+			if err == models.ErrNotFound {
+				logctx.Warn(ctx, "order of resolved swap not found", logger.String("swap_id", id), logger.String("order_id", frag.OrderId.String()))
+				continue
+			}
+			if err != nil {
+				logctx.Error(ctx, "error getting an order", logger.Error(err), logger.String("swap_id", id), logger.String("order_id", frag.OrderId.String()))
+				return nil, err
+			}
+			if order.UserId != userId {
+				continue
+			}
+			fills = append(fills, *models.NewFill(order.Symbol, *swap, frag, order))
+		}
+	}
+
+	sort.SliceStable(fills, func(i, j int) bool { return fills[i].Resolved.Before(fills[j].Resolved) })
+	return fills, nil
+}
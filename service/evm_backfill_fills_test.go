@@ -0,0 +1,83 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/data/memrepo"
+	"github.com/orbs-network/order-book/data/store"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvmClient_BackfillFills(t *testing.T) {
+	ctx := context.Background()
+
+	newOrder := func(userId uuid.UUID) models.Order {
+		return models.Order{Id: uuid.New(), ClientOId: uuid.New(), UserId: userId, Symbol: "MATIC-USDC", Side: models.SELL, Price: decimal.NewFromInt(2), Size: decimal.NewFromInt(10), Timestamp: time.Now()}
+	}
+	resolveSwap := func(t *testing.T, store store.OrderBookStore, succeeded bool, resolved time.Time, userIds []uuid.UUID, orders ...models.Order) models.Swap {
+		frags := []models.OrderFrag{}
+		for _, order := range orders {
+			frags = append(frags, models.OrderFrag{OrderId: order.Id, OutSize: decimal.NewFromInt(1), InSize: decimal.NewFromInt(2)})
+		}
+		swap := models.NewSwap([]models.RouteLeg{{Symbol: "MATIC-USDC", MakerSide: models.SELL}}, frags)
+		swap.Id = uuid.New()
+		swap.Succeeded = succeeded
+		swap.Resolved = resolved
+		require.NoError(t, store.ResolveSwap(ctx, *swap))
+		for _, userId := range userIds {
+			require.NoError(t, store.StoreUserResolvedSwap(ctx, userId, *swap))
+		}
+		return *swap
+	}
+
+	t.Run("should backfill the fills of the user's orders in successful resolved swaps, oldest first", func(t *testing.T) {
+		store, err := memrepo.NewMemoryRepository()
+		require.NoError(t, err)
+		evmClient, err := service.NewEvmSvc(store, &mocks.MockBlockchainStore{})
+		require.NoError(t, err)
+
+		userId, otherUserId := uuid.New(), uuid.New()
+		order, otherOrder := newOrder(userId), newOrder(otherUserId)
+		require.NoError(t, store.StoreOpenOrders(ctx, []models.Order{order, otherOrder}))
+
+		resolved := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+		later := resolveSwap(t, store, true, resolved.Add(time.Minute), []uuid.UUID{userId}, order)
+		earlier := resolveSwap(t, store, true, resolved, []uuid.UUID{userId, otherUserId}, order, otherOrder)
+		resolveSwap(t, store, false, resolved, []uuid.UUID{userId}, order)
+		// has fills history already
+		require.NoError(t, store.StoreUserFill(ctx, otherUserId, models.Fill{OrderId: otherOrder.Id, Symbol: "MATIC-USDC"}))
+
+		require.NoError(t, evmClient.BackfillFills(ctx))
+
+		fills, _, err := store.GetUserFills(ctx, userId, models.HistoryFilter{})
+		require.NoError(t, err)
+		require.Len(t, fills, 2)
+		assert.Equal(t, later.Id, fills[0].SwapId)
+		assert.Equal(t, earlier.Id, fills[1].SwapId)
+		assert.Equal(t, order.Id, fills[1].OrderId)
+		assert.True(t, fills[1].Resolved.Equal(resolved))
+
+		fills, _, err = store.GetUserFills(ctx, userId, models.HistoryFilter{StartAt: resolved, EndAt: resolved.Add(time.Second)})
+		require.NoError(t, err)
+		require.Len(t, fills, 1)
+		assert.Equal(t, earlier.Id, fills[0].SwapId)
+
+		fills, _, err = store.GetUserFills(ctx, otherUserId, models.HistoryFilter{})
+		require.NoError(t, err)
+		assert.Len(t, fills, 1)
+
+		// once only
+		require.NoError(t, evmClient.BackfillFills(ctx))
+		fills, _, err = store.GetUserFills(ctx, userId, models.HistoryFilter{})
+		require.NoError(t, err)
+		assert.Len(t, fills, 2)
+	})
+}
@@ -14,9 +14,8 @@ import (
 
 // update swap fields
 //
-// move swap to resolved key
-//
-// update orders state
+// update orders state, and move swap to resolved key in the same tx
+// the swap is left open to be resolved again if the orders could not be updated
 //
 // save swap to the users involved
 func (e *EvmClient) ResolveSwap(ctx context.Context, swap models.Swap, isSuccessful bool, mu *sync.Mutex) error {
@@ -29,46 +28,115 @@ func (e *EvmClient) ResolveSwap(ctx context.Context, swap models.Swap, isSuccess
 	// success status
 	swap.Succeeded = isSuccessful
 
-	// save to "swapResolved" key
-	// remove from active "swapId"
-	err := e.orderBookStore.ResolveSwap(ctx, swap)
-	if err != nil {
-		logctx.Error(ctx, "Failed to ResolveSwap in store", logger.Error(err), logger.String("swapId", swap.Id.String()))
-		return err
-	}
-
 	// Failed     ===========================================================
 	// same impl as abort swap
 	if !isSuccessful {
+		orders := findSwapOrders(ctx, e.orderBookStore, swap)
 		// unlock orders
 		// mutual impl for ABORT and RESOLVE(false) swap
-		err := unlockSwapAndHandleCancelledOrders(ctx, nil, e.orderBookStore, &swap)
+		err := unlockSwapAndHandleCancelledOrders(ctx, nil, e.orderBookStore, &swap, true)
 		if err != nil {
 			logctx.Error(ctx, "Failed unlockSwapAndHandleCancelledOrders", logger.Error(err), logger.String("swapId", swap.Id.String()))
+			return err
 		}
-		return err
+		e.publishSwapEvent(ctx, models.SwapEvent{Event: models.USER_EVENT_SWAP_FAILED, SwapId: swap.Id, TxHash: swap.TxHash}, swap.Frags, orders)
+		return nil
 	}
 
 	// successful ===========================================================
-	var orderIds []uuid.UUID
-	for _, frag := range swap.Frags {
-		orderIds = append(orderIds, frag.OrderId)
+	// fills and trades saved in history with the updated orders
+	var fills []models.Fill
+	var fillUserIds []uuid.UUID
+	var trades []models.Trade
+	var closedOrders []models.Order
+	// the orders are read and filled again if they changed in between
+	err := retryOnVersionConflict(ctx, "ResolveSwap", func() (err error) {
+		fills, fillUserIds, trades, closedOrders, err = e.fillResolvedSwapOrders(ctx, swap)
+		return err
+	})
+	if err != nil {
+		logctx.Error(ctx, "ResilvedSwap:true failed filling orders", logger.Error(err), logger.String("swapId", swap.Id.String()))
+		return err
 	}
 
-	// get orders from frags
-	orders, err := e.orderBookStore.FindOrdersByIds(ctx, orderIds, false)
-	if err != nil {
-		logctx.Error(ctx, "Failed to get orders", logger.Error(err), logger.String("swapId", swap.Id.String()))
-		return fmt.Errorf("failed to get orders: %w", err)
+	// publish Fill Events, and closed orders
+	for i, fill := range fills {
+		e.publishFillEvent(ctx, fillUserIds[i], fill)
+	}
+	for i := range closedOrders {
+		e.publishOrderEvent(ctx, &closedOrders[i])
 	}
 
 	// get user IDs from orders, in ordert o update userID:resolvedSwaps key
 	userIds := make(map[uuid.UUID]bool)
+	for _, userId := range fillUserIds {
+		userIds[userId] = true
+	}
+
+	// 1. update
+	// 2. close
+	//		- remove from user open orders
+
+	// update user(s) keys
+	for userId := range userIds {
+		// save resolved swap to a user
+		err = e.orderBookStore.StoreUserResolvedSwap(ctx, userId, swap)
+		if err != nil {
+			logctx.Error(ctx, "Error StoreUserResolvedSwap", logger.Error(err), logger.String("swapId", swap.Id.String()))
+		}
+	}
+
+	// publish public trades
+	for _, trade := range trades {
+		e.publishTradeEvent(ctx, trade)
+	}
+
+	// sum fees collected per token
+	if err := e.orderBookStore.AddFeeTotals(ctx, models.FeeTotalsOf(fills)); err != nil {
+		logctx.Error(ctx, "Error AddFeeTotals", logger.Error(err), logger.String("swapId", swap.Id.String()))
+	}
+
+	// aggregate trades into candles
+	if err := updateCandles(ctx, e.orderBookStore, trades); err != nil {
+		logctx.Error(ctx, "Error updating candles", logger.Error(err), logger.String("swapId", swap.Id.String()))
+	}
+
+	logctx.Debug(ctx, "Resolved swap", logger.String("swapId", swap.Id.String()), logger.Bool("isSuccessful", isSuccessful), logger.String("created", swap.Created.String()), logger.String("resolved", swap.Resolved.String()), logger.String("txHash", swap.TxHash))
+	return nil
+}
+
+// fillResolvedSwapOrders fills the orders of a successful swap, closes the orders fully filled, saves the fills and trades history, and resolves the swap, in a single tx
+// returns the fills, the user of each, the trades and the closed orders
+func (e *EvmClient) fillResolvedSwapOrders(ctx context.Context, swap models.Swap) ([]models.Fill, []uuid.UUID, []models.Trade, []models.Order, error) {
+	// get orders from frags, an order missing from the store is skipped so the swap is still resolved
+	orders := make(map[uuid.UUID]models.Order, len(swap.Frags))
+	for _, frag := range swap.Frags {
+		order, err := e.orderBookStore.FindOrderById(ctx, frag.OrderId, false)
+		if err == models.ErrNotFound {
+			logctx.Warn(ctx, "ResolveSwap:true order not found, its fragment is not filled", logger.String("orderId", frag.OrderId.String()), logger.String("swapId", swap.Id.String()))
+			continue
+		}
+		if err != nil {
+			logctx.Error(ctx, "Failed to get order", logger.Error(err), logger.String("orderId", frag.OrderId.String()), logger.String("swapId", swap.Id.String()))
+			return nil, nil, nil, nil, fmt.Errorf("failed to get order: %w", err)
+		}
+		orders[frag.OrderId] = *order
+	}
+
+	fills := []models.Fill{}
+	fillUserIds := []uuid.UUID{}
+	trades := []models.Trade{}
+	closedOrders := []models.Order{}
+
+	err := e.orderBookStore.PerformTx(ctx, func(txid uint) error {
+		for _, frag := range swap.Frags {
+			order, ok := orders[frag.OrderId]
+			if !ok {
+				continue
+			}
 
-	err = e.orderBookStore.PerformTx(ctx, func(txid uint) error {
-		for i, order := range orders {
 			// fill part/whole of the order
-			isFullyFilled, err := order.Fill(ctx, swap.Frags[i])
+			isFullyFilled, err := order.Fill(ctx, frag)
 			if err != nil {
 				logctx.Error(ctx, "Failed to mark order as filled", logger.Error(err), logger.String("orderId", order.Id.String()))
 				continue
@@ -80,9 +148,25 @@ func (e *EvmClient) ResolveSwap(ctx context.Context, swap models.Swap, isSuccess
 				return err
 			}
 
-			// publish Fill Event
-			fill := models.NewFill(order.Symbol, swap, swap.Frags[i], &order)
-			e.publishFillEvent(ctx, order.UserId, *fill)
+			fill := models.NewFill(order.Symbol, swap, frag, &order)
+			// a routed swap is charged once, on its last leg
+			if swap.IsFinalLeg(order.Symbol, order.Side) {
+				e.fees.ApplyToFill(fill, frag, order.UserId)
+			}
+			if err := e.orderBookStore.TxStoreUserFill(ctx, txid, order.UserId, *fill); err != nil {
+				logctx.Error(ctx, "ResolveSwap:true Failed storing fill", logger.Error(err), logger.String("orderId", order.Id.String()))
+				return err
+			}
+			fills = append(fills, *fill)
+			fillUserIds = append(fillUserIds, order.UserId)
+
+			// anonymized public trade
+			trade := models.NewTrade(*fill)
+			if err := e.orderBookStore.TxStoreTrade(ctx, txid, trade); err != nil {
+				logctx.Error(ctx, "ResolveSwap:true Failed storing trade", logger.Error(err), logger.String("orderId", order.Id.String()))
+				return err
+			}
+			trades = append(trades, trade)
 
 			// close fully filled orders
 			if isFullyFilled {
@@ -91,33 +175,20 @@ func (e *EvmClient) ResolveSwap(ctx context.Context, swap models.Swap, isSuccess
 					logctx.Error(ctx, "ResolveSwap:true Failed CLOSE filled order", logger.Error(err), logger.String("orderId", order.Id.String()))
 					return err
 				}
-				// publish a closed order
-				e.publishOrderEvent(ctx, &order)
+				closedOrders = append(closedOrders, order)
 			}
+		}
 
-			// append to users to be updated later
-			userIds[order.UserId] = true
+		// save to "swapResolved" key
+		// remove from active "swapId"
+		if err := e.orderBookStore.TxResolveSwap(ctx, txid, swap); err != nil {
+			logctx.Error(ctx, "ResolveSwap:true Failed to resolve swap", logger.Error(err), logger.String("swapId", swap.Id.String()))
+			return err
 		}
 		return nil
 	})
-
 	if err != nil {
-		logctx.Error(ctx, "ResilvedSwap:true PerformTx failed", logger.Error(err), logger.String("swapId", swap.Id.String()))
-	}
-
-	// 1. update
-	// 2. close
-	//		- remove from user open orders
-
-	// update user(s) keys
-	for userId := range userIds {
-		// save resolved swap to a user
-		err = e.orderBookStore.StoreUserResolvedSwap(ctx, userId, swap)
-		if err != nil {
-			logctx.Error(ctx, "Error StoreUserResolvedSwap", logger.Error(err), logger.String("swapId", swap.Id.String()))
-		}
+		return nil, nil, nil, nil, err
 	}
-
-	logctx.Debug(ctx, "Resolved swap", logger.String("swapId", swap.Id.String()), logger.Bool("isSuccessful", isSuccessful), logger.String("created", swap.Created.String()), logger.String("resolved", swap.Resolved.String()), logger.String("txHash", swap.TxHash))
-	return nil
+	return fills, fillUserIds, trades, closedOrders, nil
 }
@@ -0,0 +1,191 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/data/redisrepo"
+	"github.com/orbs-network/order-book/data/store"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// conflictingStore changes the order before each tx is committed, as a concurrent writer would, until conflicts run out
+type conflictingStore struct {
+	store.OrderBookStore
+	orderId   uuid.UUID
+	conflicts int
+}
+
+func (s *conflictingStore) PerformTx(ctx context.Context, action func(txid uint) error) error {
+	return s.OrderBookStore.PerformTx(ctx, func(txid uint) error {
+		if err := action(txid); err != nil {
+			return err
+		}
+		if s.conflicts == 0 {
+			return nil
+		}
+		s.conflicts--
+		order, err := s.FindOrderById(ctx, s.orderId, false)
+		if err != nil {
+			return err
+		}
+		return s.OrderBookStore.PerformTx(ctx, func(txid uint) error {
+			return s.TxModifyOrder(ctx, txid, models.Update, *order)
+		})
+	})
+}
+
+func TestEvmClient_ResolveSwap(t *testing.T) {
+	ctx := context.Background()
+
+	// returns the store with an order locked by a started swap, and the swap
+	startSwap := func(t *testing.T) (*conflictingStore, models.Order, models.Swap) {
+		mr := miniredis.RunT(t)
+		repository, err := redisrepo.NewRedisRepository(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+		require.NoError(t, err)
+		svc, err := service.New(repository, &service.EvmClient{})
+		require.NoError(t, err)
+
+		order := models.Order{Id: uuid.New(), ClientOId: uuid.New(), UserId: uuid.New(), Symbol: "MATIC-USDC", Side: models.SELL, Price: decimal.NewFromInt(2), Size: decimal.NewFromInt(10), Timestamp: time.Now()}
+		require.NoError(t, repository.StoreOpenOrder(ctx, order))
+		res, err := svc.BeginSwap(ctx, models.QuoteRes{Size: decimal.NewFromInt(1), InAmount: decimal.NewFromInt(2), OrderFrags: []models.OrderFrag{{OrderId: order.Id, OutSize: decimal.NewFromInt(1), InSize: decimal.NewFromInt(2)}}})
+		require.NoError(t, err)
+		_, err = repository.StoreNewPendingSwap(ctx, models.SwapTx{SwapId: res.SwapId, TxHash: "0x123"})
+		require.NoError(t, err)
+
+		swaps, err := repository.GetOpenSwaps(ctx)
+		require.NoError(t, err)
+		require.Len(t, swaps, 1)
+		return &conflictingStore{OrderBookStore: repository, orderId: order.Id}, order, swaps[0]
+	}
+
+	// asserts the swap is still open with its fragment locked, to be resolved again
+	assertNotResolved := func(t *testing.T, s store.OrderBookStore, order models.Order, swap models.Swap) {
+		_, err := s.GetSwap(ctx, swap.Id, true)
+		assert.NoError(t, err)
+		_, err = s.GetSwap(ctx, swap.Id, false)
+		assert.Error(t, err, "the swap should not be resolved")
+
+		stored, err := s.FindOrderById(ctx, order.Id, false)
+		require.NoError(t, err)
+		assert.True(t, stored.SizePending.Equal(decimal.NewFromInt(1)), "sizePending %s", stored.SizePending)
+		assert.True(t, stored.SizeFilled.IsZero(), "sizeFilled %s", stored.SizeFilled)
+
+		fills, _, err := s.GetUserFills(ctx, order.UserId, models.HistoryFilter{})
+		assert.NoError(t, err)
+		assert.Empty(t, fills)
+	}
+
+	t.Run("should leave a successful swap open when filling its orders fails with ErrVersionConflict, and resolve it once they are filled", func(t *testing.T) {
+		s, order, swap := startSwap(t)
+		evmClient, err := service.NewEvmSvc(s, &mocks.MockBlockchainStore{})
+		require.NoError(t, err)
+
+		s.conflicts = 100
+		err = evmClient.ResolveSwap(ctx, swap, true, &sync.Mutex{})
+		assert.ErrorIs(t, err, models.ErrVersionConflict)
+		assertNotResolved(t, s, order, swap)
+
+		pending, err := evmClient.GetPendingSwaps(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, pending, 1)
+
+		s.conflicts = 0
+		require.NoError(t, evmClient.ResolveSwap(ctx, pending[0], true, &sync.Mutex{}))
+
+		resolved, err := s.GetSwap(ctx, swap.Id, false)
+		require.NoError(t, err)
+		assert.True(t, resolved.Succeeded)
+		_, err = s.GetSwap(ctx, swap.Id, true)
+		assert.Error(t, err, "the swap should no longer be open")
+
+		stored, err := s.FindOrderById(ctx, order.Id, false)
+		require.NoError(t, err)
+		assert.True(t, stored.SizePending.IsZero(), "sizePending %s", stored.SizePending)
+		assert.True(t, stored.SizeFilled.Equal(decimal.NewFromInt(1)), "sizeFilled %s", stored.SizeFilled)
+
+		fills, _, err := s.GetUserFills(ctx, order.UserId, models.HistoryFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, fills, 1)
+	})
+
+	t.Run("should leave a failed swap open when unlocking its orders fails with ErrVersionConflict", func(t *testing.T) {
+		s, order, swap := startSwap(t)
+		evmClient, err := service.NewEvmSvc(s, &mocks.MockBlockchainStore{})
+		require.NoError(t, err)
+
+		s.conflicts = 100
+		err = evmClient.ResolveSwap(ctx, swap, false, &sync.Mutex{})
+		assert.ErrorIs(t, err, models.ErrVersionConflict)
+		assertNotResolved(t, s, order, swap)
+
+		s.conflicts = 0
+		require.NoError(t, evmClient.ResolveSwap(ctx, swap, false, &sync.Mutex{}))
+
+		resolved, err := s.GetSwap(ctx, swap.Id, false)
+		require.NoError(t, err)
+		assert.False(t, resolved.Succeeded)
+
+		stored, err := s.FindOrderById(ctx, order.Id, false)
+		require.NoError(t, err)
+		assert.True(t, stored.SizePending.IsZero(), "sizePending %s", stored.SizePending)
+	})
+
+	t.Run("should resolve a successful swap and fill its other orders when one of its orders was removed", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		repository, err := redisrepo.NewRedisRepository(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+		require.NoError(t, err)
+		svc, err := service.New(repository, &service.EvmClient{})
+		require.NoError(t, err)
+
+		removed := models.Order{Id: uuid.New(), ClientOId: uuid.New(), UserId: uuid.New(), Symbol: "MATIC-USDC", Side: models.SELL, Price: decimal.NewFromInt(2), Size: decimal.NewFromInt(10), Timestamp: time.Now()}
+		order := models.Order{Id: uuid.New(), ClientOId: uuid.New(), UserId: uuid.New(), Symbol: "MATIC-USDC", Side: models.SELL, Price: decimal.NewFromInt(3), Size: decimal.NewFromInt(10), Timestamp: time.Now()}
+		require.NoError(t, repository.StoreOpenOrder(ctx, removed))
+		require.NoError(t, repository.StoreOpenOrder(ctx, order))
+		res, err := svc.BeginSwap(ctx, models.QuoteRes{Size: decimal.NewFromInt(2), InAmount: decimal.NewFromInt(5), OrderFrags: []models.OrderFrag{
+			{OrderId: removed.Id, OutSize: decimal.NewFromInt(1), InSize: decimal.NewFromInt(2)},
+			{OrderId: order.Id, OutSize: decimal.NewFromInt(1), InSize: decimal.NewFromInt(3)},
+		}})
+		require.NoError(t, err)
+		_, err = repository.StoreNewPendingSwap(ctx, models.SwapTx{SwapId: res.SwapId, TxHash: "0x123"})
+		require.NoError(t, err)
+		swaps, err := repository.GetOpenSwaps(ctx)
+		require.NoError(t, err)
+		require.Len(t, swaps, 1)
+
+		stored, err := repository.FindOrderById(ctx, removed.Id, false)
+		require.NoError(t, err)
+		require.NoError(t, repository.PerformTx(ctx, func(txid uint) error {
+			return repository.TxRemoveOrder(ctx, txid, *stored)
+		}))
+
+		evmClient, err := service.NewEvmSvc(repository, &mocks.MockBlockchainStore{})
+		require.NoError(t, err)
+		require.NoError(t, evmClient.ResolveSwap(ctx, swaps[0], true, &sync.Mutex{}))
+
+		resolved, err := repository.GetSwap(ctx, swaps[0].Id, false)
+		require.NoError(t, err)
+		assert.True(t, resolved.Succeeded)
+		_, err = repository.GetSwap(ctx, swaps[0].Id, true)
+		assert.Error(t, err, "the swap should no longer be open")
+
+		stored, err = repository.FindOrderById(ctx, order.Id, false)
+		require.NoError(t, err)
+		assert.True(t, stored.SizePending.IsZero(), "sizePending %s", stored.SizePending)
+		assert.True(t, stored.SizeFilled.Equal(decimal.NewFromInt(1)), "sizeFilled %s", stored.SizeFilled)
+
+		fills, _, err := repository.GetUserFills(ctx, order.UserId, models.HistoryFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, fills, 1)
+	})
+}
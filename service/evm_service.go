@@ -1,15 +1,19 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/orbs-network/order-book/data/store"
 	"github.com/orbs-network/order-book/data/storeblockchain"
+	"github.com/orbs-network/order-book/models"
 )
 
 type EvmClient struct {
 	orderBookStore  store.OrderBookStore
 	blockchainStore storeblockchain.BlockchainStore
+	// nil when no fees are charged
+	fees *models.FeeSchedule
 }
 
 func NewEvmSvc(obStore store.OrderBookStore, bcStore storeblockchain.BlockchainStore) (*EvmClient, error) {
@@ -22,5 +26,10 @@ func NewEvmSvc(obStore store.OrderBookStore, bcStore storeblockchain.BlockchainS
 		return nil, errors.New("bcStore is nil")
 	}
 
-	return &EvmClient{orderBookStore: obStore, blockchainStore: bcStore}, nil
+	fees, err := NewFeeScheduleFromEnv(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return &EvmClient{orderBookStore: obStore, blockchainStore: bcStore, fees: fees}, nil
 }
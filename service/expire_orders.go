@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// loads the margin within which expiring orders are not quoted, as the swap would not make it on-chain in time
+func loadQuoteExpiryMargin() (time.Duration, error) {
+	secMargin := utils.GetEnv("SEC_EXPIRY_MARGIN", "120")
+	sec, err := strconv.Atoi(secMargin)
+	if err != nil || sec < 0 {
+		return 0, fmt.Errorf("SEC_EXPIRY_MARGIN is not a valid number of seconds: %q", secMargin)
+	}
+	return time.Second * time.Duration(sec), nil
+}
+
+// checkExpiredOrders removes all orders whose signed deadline or time in force has passed from the book
+func (s *Service) checkExpiredOrders(ctx context.Context) error {
+	ids, err := s.orderBookStore.GetExpiredOrderIds(ctx, time.Now().UTC())
+	if err != nil {
+		logctx.Error(ctx, "GetExpiredOrderIds failed", logger.Error(err))
+		return err
+	}
+
+	for _, id := range ids {
+		// the order is read and expired again if it changed in between
+		err := retryOnVersionConflict(ctx, "expireOrder", func() error {
+			return s.expireOrderById(ctx, id)
+		})
+		if err != nil {
+			logctx.Error(ctx, "failed to expire order", logger.String("orderId", id.String()), logger.Error(err))
+		}
+	}
+	return nil
+}
+
+// expireOrderById expires the order, or removes its deadline if it no longer exists
+func (s *Service) expireOrderById(ctx context.Context, id uuid.UUID) error {
+	order, err := s.orderBookStore.FindOrderById(ctx, id, false)
+	if err == models.ErrNotFound || (err == nil && order == nil) {
+		// stale deadline entry
+		logctx.Warn(ctx, "expired order not found, removing from deadlines", logger.String("orderId", id.String()))
+		err = s.orderBookStore.PerformTx(ctx, func(txid uint) error {
+			return s.orderBookStore.TxModifyOrderDeadlines(ctx, txid, models.Remove, models.Order{Id: id})
+		})
+		if err != nil {
+			logctx.Error(ctx, "failed to remove stale deadline", logger.String("orderId", id.String()), logger.Error(err))
+		}
+		return nil
+	}
+	if err != nil {
+		logctx.Error(ctx, "FindOrderById failed for expired order", logger.String("orderId", id.String()), logger.Error(err))
+		return nil
+	}
+
+	return s.expireOrder(ctx, order)
+}
+
+// expireOrder takes the order off the book, same as a cancel
+// unfilled orders are removed entirely, partially filled or pending orders are kept as cancelled
+func (s *Service) expireOrder(ctx context.Context, order *models.Order) error {
+	err := s.orderBookStore.PerformTx(ctx, func(txid uint) error {
+		// remove from prices if not cancelled already
+		if !order.Cancelled {
+			if err := s.orderBookStore.TxModifyPrices(ctx, txid, models.Remove, *order); err != nil {
+				return fmt.Errorf("failed removing order from prices: %w", err)
+			}
+		}
+
+		order.Cancelled = true
+
+		// ORDER IS UNFILLED AND NOT PENDING
+		if order.IsUnfilled() && !order.IsPending() {
+			return s.orderBookStore.TxRemoveOrder(ctx, txid, *order)
+		}
+
+		if err := s.orderBookStore.TxModifyOrderDeadlines(ctx, txid, models.Remove, *order); err != nil {
+			return fmt.Errorf("failed removing order from deadlines: %w", err)
+		}
+
+		if err := s.orderBookStore.TxModifyUserOpenOrders(ctx, txid, models.Remove, *order); err != nil {
+			return fmt.Errorf("failed removing order from user open orders: %w", err)
+		}
+		if err := s.orderBookStore.TxModifyOrder(ctx, txid, models.Update, *order); err != nil {
+			return fmt.Errorf("failed updating order to cancelled: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logctx.Info(ctx, "order expired", logger.String("orderId", order.Id.String()), logger.String("userId", order.UserId.String()), logger.String("expiry", order.ExpiryTime().String()), logger.String("timeInForce", order.TimeInForce.String()))
+
+	s.publishOrderExpiredEvent(ctx, order)
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// NewFeeScheduleFromEnv loads the fee schedule file set by FEE_SCHEDULE_JSON_FILE_PATH, nil (no fees) when not set
+func NewFeeScheduleFromEnv(ctx context.Context) (*models.FeeSchedule, error) {
+	filePath := os.Getenv("FEE_SCHEDULE_JSON_FILE_PATH")
+	if filePath == "" {
+		logctx.Info(ctx, "FEE_SCHEDULE_JSON_FILE_PATH env var not set, no fees are charged")
+		return nil, nil
+	}
+	return NewFeeSchedule(ctx, filePath)
+}
+
+func NewFeeSchedule(ctx context.Context, filePath string) (*models.FeeSchedule, error) {
+	file, err := os.ReadFile(filePath)
+	if err != nil {
+		logctx.Error(ctx, "failed to read fee schedule file", logger.Error(err), logger.String("file-path", filePath))
+		return nil, fmt.Errorf("failed to read fee schedule file: %s", err)
+	}
+
+	var schedule models.FeeSchedule
+	if err := json.Unmarshal(file, &schedule); err != nil {
+		logctx.Error(ctx, "failed to unmarshal fee schedule file", logger.Error(err), logger.String("file-path", filePath))
+		return nil, fmt.Errorf("failed to unmarshal fee schedule file: %s", err)
+	}
+	if err := schedule.Validate(); err != nil {
+		logctx.Error(ctx, "invalid fee schedule", logger.Error(err), logger.String("file-path", filePath))
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// GetFeeTotals returns the fees of resolved fills summed per token
+func (s *Service) GetFeeTotals(ctx context.Context) ([]models.FeeTotal, error) {
+	totals, err := s.orderBookStore.GetFeeTotals(ctx)
+	if err != nil {
+		logctx.Error(ctx, "error getting fee totals", logger.Error(err))
+		return nil, err
+	}
+	return totals, nil
+}
@@ -0,0 +1,64 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Fees(t *testing.T) {
+	ctx := context.Background()
+	evmClient := &service.EvmClient{}
+
+	setSchedule := func(t *testing.T, schedule string) {
+		filePath := filepath.Join(t.TempDir(), "fees.json")
+		assert.NoError(t, os.WriteFile(filePath, []byte(schedule), 0600))
+		t.Setenv("FEE_SCHEDULE_JSON_FILE_PATH", filePath)
+	}
+	newStore := func() *mocks.MockOrderBookStore {
+		ask := models.Order{Id: uuid.New(), Side: models.SELL, Price: decimal.NewFromInt(1000), Size: decimal.NewFromInt(2), Signature: models.Signature{AbiFragment: mocks.AbiFragment}}
+		return &mocks.MockOrderBookStore{AskOrderIter: &mocks.OrderIterMock{Orders: []models.Order{ask}, Index: -1}, MakerBalance: decimal.NewFromInt(1000000)}
+	}
+
+	t.Run("should take the taker fee from the out amount", func(t *testing.T) {
+		setSchedule(t, `{"default": {"takerFeeBps": "10", "makerRebateBps": "2"}}`)
+		svc, err := service.New(newStore(), evmClient)
+		assert.NoError(t, err)
+
+		res, err := svc.GetQuote(ctx, symbol, models.SELL, decimal.NewFromInt(1000), nil, "0xTOKEN")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "0.999", res.Size.String())
+		assert.Equal(t, "0.001", res.Fee.String())
+		// the fragments keep the amounts of the orders
+		assert.Equal(t, "1", res.OrderFrags[0].OutSize.String())
+	})
+
+	t.Run("should quote the in amount of the out amount and the fee", func(t *testing.T) {
+		setSchedule(t, `{"default": {"takerFeeBps": "10", "makerRebateBps": "2"}}`)
+		svc, err := service.New(newStore(), evmClient)
+		assert.NoError(t, err)
+
+		res, err := svc.GetQuoteForOut(ctx, symbol, models.SELL, decimal.NewFromFloat(0.999), nil, "0xTOKEN")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "0.999", res.Size.String())
+		assert.Equal(t, "0.001", res.Fee.String())
+		assert.Equal(t, "1000", res.InAmount.String())
+	})
+
+	t.Run("should not start with an invalid schedule", func(t *testing.T) {
+		setSchedule(t, `{"default": {"takerFeeBps": "10", "makerRebateBps": "20"}}`)
+
+		_, err := service.New(newStore(), evmClient)
+		assert.ErrorIs(t, err, models.ErrInvalidFeeSchedule)
+	})
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// loads the TTL of firm quotes requested without one (FIRM_QUOTE_TTL_SEC) and the max TTL (FIRM_QUOTE_MAX_TTL_SEC)
+func loadFirmQuoteTtl() (defaultTtl, maxTtl time.Duration, err error) {
+	defaultSec, err := loadPositiveSeconds("FIRM_QUOTE_TTL_SEC", "10")
+	if err != nil {
+		return 0, 0, err
+	}
+	maxSec, err := loadPositiveSeconds("FIRM_QUOTE_MAX_TTL_SEC", "60")
+	if err != nil {
+		return 0, 0, err
+	}
+	if defaultSec > maxSec {
+		return 0, 0, fmt.Errorf("FIRM_QUOTE_TTL_SEC (%d) must not be above FIRM_QUOTE_MAX_TTL_SEC (%d)", defaultSec, maxSec)
+	}
+	return time.Duration(defaultSec) * time.Second, time.Duration(maxSec) * time.Second, nil
+}
+
+func loadPositiveSeconds(key, fallback string) (int, error) {
+	value := utils.GetEnv(key, fallback)
+	sec, err := strconv.Atoi(value)
+	if err != nil || sec <= 0 {
+		return 0, fmt.Errorf("%s is not a valid positive number of seconds: %q", key, value)
+	}
+	return sec, nil
+}
+
+// LockQuote locks the fragments of a quote for ttl, the default TTL when 0, so it can be converted to a swap at the same amounts
+// a ttl above the max fails with an error wrapping ErrInvalidInput
+func (s *Service) LockQuote(ctx context.Context, quote models.QuoteRes, ttl time.Duration) (models.FirmQuote, error) {
+	if ttl == 0 {
+		ttl = s.firmQuoteTtl
+	}
+	if ttl < 0 || ttl > s.firmQuoteMaxTtl {
+		return models.FirmQuote{}, fmt.Errorf("%w: 'ttlSec' must be at most %d", models.ErrInvalidInput, int(s.firmQuoteMaxTtl.Seconds()))
+	}
+	if len(quote.OrderFrags) == 0 {
+		return models.FirmQuote{}, models.ErrInsufficientLiquity
+	}
+
+	orders, err := s.lockOrderFrags(ctx, quote.OrderFrags)
+	if err != nil {
+		return models.FirmQuote{}, err
+	}
+
+	now := time.Now().UTC()
+	firmQuote := models.FirmQuote{
+		Id:        uuid.New(),
+		Legs:      models.RouteLegsOf(orders),
+		InAmount:  quote.InAmount,
+		OutAmount: quote.Size,
+		Frags:     quote.OrderFrags,
+		Created:   now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := s.orderBookStore.StoreFirmQuote(ctx, firmQuote); err != nil {
+		logctx.Error(ctx, "StoreFirmQuote failed, releasing its fragments", logger.Error(err))
+		_ = s.releaseFirmQuote(ctx, firmQuote)
+		return models.FirmQuote{}, err
+	}
+
+	logctx.Info(ctx, "firm quote locked", logger.String("quoteId", firmQuote.Id.String()), logger.String("route", routeString(firmQuote.Legs)), logger.String("expiresAt", firmQuote.ExpiresAt.String()))
+	return firmQuote, nil
+}
+
+// BeginSwapFromQuote converts a firm quote from the taker's in token to their out token to a swap of its locked fragments
+func (s *Service) BeginSwapFromQuote(ctx context.Context, quoteId uuid.UUID, inToken, outToken string) (models.BeginSwapRes, error) {
+	quote, err := s.orderBookStore.GetFirmQuote(ctx, quoteId)
+	if err != nil {
+		return models.BeginSwapRes{}, err
+	}
+	if quoteIn, quoteOut := quote.TakerTokens(); quoteIn != inToken || quoteOut != outToken {
+		return models.BeginSwapRes{}, models.ErrQuoteMismatch
+	}
+
+	// taken only once, either here or by the expiry check
+	quote, err = s.orderBookStore.TakeFirmQuote(ctx, quoteId)
+	if err != nil {
+		return models.BeginSwapRes{}, err
+	}
+	if quote.IsExpired(time.Now()) {
+		_ = s.releaseFirmQuote(ctx, *quote)
+		return models.BeginSwapRes{}, models.ErrQuoteExpired
+	}
+
+	orders := []models.Order{}
+	for _, frag := range quote.Frags {
+		order, err := s.orderBookStore.FindOrderById(ctx, frag.OrderId, false)
+		if err != nil {
+			logctx.Warn(ctx, "order of firm quote not found", logger.String("quoteId", quoteId.String()), logger.Error(err))
+			_ = s.releaseFirmQuote(ctx, *quote)
+			return models.BeginSwapRes{}, models.ErrSwapInvalid
+		}
+		orders = append(orders, *order)
+	}
+
+	res, err := s.storeLockedSwap(ctx, uuid.New(), quote.OutAmount, orders, quote.Frags)
+	if err != nil {
+		_ = s.releaseFirmQuote(ctx, *quote)
+		return models.BeginSwapRes{}, err
+	}
+	return res, nil
+}
+
+// releaseFirmQuote unlocks the fragments of a quote that was taken from the store
+func (s *Service) releaseFirmQuote(ctx context.Context, quote models.FirmQuote) error {
+	swap := models.NewSwap(quote.Legs, quote.Frags)
+	err := unlockSwapAndHandleCancelledOrders(ctx, s, s.orderBookStore, swap, false)
+	if err != nil {
+		logctx.Error(ctx, "failed to release firm quote", logger.String("quoteId", quote.Id.String()), logger.Error(err))
+	}
+	return err
+}
+
+// checkExpiredQuotes releases the fragments of firm quotes that were not converted to a swap in time
+func (s *Service) checkExpiredQuotes(ctx context.Context) error {
+	quotes, err := s.orderBookStore.GetFirmQuotes(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, quote := range quotes {
+		if !quote.IsExpired(now) {
+			continue
+		}
+		// may have been converted to a swap meanwhile
+		taken, err := s.orderBookStore.TakeFirmQuote(ctx, quote.Id)
+		if err != nil {
+			continue
+		}
+		if err := s.releaseFirmQuote(ctx, *taken); err == nil {
+			logctx.Debug(ctx, "released expired firm quote", logger.String("quoteId", quote.Id.String()))
+		}
+	}
+	return nil
+}
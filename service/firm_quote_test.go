@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingSwapStore fails to store swaps
+type failingSwapStore struct {
+	*mocks.MockOrderBookStore
+}
+
+func (s *failingSwapStore) StoreSwap(ctx context.Context, swapId uuid.UUID, legs []models.RouteLeg, frags []models.OrderFrag) error {
+	return errors.New("store swap failed")
+}
+
+func TestService_FirmQuote(t *testing.T) {
+	ctx := context.Background()
+
+	newOrder := func() *models.Order {
+		return &models.Order{Id: uuid.New(), UserId: uuid.New(), Symbol: "MATIC-USDC", Side: models.SELL, Price: decimal.NewFromInt(2), Size: decimal.NewFromInt(100)}
+	}
+	quoteOf := func(order *models.Order) models.QuoteRes {
+		return models.QuoteRes{Size: decimal.NewFromInt(10), InAmount: decimal.NewFromInt(20), OrderFrags: []models.OrderFrag{{OrderId: order.Id, OutSize: decimal.NewFromInt(10), InSize: decimal.NewFromInt(20)}}}
+	}
+
+	t.Run("should lock the quote's fragments until it expires", func(t *testing.T) {
+		order := newOrder()
+		store := &mocks.MockOrderBookStore{Order: order}
+		svc, _ := service.New(store, &service.EvmClient{})
+
+		quote, err := svc.LockQuote(ctx, quoteOf(order), 5*time.Second)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []models.RouteLeg{{Symbol: "MATIC-USDC", MakerSide: models.SELL}}, quote.Legs)
+		assert.True(t, quote.OutAmount.Equal(decimal.NewFromInt(10)))
+		assert.WithinDuration(t, time.Now().Add(5*time.Second), quote.ExpiresAt, time.Second)
+		assert.Equal(t, []models.FirmQuote{quote}, store.FirmQuotes)
+	})
+
+	t.Run("should reject a ttl above the max", func(t *testing.T) {
+		order := newOrder()
+		svc, _ := service.New(&mocks.MockOrderBookStore{Order: order}, &service.EvmClient{})
+
+		_, err := svc.LockQuote(ctx, quoteOf(order), time.Hour)
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+
+	t.Run("should not lock more than the order's available size", func(t *testing.T) {
+		order := newOrder()
+		order.SizePending = decimal.NewFromInt(95)
+		svc, _ := service.New(&mocks.MockOrderBookStore{Order: order}, &service.EvmClient{})
+
+		_, err := svc.LockQuote(ctx, quoteOf(order), 0)
+		assert.ErrorIs(t, err, models.ErrSwapInvalid)
+	})
+
+	t.Run("should convert the quote to a swap once", func(t *testing.T) {
+		order := newOrder()
+		store := &mocks.MockOrderBookStore{Order: order}
+		svc, _ := service.New(store, &service.EvmClient{})
+		quote, _ := svc.LockQuote(ctx, quoteOf(order), 0)
+
+		res, err := svc.BeginSwapFromQuote(ctx, quote.Id, "USDC", "MATIC")
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, res.SwapId)
+		assert.True(t, res.OutAmount.Equal(quote.OutAmount))
+		assert.Equal(t, quote.Frags, res.Fragments)
+		assert.Empty(t, store.FirmQuotes)
+
+		_, err = svc.BeginSwapFromQuote(ctx, quote.Id, "USDC", "MATIC")
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("should not convert a quote of other tokens", func(t *testing.T) {
+		order := newOrder()
+		store := &mocks.MockOrderBookStore{Order: order}
+		svc, _ := service.New(store, &service.EvmClient{})
+		quote, _ := svc.LockQuote(ctx, quoteOf(order), 0)
+
+		_, err := svc.BeginSwapFromQuote(ctx, quote.Id, "MATIC", "USDC")
+
+		assert.ErrorIs(t, err, models.ErrQuoteMismatch)
+		assert.Len(t, store.FirmQuotes, 1)
+	})
+
+	t.Run("should release an expired quote instead of converting it", func(t *testing.T) {
+		order := newOrder()
+		store := &mocks.MockOrderBookStore{Order: order}
+		svc, _ := service.New(store, &service.EvmClient{})
+		quote, _ := svc.LockQuote(ctx, quoteOf(order), 0)
+		store.FirmQuotes[0].ExpiresAt = time.Now().Add(-time.Second)
+		// the mock store does not keep the locked order
+		order.SizePending = decimal.NewFromInt(10)
+
+		_, err := svc.BeginSwapFromQuote(ctx, quote.Id, "USDC", "MATIC")
+
+		assert.ErrorIs(t, err, models.ErrQuoteExpired)
+		assert.Empty(t, store.FirmQuotes)
+		assert.True(t, order.SizePending.IsZero())
+	})
+
+	t.Run("should release the quote when its swap could not be stored", func(t *testing.T) {
+		order := newOrder()
+		store := &mocks.MockOrderBookStore{Order: order}
+		svc, _ := service.New(&failingSwapStore{store}, &service.EvmClient{})
+		quote, _ := svc.LockQuote(ctx, quoteOf(order), 0)
+		// the mock store does not keep the locked order
+		order.SizePending = decimal.NewFromInt(10)
+
+		_, err := svc.BeginSwapFromQuote(ctx, quote.Id, "USDC", "MATIC")
+
+		assert.ErrorContains(t, err, "store swap failed")
+		assert.Empty(t, store.FirmQuotes)
+		assert.True(t, order.SizePending.IsZero())
+	})
+}
+
+func TestService_New_FirmQuoteTtl(t *testing.T) {
+
+	t.Run("invalid FIRM_QUOTE_TTL_SEC - should fail", func(t *testing.T) {
+		for _, ttl := range []string{"10s", "0", "-1", ""} {
+			t.Setenv("FIRM_QUOTE_TTL_SEC", ttl)
+			_, err := service.New(&mocks.MockOrderBookStore{}, &service.EvmClient{})
+			assert.ErrorContains(t, err, "FIRM_QUOTE_TTL_SEC", ttl)
+		}
+	})
+
+	t.Run("invalid FIRM_QUOTE_MAX_TTL_SEC - should fail", func(t *testing.T) {
+		for _, ttl := range []string{"1m", "0", ""} {
+			t.Setenv("FIRM_QUOTE_MAX_TTL_SEC", ttl)
+			_, err := service.New(&mocks.MockOrderBookStore{}, &service.EvmClient{})
+			assert.ErrorContains(t, err, "FIRM_QUOTE_MAX_TTL_SEC", ttl)
+		}
+	})
+
+	t.Run("default TTL above the max - should fail", func(t *testing.T) {
+		t.Setenv("FIRM_QUOTE_TTL_SEC", "30")
+		t.Setenv("FIRM_QUOTE_MAX_TTL_SEC", "20")
+		_, err := service.New(&mocks.MockOrderBookStore{}, &service.EvmClient{})
+		assert.ErrorContains(t, err, "FIRM_QUOTE_MAX_TTL_SEC")
+	})
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// max candles returned in a single request
+const MAX_CANDLES = 1000
+
+// GetCandles returns the symbol's candles of an interval which start within [startAt, endAt), oldest first
+// endAt defaults to now, startAt to MAX_CANDLES intervals before endAt; longer ranges are cut to the latest MAX_CANDLES intervals
+func (s *Service) GetCandles(ctx context.Context, symbol models.Symbol, interval models.CandleInterval, startAt, endAt time.Time) ([]models.Candle, error) {
+	if endAt.IsZero() {
+		endAt = time.Now()
+	}
+
+	earliest := endAt.Add(-MAX_CANDLES * interval.Duration())
+	if startAt.IsZero() || startAt.Before(earliest) {
+		startAt = earliest
+	}
+
+	if !startAt.Before(endAt) {
+		logctx.Warn(ctx, "candles startAt is not before endAt", logger.String("startAt", startAt.String()), logger.String("endAt", endAt.String()))
+		return nil, models.ErrInvalidInput
+	}
+
+	candles, err := s.orderBookStore.GetCandles(ctx, symbol, interval, startAt, endAt)
+	if err != nil {
+		logctx.Error(ctx, "error getting candles", logger.Error(err), logger.String("symbol", symbol.String()), logger.String("interval", interval.String()))
+		return nil, err
+	}
+
+	return candles, nil
+}
@@ -0,0 +1,39 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_GetCandles(t *testing.T) {
+	ctx := context.Background()
+	candles := []models.Candle{models.NewCandle("MATIC-USDC", models.CANDLE_1H, time.Unix(3600, 0))}
+
+	t.Run("should return candles", func(t *testing.T) {
+		svc, _ := service.New(&mocks.MockOrderBookStore{Candles: candles}, &mocks.MockBcClient{})
+
+		res, err := svc.GetCandles(ctx, "MATIC-USDC", models.CANDLE_1H, time.Time{}, time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, candles, res)
+	})
+
+	t.Run("should reject startAt after endAt", func(t *testing.T) {
+		svc, _ := service.New(&mocks.MockOrderBookStore{Candles: candles}, &mocks.MockBcClient{})
+
+		_, err := svc.GetCandles(ctx, "MATIC-USDC", models.CANDLE_1H, time.Unix(7200, 0), time.Unix(3600, 0))
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
+
+	t.Run("should return store error", func(t *testing.T) {
+		svc, _ := service.New(&mocks.MockOrderBookStore{Error: assert.AnError}, &mocks.MockBcClient{})
+
+		_, err := svc.GetCandles(ctx, "MATIC-USDC", models.CANDLE_1H, time.Time{}, time.Time{})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
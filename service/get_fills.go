@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+const DEFAULT_FILLS_LIMIT = 100
+const MAX_FILLS = 256
+
+// prefix of the cursors of pages read from the archive
+const archiveCursorPrefix = "archive:"
+
+// normalizeHistoryLimit applies the default page size, the max page size of a request is applied by the transport
+func normalizeHistoryLimit(filter *models.HistoryFilter) {
+	if filter.Limit <= 0 {
+		filter.Limit = DEFAULT_FILLS_LIMIT
+	}
+}
+
+// GetFills returns a page of the user's fills, newest first, and the cursor of the next page (empty on the last page)
+// the pages continue into the archive once the fills in the store are exhausted
+func (s *Service) GetFills(ctx context.Context, userId uuid.UUID, filter models.HistoryFilter) ([]models.Fill, string, error) {
+	normalizeHistoryLimit(&filter)
+
+	logctx.Debug(ctx, "getting fills for user", logger.String("user_id", userId.String()), logger.String("cursor", filter.Cursor), logger.Int("limit", filter.Limit))
+
+	if strings.HasPrefix(filter.Cursor, archiveCursorPrefix) {
+		if s.archive == nil {
+			return nil, "", models.ErrInvalidInput
+		}
+		filter.Cursor = strings.TrimPrefix(filter.Cursor, archiveCursorPrefix)
+		return s.getArchivedFills(ctx, userId, filter, []models.Fill{})
+	}
+
+	fills, cursor, err := s.orderBookStore.GetUserFills(ctx, userId, filter)
+	if err != nil {
+		logctx.Error(ctx, "error getting user fills", logger.Error(err), logger.String("user_id", userId.String()))
+		return nil, "", err
+	}
+
+	if cursor == "" && s.archive != nil && len(fills) < filter.Limit {
+		filter.Cursor = ""
+		filter.Limit -= len(fills)
+		return s.getArchivedFills(ctx, userId, filter, fills)
+	}
+
+	return fills, cursor, nil
+}
+
+// getArchivedFills appends a page of the user's archived fills to fills
+func (s *Service) getArchivedFills(ctx context.Context, userId uuid.UUID, filter models.HistoryFilter, fills []models.Fill) ([]models.Fill, string, error) {
+	archived, cursor, err := s.archive.GetUserFills(ctx, userId, filter)
+	if err != nil {
+		logctx.Error(ctx, "error getting archived user fills", logger.Error(err), logger.String("user_id", userId.String()))
+		return nil, "", err
+	}
+	if cursor != "" {
+		cursor = archiveCursorPrefix + cursor
+	}
+	return append(fills, archived...), cursor, nil
+}
+
+// GetSwapFills returns the fills of the user's orders in a resolved swap, from the store and the archive
+func (s *Service) GetSwapFills(ctx context.Context, userId uuid.UUID, swapId uuid.UUID) ([]models.Fill, error) {
+	_, err := s.orderBookStore.GetSwap(ctx, swapId, false)
+	if err == models.ErrNotFound && s.archive != nil {
+		_, err = s.archive.GetSwap(ctx, swapId)
+	}
+	if err != nil {
+		if err != models.ErrNotFound {
+			logctx.Error(ctx, "error getting resolved swap", logger.Error(err), logger.String("swapId", swapId.String()))
+		}
+		return nil, err
+	}
+
+	fills := []models.Fill{}
+	seen := map[uuid.UUID]struct{}{}
+	add := func(fill models.Fill) {
+		if _, ok := seen[fill.OrderId]; fill.SwapId != swapId || ok {
+			return
+		}
+		seen[fill.OrderId] = struct{}{}
+		fills = append(fills, fill)
+	}
+
+	stored, err := s.orderBookStore.GetUserSwapFills(ctx, userId, swapId)
+	if err != nil {
+		logctx.Error(ctx, "error getting user swap fills", logger.Error(err), logger.String("swapId", swapId.String()))
+		return nil, err
+	}
+	for _, fill := range stored {
+		add(fill)
+	}
+
+	if s.archive != nil {
+		archived, err := s.archive.GetSwapFills(ctx, userId, swapId)
+		if err != nil {
+			logctx.Error(ctx, "error getting archived swap fills", logger.Error(err), logger.String("swapId", swapId.String()))
+			return nil, err
+		}
+		for _, fill := range archived {
+			add(fill)
+		}
+	}
+
+	return fills, nil
+}
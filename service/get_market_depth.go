@@ -8,8 +8,14 @@ import (
 	"github.com/orbs-network/order-book/utils/logger/logctx"
 )
 
-func (s *Service) GetMarketDepth(ctx context.Context, symbol models.Symbol, depth int) (models.MarketDepth, error) {
-	marketDepth, err := s.orderBookStore.GetMarketDepth(ctx, symbol, depth)
+// GetMarketDepth returns price levels (L2) or orders (L3) of each side of the book, best price first
+func (s *Service) GetMarketDepth(ctx context.Context, symbol models.Symbol, query models.DepthQuery) (models.MarketDepth, error) {
+	if query.Depth <= 0 || query.Step.IsNegative() {
+		logctx.Warn(ctx, "invalid market depth query", logger.String("symbol", string(symbol)), logger.Int("depth", query.Depth), logger.String("step", query.Step.String()))
+		return models.MarketDepth{}, models.ErrInvalidInput
+	}
+
+	marketDepth, err := s.orderBookStore.GetMarketDepth(ctx, symbol, query)
 
 	if err != nil {
 		logctx.Error(ctx, "unexpected error getting market depth", logger.Error(err), logger.String("symbol", string(symbol)))
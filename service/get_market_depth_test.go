@@ -32,7 +32,7 @@ func TestGetMarketDepth(t *testing.T) {
 			MarketDepth: md,
 		}, &mocks.MockBcClient{})
 
-		marketDepth, err := svc.GetMarketDepth(ctx, "MATIC-USDC", 5)
+		marketDepth, err := svc.GetMarketDepth(ctx, "MATIC-USDC", models.DepthQuery{Mode: models.DEPTH_L2, Depth: 5})
 
 		assert.NoError(t, err, "Get market depth should not return an error")
 		assert.Equal(t, md, marketDepth, "Expected non-nil market depth")
@@ -45,11 +45,23 @@ func TestGetMarketDepth(t *testing.T) {
 			Error:       assert.AnError,
 		}, &mocks.MockBcClient{})
 
-		marketDepth, err := svc.GetMarketDepth(ctx, "MATIC-USDC", 5)
+		marketDepth, err := svc.GetMarketDepth(ctx, "MATIC-USDC", models.DepthQuery{Mode: models.DEPTH_L2, Depth: 5})
 
 		assert.Zero(t, len(marketDepth.Asks), "marketDepth.Asks should be empty")
 		assert.Zero(t, len(marketDepth.Bids), "marketDepth.Bids should be empty")
 
 		assert.Error(t, err, "Get market depth should not return an error")
 	})
+
+	t.Run("should reject invalid query", func(t *testing.T) {
+		svc, _ := service.New(&mocks.MockOrderBookStore{
+			MarketDepth: md,
+		}, &mocks.MockBcClient{})
+
+		_, err := svc.GetMarketDepth(ctx, "MATIC-USDC", models.DepthQuery{Mode: models.DEPTH_L2, Depth: 0})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+
+		_, err = svc.GetMarketDepth(ctx, "MATIC-USDC", models.DepthQuery{Mode: models.DEPTH_L2, Depth: 5, Step: decimal.NewFromInt(-1)})
+		assert.ErrorIs(t, err, models.ErrInvalidInput)
+	})
 }
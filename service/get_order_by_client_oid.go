@@ -10,7 +10,7 @@ import (
 )
 
 func (s *Service) GetOrderByClientOId(ctx context.Context, clientOId uuid.UUID) (*models.Order, error) {
-	order, err := s.orderBookStore.FindOrderById(ctx, clientOId, true)
+	order, err := s.findOrderByClientOId(ctx, clientOId)
 
 	if err == models.ErrNotFound {
 		logctx.Debug(ctx, "order not found", logger.String("clientOId", clientOId.String()))
@@ -24,3 +24,13 @@ func (s *Service) GetOrderByClientOId(ctx context.Context, clientOId uuid.UUID)
 
 	return order, nil
 }
+
+// findOrderByClientOId finds the order of the clientOId in the store, then in the archive closed orders are moved to
+// the archive is written before orders are removed from the store, so an order being archived is found in either
+func (s *Service) findOrderByClientOId(ctx context.Context, clientOId uuid.UUID) (*models.Order, error) {
+	order, err := s.orderBookStore.FindOrderById(ctx, clientOId, true)
+	if err == models.ErrNotFound && s.archive != nil {
+		order, err = s.archive.FindOrderById(ctx, clientOId, true)
+	}
+	return order, err
+}
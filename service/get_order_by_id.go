@@ -11,6 +11,10 @@ import (
 
 func (s *Service) GetOrderById(ctx context.Context, orderId uuid.UUID) (*models.Order, error) {
 	order, err := s.orderBookStore.FindOrderById(ctx, orderId, false)
+	if err == models.ErrNotFound && s.archive != nil {
+		// closed orders are moved to the archive
+		order, err = s.archive.FindOrderById(ctx, orderId, false)
+	}
 
 	if err == models.ErrNotFound {
 		logctx.Debug(ctx, "order not found", logger.String("orderId", orderId.String()))
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// GetTrades returns a page of the symbol's public trades, newest first, and the cursor of the next page (empty on the last page)
+func (s *Service) GetTrades(ctx context.Context, symbol models.Symbol, filter models.HistoryFilter) ([]models.Trade, string, error) {
+	normalizeHistoryLimit(&filter)
+
+	trades, cursor, err := s.orderBookStore.GetTrades(ctx, symbol, filter)
+	if err != nil {
+		logctx.Error(ctx, "error getting trades", logger.Error(err), logger.String("symbol", symbol.String()))
+		return nil, "", err
+	}
+
+	return trades, cursor, nil
+}
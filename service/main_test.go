@@ -0,0 +1,23 @@
+package service_test
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// tests which verify signatures set REPERMIT_ADDRESS themselves
+	err := os.Setenv("SKIP_SIGNATURE_VERIFICATION", "true")
+	if err != nil {
+		panic(err)
+	}
+
+	exitVal := m.Run()
+
+	err = os.Unsetenv("SKIP_SIGNATURE_VERIFICATION")
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(exitVal)
+}
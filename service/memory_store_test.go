@@ -0,0 +1,141 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/data/memrepo"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runs the swap and cancel flows against the in-memory store, without Redis
+func TestService_MemoryStore(t *testing.T) {
+	ctx := context.Background()
+
+	newOrder := func() models.Order {
+		return models.Order{Id: uuid.New(), ClientOId: uuid.New(), UserId: uuid.New(), Symbol: "MATIC-USDC", Side: models.SELL, Price: decimal.NewFromInt(2), Size: decimal.NewFromInt(10), Timestamp: time.Now()}
+	}
+
+	t.Run("should lock each order's size once for concurrent swaps", func(t *testing.T) {
+		store, err := memrepo.NewMemoryRepository()
+		require.NoError(t, err)
+		svc, err := service.New(store, &service.EvmClient{})
+		require.NoError(t, err)
+
+		order := newOrder()
+		require.NoError(t, store.StoreOpenOrder(ctx, order))
+		quote := models.QuoteRes{Size: decimal.NewFromInt(1), InAmount: decimal.NewFromInt(2), OrderFrags: []models.OrderFrag{{OrderId: order.Id, OutSize: decimal.NewFromInt(1), InSize: decimal.NewFromInt(2)}}}
+
+		const swaps = 50
+		errs := make(chan error, swaps)
+		var wg sync.WaitGroup
+		for i := 0; i < swaps; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := svc.BeginSwap(ctx, quote)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		locked := 0
+		for err := range errs {
+			if err == nil {
+				locked++
+			}
+		}
+		assert.Equal(t, 10, locked)
+
+		stored, err := store.FindOrderById(ctx, order.Id, false)
+		assert.NoError(t, err)
+		assert.True(t, stored.SizePending.Equal(decimal.NewFromInt(10)), "sizePending %s", stored.SizePending)
+		swapsInStore, err := store.GetOpenSwaps(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, swapsInStore, 10)
+	})
+
+	t.Run("should cancel an order while swaps lock it", func(t *testing.T) {
+		store, err := memrepo.NewMemoryRepository()
+		require.NoError(t, err)
+		svc, err := service.New(store, &service.EvmClient{})
+		require.NoError(t, err)
+
+		order := newOrder()
+		require.NoError(t, store.StoreOpenOrder(ctx, order))
+		quote := models.QuoteRes{Size: decimal.NewFromInt(1), InAmount: decimal.NewFromInt(2), OrderFrags: []models.OrderFrag{{OrderId: order.Id, OutSize: decimal.NewFromInt(1), InSize: decimal.NewFromInt(2)}}}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = svc.BeginSwap(ctx, quote)
+			}()
+		}
+		_, cancelErr := svc.CancelOrder(ctx, service.CancelOrderInput{Id: order.Id, UserId: order.UserId})
+		wg.Wait()
+
+		assert.NoError(t, cancelErr)
+		stored, err := store.FindOrderById(ctx, order.Id, false)
+		if err == nil {
+			// kept as cancelled while its swaps are pending
+			assert.True(t, stored.Cancelled)
+			assert.True(t, stored.IsPending())
+		} else {
+			assert.ErrorIs(t, err, models.ErrNotFound)
+		}
+		depth, err := store.GetMarketDepth(ctx, order.Symbol, models.DepthQuery{Mode: models.DEPTH_L2, Depth: 10})
+		assert.NoError(t, err)
+		assert.Empty(t, depth.Asks)
+	})
+
+	t.Run("should store the legs of a routed swap and send each maker the symbol of their orders", func(t *testing.T) {
+		store, err := memrepo.NewMemoryRepository()
+		require.NoError(t, err)
+		svc, err := service.New(store, &service.EvmClient{})
+		require.NoError(t, err)
+
+		first := newOrder()
+		first.Symbol = "MATIC-ETH"
+		first.Side = models.BUY
+		second := newOrder()
+		second.Symbol = "ETH-USDC"
+		second.UserId = first.UserId
+		require.NoError(t, store.StoreOpenOrder(ctx, first))
+		require.NoError(t, store.StoreOpenOrder(ctx, second))
+		quote := models.QuoteRes{Size: decimal.NewFromInt(1), InAmount: decimal.NewFromInt(2), OrderFrags: []models.OrderFrag{
+			{OrderId: first.Id, OutSize: decimal.NewFromInt(2), InSize: decimal.NewFromInt(1)},
+			{OrderId: second.Id, OutSize: decimal.NewFromInt(1), InSize: decimal.NewFromInt(2)},
+		}}
+
+		res, err := svc.BeginSwap(ctx, quote)
+		require.NoError(t, err)
+
+		swap, err := store.GetSwap(ctx, res.SwapId, true)
+		require.NoError(t, err)
+		assert.Equal(t, []models.RouteLeg{{Symbol: "MATIC-ETH", MakerSide: models.BUY}, {Symbol: "ETH-USDC", MakerSide: models.SELL}}, swap.Legs)
+
+		events, err := store.ReadUserEvents(ctx, first.UserId, 0, 10, 0)
+		require.NoError(t, err)
+		var locked models.SwapEvent
+		for _, event := range events {
+			if json.Unmarshal(event.Data, &locked) == nil && locked.Event == models.USER_EVENT_SWAP_LOCKED {
+				break
+			}
+		}
+		require.Equal(t, models.USER_EVENT_SWAP_LOCKED, locked.Event)
+		require.Len(t, locked.Frags, 2)
+		assert.Equal(t, models.Symbol("MATIC-ETH"), locked.Frags[0].Symbol)
+		assert.Equal(t, models.Symbol("ETH-USDC"), locked.Frags[1].Symbol)
+	})
+}
@@ -6,13 +6,13 @@ import (
 	"time"
 
 	"github.com/orbs-network/order-book/models"
-	"github.com/orbs-network/order-book/transport/restutils"
+	"github.com/orbs-network/order-book/utils"
 	"github.com/orbs-network/order-book/utils/logger"
 	"github.com/orbs-network/order-book/utils/logger/logctx"
 )
 
 func (s *Service) startPeriodicChecks() {
-	secPeriodic := restutils.GetEnv("SEC_PERIODIC_INTERVAL", "10")
+	secPeriodic := utils.GetEnv("SEC_PERIODIC_INTERVAL", "10")
 	sec, _ := strconv.Atoi(secPeriodic)
 	ctx := context.Background()
 	logctx.Debug(ctx, "startPeriodicChecks", logger.Int("sec_interval", sec))
@@ -25,7 +25,7 @@ func (s *Service) startPeriodicChecks() {
 }
 func (s *Service) periodicCheck(ctx context.Context) {
 	// cleanup dangeling swaps which did not start
-	secSwapStarted := restutils.GetEnv("SEC_SWAP_STARTED", "60")
+	secSwapStarted := utils.GetEnv("SEC_SWAP_STARTED", "60")
 	sec, _ := strconv.Atoi(secSwapStarted)
 
 	if sec > 0 { // USE ZERO as Turn Off feature flag
@@ -34,6 +34,16 @@ func (s *Service) periodicCheck(ctx context.Context) {
 			logctx.Error(ctx, "Error in peridic checks", logger.Error(err))
 		}
 	}
+
+	// release firm quotes which were not converted to a swap
+	if err := s.checkExpiredQuotes(ctx); err != nil {
+		logctx.Error(ctx, "Error in peridic checks", logger.Error(err))
+	}
+
+	// take orders with a passed signed deadline off the book
+	if err := s.checkExpiredOrders(ctx); err != nil {
+		logctx.Error(ctx, "Error in peridic checks", logger.Error(err))
+	}
 }
 
 func secondsSinceTimestamp(t time.Time) (int64, error) {
@@ -63,7 +73,7 @@ func (s *Service) checkSwapStarted(ctx context.Context, swap models.Swap, secPer
 		return
 	}
 	logctx.Debug(ctx, "swap was not started after allowed period", logger.String("swapId", swap.Id.String()))
-	err = s.AbortSwap(ctx, swap.Id)
+	err = s.abortSwap(ctx, swap.Id, models.SWAP_ABORT_REASON_NOT_STARTED)
 	if err != nil {
 		logctx.Error(ctx, "failed to AutoabortSwap", logger.String("created", swap.Created.String()), logger.Error(err))
 		return
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/orbs-network/order-book/data/store"
@@ -12,48 +13,163 @@ import (
 	"github.com/orbs-network/order-book/utils/logger/logctx"
 )
 
-func (s *Service) SubscribeUserOrders(ctx context.Context, userId uuid.UUID) (chan []byte, error) {
-	logctx.Info(ctx, "subscribing to user orders", logger.String("userId", userId.String()))
+// number of user events read from the store per round trip
+const userEventsBatchSize = 100
+
+// how long a read waits for new user events before checking the subscription is still open
+const userEventsBlock = 5 * time.Second
+
+// SubscribeUserOrders streams the user's events in seq order, each with its "seq", starting at fromSeq
+// a fromSeq of 0 streams new events only. Events no longer kept are reported by a single "events-gap" event
+// the returned channel is closed when the subscription ends
+func (s *Service) SubscribeUserOrders(ctx context.Context, userId uuid.UUID, fromSeq int64) (chan []byte, error) {
+	logctx.Info(ctx, "subscribing to user orders", logger.String("userId", userId.String()), logger.Int64("fromSeq", fromSeq))
+
+	afterSeq := fromSeq - 1
+	if fromSeq <= 0 {
+		lastSeq, err := s.orderBookStore.GetUserEventSeq(ctx, userId)
+		if err != nil {
+			logctx.Error(ctx, "failed to get user event seq", logger.String("userId", userId.String()), logger.Error(err))
+			return nil, fmt.Errorf("failed to subscribe to user orders: %w", err)
+		}
+		afterSeq = lastSeq
+	}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	clientChan := make(chan []byte, userEventsBatchSize)
+
+	s.userSubsMu.Lock()
+	if s.userSubs == nil {
+		s.userSubs = make(map[chan []byte]context.CancelFunc)
+	}
+	s.userSubs[clientChan] = cancel
+	s.userSubsMu.Unlock()
 
-	eventKey := models.CreateUserOrdersEventKey(userId)
+	go s.streamUserEvents(subCtx, userId, afterSeq, clientChan)
+
+	return clientChan, nil
+}
+
+func (s *Service) UnsubscribeUserOrders(ctx context.Context, userId uuid.UUID, clientChan chan []byte) error {
+	logctx.Info(ctx, "unsubscribing from user orders", logger.String("userId", userId.String()))
+
+	s.userSubsMu.Lock()
+	cancel, exists := s.userSubs[clientChan]
+	delete(s.userSubs, clientChan)
+	s.userSubsMu.Unlock()
+
+	if exists {
+		cancel()
+	}
+
+	return nil
+}
+
+// streamUserEvents sends the user's events after afterSeq to clientChan until ctx is done
+// a slow client is not dropped, reading pauses until it catches up
+func (s *Service) streamUserEvents(ctx context.Context, userId uuid.UUID, afterSeq int64, clientChan chan []byte) {
+	defer close(clientChan)
+
+	send := func(msg []byte) bool {
+		select {
+		case clientChan <- msg:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for ctx.Err() == nil {
+		events, err := s.orderBookStore.ReadUserEvents(ctx, userId, afterSeq, userEventsBatchSize, userEventsBlock)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logctx.Warn(ctx, "failed to read user events, retrying", logger.String("userId", userId.String()), logger.Error(err))
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+			}
+			continue
+		}
+
+		for _, event := range events {
+			if event.Seq > afterSeq+1 {
+				logctx.Warn(ctx, "user events were trimmed before sent", logger.String("userId", userId.String()), logger.Int64("fromSeq", afterSeq+1), logger.Int64("toSeq", event.Seq-1))
+				gap, _ := json.Marshal(models.NewEventsGap(afterSeq+1, event.Seq-1))
+				if !send(gap) {
+					return
+				}
+			}
+			if !send(event.Payload()) {
+				return
+			}
+			afterSeq = event.Seq
+		}
+	}
+}
+
+// SubscribeMarketEvents subscribes to a symbol's public book changes and trades
+func (s *Service) SubscribeMarketEvents(ctx context.Context, symbol models.Symbol) (chan []byte, error) {
+	logctx.Info(ctx, "subscribing to market events", logger.String("symbol", symbol.String()))
+
+	eventKey := models.CreateMarketEventKey(symbol)
 
 	channel, err := s.orderBookStore.SubscribeToEvents(ctx, eventKey)
 	if err != nil {
-		logctx.Error(ctx, "failed to subscribe to user orders", logger.String("event", eventKey), logger.Error(err))
-		return nil, fmt.Errorf("failed to subscribe to user orders: %w", err)
+		logctx.Error(ctx, "failed to subscribe to market events", logger.String("event", eventKey), logger.Error(err))
+		return nil, fmt.Errorf("failed to subscribe to market events: %w", err)
 	}
 
 	return channel, nil
 }
 
-func (s *Service) UnsubscribeUserOrders(ctx context.Context, userId uuid.UUID, clientChan chan []byte) error {
-	logctx.Info(ctx, "unsubscribing from user orders", logger.String("userId", userId.String()))
+func (s *Service) UnsubscribeMarketEvents(ctx context.Context, symbol models.Symbol, clientChan chan []byte) error {
+	logctx.Info(ctx, "unsubscribing from market events", logger.String("symbol", symbol.String()))
 
-	eventKey := models.CreateUserOrdersEventKey(userId)
+	eventKey := models.CreateMarketEventKey(symbol)
 
 	s.orderBookStore.UnsubscribeFromEvents(ctx, eventKey, clientChan)
 
 	return nil
 }
 
+func publishTradeEvent(ctx context.Context, store store.OrderBookStore, trade models.Trade) {
+	value, err := json.Marshal(models.MarketEvent{
+		Event:  models.MARKET_EVENT_TRADE,
+		Symbol: trade.Symbol,
+		Trade:  &trade,
+	})
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal trade to json", logger.Error(err))
+		return
+	}
+
+	key := models.CreateMarketEventKey(trade.Symbol)
+	if err := store.PublishEvent(ctx, key, value); err != nil {
+		logctx.Error(ctx, "failed to publish trade event", logger.String("event", key), logger.Error(err))
+	}
+}
+
+func (e *EvmClient) publishTradeEvent(ctx context.Context, trade models.Trade) {
+	publishTradeEvent(ctx, e.orderBookStore, trade)
+}
+
 func publishFillEvent(ctx context.Context, store store.OrderBookStore, userId uuid.UUID, fill models.Fill) {
 	//value, err := json.Marshal(fill)
 	value, err := json.Marshal(struct {
 		Event string `json:"event"`
 		models.Fill
 	}{
-		Event: "order-fill",
+		Event: models.USER_EVENT_ORDER_FILL,
 		Fill:  fill,
 	})
 	if err != nil {
 		logctx.Error(ctx, "failed to marshal order to json", logger.Error(err))
+		return
 	}
 
-	key := models.CreateUserOrdersEventKey(userId)
-	if err := store.PublishEvent(ctx, key, value); err != nil {
-		logctx.Error(ctx, "failed to publish fill event", logger.String("event", key), logger.Error(err))
-	}
-
+	appendUserEvent(ctx, store, userId, value)
 }
 func (e *EvmClient) publishFillEvent(ctx context.Context, userId uuid.UUID, fill models.Fill) {
 	publishFillEvent(ctx, e.orderBookStore, userId, fill)
@@ -64,13 +180,22 @@ func (s *Service) publishFillEvent(ctx context.Context, userId uuid.UUID, fill m
 }
 
 func publishOrderEvent(ctx context.Context, store store.OrderBookStore, order *models.Order) {
-	key, value, err := createOrderEvent(ctx, order)
+	publishOrderEventOfType(ctx, store, models.USER_EVENT_ORDER_CHANGED, order)
+}
+
+func publishOrderEventOfType(ctx context.Context, store store.OrderBookStore, event string, order *models.Order) {
+	value, err := createOrderEvent(ctx, event, order)
 	if err != nil {
 		return
 	}
 
-	if err := store.PublishEvent(ctx, key, value); err != nil {
-		logctx.Error(ctx, "failed to publish order event", logger.String("event", key), logger.Error(err))
+	appendUserEvent(ctx, store, order.UserId, value)
+}
+
+// appendUserEvent adds the event to the user's sequenced events, which subscriptions read from
+func appendUserEvent(ctx context.Context, store store.OrderBookStore, userId uuid.UUID, value []byte) {
+	if _, err := store.AppendUserEvent(ctx, userId, value); err != nil {
+		logctx.Error(ctx, "failed to append user event", logger.String("userId", userId.String()), logger.Error(err))
 	}
 }
 
@@ -81,21 +206,27 @@ func (s *Service) publishOrderEvent(ctx context.Context, order *models.Order) {
 	publishOrderEvent(ctx, s.orderBookStore, order)
 }
 
-func createOrderEvent(ctx context.Context, order *models.Order) (key string, value []byte, err error) {
+func (s *Service) publishOrderAmendedEvent(ctx context.Context, order *models.Order) {
+	publishOrderEventOfType(ctx, s.orderBookStore, models.USER_EVENT_ORDER_AMENDED, order)
+}
+
+func (s *Service) publishOrderExpiredEvent(ctx context.Context, order *models.Order) {
+	publishOrderEventOfType(ctx, s.orderBookStore, models.USER_EVENT_ORDER_EXPIRED, order)
+}
+
+func createOrderEvent(ctx context.Context, event string, order *models.Order) (value []byte, err error) {
 	//value, err = order.ToJson()
 
 	value, err = json.Marshal(struct {
 		Event string `json:"event"`
 		models.Order
 	}{
-		Event: "order-changed",
+		Event: event,
 		Order: *order,
 	})
 	if err != nil {
 		logctx.Error(ctx, "failed to marshal order to json", logger.Error(err))
 	}
 
-	key = models.CreateUserOrdersEventKey(order.UserId)
-
-	return key, value, err
+	return value, err
 }
@@ -3,34 +3,116 @@ package service_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
 	"github.com/orbs-network/order-book/service"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestService_publishOrderEvent(t *testing.T) {
+func TestService_SubscribeUserOrders(t *testing.T) {
 	ctx := context.Background()
 
-	t.Run("should subscribe to user order event updates", func(t *testing.T) {
+	receive := func(t *testing.T, channel chan []byte) string {
+		select {
+		case msg := <-channel:
+			return string(msg)
+		case <-time.After(time.Second):
+			t.Fatal("no user event received")
+			return ""
+		}
+	}
+
+	t.Run("should stream only new events when fromSeq is 0", func(t *testing.T) {
+		store := &mocks.MockOrderBookStore{}
+		_, _ = store.AppendUserEvent(ctx, mocks.UserId, []byte(`{"event":"order-changed"}`))
+		svc, _ := service.New(store, &mocks.MockBcClient{})
+
+		channel, err := svc.SubscribeUserOrders(ctx, mocks.UserId, 0)
+		assert.NoError(t, err)
+
+		_, _ = store.AppendUserEvent(ctx, mocks.UserId, []byte(`{"event":"order-fill"}`))
+		assert.Equal(t, `{"seq":2,"event":"order-fill"}`, receive(t, channel))
+		assert.NoError(t, svc.UnsubscribeUserOrders(ctx, mocks.UserId, channel))
+	})
+
+	t.Run("should replay events from seq", func(t *testing.T) {
+		store := &mocks.MockOrderBookStore{}
+		_, _ = store.AppendUserEvent(ctx, mocks.UserId, []byte(`{"event":"order-changed"}`))
+		_, _ = store.AppendUserEvent(ctx, mocks.UserId, []byte(`{"event":"order-fill"}`))
+		svc, _ := service.New(store, &mocks.MockBcClient{})
+
+		channel, err := svc.SubscribeUserOrders(ctx, mocks.UserId, 1)
+		assert.NoError(t, err)
+
+		assert.Equal(t, `{"seq":1,"event":"order-changed"}`, receive(t, channel))
+		assert.Equal(t, `{"seq":2,"event":"order-fill"}`, receive(t, channel))
+		assert.NoError(t, svc.UnsubscribeUserOrders(ctx, mocks.UserId, channel))
+	})
+
+	t.Run("should notify gap of events no longer kept", func(t *testing.T) {
+		store := &mocks.MockOrderBookStore{UserEvents: []models.UserEvent{{Seq: 5, Data: []byte(`{"event":"order-fill"}`)}}}
+		svc, _ := service.New(store, &mocks.MockBcClient{})
+
+		channel, err := svc.SubscribeUserOrders(ctx, mocks.UserId, 2)
+		assert.NoError(t, err)
+
+		assert.Equal(t, `{"event":"events-gap","fromSeq":2,"toSeq":4}`, receive(t, channel))
+		assert.Equal(t, `{"seq":5,"event":"order-fill"}`, receive(t, channel))
+		assert.NoError(t, svc.UnsubscribeUserOrders(ctx, mocks.UserId, channel))
+	})
+
+	t.Run("should close channel on unsubscribe", func(t *testing.T) {
+		svc, _ := service.New(&mocks.MockOrderBookStore{}, &mocks.MockBcClient{})
+
+		channel, err := svc.SubscribeUserOrders(ctx, mocks.UserId, 0)
+		assert.NoError(t, err)
+		assert.NoError(t, svc.UnsubscribeUserOrders(ctx, mocks.UserId, channel))
+
+		select {
+		case _, ok := <-channel:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("channel not closed")
+		}
+	})
+
+	t.Run("should return error when failed to get last seq", func(t *testing.T) {
+		svc, _ := service.New(&mocks.MockOrderBookStore{
+			Error: assert.AnError,
+		}, &mocks.MockBcClient{})
+
+		channel, err := svc.SubscribeUserOrders(ctx, mocks.UserId, 0)
+
+		assert.Nil(t, channel)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestService_SubscribeMarketEvents(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should subscribe to market events of a symbol", func(t *testing.T) {
 		svc, _ := service.New(&mocks.MockOrderBookStore{
 			EventsChan: make(chan []byte),
 		}, &mocks.MockBcClient{})
 
-		channel, err := svc.SubscribeUserOrders(ctx, mocks.UserId)
+		channel, err := svc.SubscribeMarketEvents(ctx, "MATIC-USDC")
 
 		assert.NotNil(t, channel)
 		assert.NoError(t, err)
+		assert.NoError(t, svc.UnsubscribeMarketEvents(ctx, "MATIC-USDC", channel))
 	})
 
-	t.Run("should return error when failed to subscribe to user order event updates", func(t *testing.T) {
+	t.Run("should return error when failed to subscribe to market events", func(t *testing.T) {
 		svc, _ := service.New(&mocks.MockOrderBookStore{
 			Error: assert.AnError,
 		}, &mocks.MockBcClient{})
 
-		channel, err := svc.SubscribeUserOrders(ctx, mocks.UserId)
+		channel, err := svc.SubscribeMarketEvents(ctx, "MATIC-USDC")
 
 		assert.Nil(t, channel)
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
 	})
 }
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/shopspring/decimal"
+)
+
+var bps = decimal.NewFromInt(10000)
+
+// price levels of each side read for the analytics of a quote, the remaining depth is of these levels only
+const quoteDepthLevels = 100
+
+// quoteStats collects the orders filled by a quote while the book is walked
+type quoteStats struct {
+	// price of each order filled, best first
+	prices []decimal.Decimal
+	// A token
+	sizeA decimal.Decimal
+	sizeB decimal.Decimal
+}
+
+// add an order filled by takerSizeA of the A token
+func (q *quoteStats) add(order *models.Order, takerSizeA decimal.Decimal) {
+	q.prices = append(q.prices, order.Price)
+	q.sizeA = q.sizeA.Add(takerSizeA)
+	q.sizeB = q.sizeB.Add(order.Price.Mul(takerSizeA))
+}
+
+// analytics of the fill against the L2 depth of the book, read once per quote rather than walking the rest of the book
+func (q *quoteStats) analytics(makerSide models.Side, depth models.MarketDepth) models.QuoteAnalytics {
+	side := depth.Asks
+	if makerSide == models.BUY {
+		side = depth.Bids
+	}
+	remainingDepth := decimal.Zero
+	for _, level := range side {
+		if len(level) > 1 {
+			remainingDepth = remainingDepth.Add(level[1])
+		}
+	}
+	remainingDepth = decimal.Max(remainingDepth.Sub(q.sizeA), decimal.Zero)
+
+	midPrice := depthMidPrice(depth)
+	res := models.QuoteAnalytics{MidPrice: midPrice, RemainingDepth: remainingDepth}
+	if len(q.prices) == 0 || !q.sizeA.IsPositive() {
+		return res
+	}
+
+	res.BestPrice = q.prices[0]
+	res.Vwap = q.sizeB.Div(q.sizeA)
+	for i, price := range q.prices {
+		if i == 0 || !price.Equal(q.prices[i-1]) {
+			res.Levels++
+		}
+	}
+
+	if midPrice.IsPositive() {
+		// the taker buys the A token from asks above the mid, and sells it to bids below
+		diff := res.Vwap.Sub(midPrice)
+		if makerSide == models.BUY {
+			diff = diff.Neg()
+		}
+		res.PriceImpactBps = diff.Mul(bps).Div(midPrice).Round(2)
+	}
+	return res
+}
+
+// quoteDepth returns the top price levels of the symbol for the analytics of a quote, empty when they can't be read
+func (s *Service) quoteDepth(ctx context.Context, symbol models.Symbol) models.MarketDepth {
+	depth, err := s.orderBookStore.GetMarketDepth(ctx, symbol, models.DepthQuery{Mode: models.DEPTH_L2, Depth: quoteDepthLevels})
+	if err != nil {
+		logctx.Warn(ctx, "failed to get the depth of the book for the quote analytics", logger.String("symbol", symbol.String()), logger.Error(err))
+		return models.MarketDepth{}
+	}
+	return depth
+}
+
+// depthMidPrice returns the mid of the best bid and ask, zero when a side is empty
+func depthMidPrice(depth models.MarketDepth) decimal.Decimal {
+	if len(depth.Asks) == 0 || len(depth.Bids) == 0 || len(depth.Asks[0]) == 0 || len(depth.Bids[0]) == 0 {
+		return decimal.Zero
+	}
+	return depth.Asks[0][0].Add(depth.Bids[0][0]).Div(decimal.NewFromInt(2))
+}
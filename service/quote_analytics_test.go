@@ -0,0 +1,82 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_GetQuote_Analytics(t *testing.T) {
+	ctx := context.Background()
+	evmClient := &service.EvmClient{}
+
+	newOrder := func(side models.Side, price, size int64) models.Order {
+		return models.Order{Id: uuid.New(), Side: side, Price: decimal.NewFromInt(price), Size: decimal.NewFromInt(size), Signature: models.Signature{AbiFragment: mocks.AbiFragment}}
+	}
+	level := func(price, size int64) []decimal.Decimal {
+		return []decimal.Decimal{decimal.NewFromInt(price), decimal.NewFromInt(size), decimal.NewFromInt(1)}
+	}
+	newStore := func() *mocks.MockOrderBookStore {
+		return &mocks.MockOrderBookStore{
+			AskOrderIter: &mocks.OrderIterMock{Orders: []models.Order{newOrder(models.SELL, 1000, 1), newOrder(models.SELL, 1001, 2), newOrder(models.SELL, 1002, 3)}, Index: -1},
+			BidOrderIter: &mocks.OrderIterMock{Orders: []models.Order{newOrder(models.BUY, 900, 1), newOrder(models.BUY, 800, 2)}, Index: -1},
+			MarketDepth: models.MarketDepth{
+				Asks: [][]decimal.Decimal{level(1000, 1), level(1001, 2), level(1002, 3)},
+				Bids: [][]decimal.Decimal{level(900, 1), level(800, 2)},
+			},
+			MakerBalance: decimal.NewFromInt(1000000),
+		}
+	}
+
+	t.Run("should describe a fill of asks", func(t *testing.T) {
+		svc, _ := service.New(newStore(), evmClient)
+
+		// 1 * 1000 + 1 * 1001
+		res, err := svc.GetQuote(ctx, symbol, models.SELL, decimal.NewFromInt(2001), nil, "0xTOKEN")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1000", res.Analytics.BestPrice.String())
+		assert.Equal(t, "1000.5", res.Analytics.Vwap.String())
+		assert.Equal(t, "950", res.Analytics.MidPrice.String())
+		// (1000.5 - 950) / 950
+		assert.Equal(t, "531.58", res.Analytics.PriceImpactBps.String())
+		assert.Equal(t, 2, res.Analytics.Levels)
+		// 1 left of the second ask and the third ask
+		assert.Equal(t, "4", res.Analytics.RemainingDepth.String())
+	})
+
+	t.Run("should describe a fill of bids", func(t *testing.T) {
+		svc, _ := service.New(newStore(), evmClient)
+
+		res, err := svc.GetQuote(ctx, symbol, models.BUY, decimal.NewFromFloat(1.5), nil, "0xTOKEN")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "900", res.Analytics.BestPrice.String())
+		// (900 + 400) / 1.5
+		assert.True(t, decimal.RequireFromString("866.6667").Equal(res.Analytics.Vwap.Round(4)))
+		// (950 - 866.67) / 950
+		assert.Equal(t, "877.19", res.Analytics.PriceImpactBps.String())
+		assert.Equal(t, 2, res.Analytics.Levels)
+		assert.Equal(t, "1.5", res.Analytics.RemainingDepth.String())
+	})
+
+	t.Run("should have no price impact without a mid", func(t *testing.T) {
+		store := newStore()
+		store.MarketDepth.Bids = [][]decimal.Decimal{}
+		svc, _ := service.New(store, evmClient)
+
+		res, err := svc.GetQuote(ctx, symbol, models.SELL, decimal.NewFromInt(1000), nil, "0xTOKEN")
+
+		assert.NoError(t, err)
+		assert.True(t, res.Analytics.MidPrice.IsZero())
+		assert.True(t, res.Analytics.PriceImpactBps.IsZero())
+		assert.Equal(t, 1, res.Analytics.Levels)
+		assert.Equal(t, "5", res.Analytics.RemainingDepth.String())
+	})
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/shopspring/decimal"
+)
+
+type ReplaceOrdersInput struct {
+	UserId     uuid.UUID
+	UserPubKey string
+	Symbol     models.Symbol
+	// orders to cancel, by orderId or clientOId
+	Cancels []CancelOrderInput
+	// new orders to place, all of Symbol
+	Orders []CreateOrderInput
+}
+
+// ReplaceOrderResult is the outcome of a single cancel or new order of a replace request
+type ReplaceOrderResult struct {
+	// orderId or clientOId as requested for cancels, clientOId for new orders
+	Id    uuid.UUID
+	Order *models.Order
+	Err   error
+}
+
+type ReplaceOrdersRes struct {
+	Cancelled []ReplaceOrderResult
+	Created   []ReplaceOrderResult
+}
+
+// ReplaceOrders cancels and places orders of a single symbol in one tx, so the book is never empty or crossed in between
+// invalid items are skipped and reported in their result, the rest are applied together
+// new orders are checked for cross trade against the book without the cancelled orders, and against each other
+// the request is validated and applied again if an order to cancel changed in between
+func (s *Service) ReplaceOrders(ctx context.Context, input ReplaceOrdersInput) (ReplaceOrdersRes, error) {
+	var res ReplaceOrdersRes
+	err := retryOnVersionConflict(ctx, "ReplaceOrders", func() (err error) {
+		res, err = s.replaceOrders(ctx, input)
+		return err
+	})
+	return res, err
+}
+
+func (s *Service) replaceOrders(ctx context.Context, input ReplaceOrdersInput) (ReplaceOrdersRes, error) {
+	res := ReplaceOrdersRes{
+		Cancelled: make([]ReplaceOrderResult, len(input.Cancels)),
+		Created:   make([]ReplaceOrderResult, len(input.Orders)),
+	}
+
+	// validate cancels
+	cancelIds := map[uuid.UUID]bool{}
+	cancels := []*models.Order{}
+	for i, cancel := range input.Cancels {
+		res.Cancelled[i].Id = cancel.Id
+		order, err := s.validateReplaceCancel(ctx, input, cancel)
+		if err == nil && cancelIds[order.Id] {
+			logctx.Warn(ctx, "order cancelled twice in replace request", logger.String("orderId", order.Id.String()))
+			err = models.ErrOrderCancelled
+		}
+		if err != nil {
+			res.Cancelled[i].Err = err
+			continue
+		}
+		cancelIds[order.Id] = true
+		cancels = append(cancels, order)
+		res.Cancelled[i].Order = order
+	}
+
+	// top of book once the cancels are applied
+	minAsk := s.bestPriceExcluding(ctx, s.orderBookStore.GetMinAsk(ctx, input.Symbol), cancelIds)
+	maxBid := s.bestPriceExcluding(ctx, s.orderBookStore.GetMaxBid(ctx, input.Symbol), cancelIds)
+
+	// validate new orders
+	clientOIds := map[uuid.UUID]bool{}
+	creates := []*models.Order{}
+	for i, newOrder := range input.Orders {
+		res.Created[i].Id = newOrder.ClientOrderID
+		if clientOIds[newOrder.ClientOrderID] {
+			logctx.Warn(ctx, "clientOrderId used twice in replace request", logger.String("clientOrderId", newOrder.ClientOrderID.String()))
+			res.Created[i].Err = models.ErrClashingClientOrderId
+			continue
+		}
+		order, err := s.validateReplaceOrder(ctx, input, newOrder, minAsk, maxBid)
+		if err != nil {
+			res.Created[i].Err = err
+			continue
+		}
+		clientOIds[order.ClientOId] = true
+		creates = append(creates, order)
+		res.Created[i].Order = order
+
+		// later orders of the batch must not cross this one
+		if order.Side == models.SELL && (minAsk == nil || order.Price.LessThan(*minAsk)) {
+			minAsk = &order.Price
+		}
+		if order.Side == models.BUY && (maxBid == nil || order.Price.GreaterThan(*maxBid)) {
+			maxBid = &order.Price
+		}
+	}
+
+	if len(cancels) == 0 && len(creates) == 0 {
+		logctx.Warn(ctx, "no valid items in replace request", logger.String("userId", input.UserId.String()))
+		return res, nil
+	}
+
+	err := s.orderBookStore.PerformTx(ctx, func(txid uint) error {
+		for _, order := range cancels {
+			if err := s.txCancelOrder(ctx, txid, order); err != nil {
+				return err
+			}
+		}
+		for _, order := range creates {
+			if err := s.orderBookStore.TxStoreOpenOrder(ctx, txid, *order); err != nil {
+				return fmt.Errorf("failed storing new order: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logctx.Error(ctx, "failed to replace orders", logger.String("userId", input.UserId.String()), logger.Error(err))
+		return ReplaceOrdersRes{}, err
+	}
+
+	logctx.Info(ctx, "orders replaced", logger.String("userId", input.UserId.String()), logger.String("symbol", input.Symbol.String()), logger.Int("cancelled", len(cancels)), logger.Int("created", len(creates)))
+
+	for _, order := range cancels {
+		s.publishOrderEvent(ctx, order)
+	}
+	for _, order := range creates {
+		s.publishOrderEvent(ctx, order)
+	}
+
+	return res, nil
+}
+
+// validateReplaceCancel finds an order to cancel and makes sure the user can cancel it
+func (s *Service) validateReplaceCancel(ctx context.Context, input ReplaceOrdersInput, cancel CancelOrderInput) (*models.Order, error) {
+	order, err := s.getOrder(ctx, cancel.IsClientOId, cancel.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order == nil {
+		logctx.Warn(ctx, "order not found", logger.String("id", cancel.Id.String()), logger.Bool("isClientOId", cancel.IsClientOId))
+		return nil, models.ErrNotFound
+	}
+
+	if order.UserId != input.UserId {
+		logctx.Warn(ctx, "user not allowed to cancel order", logger.String("orderId", order.Id.String()), logger.String("userId", input.UserId.String()))
+		return nil, models.ErrUnauthorized
+	}
+
+	if order.Symbol != input.Symbol {
+		logctx.Warn(ctx, "order symbol does not match replace symbol", logger.String("orderId", order.Id.String()), logger.String("symbol", order.Symbol.String()), logger.String("replaceSymbol", input.Symbol.String()))
+		return nil, models.ErrInvalidInput
+	}
+
+	if order.Cancelled {
+		logctx.Warn(ctx, "order already cancelled", logger.String("orderId", order.Id.String()))
+		return nil, models.ErrOrderCancelled
+	}
+
+	if order.IsFilled() {
+		logctx.Warn(ctx, "order already filled", logger.String("orderId", order.Id.String()))
+		return nil, models.ErrOrderFilled
+	}
+
+	return order, nil
+}
+
+// validateReplaceOrder validates and builds a new order of a replace request
+func (s *Service) validateReplaceOrder(ctx context.Context, input ReplaceOrdersInput, newOrder CreateOrderInput, minAsk, maxBid *decimal.Decimal) (*models.Order, error) {
+	newOrder.UserId = input.UserId
+	newOrder.UserPubKey = input.UserPubKey
+
+	if newOrder.Symbol != input.Symbol {
+		logctx.Warn(ctx, "order symbol does not match replace symbol", logger.String("clientOrderId", newOrder.ClientOrderID.String()), logger.String("symbol", newOrder.Symbol.String()), logger.String("replaceSymbol", input.Symbol.String()))
+		return nil, models.ErrInvalidInput
+	}
+
+	existingOrder, err := s.findOrderByClientOId(ctx, newOrder.ClientOrderID)
+	if err != nil && err != models.ErrNotFound {
+		logctx.Error(ctx, "unexpected error when finding order by clientOrderId", logger.Error(err))
+		return nil, err
+	}
+	if existingOrder != nil {
+		logctx.Warn(ctx, "order already exists with same clientOrderId", logger.String("clientOrderId", newOrder.ClientOrderID.String()))
+		return nil, models.ErrClashingClientOrderId
+	}
+
+	// validate price
+	if newOrder.Price.IsZero() || newOrder.Price.IsNegative() {
+		logctx.Warn(ctx, "price has to be positive", logger.String("clientOrderId", newOrder.ClientOrderID.String()), logger.String("price", newOrder.Price.String()))
+		return nil, models.ErrInvalidInput
+	}
+
+	// validate cross trade
+	if newOrder.Side == models.BUY && minAsk != nil && newOrder.Price.GreaterThanOrEqual(*minAsk) {
+		logctx.Warn(ctx, "CrossTrade order rejected. bid price is higher than minAsk", logger.String("clientOrderId", newOrder.ClientOrderID.String()), logger.String("price", newOrder.Price.String()), logger.String("min_ask", minAsk.String()))
+		return nil, models.ErrCrossTrade
+	}
+	if newOrder.Side == models.SELL && maxBid != nil && newOrder.Price.LessThanOrEqual(*maxBid) {
+		logctx.Warn(ctx, "CrossTrade order rejected. ask price is lower than maxBid", logger.String("clientOrderId", newOrder.ClientOrderID.String()), logger.String("price", newOrder.Price.String()), logger.String("maxBid", maxBid.String()))
+		return nil, models.ErrCrossTrade
+	}
+
+	order, err := s.buildOrder(ctx, uuid.New(), newOrder, input.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// bestPriceExcluding returns the price of the first order of the iterator which is not excluded, nil if there is none
+func (s *Service) bestPriceExcluding(ctx context.Context, it models.OrderIter, exclude map[uuid.UUID]bool) *decimal.Decimal {
+	if it == nil {
+		return nil
+	}
+	for it.HasNext() {
+		order := it.Next(ctx)
+		if order == nil || exclude[order.Id] {
+			continue
+		}
+		return &order.Price
+	}
+	return nil
+}
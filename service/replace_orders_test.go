@@ -0,0 +1,111 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_ReplaceOrders(t *testing.T) {
+	ctx := context.Background()
+	mockBcClient := &mocks.MockBcClient{IsVerified: true}
+
+	symbol, _ := models.StrToSymbol("MATIC-USDC")
+	userId := uuid.MustParse("a577273e-12de-4acc-a4f8-de7fb5b86e37")
+
+	newOrder := func(side models.Side, price float64) service.CreateOrderInput {
+		return service.CreateOrderInput{
+			Price:         decimal.NewFromFloat(price),
+			Symbol:        symbol,
+			Size:          decimal.NewFromFloat(100),
+			Side:          side,
+			ClientOrderID: uuid.New(),
+			Eip712Sig:     "mock-sig",
+			AbiFragment:   mocks.AbiFragment,
+		}
+	}
+
+	t.Run("new orders must not cross the book or each other", func(t *testing.T) {
+		ask := models.Order{Id: uuid.New(), Price: decimal.NewFromFloat(10), Side: models.SELL}
+		svc, _ := service.New(&mocks.MockOrderBookStore{AskOrderIter: &mocks.OrderIterMock{Orders: []models.Order{ask}, Index: -1}}, mockBcClient)
+
+		dup := newOrder(models.BUY, 8)
+		res, err := svc.ReplaceOrders(ctx, service.ReplaceOrdersInput{
+			UserId: userId,
+			Symbol: symbol,
+			Cancels: []service.CancelOrderInput{
+				{Id: uuid.New(), UserId: userId},
+			},
+			Orders: []service.CreateOrderInput{
+				newOrder(models.BUY, 10),
+				newOrder(models.BUY, 9),
+				newOrder(models.SELL, 9),
+				dup,
+				dup,
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, res.Cancelled, 1)
+		assert.Equal(t, models.ErrNotFound, res.Cancelled[0].Err)
+
+		assert.Len(t, res.Created, 5)
+		assert.Equal(t, models.ErrCrossTrade, res.Created[0].Err)
+		assert.NoError(t, res.Created[1].Err)
+		assert.Equal(t, userId, res.Created[1].Order.UserId)
+		assert.Equal(t, models.ErrCrossTrade, res.Created[2].Err)
+		assert.NoError(t, res.Created[3].Err)
+		assert.Equal(t, models.ErrClashingClientOrderId, res.Created[4].Err)
+	})
+
+	t.Run("order cancelled twice - returns `ErrOrderCancelled` for the second", func(t *testing.T) {
+		ask := models.Order{Id: uuid.New(), UserId: userId, Symbol: symbol, Price: decimal.NewFromFloat(10), Side: models.SELL, Size: decimal.NewFromFloat(100)}
+		svc, _ := service.New(&mocks.MockOrderBookStore{Order: &ask}, mockBcClient)
+
+		res, err := svc.ReplaceOrders(ctx, service.ReplaceOrdersInput{
+			UserId:  userId,
+			Symbol:  symbol,
+			Cancels: []service.CancelOrderInput{{Id: ask.Id, UserId: userId}, {Id: ask.Id, UserId: userId}},
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, res.Cancelled[0].Err)
+		assert.Equal(t, ask.Id, res.Cancelled[0].Order.Id)
+		assert.Equal(t, models.ErrOrderCancelled, res.Cancelled[1].Err)
+	})
+
+	t.Run("cancel of other user's order - returns `ErrUnauthorized`", func(t *testing.T) {
+		order := models.Order{Id: uuid.New(), UserId: uuid.New(), Symbol: symbol, Size: decimal.NewFromFloat(100)}
+		svc, _ := service.New(&mocks.MockOrderBookStore{Order: &order}, mockBcClient)
+
+		res, err := svc.ReplaceOrders(ctx, service.ReplaceOrdersInput{
+			UserId:  userId,
+			Symbol:  symbol,
+			Cancels: []service.CancelOrderInput{{Id: order.Id, UserId: userId}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.ErrUnauthorized, res.Cancelled[0].Err)
+	})
+
+	t.Run("store error - reported per item", func(t *testing.T) {
+		svc, _ := service.New(&mocks.MockOrderBookStore{Error: assert.AnError}, mockBcClient)
+
+		res, err := svc.ReplaceOrders(ctx, service.ReplaceOrdersInput{
+			UserId:  userId,
+			Symbol:  symbol,
+			Cancels: []service.CancelOrderInput{{Id: uuid.New(), UserId: userId}},
+			Orders:  []service.CreateOrderInput{newOrder(models.BUY, 9)},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, assert.AnError, res.Cancelled[0].Err)
+		assert.Equal(t, assert.AnError, res.Created[0].Err)
+	})
+}
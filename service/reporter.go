@@ -7,7 +7,7 @@ import (
 	"time"
 
 	"github.com/orbs-network/order-book/models"
-	"github.com/orbs-network/order-book/transport/restutils"
+	"github.com/orbs-network/order-book/utils"
 	"github.com/orbs-network/order-book/utils/logger"
 	"github.com/orbs-network/order-book/utils/logger/logctx"
 )
@@ -21,7 +21,7 @@ type Reporter struct {
 }
 
 func NewReporter(svc *Service) *Reporter {
-	strSec := restutils.GetEnv("REPORT_SEC_INTERVAL", "10")
+	strSec := utils.GetEnv("REPORT_SEC_INTERVAL", "10")
 	num, err := strconv.ParseUint(strSec, 10, 64)
 	if err != nil {
 		fmt.Println("Error:", err)
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/shopspring/decimal"
+)
+
+// GetRouteQuote quotes each route leg by leg, the out amount of a leg is the in amount of the next one, and returns the route with the best out amount
+// the fragments of all the legs make a single swap, and the taker fee is taken once from the out amount of the last leg
+func (s *Service) GetRouteQuote(ctx context.Context, routes [][]models.RouteLeg, inAmount decimal.Decimal, minOutAmount *decimal.Decimal) (models.QuoteRes, error) {
+	if !inAmount.IsPositive() {
+		return models.QuoteRes{}, models.ErrInAmount
+	}
+	if len(routes) == 0 {
+		return models.QuoteRes{}, models.ErrInsufficientLiquity
+	}
+
+	var best *models.QuoteRes
+	var lastErr error
+	for _, route := range routes {
+		res, err := s.quoteRoute(ctx, route, inAmount)
+		if err != nil {
+			logctx.Debug(ctx, "route can't be quoted", logger.String("route", routeString(route)), logger.Error(err))
+			lastErr = err
+			continue
+		}
+		if best == nil || res.Size.GreaterThan(best.Size) {
+			best = &res
+		}
+	}
+	if best == nil {
+		return models.QuoteRes{}, lastErr
+	}
+
+	if minOutAmount != nil && minOutAmount.GreaterThan(best.Size) {
+		logctx.Info(ctx, "minOutAmount was applied", logger.String("route", routeString(best.Legs)), logger.String("minOutAmount", minOutAmount.String()), logger.String("amountOut", best.Size.String()))
+		return models.QuoteRes{}, models.ErrMinOutAmount
+	}
+
+	logctx.Info(ctx, "GetRouteQuote Finished OK", logger.String("route", routeString(best.Legs)), logger.String("inAmount", inAmount.String()), logger.String("outAmount", best.Size.String()))
+	return *best, nil
+}
+
+func (s *Service) quoteRoute(ctx context.Context, route []models.RouteLeg, inAmount decimal.Decimal) (models.QuoteRes, error) {
+	res := models.QuoteRes{InAmount: inAmount, Legs: route}
+
+	// the legs are quoted before the fee, the next leg gets all of the out amount of the orders
+	amount := inAmount
+	for _, leg := range route {
+		legRes, err := s.getGrossQuote(ctx, leg.Symbol, leg.MakerSide, amount, leg.MakerInToken)
+		if err != nil {
+			return models.QuoteRes{}, err
+		}
+		res.OrderFrags = append(res.OrderFrags, legRes.OrderFrags...)
+		amount = legRes.Size
+	}
+	res.Fee = s.fees.TakerFee(route[len(route)-1].Symbol, amount)
+	res.Size = amount.Sub(res.Fee)
+	return res, nil
+}
+
+func routeString(route []models.RouteLeg) string {
+	str := ""
+	for i, leg := range route {
+		if i > 0 {
+			str += ">"
+		}
+		str += leg.Symbol.String()
+	}
+	return str
+}
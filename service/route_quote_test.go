@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_GetRouteQuote(t *testing.T) {
+	ctx := context.Background()
+	evmClient := &service.EvmClient{}
+
+	newOrder := func(symbol models.Symbol, side models.Side, price, size int64) models.Order {
+		return models.Order{Id: uuid.New(), Symbol: symbol, Side: side, Price: decimal.NewFromInt(price), Size: decimal.NewFromInt(size), Signature: models.Signature{AbiFragment: mocks.AbiFragment}}
+	}
+	// the mock store returns the same book for every symbol, so each leg takes a different side
+	// MATIC to ETH on the MATIC-ETH bids, then ETH to BTC on the BTC-ETH asks
+	route := []models.RouteLeg{{Symbol: "MATIC-ETH", MakerSide: models.BUY}, {Symbol: "BTC-ETH", MakerSide: models.SELL}}
+	newStore := func() *mocks.MockOrderBookStore {
+		return &mocks.MockOrderBookStore{
+			BidOrderIter: &mocks.OrderIterMock{Orders: []models.Order{newOrder("MATIC-ETH", models.BUY, 900, 1), newOrder("MATIC-ETH", models.BUY, 800, 2)}, Index: -1},
+			AskOrderIter: &mocks.OrderIterMock{Orders: []models.Order{newOrder("BTC-ETH", models.SELL, 1000, 1), newOrder("BTC-ETH", models.SELL, 1001, 1)}, Index: -1},
+			MakerBalance: decimal.NewFromInt(1000000),
+		}
+	}
+
+	t.Run("should chain the out amount of each leg to the next", func(t *testing.T) {
+		svc, _ := service.New(newStore(), evmClient)
+
+		res, err := svc.GetRouteQuote(ctx, [][]models.RouteLeg{route}, decimal.NewFromInt(1), nil)
+
+		assert.NoError(t, err)
+		// 1 MATIC -> 900 ETH -> 0.9 BTC
+		assert.Equal(t, "0.9", res.Size.String())
+		assert.Equal(t, "1", res.InAmount.String())
+		assert.Equal(t, route, res.Legs)
+		assert.Len(t, res.OrderFrags, 2)
+		assert.Equal(t, "900", res.OrderFrags[0].OutSize.String())
+		assert.Equal(t, "900", res.OrderFrags[1].InSize.String())
+	})
+
+	t.Run("should fail below minOutAmount", func(t *testing.T) {
+		svc, _ := service.New(newStore(), evmClient)
+		minOutAmount := decimal.NewFromInt(1)
+
+		_, err := svc.GetRouteQuote(ctx, [][]models.RouteLeg{route}, decimal.NewFromInt(1), &minOutAmount)
+		assert.ErrorIs(t, err, models.ErrMinOutAmount)
+	})
+
+	t.Run("should fail when a leg can't be quoted", func(t *testing.T) {
+		svc, _ := service.New(newStore(), evmClient)
+
+		// 3 MATIC -> 2500 ETH, more than the BTC-ETH asks can fill
+		_, err := svc.GetRouteQuote(ctx, [][]models.RouteLeg{route}, decimal.NewFromInt(3), nil)
+		assert.ErrorIs(t, err, models.ErrInsufficientLiquity)
+	})
+
+	t.Run("should charge the taker fee once on a 2-leg route", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "fees.json")
+		assert.NoError(t, os.WriteFile(filePath, []byte(`{"default": {"takerFeeBps": "10", "makerRebateBps": "2"}}`), 0600))
+		t.Setenv("FEE_SCHEDULE_JSON_FILE_PATH", filePath)
+		svc, err := service.New(newStore(), evmClient)
+		assert.NoError(t, err)
+
+		res, err := svc.GetRouteQuote(ctx, [][]models.RouteLeg{route}, decimal.NewFromInt(1), nil)
+
+		assert.NoError(t, err)
+		// the second leg gets all of the 900 ETH of the first, 0.9 BTC less 10 bps
+		assert.Equal(t, "900", res.OrderFrags[1].InSize.String())
+		assert.Equal(t, "0.0009", res.Fee.String())
+		assert.Equal(t, "0.8991", res.Size.String())
+	})
+
+	t.Run("should fail with no routes", func(t *testing.T) {
+		svc, _ := service.New(newStore(), evmClient)
+
+		_, err := svc.GetRouteQuote(ctx, [][]models.RouteLeg{}, decimal.NewFromInt(1), nil)
+		assert.ErrorIs(t, err, models.ErrInsufficientLiquity)
+	})
+}
@@ -5,31 +5,61 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/abi"
 	"github.com/orbs-network/order-book/data/store"
+	"github.com/orbs-network/order-book/data/storearchive"
 	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
 	"github.com/shopspring/decimal"
 )
 
 type OrderBookService interface {
 	CreateOrder(ctx context.Context, input CreateOrderInput) (models.Order, error)
 	CancelOrder(ctx context.Context, input CancelOrderInput) (*uuid.UUID, error)
+	AmendOrder(ctx context.Context, input AmendOrderInput) (models.Order, error)
+	ReplaceOrders(ctx context.Context, input ReplaceOrdersInput) (ReplaceOrdersRes, error)
 	GetOrderById(ctx context.Context, orderId uuid.UUID) (*models.Order, error)
 	GetOrderByClientOId(ctx context.Context, clientOId uuid.UUID) (*models.Order, error)
-	GetMarketDepth(ctx context.Context, symbol models.Symbol, depth int) (models.MarketDepth, error)
+	GetMarketDepth(ctx context.Context, symbol models.Symbol, query models.DepthQuery) (models.MarketDepth, error)
 	CancelOrdersForUser(ctx context.Context, userId uuid.UUID, symbol models.Symbol) (orderIds []uuid.UUID, err error)
 	GetSymbols(ctx context.Context) ([]models.Symbol, error)
 	GetOpenOrders(ctx context.Context, userId uuid.UUID, symbol models.Symbol) (orders []models.Order, totalOrders int, err error)
-	GetSwapFills(ctx context.Context, userId uuid.UUID, symbol models.Symbol, startAt, endAt time.Time) ([]models.Fill, error)
-	// Subscribe to order updates for a specific user
-	SubscribeUserOrders(ctx context.Context, userId uuid.UUID) (chan []byte, error)
+	// Fills history of the user and public trades history, newest first
+	GetFills(ctx context.Context, userId uuid.UUID, filter models.HistoryFilter) (fills []models.Fill, nextCursor string, err error)
+	// fills of the user's orders in a resolved swap
+	GetSwapFills(ctx context.Context, userId uuid.UUID, swapId uuid.UUID) ([]models.Fill, error)
+	GetTrades(ctx context.Context, symbol models.Symbol, filter models.HistoryFilter) (trades []models.Trade, nextCursor string, err error)
+	GetCandles(ctx context.Context, symbol models.Symbol, interval models.CandleInterval, startAt, endAt time.Time) ([]models.Candle, error)
+	// fees of resolved fills summed per token
+	GetFeeTotals(ctx context.Context) ([]models.FeeTotal, error)
+	// Subscribe to order updates for a specific user, from fromSeq or from new events only when 0
+	SubscribeUserOrders(ctx context.Context, userId uuid.UUID, fromSeq int64) (chan []byte, error)
 	UnsubscribeUserOrders(ctx context.Context, userId uuid.UUID, clientChan chan []byte) error
+	// Subscribe to public book changes and trades of a symbol
+	SubscribeMarketEvents(ctx context.Context, symbol models.Symbol) (chan []byte, error)
+	UnsubscribeMarketEvents(ctx context.Context, symbol models.Symbol, clientChan chan []byte) error
+	// Webhooks that get the user's events
+	CreateWebhook(ctx context.Context, userId uuid.UUID, url string) (models.Webhook, error)
+	GetWebhooks(ctx context.Context, userId uuid.UUID) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, userId uuid.UUID, webhookId uuid.UUID) error
+	GetWebhookDeliveries(ctx context.Context, userId uuid.UUID, webhookId uuid.UUID, limit int) ([]models.WebhookDelivery, error)
 
 	// taker api - INSTEAD
 	GetQuote(ctx context.Context, symbol models.Symbol, makerSide models.Side, inAmount decimal.Decimal, minOutAmount *decimal.Decimal, makerInToken string) (models.QuoteRes, error)
+	// quote of the in amount required to get exactly outAmount
+	GetQuoteForOut(ctx context.Context, symbol models.Symbol, makerSide models.Side, outAmount decimal.Decimal, maxInAmount *decimal.Decimal, makerInToken string) (models.QuoteRes, error)
 	BeginSwap(ctx context.Context, data models.QuoteRes) (models.BeginSwapRes, error)
+	// firm quotes lock their fragments until converted to a swap or expired
+	LockQuote(ctx context.Context, quote models.QuoteRes, ttl time.Duration) (models.FirmQuote, error)
+	BeginSwapFromQuote(ctx context.Context, quoteId uuid.UUID, inToken, outToken string) (models.BeginSwapRes, error)
+	// quote through the best of the routes, for tokens with no direct pair
+	GetRouteQuote(ctx context.Context, routes [][]models.RouteLeg, inAmount decimal.Decimal, minOutAmount *decimal.Decimal) (models.QuoteRes, error)
 	SwapStarted(ctx context.Context, swapId uuid.UUID, txHash string) error
 	AbortSwap(ctx context.Context, swapId uuid.UUID) error
 	FillSwap(ctx context.Context, swapId uuid.UUID) error
@@ -44,6 +74,23 @@ type Service struct {
 	orderBookStore   store.OrderBookStore
 	blockchainClient BlockChainService
 	reporter         *Reporter
+	// nil when signature verification is disabled
+	eip712Domain *abi.Eip712Domain
+	// nil when signed amounts verification is disabled
+	supportedTokens      *SupportedTokens
+	signedOrderTolerance decimal.Decimal
+	// orders expiring within this margin are not quoted
+	quoteExpiryMargin time.Duration
+	// TTL of firm quotes requested without one, and the max TTL requested
+	firmQuoteTtl    time.Duration
+	firmQuoteMaxTtl time.Duration
+	// nil when no fees are charged
+	fees *models.FeeSchedule
+	// nil when records are not archived
+	archive storearchive.ArchiveStore
+	// cancels the stream of each user orders subscription
+	userSubs   map[chan []byte]context.CancelFunc
+	userSubsMu sync.Mutex
 }
 
 // New creates a new Service with injected dependencies.
@@ -56,8 +103,39 @@ func New(store store.OrderBookStore, bcClient BlockChainService) (*Service, erro
 		return nil, errors.New("bcClient cannot be nil")
 	}
 
+	eip712Domain, err := loadEip712Domain()
+	if err != nil {
+		return nil, err
+	}
+	quoteExpiryMargin, err := loadQuoteExpiryMargin()
+	if err != nil {
+		return nil, err
+	}
+	firmQuoteTtl, firmQuoteMaxTtl, err := loadFirmQuoteTtl()
+	if err != nil {
+		return nil, err
+	}
+	signedOrderTolerance, err := loadSignedOrderTolerance()
+	if err != nil {
+		return nil, err
+	}
+
 	// start report routine
-	svc := Service{orderBookStore: store, blockchainClient: bcClient}
+	svc := Service{orderBookStore: store, blockchainClient: bcClient, eip712Domain: eip712Domain, signedOrderTolerance: signedOrderTolerance, quoteExpiryMargin: quoteExpiryMargin, firmQuoteTtl: firmQuoteTtl, firmQuoteMaxTtl: firmQuoteMaxTtl}
+
+	// load supported tokens to verify signed amounts, they are required unless signature verification is skipped
+	st, err := NewSupportedTokensFromEnv(context.Background())
+	if err != nil && eip712Domain != nil {
+		return nil, fmt.Errorf("supported tokens are required to verify signed order amounts: %w", err)
+	}
+	if err != nil {
+		logctx.Warn(context.Background(), "supported tokens not loaded, signed order amounts will not be verified", logger.Error(err))
+	} else {
+		svc.supportedTokens = st
+	}
+	if svc.fees, err = NewFeeScheduleFromEnv(context.Background()); err != nil {
+		return nil, err
+	}
 	svc.reporter = NewReporter(&svc)
 	svc.reporter.Start()
 
@@ -66,3 +144,8 @@ func New(store store.OrderBookStore, bcClient BlockChainService) (*Service, erro
 
 	return &svc, nil
 }
+
+// SetArchive makes the lookups of orders, swaps and fills fall back to the archive when they are no longer in the store
+func (s *Service) SetArchive(archive storearchive.ArchiveStore) {
+	s.archive = archive
+}
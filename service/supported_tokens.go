@@ -68,6 +68,17 @@ func NewSupportedTokens(ctx context.Context, filePath string) (*SupportedTokens,
 		Adrs2Token: adrs2Token,
 	}, nil
 }
+
+// NewSupportedTokensFromEnv loads the supported tokens file set by SUPPORTED_TOKENS_JSON_FILE_PATH
+func NewSupportedTokensFromEnv(ctx context.Context) (*SupportedTokens, error) {
+	supportedTokensPath := os.Getenv("SUPPORTED_TOKENS_JSON_FILE_PATH")
+	if supportedTokensPath == "" {
+		logctx.Warn(ctx, "SUPPORTED_TOKENS_JSON_FILE_PATH env var not set, using default")
+		supportedTokensPath = "supportedTokens.json"
+	}
+	return NewSupportedTokens(ctx, supportedTokensPath)
+}
+
 func loadSupportedTokens(ctx context.Context, filePath string) (TokenMap, error) {
 	file, err := os.ReadFile(filePath)
 
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/data/store"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// findSwapOrders returns the orders of the swap's fragments, nil if they can't be found
+// must be called before the orders are unlocked, as cancelled orders are removed once unlocked
+func findSwapOrders(ctx context.Context, store store.OrderBookStore, swap models.Swap) []models.Order {
+	orderIds := make([]uuid.UUID, 0, len(swap.Frags))
+	for _, frag := range swap.Frags {
+		orderIds = append(orderIds, frag.OrderId)
+	}
+
+	orders, err := store.FindOrdersByIds(ctx, orderIds, false)
+	if err != nil {
+		logctx.Warn(ctx, "failed to find swap orders, swap event not published", logger.String("swapId", swap.Id.String()), logger.Error(err))
+		return nil
+	}
+	return orders
+}
+
+// publishSwapEvent sends the event to each maker with orders in the swap, with the fragments of their own orders
+// unless set, the symbol is of the maker's first order, as a routed swap has orders of several symbols
+func publishSwapEvent(ctx context.Context, store store.OrderBookStore, event models.SwapEvent, frags []models.OrderFrag, orders []models.Order) {
+	ordersById := make(map[uuid.UUID]*models.Order, len(orders))
+	for i := range orders {
+		ordersById[orders[i].Id] = &orders[i]
+	}
+
+	// keep the makers in frags order
+	userIds := []uuid.UUID{}
+	userFrags := make(map[uuid.UUID][]models.SwapEventFrag)
+	userSymbols := make(map[uuid.UUID]models.Symbol)
+	for _, frag := range frags {
+		order, ok := ordersById[frag.OrderId]
+		if !ok {
+			logctx.Warn(ctx, "swap order not found for swap event", logger.String("swapId", event.SwapId.String()), logger.String("orderId", frag.OrderId.String()))
+			continue
+		}
+		if _, exists := userFrags[order.UserId]; !exists {
+			userIds = append(userIds, order.UserId)
+			userSymbols[order.UserId] = order.Symbol
+		}
+		userFrags[order.UserId] = append(userFrags[order.UserId], models.NewSwapEventFrag(frag, order))
+	}
+
+	event.Timestamp = time.Now()
+	symbol := event.Symbol
+	for _, userId := range userIds {
+		event.Frags = userFrags[userId]
+		event.Symbol = symbol
+		if event.Symbol == "" {
+			event.Symbol = userSymbols[userId]
+		}
+		value, err := json.Marshal(event)
+		if err != nil {
+			logctx.Error(ctx, "failed to marshal swap event to json", logger.Error(err))
+			return
+		}
+		appendUserEvent(ctx, store, userId, value)
+	}
+}
+
+func (s *Service) publishSwapEvent(ctx context.Context, event models.SwapEvent, frags []models.OrderFrag, orders []models.Order) {
+	publishSwapEvent(ctx, s.orderBookStore, event, frags, orders)
+}
+
+func (e *EvmClient) publishSwapEvent(ctx context.Context, event models.SwapEvent, frags []models.OrderFrag, orders []models.Order) {
+	publishSwapEvent(ctx, e.orderBookStore, event, frags, orders)
+}
@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_SwapEvents(t *testing.T) {
+	ctx := context.Background()
+	swapId := uuid.New()
+	maker1, maker2 := uuid.New(), uuid.New()
+
+	newOrder := func(userId uuid.UUID) models.Order {
+		return models.Order{Id: uuid.New(), ClientOId: uuid.New(), UserId: userId, Symbol: "MATIC-USDC", Side: models.SELL, Price: decimal.NewFromInt(2), Size: decimal.NewFromInt(100), SizePending: decimal.NewFromInt(10)}
+	}
+	orders := []models.Order{newOrder(maker1), newOrder(maker2), newOrder(maker1)}
+	frags := []models.OrderFrag{}
+	for _, order := range orders {
+		frags = append(frags, models.OrderFrag{OrderId: order.Id, OutSize: decimal.NewFromInt(10), InSize: decimal.NewFromInt(20)})
+	}
+
+	newStore := func() *mocks.MockOrderBookStore {
+		return &mocks.MockOrderBookStore{Order: &orders[0], Orders: orders, Frags: frags}
+	}
+
+	// decodes the swap events of the store, one per maker
+	swapEvents := func(t *testing.T, store *mocks.MockOrderBookStore) []models.SwapEvent {
+		events := []models.SwapEvent{}
+		for _, userEvent := range store.UserEvents {
+			var event models.SwapEvent
+			assert.NoError(t, json.Unmarshal(userEvent.Data, &event))
+			if event.SwapId == swapId {
+				events = append(events, event)
+			}
+		}
+		return events
+	}
+
+	t.Run("should send swap-started with tx hash to each maker with their own frags", func(t *testing.T) {
+		store := newStore()
+		svc, _ := service.New(store, &service.EvmClient{})
+
+		assert.NoError(t, svc.SwapStarted(ctx, swapId, "0xabc"))
+
+		events := swapEvents(t, store)
+		assert.Len(t, events, 2)
+		assert.Equal(t, models.USER_EVENT_SWAP_STARTED, events[0].Event)
+		assert.Equal(t, "0xabc", events[0].TxHash)
+		assert.Equal(t, models.Symbol("MATIC-USDC"), events[0].Symbol)
+		assert.Equal(t, []uuid.UUID{orders[0].Id, orders[2].Id}, []uuid.UUID{events[0].Frags[0].OrderId, events[0].Frags[1].OrderId})
+		assert.Equal(t, orders[0].ClientOId, events[0].Frags[0].ClientOId)
+		assert.Len(t, events[1].Frags, 1)
+		assert.Equal(t, orders[1].Id, events[1].Frags[0].OrderId)
+	})
+
+	t.Run("should send swap-aborted with reason", func(t *testing.T) {
+		store := newStore()
+		svc, _ := service.New(store, &service.EvmClient{})
+
+		assert.NoError(t, svc.AbortSwap(ctx, swapId))
+
+		events := swapEvents(t, store)
+		assert.Len(t, events, 2)
+		assert.Equal(t, models.USER_EVENT_SWAP_ABORTED, events[0].Event)
+		assert.Equal(t, models.SWAP_ABORT_REASON_TAKER, events[0].Reason)
+	})
+
+	t.Run("should not send events when the swap orders are not found", func(t *testing.T) {
+		store := newStore()
+		store.Orders = nil
+		svc, _ := service.New(store, &service.EvmClient{})
+
+		assert.NoError(t, svc.SwapStarted(ctx, swapId, "0xabc"))
+		assert.Empty(t, swapEvents(t, store))
+	})
+}
@@ -8,21 +8,9 @@ import (
 	"github.com/orbs-network/order-book/models"
 	"github.com/orbs-network/order-book/utils/logger"
 	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/shopspring/decimal"
 )
 
-func validateOrderFrag(frag models.OrderFrag, order *models.Order) bool {
-
-	// check if order is still open
-	// TODO: we no longer need this check as we are not storing open orders?
-	if order.IsFilled() {
-		return false
-	}
-	// order.size - (Order.filled + Order.pending) >= frag.size
-	// always in A token
-	size := order.FragAtokenSize(frag)
-	return order.GetAvailableSize().GreaterThanOrEqual(size)
-}
-
 func validatePendingFrag(frag models.OrderFrag, order *models.Order) bool {
 	// check if order is still open
 	if order.IsFilled() {
@@ -40,68 +28,50 @@ func (s *Service) BeginSwap(ctx context.Context, data models.QuoteRes) (models.B
 	swapId := uuid.New()
 	// no re-entry is needed
 
-	res := models.BeginSwapRes{
-		OutAmount: data.Size,
-		SwapId:    swapId,
+	orders, err := s.lockOrderFrags(ctx, data.OrderFrags)
+	if err != nil {
+		return models.BeginSwapRes{}, err
 	}
 
-	// validate all orders of a swap
-	for _, frag := range data.OrderFrags {
-		// get order by ID
-		order, err := s.orderBookStore.FindOrderById(ctx, frag.OrderId, false)
-		if err != nil {
-			logctx.Warn(ctx, err.Error())
-			return models.BeginSwapRes{}, models.ErrNotFound
-		} else if !validateOrderFrag(frag, order) {
-			// cancel swap
-			_ = s.orderBookStore.RemoveSwap(ctx, swapId)
-
-			// return empty
-			logctx.Warn(ctx, "failed to validate order frag")
-			return models.BeginSwapRes{}, models.ErrSwapInvalid
-		} else {
-			// success- append
-			res.Orders = append(res.Orders, *order)
-			res.Fragments = append(res.Fragments, frag)
-		}
+	return s.storeLockedSwap(ctx, swapId, data.Size, orders, data.OrderFrags)
+}
+
+// lockOrderFrags locks all fragments as pending on their orders in the store, which validates them in the same tx
+// concurrent swaps can't lock the same available size, the losing one fails with ErrSwapInvalid
+func (s *Service) lockOrderFrags(ctx context.Context, frags []models.OrderFrag) ([]models.Order, error) {
+	orders, err := s.orderBookStore.LockOrderFrags(ctx, frags)
+	if err != nil {
+		logctx.Warn(ctx, "BeginSwap failed to lock order frags", logger.Error(err))
+		return nil, err
 	}
-	// lock liquidity - Only after fragments were validated
-	// set order fragments as Pending
-	for i := 0; i < len(res.Orders); i++ {
-		// lock frag.Amount as pending per order - no STATUS_PENDING is needed
-		logctx.Debug(ctx, "Lock Fragment", logger.String("orderID", res.Orders[i].Id.String()), logger.String("OutSize", res.Fragments[i].OutSize.String()))
 
-		err := res.Orders[i].Lock(ctx, res.Fragments[i])
-		if err != nil {
-			logctx.Error(ctx, "Lock order Failed", logger.Error(err))
-			return models.BeginSwapRes{}, err
-		}
-		s.publishOrderEvent(ctx, &res.Orders[i])
+	for i := range orders {
+		logctx.Debug(ctx, "Locked Fragment", logger.String("orderID", orders[i].Id.String()), logger.String("OutSize", frags[i].OutSize.String()))
+		s.publishOrderEvent(ctx, &orders[i])
 	}
+	return orders, nil
+}
 
-	// update db
-	err := s.orderBookStore.PerformTx(ctx, func(txid uint) error {
-		for _, order := range res.Orders {
-			// update db
-			if err := s.orderBookStore.TxModifyOrder(ctx, txid, models.Update, order); err != nil {
-				logctx.Error(ctx, "BeginSwap Failed updating locked order", logger.Error(err), logger.String("orderId", order.Id.String()))
-				return err
-			}
-		}
-		return nil
-	})
-	if err != nil {
-		logctx.Error(ctx, "BeginSwap Failed store:PerformTX", logger.Error(err))
+// storeLockedSwap stores a swap of fragments already locked on their orders
+func (s *Service) storeLockedSwap(ctx context.Context, swapId uuid.UUID, outAmount decimal.Decimal, orders []models.Order, frags []models.OrderFrag) (models.BeginSwapRes, error) {
+	res := models.BeginSwapRes{
+		OutAmount: outAmount,
+		SwapId:    swapId,
+		Orders:    orders,
+		Fragments: frags,
 	}
 
-	// resolve symbol and side from orders[0]
-	err = s.orderBookStore.StoreSwap(ctx, swapId, res.Orders[0].Symbol, res.Orders[0].Side, res.Fragments)
+	// a routed swap has orders of each of its legs
+	legs := models.RouteLegsOf(res.Orders)
+	err := s.orderBookStore.StoreSwap(ctx, swapId, legs, res.Fragments)
 	if err != nil {
 		logctx.Error(ctx, "StoreSwap Failed", logger.Error(err))
 		return models.BeginSwapRes{}, err
 	}
 
-	logctx.Info(ctx, "BeginSwap end ok", logger.String("symbol", res.Orders[0].Symbol.String()), logger.String("side", string(res.Orders[0].Side)))
+	s.publishSwapEvent(ctx, models.SwapEvent{Event: models.USER_EVENT_SWAP_LOCKED, SwapId: swapId}, res.Fragments, res.Orders)
+
+	logctx.Info(ctx, "BeginSwap end ok", logger.String("route", routeString(legs)), logger.String("side", string(legs[0].MakerSide)))
 
 	// add oredebook signature on the buffer HERE if needed
 	return res, nil
@@ -115,14 +85,39 @@ func (s *Service) SwapStarted(ctx context.Context, swapId uuid.UUID, txHash stri
 	})
 	if err != nil {
 		logctx.Error(ctx, "StoreNewPendingSwap failed", logger.Error(err))
+		return err
 	}
 
-	logctx.Info(ctx, "swapStarted", logger.String("symbol", swap.Symbol), logger.String("side", swap.Side), logger.String("swapId", swapId.String()))
-	return err
+	swap.Id = swapId
+	s.publishSwapEvent(ctx, models.SwapEvent{Event: models.USER_EVENT_SWAP_STARTED, SwapId: swapId, TxHash: txHash}, swap.Frags, findSwapOrders(ctx, s.orderBookStore, *swap))
+
+	logctx.Info(ctx, "swapStarted", logger.String("route", routeString(swap.Legs)), logger.String("side", swap.Side), logger.String("swapId", swapId.String()))
+	return nil
 }
 
 // to be reused by resolveSwap
-func unlockSwapAndHandleCancelledOrders(ctx context.Context, svc *Service, store store.OrderBookStore, swap *models.Swap) error {
+// the orders are read and unlocked again if they changed in between
+func unlockSwapAndHandleCancelledOrders(ctx context.Context, svc *Service, store store.OrderBookStore, swap *models.Swap, resolve bool) error {
+	var unlockedOrders []models.Order
+	err := retryOnVersionConflict(ctx, "unlockSwap", func() (err error) {
+		unlockedOrders, err = unlockSwapOrders(ctx, store, swap, resolve)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if svc != nil {
+		for i := range unlockedOrders {
+			svc.publishOrderEvent(ctx, &unlockedOrders[i])
+		}
+	}
+	return nil
+}
+
+// unlockSwapOrders unlocks the swap's fragments and removes cancelled orders left unfilled, and returns the unlocked orders which are not cancelled
+// if resolve is true the swap is moved to the resolved swaps in the same tx
+func unlockSwapOrders(ctx context.Context, store store.OrderBookStore, swap *models.Swap, resolve bool) ([]models.Order, error) {
 	unlockedOrders := []models.Order{}
 	ordersToRemove := []models.Order{}
 	// validate all pending orders fragments of auction
@@ -140,13 +135,10 @@ func unlockSwapAndHandleCancelledOrders(ctx context.Context, svc *Service, store
 			err = order.Unlock(ctx, frag)
 			if err != nil {
 				logctx.Error(ctx, "Unlock Failed", logger.Error(err))
-				return err
+				return nil, err
 			}
 			// no need to publish nor update cancelled order
 			if !order.Cancelled {
-				if svc != nil {
-					svc.publishOrderEvent(ctx, order)
-				}
 				// save to modify/update new pending state in db
 				unlockedOrders = append(unlockedOrders, *order)
 			}
@@ -171,14 +163,27 @@ func unlockSwapAndHandleCancelledOrders(ctx context.Context, svc *Service, store
 				return err
 			}
 		}
+		if resolve {
+			if err := store.TxResolveSwap(ctx, txid, *swap); err != nil {
+				logctx.Error(ctx, "TxResolveSwap Failed", logger.Error(err), logger.String("swapId", swap.Id.String()))
+				return err
+			}
+		}
 		return nil
 	})
-	return err
-
+	if err != nil {
+		return nil, err
+	}
+	return unlockedOrders, nil
 }
 
 func (s *Service) AbortSwap(ctx context.Context, swapId uuid.UUID) error {
-	logctx.Debug(ctx, "AbortSwap", logger.String("swapId", swapId.String()))
+	return s.abortSwap(ctx, swapId, models.SWAP_ABORT_REASON_TAKER)
+}
+
+// abortSwap unlocks the swap's orders and removes it, reason is sent to the makers
+func (s *Service) abortSwap(ctx context.Context, swapId uuid.UUID, reason string) error {
+	logctx.Debug(ctx, "AbortSwap", logger.String("swapId", swapId.String()), logger.String("reason", reason))
 	// get swap from store
 	swap, err := s.orderBookStore.GetSwap(ctx, swapId, true)
 
@@ -187,16 +192,25 @@ func (s *Service) AbortSwap(ctx context.Context, swapId uuid.UUID) error {
 		return err
 	}
 
+	swap.Id = swapId
+	orders := findSwapOrders(ctx, s.orderBookStore, *swap)
+
 	// mutual impl for ABORT and RESOLVE(false) swap
-	err = unlockSwapAndHandleCancelledOrders(ctx, s, s.orderBookStore, swap)
+	err = unlockSwapAndHandleCancelledOrders(ctx, s, s.orderBookStore, swap, false)
 	if err != nil {
 		logctx.Warn(ctx, "unlockSwapAndHandleCancelledOrders Failed", logger.Error(err))
 		return err
 	}
 
-	return s.orderBookStore.RemoveSwap(ctx, swapId)
+	if err := s.orderBookStore.RemoveSwap(ctx, swapId); err != nil {
+		return err
+	}
+
+	s.publishSwapEvent(ctx, models.SwapEvent{Event: models.USER_EVENT_SWAP_ABORTED, SwapId: swapId, Reason: reason}, swap.Frags, orders)
+	return nil
 }
 
+// FillSwap fills the swap's fragments, the orders are read and filled again if they changed in between
 func (s *Service) FillSwap(ctx context.Context, swapId uuid.UUID) error {
 	logctx.Debug(ctx, "FillSwap", logger.String("swapId", swapId.String()))
 
@@ -207,6 +221,30 @@ func (s *Service) FillSwap(ctx context.Context, swapId uuid.UUID) error {
 		return err
 	}
 
+	var fills []models.Fill
+	var fillUserIds []uuid.UUID
+	err = retryOnVersionConflict(ctx, "FillSwap", func() (err error) {
+		fills, fillUserIds, err = s.fillSwapOrders(ctx, swap)
+		return err
+	})
+	if err != nil {
+		logctx.Warn(ctx, "FillSwap Failed", logger.Error(err), logger.String("swapId", swapId.String()))
+		return err
+	}
+
+	// publish fill events
+	for i, fill := range fills {
+		s.publishFillEvent(ctx, fillUserIds[i], fill)
+	}
+
+	return s.orderBookStore.RemoveSwap(ctx, swapId)
+}
+
+// fillSwapOrders moves the swap's fragments from pending to filled, and closes the orders filled, in a single tx
+// returns the fills and the user of each
+func (s *Service) fillSwapOrders(ctx context.Context, swap *models.Swap) ([]models.Fill, []uuid.UUID, error) {
+	fills := []models.Fill{}
+	fillUserIds := []uuid.UUID{}
 	filledOrders := []models.Order{}
 	openOrders := []models.Order{}
 	// validate all pending orders fragments of auction
@@ -224,10 +262,15 @@ func (s *Service) FillSwap(ctx context.Context, swapId uuid.UUID) error {
 			filled, err := order.Fill(ctx, frag)
 			if err != nil {
 				logctx.Error(ctx, "FillOrder Failed", logger.Error(err))
-				return err
+				return nil, nil, err
+			}
+			fill := models.NewFill(order.Symbol, *swap, frag, order)
+			// a routed swap is charged once, on its last leg
+			if swap.IsFinalLeg(order.Symbol, order.Side) {
+				s.fees.ApplyToFill(fill, frag, order.UserId)
 			}
-			// publish fill event
-			s.publishFillEvent(ctx, order.UserId, *models.NewFill(order.Symbol, *swap, frag, order))
+			fills = append(fills, *fill)
+			fillUserIds = append(fillUserIds, order.UserId)
 
 			if filled {
 				filledOrders = append(filledOrders, *order)
@@ -238,7 +281,7 @@ func (s *Service) FillSwap(ctx context.Context, swapId uuid.UUID) error {
 		}
 	}
 	// update db
-	err = s.orderBookStore.PerformTx(ctx, func(txid uint) error {
+	err := s.orderBookStore.PerformTx(ctx, func(txid uint) error {
 		for _, order := range openOrders {
 			// update db
 			if err := s.orderBookStore.TxModifyOrder(ctx, txid, models.Update, order); err != nil {
@@ -246,18 +289,22 @@ func (s *Service) FillSwap(ctx context.Context, swapId uuid.UUID) error {
 				return err
 			}
 		}
+		// store filled orders
+		for _, order := range filledOrders {
+			if err := s.orderBookStore.TxModifyOrder(ctx, txid, models.Update, order); err != nil {
+				logctx.Error(ctx, "FillSwap Failed updating filled order", logger.Error(err), logger.String("orderId", order.Id.String()))
+				return err
+			}
+			if err := s.orderBookStore.TxCloseOrder(ctx, txid, order); err != nil {
+				logctx.Error(ctx, "FillSwap Failed closing filled order", logger.Error(err), logger.String("orderId", order.Id.String()))
+				return err
+			}
+		}
 		return nil
 	})
 	if err != nil {
-		logctx.Error(ctx, "BeginSwap Failed store:PerformTX", logger.Error(err))
+		logctx.Error(ctx, "FillSwap Failed store:PerformTX", logger.Error(err))
+		return nil, nil, err
 	}
-	// store filled orders
-	// NEED TO DEPRECATE THIS AS WELL
-	err = s.orderBookStore.StoreFilledOrders(ctx, filledOrders)
-	if err != nil {
-		logctx.Warn(ctx, "StoreFilledOrders Failed", logger.Error(err))
-		return err
-	}
-
-	return s.orderBookStore.RemoveSwap(ctx, swapId)
+	return fills, fillUserIds, nil
 }
@@ -2,7 +2,9 @@ package service_test
 
 import (
 	"context"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/orbs-network/order-book/mocks"
@@ -136,3 +138,51 @@ func TestTaker_SwapStarted(t *testing.T) {
 // 		}
 // 	})
 // }
+
+func TestService_New_QuoteExpiryMargin(t *testing.T) {
+
+	t.Run("invalid SEC_EXPIRY_MARGIN - should fail", func(t *testing.T) {
+		for _, margin := range []string{"2m", "-1", ""} {
+			t.Setenv("SEC_EXPIRY_MARGIN", margin)
+			_, err := service.New(&mocks.MockOrderBookStore{}, &service.EvmClient{})
+			assert.ErrorContains(t, err, "SEC_EXPIRY_MARGIN", margin)
+		}
+	})
+}
+
+func TestTaker_QuoteSkipsExpiringOrders(t *testing.T) {
+	ctx := context.Background()
+	evmClient := &service.EvmClient{}
+	t.Setenv("SEC_EXPIRY_MARGIN", "120")
+
+	newAsk := func(deadline time.Time) models.Order {
+		abiFragment := mocks.AbiFragment
+		abiFragment.Info.Deadline = big.NewInt(deadline.Unix())
+		return models.Order{
+			Id:        uuid.New(),
+			Price:     decimal.NewFromInt(1000),
+			Size:      decimal.NewFromInt(1),
+			Side:      models.SELL,
+			Signature: models.Signature{AbiFragment: abiFragment},
+		}
+	}
+
+	t.Run("QUOTE should skip orders expiring within the margin", func(t *testing.T) {
+		store := mocks.MockOrderBookStore{
+			AskOrderIter: &mocks.OrderIterMock{Orders: []models.Order{newAsk(time.Now().Add(time.Minute))}, Index: -1},
+		}
+		svc, _ := service.New(&store, evmClient)
+		_, err := svc.GetQuote(ctx, symbol, models.SELL, decimal.NewFromInt(1000), nil, "0xTOKEN")
+		assert.ErrorIs(t, err, models.ErrInsufficientLiquity)
+	})
+
+	t.Run("QUOTE should use orders expiring after the margin", func(t *testing.T) {
+		store := mocks.MockOrderBookStore{
+			AskOrderIter: &mocks.OrderIterMock{Orders: []models.Order{newAsk(time.Now().Add(time.Hour))}, Index: -1},
+		}
+		svc, _ := service.New(&store, evmClient)
+		// mock maker balance is zero, so a quote which uses the order fails on balance
+		_, err := svc.GetQuote(ctx, symbol, models.SELL, decimal.NewFromInt(1000), nil, "0xTOKEN")
+		assert.ErrorIs(t, err, models.ErrInsufficientBalance)
+	})
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// timeInForceExpiry returns the time in force expiry of a new order, zero time for GTC
+// GTT       - ExpiresAt must be in the future and no later than the signed deadline
+// POST_ONLY - cancelled after CancelAfter, capped by the signed deadline. A crossing order is rejected by the cross trade check
+func timeInForceExpiry(ctx context.Context, input CreateOrderInput, order *models.Order) (time.Time, error) {
+	now := order.Timestamp
+	deadline := order.Deadline()
+
+	switch order.TimeInForce {
+	case models.GTC:
+		return time.Time{}, nil
+	case models.GTT:
+		if !input.ExpiresAt.After(now) {
+			logctx.Warn(ctx, "GTT expiry must be in the future", logger.String("clientOrderId", input.ClientOrderID.String()), logger.String("expiresAt", input.ExpiresAt.String()))
+			return time.Time{}, models.ErrInvalidTimeInForce
+		}
+		if !deadline.IsZero() && input.ExpiresAt.After(deadline) {
+			logctx.Warn(ctx, "GTT expiry is later than the signed deadline", logger.String("clientOrderId", input.ClientOrderID.String()), logger.String("expiresAt", input.ExpiresAt.String()), logger.String("deadline", deadline.String()))
+			return time.Time{}, models.ErrInvalidTimeInForce
+		}
+		return input.ExpiresAt.UTC(), nil
+	case models.POST_ONLY:
+		if input.CancelAfter <= 0 {
+			logctx.Warn(ctx, "POST_ONLY cancelAfter must be positive", logger.String("clientOrderId", input.ClientOrderID.String()), logger.String("cancelAfter", input.CancelAfter.String()))
+			return time.Time{}, models.ErrInvalidTimeInForce
+		}
+		expiresAt := now.Add(input.CancelAfter)
+		if !deadline.IsZero() && expiresAt.After(deadline) {
+			expiresAt = deadline
+		}
+		return expiresAt.UTC(), nil
+	default:
+		logctx.Warn(ctx, "unsupported time in force", logger.String("clientOrderId", input.ClientOrderID.String()), logger.String("timeInForce", order.TimeInForce.String()))
+		return time.Time{}, models.ErrInvalidTimeInForce
+	}
+}
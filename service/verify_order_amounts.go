@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/shopspring/decimal"
+)
+
+// loads the max relative diff allowed between the signed amounts and the order's price and size
+func loadSignedOrderTolerance() (decimal.Decimal, error) {
+	strTolerance := utils.GetEnv("SIGNED_ORDER_TOLERANCE", "0.001")
+	tolerance, err := decimal.NewFromString(strTolerance)
+	if err != nil || tolerance.IsNegative() {
+		return decimal.Zero, fmt.Errorf("SIGNED_ORDER_TOLERANCE is not a valid relative tolerance: %q", strTolerance)
+	}
+	return tolerance, nil
+}
+
+// withinTolerance checks that val is within the relative tolerance of expected
+func withinTolerance(val, expected, tolerance decimal.Decimal) bool {
+	return val.Sub(expected).Abs().LessThanOrEqual(expected.Mul(tolerance))
+}
+
+// verifyOrderAmounts makes sure the signed abi order honours the order's symbol, side, size and price
+// maker SELL - input is the A token (size), output is the B token (size*price)
+// maker BUY  - input is the B token (size*price), output is the A token (size)
+func (s *Service) verifyOrderAmounts(ctx context.Context, order *models.Order) error {
+	// only when signature verification is skipped, New fails otherwise
+	if s.supportedTokens == nil {
+		return nil
+	}
+
+	frag := order.Signature.AbiFragment
+	if frag.Input.Amount == nil || len(frag.Outputs) == 0 || frag.Outputs[0].Amount == nil {
+		logctx.Warn(ctx, "signed order has no input or output amounts", logger.String("orderId", order.Id.String()))
+		return models.ErrSignedOrderMismatch
+	}
+
+	aName, bName := order.Symbol.Tokens()
+	aToken := s.supportedTokens.ByName(aName)
+	bToken := s.supportedTokens.ByName(bName)
+	if aToken == nil || bToken == nil {
+		logctx.Error(ctx, "symbol token is not supported", logger.String("symbol", order.Symbol.String()))
+		return models.ErrTokenNotsupported
+	}
+
+	inToken := s.supportedTokens.ByAddress(frag.Input.Token.Hex())
+	outToken := s.supportedTokens.ByAddress(frag.Outputs[0].Token.Hex())
+	if inToken == nil || outToken == nil {
+		logctx.Warn(ctx, "signed order token is not supported", logger.String("orderId", order.Id.String()), logger.String("inToken", frag.Input.Token.Hex()), logger.String("outToken", frag.Outputs[0].Token.Hex()))
+		return models.ErrSignedOrderMismatch
+	}
+
+	// validate token pair and direction
+	makerInToken, makerOutToken := aToken, bToken
+	if order.Side == models.BUY {
+		makerInToken, makerOutToken = bToken, aToken
+	}
+	if inToken != makerInToken || outToken != makerOutToken {
+		logctx.Warn(ctx, "signed order tokens do not match symbol and side", logger.String("orderId", order.Id.String()), logger.String("symbol", order.Symbol.String()), logger.String("side", order.Side.String()), logger.String("inToken", inToken.Name), logger.String("outToken", outToken.Name))
+		return models.ErrSignedOrderMismatch
+	}
+
+	// validate size
+	signedSize := models.BigInt2Dcml(frag.Input.Amount, int64(inToken.Decimals))
+	if order.Side == models.BUY {
+		signedSize = models.BigInt2Dcml(frag.Outputs[0].Amount, int64(outToken.Decimals))
+	}
+	if !withinTolerance(signedSize, order.Size, s.signedOrderTolerance) {
+		logctx.Warn(ctx, "signed order size does not match order size", logger.String("orderId", order.Id.String()), logger.String("size", order.Size.String()), logger.String("signedSize", signedSize.String()))
+		return models.ErrSignedOrderMismatch
+	}
+
+	// validate price
+	if signedSize.IsZero() {
+		logctx.Warn(ctx, "signed order size is zero", logger.String("orderId", order.Id.String()))
+		return models.ErrSignedOrderMismatch
+	}
+	signedPrice, err := order.OnchainPrice(inToken.Decimals, outToken.Decimals)
+	if err != nil {
+		logctx.Warn(ctx, "failed to calc signed order price", logger.String("orderId", order.Id.String()), logger.Error(err))
+		return models.ErrSignedOrderMismatch
+	}
+	if !withinTolerance(signedPrice, order.Price, s.signedOrderTolerance) {
+		logctx.Warn(ctx, "signed order price does not match order price", logger.String("orderId", order.Id.String()), logger.String("price", order.Price.String()), logger.String("signedPrice", signedPrice.String()))
+		return models.ErrSignedOrderMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/orbs-network/order-book/abi"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// loads the RePermit EIP712 domain makers sign orders with
+// returns nil (verification disabled) only when REPERMIT_ADDRESS is not set and SKIP_SIGNATURE_VERIFICATION is "true"
+func loadEip712Domain() (*abi.Eip712Domain, error) {
+	ctx := context.Background()
+	repermit := utils.GetEnv("REPERMIT_ADDRESS", "")
+	if repermit == "" {
+		if utils.GetEnv("SKIP_SIGNATURE_VERIFICATION", "") != "true" {
+			return nil, errors.New("REPERMIT_ADDRESS is not set, set SKIP_SIGNATURE_VERIFICATION=true to run without verifying order signatures")
+		}
+		logctx.Warn(ctx, "SKIP_SIGNATURE_VERIFICATION is set, order signatures will not be verified")
+		return nil, nil
+	}
+	if !common.IsHexAddress(repermit) {
+		logctx.Error(ctx, "REPERMIT_ADDRESS is not a valid address", logger.String("address", repermit))
+		return nil, fmt.Errorf("REPERMIT_ADDRESS is not a valid address: %q", repermit)
+	}
+
+	strChainId := utils.GetEnv("CHAIN_ID", "137")
+	chainId, ok := new(big.Int).SetString(strChainId, 10)
+	if !ok {
+		logctx.Error(ctx, "CHAIN_ID is not a valid number", logger.String("chainId", strChainId))
+		return nil, fmt.Errorf("CHAIN_ID is not a valid number: %q", strChainId)
+	}
+
+	return &abi.Eip712Domain{
+		Name:              utils.GetEnv("REPERMIT_NAME", "RePermit"),
+		Version:           utils.GetEnv("REPERMIT_VERSION", "1"),
+		ChainId:           chainId,
+		VerifyingContract: common.HexToAddress(repermit),
+	}, nil
+}
+
+// pubKeyToAddress converts a user's PubKey to its wallet address
+// supported formats: hex address, hex uncompressed secp256k1 key (64/65 bytes) and base64 DER (SPKI) encoded key
+func pubKeyToAddress(pubKey string) (common.Address, bool) {
+	if common.IsHexAddress(pubKey) {
+		return common.HexToAddress(pubKey), true
+	}
+
+	var raw []byte
+	if b, err := hex.DecodeString(strings.TrimPrefix(pubKey, "0x")); err == nil {
+		raw = b
+	} else if b, err := base64.StdEncoding.DecodeString(pubKey); err == nil {
+		raw = b
+	} else {
+		return common.Address{}, false
+	}
+
+	switch {
+	case len(raw) == 64:
+		raw = append([]byte{0x04}, raw...)
+	case len(raw) > 65:
+		// DER SPKI - the uncompressed point is the key's suffix
+		raw = raw[len(raw)-65:]
+	}
+
+	key, err := crypto.UnmarshalPubkey(raw)
+	if err != nil {
+		return common.Address{}, false
+	}
+	return crypto.PubkeyToAddress(*key), true
+}
+
+// verifyOrderSignature recovers the EIP712 signer of the order and makes sure it is both the swapper and the user
+func (s *Service) verifyOrderSignature(ctx context.Context, order *models.Order, userPubKey string) error {
+	if s.eip712Domain == nil {
+		return nil
+	}
+
+	frag := order.Signature.AbiFragment
+	signer, err := frag.RecoverSigner(*s.eip712Domain, order.Signature.Eip712Sig)
+	if err != nil {
+		logctx.Warn(ctx, "failed to recover order signer", logger.String("userId", order.UserId.String()), logger.String("clientOrderId", order.ClientOId.String()), logger.Error(err))
+		return models.ErrInvalidSignature
+	}
+
+	if signer != frag.Info.Swapper {
+		logctx.Warn(ctx, "order signer is not the swapper", logger.String("userId", order.UserId.String()), logger.String("signer", signer.Hex()), logger.String("swapper", frag.Info.Swapper.Hex()))
+		return models.ErrInvalidSignature
+	}
+
+	userAdrs, ok := pubKeyToAddress(userPubKey)
+	if !ok {
+		logctx.Warn(ctx, "user pubKey could not be converted to an address", logger.String("userId", order.UserId.String()), logger.String("pubKey", userPubKey))
+		return models.ErrInvalidSignature
+	}
+
+	if signer != userAdrs {
+		logctx.Warn(ctx, "order signer does not match user pubKey", logger.String("userId", order.UserId.String()), logger.String("signer", signer.Hex()), logger.String("userAddress", userAdrs.Hex()))
+		return models.ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// times an operation is tried again when its orders were changed since it read them
+const versionConflictRetries = 3
+
+// retryOnVersionConflict runs op again while it fails with ErrVersionConflict, up to versionConflictRetries times
+// op must read the orders it modifies again, and publish nothing before its tx is committed
+// returns ErrVersionConflict itself once the retries are used up
+func retryOnVersionConflict(ctx context.Context, name string, op func() error) error {
+	err := op()
+	for attempt := 1; attempt <= versionConflictRetries && errors.Is(err, models.ErrVersionConflict); attempt++ {
+		logctx.Warn(ctx, "orders changed since they were read, retrying", logger.String("op", name), logger.Int("attempt", attempt))
+		err = op()
+	}
+	if errors.Is(err, models.ErrVersionConflict) {
+		return models.ErrVersionConflict
+	}
+	return err
+}
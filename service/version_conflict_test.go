@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_VersionConflict(t *testing.T) {
+	ctx := context.Background()
+
+	userId := uuid.New()
+	newOrder := func() *models.Order {
+		return &models.Order{Id: uuid.New(), UserId: userId, Symbol: "MATIC-USDC", Side: models.SELL, Price: decimal.NewFromInt(2), Size: decimal.NewFromInt(100), Version: 4}
+	}
+	size := decimal.NewFromInt(50)
+
+	t.Run("should cancel again once the order changed", func(t *testing.T) {
+		order := newOrder()
+		store := &mocks.MockOrderBookStore{Order: order, VersionConflicts: 2}
+		svc, _ := service.New(store, &service.EvmClient{})
+
+		orderId, err := svc.CancelOrder(ctx, service.CancelOrderInput{Id: order.Id, UserId: userId})
+
+		assert.NoError(t, err)
+		assert.Equal(t, order.Id, *orderId)
+		assert.Equal(t, 3, store.Txs)
+	})
+
+	t.Run("should fail once the order keeps changing", func(t *testing.T) {
+		order := newOrder()
+		store := &mocks.MockOrderBookStore{Order: order, VersionConflicts: 10}
+		svc, _ := service.New(store, &service.EvmClient{})
+
+		_, err := svc.CancelOrder(ctx, service.CancelOrderInput{Id: order.Id, UserId: userId})
+
+		assert.ErrorIs(t, err, models.ErrVersionConflict)
+		assert.Equal(t, 4, store.Txs)
+	})
+
+	t.Run("should amend and return the next version", func(t *testing.T) {
+		order := newOrder()
+		store := &mocks.MockOrderBookStore{Order: order, VersionConflicts: 1}
+		svc, _ := service.New(store, &service.EvmClient{})
+
+		amended, err := svc.AmendOrder(ctx, service.AmendOrderInput{Id: order.Id, UserId: userId, Size: &size})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), amended.Version)
+		assert.Equal(t, 2, store.Txs)
+	})
+
+	t.Run("should amend the given version", func(t *testing.T) {
+		order := newOrder()
+		svc, _ := service.New(&mocks.MockOrderBookStore{Order: order}, &service.EvmClient{})
+		version := int64(4)
+
+		amended, err := svc.AmendOrder(ctx, service.AmendOrderInput{Id: order.Id, UserId: userId, Size: &size, Version: &version})
+
+		assert.NoError(t, err)
+		assert.True(t, amended.Size.Equal(size))
+	})
+
+	t.Run("should not amend another version", func(t *testing.T) {
+		order := newOrder()
+		store := &mocks.MockOrderBookStore{Order: order}
+		svc, _ := service.New(store, &service.EvmClient{})
+		version := int64(3)
+
+		_, err := svc.AmendOrder(ctx, service.AmendOrderInput{Id: order.Id, UserId: userId, Size: &size, Version: &version})
+
+		assert.ErrorIs(t, err, models.ErrVersionConflict)
+		assert.Equal(t, 0, store.Txs)
+	})
+
+	t.Run("should not retry an amend of a given version", func(t *testing.T) {
+		order := newOrder()
+		store := &mocks.MockOrderBookStore{Order: order, VersionConflicts: 1}
+		svc, _ := service.New(store, &service.EvmClient{})
+		version := int64(4)
+
+		_, err := svc.AmendOrder(ctx, service.AmendOrderInput{Id: order.Id, UserId: userId, Size: &size, Version: &version})
+
+		assert.ErrorIs(t, err, models.ErrVersionConflict)
+		assert.Equal(t, 1, store.Txs)
+	})
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/data/store"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// user events delivered per webhook per run
+const webhookBatchSize = 100
+
+// held while delivering a batch, long enough for every event of it to time out
+const webhookLeaseTTL = 5 * time.Minute
+
+const (
+	webhookMinBackoff = time.Second
+	webhookMaxBackoff = 10 * time.Minute
+)
+
+// WebhookWorker delivers the users' events to their webhooks, in seq order and at least once
+// The users' event streams are the outbox, each webhook keeps the seq of the last event it was delivered
+type WebhookWorker struct {
+	store  store.OrderBookStore
+	client *http.Client
+	// lease owner of this instance
+	owner string
+	// an event is given up on after this number of failed attempts
+	maxAttempts int
+}
+
+// NewWebhookWorker creates a worker, with a default client if client is nil
+func NewWebhookWorker(store store.OrderBookStore, client *http.Client) (*WebhookWorker, error) {
+	if store == nil {
+		return nil, errors.New("store cannot be nil")
+	}
+
+	if client == nil {
+		timeoutSec, _ := strconv.Atoi(utils.GetEnv("WEBHOOK_TIMEOUT_SEC", "10"))
+		client = &http.Client{Timeout: time.Duration(timeoutSec) * time.Second}
+	}
+
+	maxAttempts, err := strconv.Atoi(utils.GetEnv("WEBHOOK_MAX_ATTEMPTS", "10"))
+	if err != nil || maxAttempts <= 0 {
+		maxAttempts = 10
+	}
+
+	return &WebhookWorker{store: store, client: client, owner: uuid.NewString(), maxAttempts: maxAttempts}, nil
+}
+
+// Start runs the worker every WEBHOOK_POLL_MS until ctx is done
+func (w *WebhookWorker) Start(ctx context.Context) {
+	pollMs, _ := strconv.Atoi(utils.GetEnv("WEBHOOK_POLL_MS", "1000"))
+	if pollMs <= 0 {
+		pollMs = 1000
+	}
+	logctx.Debug(ctx, "starting webhook worker", logger.Int("pollMs", pollMs))
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(pollMs) * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.RunOnce(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// RunOnce delivers the pending events of every webhook that is not waiting for a retry
+func (w *WebhookWorker) RunOnce(ctx context.Context) {
+	webhooks, err := w.store.GetWebhooks(ctx)
+	if err != nil {
+		logctx.Error(ctx, "failed to get webhooks", logger.Error(err))
+		return
+	}
+
+	now := time.Now()
+	var wg sync.WaitGroup
+	for _, webhook := range webhooks {
+		if webhook.NextAttemptAt.After(now) {
+			continue
+		}
+		wg.Add(1)
+		go func(webhook models.Webhook) {
+			defer wg.Done()
+			w.deliverPending(ctx, webhook)
+		}(webhook)
+	}
+	wg.Wait()
+}
+
+func (w *WebhookWorker) deliverPending(ctx context.Context, webhook models.Webhook) {
+	acquired, err := w.store.AcquireWebhookLease(ctx, webhook.Id, w.owner, webhookLeaseTTL)
+	if err != nil || !acquired {
+		return
+	}
+	defer func() {
+		_ = w.store.ReleaseWebhookLease(ctx, webhook.Id, w.owner)
+	}()
+
+	events, err := w.store.ReadUserEvents(ctx, webhook.UserId, webhook.LastSeq, webhookBatchSize, 0)
+	if err != nil {
+		logctx.Warn(ctx, "failed to read user events for webhook", logger.String("webhookId", webhook.Id.String()), logger.Error(err))
+		return
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	for _, event := range events {
+		if event.Seq > webhook.LastSeq+1 {
+			gap, _ := json.Marshal(models.NewEventsGap(webhook.LastSeq+1, event.Seq-1))
+			if !w.deliver(ctx, &webhook, event.Seq-1, models.USER_EVENT_GAP, gap) {
+				break
+			}
+		}
+		if !w.deliver(ctx, &webhook, event.Seq, eventName(event.Data), event.Payload()) {
+			break
+		}
+	}
+
+	if err := w.store.UpdateWebhook(ctx, webhook); err != nil && err != models.ErrNotFound {
+		logctx.Error(ctx, "failed to update webhook delivery state", logger.String("webhookId", webhook.Id.String()), logger.Error(err))
+	}
+}
+
+// deliver makes a single attempt to deliver the event and logs it
+// returns true if the next event can be delivered, either because this one was or because it was given up on
+func (w *WebhookWorker) deliver(ctx context.Context, webhook *models.Webhook, seq int64, event string, payload []byte) bool {
+	webhook.Attempts++
+	delivery := models.WebhookDelivery{
+		Id:        uuid.New(),
+		WebhookId: webhook.Id,
+		Seq:       seq,
+		Event:     event,
+		Attempt:   webhook.Attempts,
+		Timestamp: time.Now(),
+	}
+
+	statusCode, err := w.post(ctx, *webhook, seq, event, payload)
+	delivery.Duration = time.Since(delivery.Timestamp)
+	delivery.StatusCode = statusCode
+
+	next := true
+	if err == nil {
+		delivery.Success = true
+	} else {
+		delivery.Error = err.Error()
+		if webhook.Attempts >= w.maxAttempts {
+			delivery.GaveUp = true
+			logctx.Warn(ctx, "webhook event given up on", logger.String("webhookId", webhook.Id.String()), logger.Int64("seq", seq), logger.Error(err))
+		} else {
+			webhook.NextAttemptAt = time.Now().Add(webhookBackoff(webhook.Attempts))
+			next = false
+		}
+	}
+	if next {
+		webhook.LastSeq = seq
+		webhook.Attempts = 0
+		webhook.NextAttemptAt = time.Time{}
+	}
+
+	if err := w.store.StoreWebhookDelivery(ctx, delivery); err != nil {
+		logctx.Error(ctx, "failed to store webhook delivery", logger.String("webhookId", webhook.Id.String()), logger.Error(err))
+	}
+	return next
+}
+
+// post sends the payload signed with the webhook's secret, any response other than 2xx is an error
+func (w *WebhookWorker) post(ctx context.Context, webhook models.Webhook, seq int64, event string, payload []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Id", webhook.Id.String())
+	req.Header.Set("X-Webhook-Event", event)
+	req.Header.Set("X-Webhook-Seq", strconv.FormatInt(seq, 10))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhookPayload(webhook.Secret, timestamp, payload))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64*1024))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<payload>" with the webhook's secret
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff doubles the wait with every failed attempt, up to webhookMaxBackoff
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookMinBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > webhookMaxBackoff {
+		return webhookMaxBackoff
+	}
+	return backoff
+}
+
+func eventName(data []byte) string {
+	var event struct {
+		Event string `json:"event"`
+	}
+	_ = json.Unmarshal(data, &event)
+	return event.Event
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// max delivery attempts returned at once
+const MAX_WEBHOOK_DELIVERIES = 1000
+
+// CreateWebhook registers a webhook which gets the user's events from now on, with a new secret to verify them
+func (s *Service) CreateWebhook(ctx context.Context, userId uuid.UUID, webhookUrl string) (models.Webhook, error) {
+	if !isValidWebhookUrl(webhookUrl) {
+		return models.Webhook{}, models.ErrInvalidWebhookUrl
+	}
+
+	webhooks, err := s.orderBookStore.GetUserWebhooks(ctx, userId)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+	if len(webhooks) >= models.MAX_USER_WEBHOOKS {
+		return models.Webhook{}, models.ErrTooManyWebhooks
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		logctx.Error(ctx, "failed to generate webhook secret", logger.Error(err))
+		return models.Webhook{}, err
+	}
+
+	lastSeq, err := s.orderBookStore.GetUserEventSeq(ctx, userId)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	webhook := models.Webhook{
+		Id:      uuid.New(),
+		UserId:  userId,
+		Url:     webhookUrl,
+		Secret:  hex.EncodeToString(secret),
+		Created: time.Now(),
+		LastSeq: lastSeq,
+	}
+	if err := s.orderBookStore.StoreWebhook(ctx, webhook); err != nil {
+		return models.Webhook{}, err
+	}
+
+	logctx.Info(ctx, "webhook created", logger.String("userId", userId.String()), logger.String("webhookId", webhook.Id.String()))
+	return webhook, nil
+}
+
+func (s *Service) GetWebhooks(ctx context.Context, userId uuid.UUID) ([]models.Webhook, error) {
+	return s.orderBookStore.GetUserWebhooks(ctx, userId)
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, userId uuid.UUID, webhookId uuid.UUID) error {
+	webhook, err := s.getUserWebhook(ctx, userId, webhookId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.orderBookStore.RemoveWebhook(ctx, *webhook); err != nil {
+		return err
+	}
+
+	logctx.Info(ctx, "webhook deleted", logger.String("userId", userId.String()), logger.String("webhookId", webhookId.String()))
+	return nil
+}
+
+// GetWebhookDeliveries returns the latest delivery attempts of the user's webhook, newest first
+func (s *Service) GetWebhookDeliveries(ctx context.Context, userId uuid.UUID, webhookId uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	if limit <= 0 || limit > MAX_WEBHOOK_DELIVERIES {
+		return nil, models.ErrInvalidInput
+	}
+
+	if _, err := s.getUserWebhook(ctx, userId, webhookId); err != nil {
+		return nil, err
+	}
+
+	return s.orderBookStore.GetWebhookDeliveries(ctx, webhookId, limit)
+}
+
+// getUserWebhook returns the webhook, or ErrUnauthorized if it belongs to another user
+func (s *Service) getUserWebhook(ctx context.Context, userId uuid.UUID, webhookId uuid.UUID) (*models.Webhook, error) {
+	webhook, err := s.orderBookStore.GetWebhook(ctx, webhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook.UserId != userId {
+		logctx.Warn(ctx, "user trying to access webhook of another user", logger.String("userId", userId.String()), logger.String("webhookId", webhookId.String()))
+		return nil, models.ErrUnauthorized
+	}
+	return webhook, nil
+}
+
+// isValidWebhookUrl requires https, unless WEBHOOK_ALLOW_HTTP is set for local testing
+func isValidWebhookUrl(webhookUrl string) bool {
+	u, err := url.ParseRequestURI(webhookUrl)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "https" || (u.Scheme == "http" && utils.GetEnv("WEBHOOK_ALLOW_HTTP", "") == "true")
+}
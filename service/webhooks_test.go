@@ -0,0 +1,201 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_CreateWebhook(t *testing.T) {
+	ctx := context.Background()
+	userId := uuid.New()
+
+	t.Run("should create webhook starting after the user's last event", func(t *testing.T) {
+		store := &mocks.MockOrderBookStore{UserEvents: []models.UserEvent{{Seq: 1}, {Seq: 2}}}
+		svc, _ := service.New(store, &service.EvmClient{})
+
+		webhook, err := svc.CreateWebhook(ctx, userId, "https://mm.example.com/events")
+
+		assert.NoError(t, err)
+		assert.Equal(t, userId, webhook.UserId)
+		assert.Equal(t, int64(2), webhook.LastSeq)
+		assert.Len(t, webhook.Secret, 64)
+		assert.Equal(t, []models.Webhook{webhook}, store.Webhooks)
+	})
+
+	t.Run("should reject a url that is not https", func(t *testing.T) {
+		svc, _ := service.New(&mocks.MockOrderBookStore{}, &service.EvmClient{})
+
+		for _, url := range []string{"http://mm.example.com/events", "mm.example.com", "https://"} {
+			_, err := svc.CreateWebhook(ctx, userId, url)
+			assert.ErrorIs(t, err, models.ErrInvalidWebhookUrl, url)
+		}
+	})
+
+	t.Run("should reject more than the max webhooks of a user", func(t *testing.T) {
+		store := &mocks.MockOrderBookStore{}
+		for i := 0; i < models.MAX_USER_WEBHOOKS; i++ {
+			store.Webhooks = append(store.Webhooks, models.Webhook{Id: uuid.New(), UserId: userId})
+		}
+		svc, _ := service.New(store, &service.EvmClient{})
+
+		_, err := svc.CreateWebhook(ctx, userId, "https://mm.example.com/events")
+		assert.ErrorIs(t, err, models.ErrTooManyWebhooks)
+	})
+}
+
+func TestService_DeleteWebhook(t *testing.T) {
+	ctx := context.Background()
+	webhook := models.Webhook{Id: uuid.New(), UserId: uuid.New()}
+
+	t.Run("should not delete webhook of another user", func(t *testing.T) {
+		store := &mocks.MockOrderBookStore{Webhooks: []models.Webhook{webhook}}
+		svc, _ := service.New(store, &service.EvmClient{})
+
+		assert.ErrorIs(t, svc.DeleteWebhook(ctx, uuid.New(), webhook.Id), models.ErrUnauthorized)
+		assert.Len(t, store.Webhooks, 1)
+	})
+
+	t.Run("should delete webhook", func(t *testing.T) {
+		store := &mocks.MockOrderBookStore{Webhooks: []models.Webhook{webhook}}
+		svc, _ := service.New(store, &service.EvmClient{})
+
+		assert.NoError(t, svc.DeleteWebhook(ctx, webhook.UserId, webhook.Id))
+		assert.Empty(t, store.Webhooks)
+		assert.ErrorIs(t, svc.DeleteWebhook(ctx, webhook.UserId, webhook.Id), models.ErrNotFound)
+	})
+}
+
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newWebhookServer records the requests it gets and replies with the next status, then 200
+func newWebhookServer(t *testing.T, statuses ...int) (*httptest.Server, func() []webhookRequest) {
+	var mu sync.Mutex
+	requests := []webhookRequest{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, webhookRequest{header: r.Header.Clone(), body: body})
+		if len(statuses) > 0 {
+			w.WriteHeader(statuses[0])
+			statuses = statuses[1:]
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []webhookRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]webhookRequest{}, requests...)
+	}
+}
+
+func TestWebhookWorker_RunOnce(t *testing.T) {
+	ctx := context.Background()
+	userId := uuid.New()
+	events := []models.UserEvent{
+		{Seq: 1, Data: []byte(`{"event":"order-changed"}`)},
+		{Seq: 2, Data: []byte(`{"event":"order-fill"}`)},
+	}
+
+	t.Run("should deliver signed events in seq order", func(t *testing.T) {
+		server, requests := newWebhookServer(t)
+		webhook := models.Webhook{Id: uuid.New(), UserId: userId, Url: server.URL, Secret: "secret"}
+		store := &mocks.MockOrderBookStore{UserEvents: events, Webhooks: []models.Webhook{webhook}}
+		worker, _ := service.NewWebhookWorker(store, server.Client())
+
+		worker.RunOnce(ctx)
+
+		reqs := requests()
+		assert.Len(t, reqs, 2)
+		assert.Equal(t, `{"seq":1,"event":"order-changed"}`, string(reqs[0].body))
+		assert.Equal(t, "2", reqs[1].header.Get("X-Webhook-Seq"))
+		assert.Equal(t, "order-fill", reqs[1].header.Get("X-Webhook-Event"))
+		assert.Equal(t, webhook.Id.String(), reqs[1].header.Get("X-Webhook-Id"))
+		timestamp := reqs[0].header.Get("X-Webhook-Timestamp")
+		assert.Equal(t, "sha256="+service.SignWebhookPayload("secret", timestamp, reqs[0].body), reqs[0].header.Get("X-Webhook-Signature"))
+
+		assert.Equal(t, int64(2), store.Webhooks[0].LastSeq)
+		assert.Len(t, store.WebhookDeliveries, 2)
+		assert.True(t, store.WebhookDeliveries[1].Success)
+		assert.Equal(t, http.StatusOK, store.WebhookDeliveries[1].StatusCode)
+	})
+
+	t.Run("should back off after a failed attempt and retry the same event", func(t *testing.T) {
+		server, requests := newWebhookServer(t, http.StatusInternalServerError)
+		webhook := models.Webhook{Id: uuid.New(), UserId: userId, Url: server.URL, Secret: "secret"}
+		store := &mocks.MockOrderBookStore{UserEvents: events, Webhooks: []models.Webhook{webhook}}
+		worker, _ := service.NewWebhookWorker(store, server.Client())
+
+		worker.RunOnce(ctx)
+
+		assert.Len(t, requests(), 1)
+		assert.Equal(t, int64(0), store.Webhooks[0].LastSeq)
+		assert.Equal(t, 1, store.Webhooks[0].Attempts)
+		assert.True(t, store.Webhooks[0].NextAttemptAt.After(time.Now()))
+		assert.False(t, store.WebhookDeliveries[0].Success)
+		assert.Equal(t, http.StatusInternalServerError, store.WebhookDeliveries[0].StatusCode)
+
+		// not due yet
+		worker.RunOnce(ctx)
+		assert.Len(t, requests(), 1)
+
+		store.Webhooks[0].NextAttemptAt = time.Now()
+		worker.RunOnce(ctx)
+
+		reqs := requests()
+		assert.Len(t, reqs, 3)
+		assert.Equal(t, "1", reqs[1].header.Get("X-Webhook-Seq"))
+		assert.Equal(t, 2, store.WebhookDeliveries[1].Attempt)
+		assert.Equal(t, int64(2), store.Webhooks[0].LastSeq)
+		assert.Equal(t, 0, store.Webhooks[0].Attempts)
+	})
+
+	t.Run("should give up on an event after max attempts", func(t *testing.T) {
+		t.Setenv("WEBHOOK_MAX_ATTEMPTS", "1")
+		server, requests := newWebhookServer(t, http.StatusBadRequest)
+		webhook := models.Webhook{Id: uuid.New(), UserId: userId, Url: server.URL, Secret: "secret"}
+		store := &mocks.MockOrderBookStore{UserEvents: events, Webhooks: []models.Webhook{webhook}}
+		worker, _ := service.NewWebhookWorker(store, server.Client())
+
+		worker.RunOnce(ctx)
+
+		assert.Len(t, requests(), 2)
+		assert.True(t, store.WebhookDeliveries[0].GaveUp)
+		assert.True(t, store.WebhookDeliveries[1].Success)
+		assert.Equal(t, int64(2), store.Webhooks[0].LastSeq)
+	})
+
+	t.Run("should send a gap for events no longer kept", func(t *testing.T) {
+		server, requests := newWebhookServer(t)
+		webhook := models.Webhook{Id: uuid.New(), UserId: userId, Url: server.URL, Secret: "secret"}
+		store := &mocks.MockOrderBookStore{UserEvents: events[1:], Webhooks: []models.Webhook{webhook}}
+		worker, _ := service.NewWebhookWorker(store, server.Client())
+
+		worker.RunOnce(ctx)
+
+		reqs := requests()
+		assert.Len(t, reqs, 2)
+		var gap models.EventsGap
+		assert.NoError(t, json.Unmarshal(reqs[0].body, &gap))
+		assert.Equal(t, models.USER_EVENT_GAP, gap.Event)
+		assert.Equal(t, int64(1), gap.FromSeq)
+		assert.Equal(t, int64(1), gap.ToSeq)
+		assert.Equal(t, "2", reqs[1].header.Get("X-Webhook-Seq"))
+	})
+}
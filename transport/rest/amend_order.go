@@ -0,0 +1,206 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/orbs-network/order-book/transport/restutils"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+type AmendOrderRequest struct {
+	// new total size, can only be reduced
+	Size string `json:"size,omitempty"`
+	// new price, requires a new signed order
+	Price     string                 `json:"price,omitempty"`
+	Eip712Sig string                 `json:"eip712Sig,omitempty"`
+	Eip712Msg map[string]interface{} `json:"eip712Msg,omitempty"`
+	// version of the order to amend, the amend fails once the order is at another version
+	Version *int64 `json:"version,omitempty"`
+}
+
+type AmendOrderResponse struct {
+	OrderId string `json:"orderId"`
+	Price   string `json:"price"`
+	Size    string `json:"size"`
+	Version int64  `json:"version"`
+}
+
+func (h *Handler) AmendOrderByOrderId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := utils.GetUserCtx(ctx)
+	if user == nil {
+		logctx.Error(ctx, "user should be in context")
+		restutils.WriteJSONError(ctx, w, http.StatusUnauthorized, "User not found")
+		return
+	}
+
+	vars := mux.Vars(r)
+	orderIdStr := vars["orderId"]
+
+	orderId, err := uuid.Parse(orderIdStr)
+	if err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Invalid order ID")
+		return
+	}
+
+	logctx.Debug(ctx, "user trying to amend order by orderID", logger.String("userId", user.Id.String()), logger.String("orderId", orderId.String()))
+
+	h.handleAmendOrder(ctx, w, r, orderId, false, user)
+}
+
+func (h *Handler) AmendOrderByClientOId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := utils.GetUserCtx(ctx)
+	if user == nil {
+		logctx.Error(ctx, "user should be in context")
+		restutils.WriteJSONError(ctx, w, http.StatusUnauthorized, "User not found")
+		return
+	}
+
+	vars := mux.Vars(r)
+	clientOIdStr := vars["clientOId"]
+
+	clientOId, err := uuid.Parse(clientOIdStr)
+	if err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Invalid clientOId")
+		return
+	}
+
+	logctx.Debug(ctx, "user trying to amend order by clientOId", logger.String("userId", user.Id.String()), logger.String("clientOId", clientOId.String()))
+
+	h.handleAmendOrder(ctx, w, r, clientOId, true, user)
+}
+
+// handleAmendOrder parses the amend request, calls the service and writes the response to the client
+func (h *Handler) handleAmendOrder(ctx context.Context, w http.ResponseWriter, r *http.Request, id uuid.UUID, isClientOId bool, user *models.User) {
+	var args AmendOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		logctx.Warn(ctx, "invalid JSON body", logger.Error(err))
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	input, err := parseAmendOrderRequest(args)
+	if err != nil {
+		logctx.Warn(ctx, "failed to parse amend order fields", logger.Error(err))
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, err.Error())
+		return
+	}
+	input.Id = id
+	input.IsClientOId = isClientOId
+	input.UserId = user.Id
+	input.UserPubKey = user.PubKey
+
+	order, err := h.svc.AmendOrder(ctx, *input)
+
+	switch err {
+	case nil:
+	case models.ErrNotFound:
+		logctx.Warn(ctx, "order not found", logger.String("id", id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusNotFound, "Order not found")
+		return
+	case models.ErrUnauthorized:
+		logctx.Warn(ctx, "user not authorized to amend order", logger.String("id", id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusUnauthorized, "Not authorized")
+		return
+	case models.ErrOrderCancelled:
+		logctx.Warn(ctx, "amending order not possible when order is cancelled", logger.String("id", id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusConflict, "Cannot amend cancelled order")
+		return
+	case models.ErrOrderFilled:
+		logctx.Warn(ctx, "amending order not possible when order is filled", logger.String("id", id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusConflict, "Cannot amend filled order")
+		return
+	case models.ErrOrderPending:
+		logctx.Warn(ctx, "amending price not possible when order is pending", logger.String("id", id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusConflict, "Cannot amend price while some of the order's size is pending")
+		return
+	case models.ErrVersionConflict:
+		logctx.Warn(ctx, "order changed while amending", logger.String("id", id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusConflict, "Order was changed, get its current version and try again")
+		return
+	case models.ErrCrossTrade:
+		logctx.Warn(ctx, "amended price crosses the book", logger.String("id", id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusConflict, err.Error())
+		return
+	case models.ErrInvalidInput:
+		logctx.Warn(ctx, "invalid amended size or price", logger.String("id", id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Size can only be reduced, and not below the filled and pending size")
+		return
+	case models.ErrInvalidSignature, models.ErrSignedOrderMismatch:
+		logctx.Warn(ctx, "invalid signed order", logger.Error(err), logger.String("id", id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, err.Error())
+		return
+	default:
+		logctx.Error(ctx, "failed to amend order", logger.Error(err))
+		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error amending order. Try again later")
+		return
+	}
+
+	resp, err := json.Marshal(AmendOrderResponse{
+		OrderId: order.Id.String(),
+		Price:   order.Price.String(),
+		Size:    order.Size.String(),
+		Version: order.Version,
+	})
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal amended order", logger.Error(err))
+		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error amending order. Try again later")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(resp); err != nil {
+		logctx.Error(ctx, "failed to write response", logger.Error(err), logger.String("orderId", order.Id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error amending order. Try again later")
+	}
+}
+
+func parseAmendOrderRequest(args AmendOrderRequest) (*service.AmendOrderInput, error) {
+	if args.Size == "" && args.Price == "" {
+		return nil, fmt.Errorf("at least one of 'size' or 'price' is required")
+	}
+
+	input := service.AmendOrderInput{Version: args.Version}
+
+	if args.Size != "" {
+		decSize, err := parseSize(args.Size)
+		if err != nil {
+			return nil, err
+		}
+		input.Size = &decSize
+	}
+
+	if args.Price != "" {
+		decPrice, err := parsePrice(args.Price)
+		if err != nil {
+			return nil, err
+		}
+		if args.Eip712Sig == "" {
+			return nil, fmt.Errorf("missing required field 'eip712Sig'")
+		}
+		if args.Eip712Msg == nil {
+			return nil, fmt.Errorf("missing required field 'eip712Msg'")
+		}
+		abiFragment, err := restutils.ConvertToAbiFragment(args.Eip712Msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse eip712Msg: %w", err)
+		}
+		input.Price = &decPrice
+		input.Eip712Sig = args.Eip712Sig
+		input.AbiFragment = abiFragment
+	}
+
+	return &input, nil
+}
@@ -0,0 +1,89 @@
+package rest_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/transport/rest"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_AmendOrderByOrderId(t *testing.T) {
+	orderId := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	url := fmt.Sprintf("/order/%s", orderId.String())
+
+	t.Run("no user in context - should return `User not found` error", func(t *testing.T) {
+		router := mux.NewRouter()
+
+		h, _ := rest.NewHandler(&mocks.MockOrderBookService{}, router)
+
+		req, err := http.NewRequest("PUT", url, bytes.NewBufferString(`{"size":"1"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		router.HandleFunc("/order/{orderId}", h.AmendOrderByOrderId).Methods("PUT")
+
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+		assert.Equal(t, "{\"status\":401,\"msg\":\"User not found\"}\n", rr.Body.String())
+	})
+
+	ctx := mocks.AddUserToCtx(nil)
+
+	tests := []struct {
+		name         string
+		mockService  *mocks.MockOrderBookService
+		url          string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"invalid orderId", &mocks.MockOrderBookService{}, "/order/invalid", `{"size":"1"}`, http.StatusBadRequest, "{\"status\":400,\"msg\":\"Invalid order ID\"}\n"},
+		{"invalid body", &mocks.MockOrderBookService{}, url, `{`, http.StatusBadRequest, "{\"status\":400,\"msg\":\"Invalid JSON body\"}\n"},
+		{"nothing to amend", &mocks.MockOrderBookService{}, url, `{}`, http.StatusBadRequest, "{\"status\":400,\"msg\":\"at least one of 'size' or 'price' is required\"}\n"},
+		{"invalid size", &mocks.MockOrderBookService{}, url, `{"size":"-1"}`, http.StatusBadRequest, "{\"status\":400,\"msg\":\"'size' must be positive\"}\n"},
+		{"price without signature", &mocks.MockOrderBookService{}, url, `{"price":"10"}`, http.StatusBadRequest, "{\"status\":400,\"msg\":\"missing required field 'eip712Sig'\"}\n"},
+		{"order not found", &mocks.MockOrderBookService{Error: models.ErrNotFound}, url, `{"size":"1"}`, http.StatusNotFound, "{\"status\":404,\"msg\":\"Order not found\"}\n"},
+		{"order of other user", &mocks.MockOrderBookService{Error: models.ErrUnauthorized}, url, `{"size":"1"}`, http.StatusUnauthorized, "{\"status\":401,\"msg\":\"Not authorized\"}\n"},
+		{"order cancelled", &mocks.MockOrderBookService{Error: models.ErrOrderCancelled}, url, `{"size":"1"}`, http.StatusConflict, "{\"status\":409,\"msg\":\"Cannot amend cancelled order\"}\n"},
+		{"order filled", &mocks.MockOrderBookService{Error: models.ErrOrderFilled}, url, `{"size":"1"}`, http.StatusConflict, "{\"status\":409,\"msg\":\"Cannot amend filled order\"}\n"},
+		{"size not reduced", &mocks.MockOrderBookService{Error: models.ErrInvalidInput}, url, `{"size":"1"}`, http.StatusBadRequest, "{\"status\":400,\"msg\":\"Size can only be reduced, and not below the filled and pending size\"}\n"},
+		{"unexpected error from service", &mocks.MockOrderBookService{Error: assert.AnError}, url, `{"size":"1"}`, http.StatusInternalServerError, "{\"status\":500,\"msg\":\"Error amending order. Try again later\"}\n"},
+		{"order changed since its version", &mocks.MockOrderBookService{Error: models.ErrVersionConflict}, url, `{"size":"1","version":3}`, http.StatusConflict, "{\"status\":409,\"msg\":\"Order was changed, get its current version and try again\"}\n"},
+		{"successful amend", &mocks.MockOrderBookService{Order: &models.Order{Id: orderId, Price: decimal.NewFromInt(10), Size: decimal.NewFromInt(1), Version: 4}}, url, `{"size":"1"}`, http.StatusOK, fmt.Sprintf("{\"orderId\":\"%s\",\"price\":\"10\",\"size\":\"1\",\"version\":4}", orderId.String())},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := mux.NewRouter()
+
+			h, _ := rest.NewHandler(test.mockService, router)
+
+			req, err := http.NewRequest("PUT", test.url, bytes.NewBufferString(test.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			reqWithCtx := req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			router.HandleFunc("/order/{orderId}", h.AmendOrderByOrderId).Methods("PUT")
+
+			router.ServeHTTP(rr, reqWithCtx)
+
+			assert.Equal(t, test.expectedCode, rr.Code)
+			assert.Equal(t, test.expectedBody, rr.Body.String())
+		})
+	}
+}
@@ -118,6 +118,12 @@ func (h *Handler) handleCancelOrder(input hInput) {
 		return
 	}
 
+	if err == models.ErrVersionConflict {
+		logctx.Warn(input.ctx, "order kept changing while cancelling", logger.String("id", input.id.String()))
+		restutils.WriteJSONError(input.ctx, input.w, http.StatusConflict, "Order was changed while cancelling. Try again")
+		return
+	}
+
 	if err == models.ErrOrderFilled {
 		logctx.Warn(input.ctx, "cancelling order not possible when order is filled", logger.String("id", input.id.String()))
 		restutils.WriteJSONError(input.ctx, input.w, http.StatusConflict, "Cannot cancel filled order")
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/orbs-network/order-book/models"
@@ -23,6 +24,12 @@ type CreateOrderRequest struct {
 	ClientOrderId string                 `json:"clientOrderId"`
 	Eip712Sig     string                 `json:"eip712Sig"`
 	Eip712Msg     map[string]interface{} `json:"eip712Msg"`
+	// GTC (default), GTT or POST_ONLY
+	TimeInForce string `json:"timeInForce,omitempty"`
+	// GTT expiry in ms
+	ExpireTime string `json:"expireTime,omitempty"`
+	// POST_ONLY auto cancel period
+	CancelAfterSec int `json:"cancelAfterSec,omitempty"`
 }
 
 type CreateOrderResponse struct {
@@ -46,64 +53,41 @@ func (h *Handler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := handleValidateRequiredFields(hVRFArgs{
-		price:         args.Price,
-		size:          args.Size,
-		symbol:        args.Symbol,
-		side:          args.Side,
-		clientOrderId: args.ClientOrderId,
-		eip712Sig:     args.Eip712Sig,
-		eip712Msg:     &args.Eip712Msg,
-	}); err != nil {
+	input, err := parseCreateOrderRequest(user, args)
+	if err != nil {
+		logctx.Warn(ctx, "failed to parse order", logger.Error(err))
 		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	parsedFields, err := parseFields(w, pFInput{
-		price:         args.Price,
-		size:          args.Size,
-		symbol:        args.Symbol,
-		side:          args.Side,
-		clientOrderId: args.ClientOrderId,
-	})
-	if err != nil {
-		logctx.Warn(ctx, "failed to parse order fields", logger.Error(err))
-		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, err.Error())
+	order, err := h.svc.CreateOrder(ctx, input)
+
+	if err == models.ErrCrossTrade {
+		logctx.Warn(ctx, "Price CrossTrade order", logger.String("userId", user.Id.String()), logger.String("orderId", input.ClientOrderID.String()), logger.String("Price", input.Price.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusConflict, err.Error())
 		return
 	}
 
-	abiFragment, err := restutils.ConvertToAbiFragment(args.Eip712Msg)
-	if err != nil {
-		logctx.Warn(ctx, "failed to convert eip712Msg to abi fragment", logger.Error(err))
-		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, fmt.Errorf("failed to parse eip712Msg: %w", err).Error())
+	if err == models.ErrInvalidSignature || err == models.ErrSignedOrderMismatch {
+		logctx.Warn(ctx, "invalid signed order", logger.Error(err), logger.String("userId", user.Id.String()), logger.String("clientOrderId", input.ClientOrderID.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	order, err := h.svc.CreateOrder(ctx, service.CreateOrderInput{
-		UserId:        user.Id,
-		Price:         parsedFields.roundedDecPrice,
-		Symbol:        parsedFields.symbol,
-		Size:          parsedFields.decSize,
-		Side:          parsedFields.side,
-		ClientOrderID: parsedFields.clientOrderId,
-		Eip712Sig:     args.Eip712Sig,
-		AbiFragment:   abiFragment,
-	})
-
-	if err == models.ErrCrossTrade {
-		logctx.Warn(ctx, "Price CrossTrade order", logger.String("userId", user.Id.String()), logger.String("orderId", parsedFields.clientOrderId.String()), logger.String("Price", parsedFields.roundedDecPrice.String()))
-		restutils.WriteJSONError(ctx, w, http.StatusConflict, err.Error())
+	if err == models.ErrInvalidTimeInForce {
+		logctx.Warn(ctx, "invalid time in force", logger.String("userId", user.Id.String()), logger.String("clientOrderId", input.ClientOrderID.String()), logger.String("timeInForce", input.TimeInForce.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	if err == models.ErrClashingOrderId {
-		logctx.Warn(ctx, "clashing order ID", logger.String("userId", user.Id.String()), logger.String("orderId", parsedFields.clientOrderId.String()))
+		logctx.Warn(ctx, "clashing order ID", logger.String("userId", user.Id.String()), logger.String("orderId", input.ClientOrderID.String()))
 		restutils.WriteJSONError(ctx, w, http.StatusConflict, "Clashing order ID. Please retry")
 		return
 	}
 
 	if err == models.ErrClashingClientOrderId {
-		logctx.Warn(ctx, "clashing client order ID", logger.String("userId", user.Id.String()), logger.String("clientOrderId", parsedFields.clientOrderId.String()))
+		logctx.Warn(ctx, "clashing client order ID", logger.String("userId", user.Id.String()), logger.String("clientOrderId", input.ClientOrderID.String()))
 		restutils.WriteJSONError(ctx, w, http.StatusConflict, fmt.Sprintf("Order with clientOrderId %s already exists", args.ClientOrderId))
 		return
 	}
@@ -128,11 +112,69 @@ func (h *Handler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 
 	if _, err := w.Write(resp); err != nil {
-		logctx.Error(ctx, "failed to write response", logger.Error(err), logger.String("orderId", parsedFields.clientOrderId.String()))
+		logctx.Error(ctx, "failed to write response", logger.Error(err), logger.String("orderId", input.ClientOrderID.String()))
 		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error creating order. Try again later")
 	}
 }
 
+// parseCreateOrderRequest validates a new order of the user, the returned error is meant for the client
+func parseCreateOrderRequest(user *models.User, args CreateOrderRequest) (service.CreateOrderInput, error) {
+	if err := handleValidateRequiredFields(hVRFArgs{
+		price:         args.Price,
+		size:          args.Size,
+		symbol:        args.Symbol,
+		side:          args.Side,
+		clientOrderId: args.ClientOrderId,
+		eip712Sig:     args.Eip712Sig,
+		eip712Msg:     &args.Eip712Msg,
+	}); err != nil {
+		return service.CreateOrderInput{}, err
+	}
+
+	parsedFields, err := parseFields(nil, pFInput{
+		price:          args.Price,
+		size:           args.Size,
+		symbol:         args.Symbol,
+		side:           args.Side,
+		clientOrderId:  args.ClientOrderId,
+		timeInForce:    args.TimeInForce,
+		expireTime:     args.ExpireTime,
+		cancelAfterSec: args.CancelAfterSec,
+	})
+	if err != nil {
+		return service.CreateOrderInput{}, err
+	}
+
+	abiFragment, err := restutils.ConvertToAbiFragment(args.Eip712Msg)
+	if err != nil {
+		return service.CreateOrderInput{}, fmt.Errorf("failed to parse eip712Msg: %w", err)
+	}
+
+	return service.CreateOrderInput{
+		UserId:        user.Id,
+		Price:         parsedFields.roundedDecPrice,
+		Symbol:        parsedFields.symbol,
+		Size:          parsedFields.decSize,
+		Side:          parsedFields.side,
+		ClientOrderID: parsedFields.clientOrderId,
+		Eip712Sig:     args.Eip712Sig,
+		AbiFragment:   abiFragment,
+		UserPubKey:    user.PubKey,
+		TimeInForce:   parsedFields.timeInForce,
+		ExpiresAt:     parsedFields.expiresAt,
+		CancelAfter:   parsedFields.cancelAfter,
+	}, nil
+}
+
+// parseWsOrder parses an order placed on the orders websocket the same way as POST /order
+func parseWsOrder(user *models.User, data json.RawMessage) (service.CreateOrderInput, error) {
+	var args CreateOrderRequest
+	if err := json.Unmarshal(data, &args); err != nil {
+		return service.CreateOrderInput{}, fmt.Errorf("invalid order")
+	}
+	return parseCreateOrderRequest(user, args)
+}
+
 type hVRFArgs struct {
 	price         string
 	size          string
@@ -177,43 +219,31 @@ type pfParsed struct {
 	symbol          models.Symbol
 	side            models.Side
 	clientOrderId   uuid.UUID
+	timeInForce     models.TimeInForce
+	expiresAt       time.Time
+	cancelAfter     time.Duration
 }
 
 type pFInput struct {
-	price         string
-	size          string
-	symbol        string
-	side          string
-	clientOrderId string
+	price          string
+	size           string
+	symbol         string
+	side           string
+	clientOrderId  string
+	timeInForce    string
+	expireTime     string
+	cancelAfterSec int
 }
 
 func parseFields(_ http.ResponseWriter, input pFInput) (*pfParsed, error) {
-	decPrice, err := decimal.NewFromString(input.price)
+	roundedDecPrice, err := parsePrice(input.price)
 	if err != nil {
-		return nil, fmt.Errorf("'price' is not a valid number format")
-	}
-
-	if decPrice.IsZero() || decPrice.IsNegative() {
-		return nil, fmt.Errorf("'price' must be positive")
-	}
-
-	// Ensure price is 8 decimal places - aligns with Binance's precision
-	if decPrice.Exponent() < -8 {
-		return nil, fmt.Errorf("'price' must not exceed 8 decimal places")
+		return nil, err
 	}
-	roundedDecPrice := decPrice.Round(8)
 
-	decSize, err := decimal.NewFromString(input.size)
+	decSize, err := parseSize(input.size)
 	if err != nil {
-		return nil, fmt.Errorf("'size' is not a valid number format")
-	}
-
-	if decSize.IsZero() || decSize.IsNegative() {
-		return nil, fmt.Errorf("'size' must be positive")
-	}
-
-	if decSize.Exponent() < -4 {
-		return nil, fmt.Errorf("'size' must not exceed 4 decimal places")
+		return nil, err
 	}
 
 	symbol, err := models.StrToSymbol(input.symbol)
@@ -231,11 +261,67 @@ func parseFields(_ http.ResponseWriter, input pFInput) (*pfParsed, error) {
 		return nil, fmt.Errorf("'clientOrderId' is not valid")
 	}
 
+	timeInForce, err := models.StrToTimeInForce(input.timeInForce)
+	if err != nil {
+		return nil, fmt.Errorf("'timeInForce' is not valid")
+	}
+
+	var expiresAt time.Time
+	if timeInForce == models.GTT {
+		if expiresAt, err = str2time(input.expireTime); err != nil {
+			return nil, fmt.Errorf("'expireTime' is required for GTT orders and must be a timestamp in ms")
+		}
+	}
+
+	var cancelAfter time.Duration
+	if timeInForce == models.POST_ONLY {
+		if input.cancelAfterSec <= 0 {
+			return nil, fmt.Errorf("'cancelAfterSec' is required for POST_ONLY orders and must be positive")
+		}
+		cancelAfter = time.Duration(input.cancelAfterSec) * time.Second
+	}
+
 	return &pfParsed{
 		roundedDecPrice: roundedDecPrice,
 		decSize:         decSize,
 		symbol:          symbol,
 		side:            side,
 		clientOrderId:   clientOrderId,
+		timeInForce:     timeInForce,
+		expiresAt:       expiresAt,
+		cancelAfter:     cancelAfter,
 	}, nil
 }
+
+func parsePrice(price string) (decimal.Decimal, error) {
+	decPrice, err := decimal.NewFromString(price)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("'price' is not a valid number format")
+	}
+
+	if decPrice.IsZero() || decPrice.IsNegative() {
+		return decimal.Zero, fmt.Errorf("'price' must be positive")
+	}
+
+	// Ensure price is 8 decimal places - aligns with Binance's precision
+	if decPrice.Exponent() < -8 {
+		return decimal.Zero, fmt.Errorf("'price' must not exceed 8 decimal places")
+	}
+	return decPrice.Round(8), nil
+}
+
+func parseSize(size string) (decimal.Decimal, error) {
+	decSize, err := decimal.NewFromString(size)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("'size' is not a valid number format")
+	}
+
+	if decSize.IsZero() || decSize.IsNegative() {
+		return decimal.Zero, fmt.Errorf("'size' must be positive")
+	}
+
+	if decSize.Exponent() < -4 {
+		return decimal.Zero, fmt.Errorf("'size' must not exceed 4 decimal places")
+	}
+	return decSize, nil
+}
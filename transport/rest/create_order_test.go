@@ -310,6 +310,54 @@ func TestHandler_CreateOrder(t *testing.T) {
 			http.StatusBadRequest,
 			"{\"status\":400,\"msg\":\"'clientOrderId' is not valid\"}\n",
 		},
+		{
+			"invalid time in force - should return `timeInForce is not valid` error",
+			&mocks.MockOrderBookService{},
+			createBody(t, rest.CreateOrderRequest{
+				Price:         "100.0",
+				Size:          "10",
+				Symbol:        "MATIC-USDC",
+				Side:          "sell",
+				ClientOrderId: "a677273e-12de-4acc-a4f8-de7fb5b86e37",
+				Eip712Sig:     "mock-sig",
+				Eip712Msg:     map[string]interface{}{},
+				TimeInForce:   "FOK",
+			}),
+			http.StatusBadRequest,
+			"{\"status\":400,\"msg\":\"'timeInForce' is not valid\"}\n",
+		},
+		{
+			"GTT with no expire time - should return `expireTime is required` error",
+			&mocks.MockOrderBookService{},
+			createBody(t, rest.CreateOrderRequest{
+				Price:         "100.0",
+				Size:          "10",
+				Symbol:        "MATIC-USDC",
+				Side:          "sell",
+				ClientOrderId: "a677273e-12de-4acc-a4f8-de7fb5b86e37",
+				Eip712Sig:     "mock-sig",
+				Eip712Msg:     map[string]interface{}{},
+				TimeInForce:   "GTT",
+			}),
+			http.StatusBadRequest,
+			"{\"status\":400,\"msg\":\"'expireTime' is required for GTT orders and must be a timestamp in ms\"}\n",
+		},
+		{
+			"POST_ONLY with no cancel after - should return `cancelAfterSec is required` error",
+			&mocks.MockOrderBookService{},
+			createBody(t, rest.CreateOrderRequest{
+				Price:         "100.0",
+				Size:          "10",
+				Symbol:        "MATIC-USDC",
+				Side:          "sell",
+				ClientOrderId: "a677273e-12de-4acc-a4f8-de7fb5b86e37",
+				Eip712Sig:     "mock-sig",
+				Eip712Msg:     map[string]interface{}{},
+				TimeInForce:   "POST_ONLY",
+			}),
+			http.StatusBadRequest,
+			"{\"status\":400,\"msg\":\"'cancelAfterSec' is required for POST_ONLY orders and must be positive\"}\n",
+		},
 		// ----- Create order tests -----
 		{
 			"create order success - should return `order created`",
@@ -318,6 +366,13 @@ func TestHandler_CreateOrder(t *testing.T) {
 			http.StatusCreated,
 			string(orderSucessResJSON),
 		},
+		{
+			"invalid time in force from service - should return 400",
+			&mocks.MockOrderBookService{Order: &models.Order{}, Error: models.ErrInvalidTimeInForce},
+			createBody(t, orderReq),
+			http.StatusBadRequest,
+			"{\"status\":400,\"msg\":\"invalid time in force\"}\n",
+		},
 		{
 			"clashing order id - should return `Clashing order ID. Please retry` error",
 			&mocks.MockOrderBookService{Order: &models.Order{}, Error: models.ErrClashingOrderId},
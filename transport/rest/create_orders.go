@@ -90,11 +90,14 @@ func (h *Handler) CreateOrders(w http.ResponseWriter, r *http.Request) {
 
 	for _, order := range args.Orders {
 		parsedFields, err := parseFields(w, pFInput{
-			price:         order.Price,
-			size:          order.Size,
-			symbol:        args.Symbol,
-			side:          order.Side,
-			clientOrderId: order.ClientOrderId,
+			price:          order.Price,
+			size:           order.Size,
+			symbol:         args.Symbol,
+			side:           order.Side,
+			clientOrderId:  order.ClientOrderId,
+			timeInForce:    order.TimeInForce,
+			expireTime:     order.ExpireTime,
+			cancelAfterSec: order.CancelAfterSec,
 		})
 		if err != nil {
 			logctx.Warn(ctx, "failed to parse fields", logger.Error(err), logger.String("userId", user.Id.String()))
@@ -122,6 +125,10 @@ func (h *Handler) CreateOrders(w http.ResponseWriter, r *http.Request) {
 			ClientOrderID: parsedFields.clientOrderId,
 			Eip712Sig:     order.Eip712Sig,
 			AbiFragment:   abiFragment,
+			UserPubKey:    user.PubKey,
+			TimeInForce:   parsedFields.timeInForce,
+			ExpiresAt:     parsedFields.expiresAt,
+			CancelAfter:   parsedFields.cancelAfter,
 		})
 
 		if err == models.ErrClashingClientOrderId {
@@ -131,6 +138,20 @@ func (h *Handler) CreateOrders(w http.ResponseWriter, r *http.Request) {
 			break
 		}
 
+		if err == models.ErrInvalidSignature || err == models.ErrSignedOrderMismatch {
+			logctx.Warn(ctx, "invalid signed order", logger.Error(err), logger.String("clientOrderId", parsedFields.clientOrderId.String()), logger.String("userId", user.Id.String()))
+			response.Status = http.StatusBadRequest
+			response.Msg = fmt.Sprintf("Invalid signed order with clientOrderId %q: %s", parsedFields.clientOrderId.String(), err.Error())
+			break
+		}
+
+		if err == models.ErrInvalidTimeInForce {
+			logctx.Warn(ctx, "invalid time in force", logger.String("clientOrderId", parsedFields.clientOrderId.String()), logger.String("userId", user.Id.String()), logger.String("timeInForce", parsedFields.timeInForce.String()))
+			response.Status = http.StatusBadRequest
+			response.Msg = fmt.Sprintf("Invalid time in force for order with clientOrderId %q", parsedFields.clientOrderId.String())
+			break
+		}
+
 		if err == models.ErrClashingOrderId {
 			logctx.Warn(ctx, "order with orderId already exists", logger.Error(err), logger.String("clientOrderId", parsedFields.clientOrderId.String()), logger.String("userId", user.Id.String()))
 			response.Status = http.StatusConflict
@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/transport/restutils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+type CandlesResponse struct {
+	Symbol   string          `json:"symbol"`
+	Interval string          `json:"interval"`
+	Candles  []models.Candle `json:"candles"`
+}
+
+// GetCandles returns the OHLCV candles of a symbol, oldest first. Public - no user is required
+// query params: interval (1m, 5m, 1h or 1d, default 1m), optional startAt and endAt (ms)
+func (h *Handler) GetCandles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	symbol, err := models.StrToSymbol(mux.Vars(r)["symbol"])
+	if err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Invalid symbol")
+		return
+	}
+
+	q := r.URL.Query()
+
+	interval := models.CANDLE_1M
+	if intervalStr := q.Get("interval"); intervalStr != "" {
+		interval, err = models.StrToCandleInterval(intervalStr)
+		if err != nil {
+			restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "'interval' must be one of 1m, 5m, 1h or 1d")
+			return
+		}
+	}
+
+	var startAt, endAt time.Time
+	if startAtStr := q.Get("startAt"); startAtStr != "" {
+		if startAt, err = str2time(startAtStr); err != nil {
+			restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "'startAt' must be a timestamp in ms")
+			return
+		}
+	}
+	if endAtStr := q.Get("endAt"); endAtStr != "" {
+		if endAt, err = str2time(endAtStr); err != nil {
+			restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "'endAt' must be a timestamp in ms")
+			return
+		}
+	}
+
+	candles, err := h.svc.GetCandles(ctx, symbol, interval, startAt, endAt)
+	if err == models.ErrInvalidInput {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "'startAt' must be before 'endAt'")
+		return
+	}
+	if err != nil {
+		logctx.Warn(ctx, "failed GetCandles", logger.Error(err), logger.String("symbol", symbol.String()), logger.String("interval", interval.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error getting candles. Try again later")
+		return
+	}
+
+	restutils.WriteJSONResponse(ctx, w, http.StatusOK, CandlesResponse{Symbol: symbol.String(), Interval: interval.String(), Candles: candles}, logger.String("symbol", symbol.String()))
+}
@@ -0,0 +1,64 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/transport/rest"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_GetCandles(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	candle := models.Candle{Symbol: "ETH-USDC", Interval: models.CANDLE_1H, Start: start, Open: decimal.NewFromInt(2000), High: decimal.NewFromInt(2100), Low: decimal.NewFromInt(1900), Close: decimal.NewFromInt(2050), Volume: decimal.NewFromInt(3), QuoteVolume: decimal.NewFromInt(6150), Trades: 2, FirstTradeAt: start, LastTradeAt: start}
+
+	tests := []struct {
+		name         string
+		mockService  *mocks.MockOrderBookService
+		url          string
+		expectedCode int
+		expectedBody string
+	}{
+		{"invalid symbol", &mocks.MockOrderBookService{}, "/api/v1/candles/nope", http.StatusBadRequest, "{\"status\":400,\"msg\":\"Invalid symbol\"}\n"},
+		{"invalid interval", &mocks.MockOrderBookService{}, "/api/v1/candles/ETH-USDC?interval=2m", http.StatusBadRequest, "{\"status\":400,\"msg\":\"'interval' must be one of 1m, 5m, 1h or 1d\"}\n"},
+		{"invalid startAt", &mocks.MockOrderBookService{}, "/api/v1/candles/ETH-USDC?startAt=yesterday", http.StatusBadRequest, "{\"status\":400,\"msg\":\"'startAt' must be a timestamp in ms\"}\n"},
+		{"invalid range", &mocks.MockOrderBookService{Error: models.ErrInvalidInput}, "/api/v1/candles/ETH-USDC?startAt=2000&endAt=1000", http.StatusBadRequest, "{\"status\":400,\"msg\":\"'startAt' must be before 'endAt'\"}\n"},
+		{"service error", &mocks.MockOrderBookService{Error: assert.AnError}, "/api/v1/candles/ETH-USDC", http.StatusInternalServerError, "{\"status\":500,\"msg\":\"Error getting candles. Try again later\"}\n"},
+		{
+			"candles, no API key required",
+			&mocks.MockOrderBookService{Candles: []models.Candle{candle}},
+			"/api/v1/candles/ETH-USDC?interval=1h&startAt=1709294400000",
+			http.StatusOK,
+			"{\"symbol\":\"ETH-USDC\",\"interval\":\"1h\",\"candles\":[{\"symbol\":\"ETH-USDC\",\"interval\":\"1h\",\"start\":\"2024-03-01T12:00:00Z\",\"open\":\"2000\",\"high\":\"2100\",\"low\":\"1900\",\"close\":\"2050\",\"volume\":\"3\",\"quoteVolume\":\"6150\",\"trades\":2,\"firstTradeAt\":\"2024-03-01T12:00:00Z\",\"lastTradeAt\":\"2024-03-01T12:00:00Z\"}]}\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := mux.NewRouter()
+
+			h, _ := rest.NewHandler(test.mockService, router)
+			h.Init(func(ctx context.Context, apiKey string) (*models.User, error) {
+				return nil, models.ErrNotFound
+			})
+
+			req, err := http.NewRequest("GET", test.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedCode, rr.Code)
+			assert.Equal(t, test.expectedBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/orbs-network/order-book/transport/restutils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+type FeeTotalResponse struct {
+	Token        string `json:"token"`
+	TakerFees    string `json:"takerFees"`
+	MakerRebates string `json:"makerRebates"`
+	ProtocolFees string `json:"protocolFees"`
+}
+
+type FeeTotalsResponse struct {
+	Fees []FeeTotalResponse `json:"fees"`
+}
+
+// GetFeeTotals returns the fees of resolved fills summed per token, admins only
+func (h *Handler) GetFeeTotals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	totals, err := h.svc.GetFeeTotals(ctx)
+	if err != nil {
+		logctx.Warn(ctx, "failed GetFeeTotals", logger.Error(err))
+		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error getting fees. Try again later")
+		return
+	}
+
+	res := FeeTotalsResponse{Fees: make([]FeeTotalResponse, 0, len(totals))}
+	for _, total := range totals {
+		res.Fees = append(res.Fees, FeeTotalResponse{
+			Token:        total.Token,
+			TakerFees:    total.TakerFees.String(),
+			MakerRebates: total.MakerRebates.String(),
+			ProtocolFees: total.ProtocolFees().String(),
+		})
+	}
+	restutils.WriteJSONResponse(ctx, w, http.StatusOK, res)
+}
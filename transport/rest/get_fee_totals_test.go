@@ -0,0 +1,55 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/transport/rest"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_GetFeeTotals(t *testing.T) {
+	ctx := mocks.AddUserToCtx(nil)
+
+	tests := []struct {
+		name         string
+		mockService  *mocks.MockOrderBookService
+		expectedCode int
+		expectedBody string
+	}{
+		{"service error", &mocks.MockOrderBookService{Error: assert.AnError}, http.StatusInternalServerError, "{\"status\":500,\"msg\":\"Error getting fees. Try again later\"}\n"},
+		{"no fees", &mocks.MockOrderBookService{}, http.StatusOK, "{\"fees\":[]}\n"},
+		{
+			"fees per token",
+			&mocks.MockOrderBookService{FeeTotals: []models.FeeTotal{{Token: "USDC", TakerFees: decimal.NewFromFloat(1.5), MakerRebates: decimal.NewFromFloat(0.5)}}},
+			http.StatusOK,
+			"{\"fees\":[{\"token\":\"USDC\",\"takerFees\":\"1.5\",\"makerRebates\":\"0.5\",\"protocolFees\":\"1\"}]}\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := mux.NewRouter()
+
+			h, _ := rest.NewHandler(test.mockService, router)
+
+			req, err := http.NewRequest("GET", "/fees", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.HandleFunc("/fees", h.GetFeeTotals).Methods("GET")
+
+			router.ServeHTTP(rr, req.WithContext(ctx))
+
+			assert.Equal(t, test.expectedCode, rr.Code)
+			assert.Equal(t, test.expectedBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/orbs-network/order-book/transport/restutils"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+type FillsResponse struct {
+	Fills      []models.Fill `json:"fills"`
+	NextCursor string        `json:"nextCursor"`
+}
+
+// GetFills returns the user's fills resolved between startAt and endAt (ms), the last 24 hours by default
+// responds 413 if there are more than MAX_FILLS, to narrow down the range
+func (h *Handler) GetFills(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := utils.GetUserCtx(ctx)
+	if user == nil {
+		logctx.Error(ctx, "user should be in context")
+		restutils.WriteJSONError(ctx, w, http.StatusUnauthorized, "User not found")
+		return
+	}
+
+	logctx.Debug(r.Context(), "user trying to get their fills", logger.String("userId", user.Id.String()))
+
+	startAt, endAt := getStartEndTime(r)
+	// one more than MAX_FILLS, to tell whether it is exceeded
+	fills, _, err := h.svc.GetFills(r.Context(), user.Id, models.HistoryFilter{StartAt: startAt, EndAt: endAt, Limit: service.MAX_FILLS + 1})
+	if err != nil {
+		logctx.Warn(r.Context(), "failed GetFills", logger.Error(err), logger.String("userId", user.Id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error getting fills. Try again later")
+		return
+	}
+	// narrow down the time range, MAX_FILLS exceeded
+	if len(fills) > service.MAX_FILLS {
+		restutils.WriteJSONError(ctx, w, http.StatusRequestEntityTooLarge, models.ErrMaxRecExceeded.Error())
+		return
+	}
+
+	restutils.WriteJSONResponse(ctx, w, http.StatusOK, fills, logger.String("userId", user.Id.String()))
+}
+
+// GetFillsHistory returns a page of the user's fills, newest first
+// optional query params: symbol, side, startAt, endAt (ms), cursor and limit
+func (h *Handler) GetFillsHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := utils.GetUserCtx(ctx)
+	if user == nil {
+		logctx.Error(ctx, "user should be in context")
+		restutils.WriteJSONError(ctx, w, http.StatusUnauthorized, "User not found")
+		return
+	}
+
+	logctx.Debug(r.Context(), "user trying to get their fills history", logger.String("userId", user.Id.String()))
+
+	filter, err := parseHistoryFilter(r)
+	if err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	fills, nextCursor, err := h.svc.GetFills(r.Context(), user.Id, filter)
+	if err != nil {
+		logctx.Warn(r.Context(), "failed GetFills", logger.Error(err), logger.String("userId", user.Id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error getting fills. Try again later")
+		return
+	}
+
+	restutils.WriteJSONResponse(ctx, w, http.StatusOK, FillsResponse{Fills: fills, NextCursor: nextCursor}, logger.String("userId", user.Id.String()))
+}
@@ -0,0 +1,111 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/orbs-network/order-book/transport/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_GetFillsHistory(t *testing.T) {
+	ctx := mocks.AddUserToCtx(nil)
+	swapId := uuid.MustParse("00000000-0000-0000-0000-000000000007")
+
+	tests := []struct {
+		name         string
+		mockService  *mocks.MockOrderBookService
+		url          string
+		expectedCode int
+		expectedBody string
+	}{
+		{"invalid symbol", &mocks.MockOrderBookService{}, "/fills/history?symbol=nope", http.StatusBadRequest, "{\"status\":400,\"msg\":\"'symbol' is not valid\"}\n"},
+		{"invalid side", &mocks.MockOrderBookService{}, "/fills/history?side=up", http.StatusBadRequest, "{\"status\":400,\"msg\":\"'side' is not valid\"}\n"},
+		{"invalid cursor", &mocks.MockOrderBookService{}, "/fills/history?cursor=abc", http.StatusBadRequest, "{\"status\":400,\"msg\":\"'cursor' is not valid\"}\n"},
+		{"invalid limit", &mocks.MockOrderBookService{}, "/fills/history?limit=0", http.StatusBadRequest, "{\"status\":400,\"msg\":\"'limit' must be a positive number\"}\n"},
+		{"invalid startAt", &mocks.MockOrderBookService{}, "/fills/history?startAt=yesterday", http.StatusBadRequest, "{\"status\":400,\"msg\":\"'startAt' must be a timestamp in ms\"}\n"},
+		{"service error", &mocks.MockOrderBookService{Error: assert.AnError}, "/fills/history", http.StatusInternalServerError, "{\"status\":500,\"msg\":\"Error getting fills. Try again later\"}\n"},
+		{"no fills", &mocks.MockOrderBookService{Fills: []models.Fill{}}, "/fills/history?symbol=MATIC-USDC&side=buy&cursor=12-0&limit=5", http.StatusOK, "{\"fills\":[],\"nextCursor\":\"\"}\n"},
+		{"archive cursor", &mocks.MockOrderBookService{Fills: []models.Fill{}}, "/fills/history?cursor=archive:12:" + swapId.String() + ":" + swapId.String(), http.StatusOK, "{\"fills\":[],\"nextCursor\":\"\"}\n"},
+		{
+			"page of fills with next cursor",
+			&mocks.MockOrderBookService{Fills: []models.Fill{{SwapId: swapId, Symbol: "MATIC-USDC", Side: models.BUY}}, NextCursor: "12-0"},
+			"/fills/history",
+			http.StatusOK,
+			"{\"fills\":[{\"orderId\":\"00000000-0000-0000-0000-000000000000\",\"clientOrderId\":\"00000000-0000-0000-0000-000000000000\",\"swapId\":\"00000000-0000-0000-0000-000000000007\",\"side\":\"buy\",\"symbol\":\"MATIC-USDC\",\"mined\":\"0001-01-01T00:00:00Z\",\"resolved\":\"0001-01-01T00:00:00Z\",\"price\":\"0\",\"size\":\"0\",\"orderSize\":\"0\",\"takerFee\":\"0\",\"makerRebate\":\"0\"}],\"nextCursor\":\"12-0\"}\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := mux.NewRouter()
+
+			h, _ := rest.NewHandler(test.mockService, router)
+
+			req, err := http.NewRequest("GET", test.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.HandleFunc("/fills/history", h.GetFillsHistory).Methods("GET")
+
+			router.ServeHTTP(rr, req.WithContext(ctx))
+
+			assert.Equal(t, test.expectedCode, rr.Code)
+			assert.Equal(t, test.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestHandler_GetFills(t *testing.T) {
+	ctx := mocks.AddUserToCtx(nil)
+	swapId := uuid.MustParse("00000000-0000-0000-0000-000000000007")
+
+	tests := []struct {
+		name         string
+		mockService  *mocks.MockOrderBookService
+		url          string
+		expectedCode int
+		expectedBody string
+	}{
+		{"service error", &mocks.MockOrderBookService{Error: assert.AnError}, "/fills", http.StatusInternalServerError, "{\"status\":500,\"msg\":\"Error getting fills. Try again later\"}\n"},
+		{"too many fills", &mocks.MockOrderBookService{Fills: make([]models.Fill, service.MAX_FILLS+1), NextCursor: "12-0"}, "/fills?startAt=1648230000000", http.StatusRequestEntityTooLarge, "{\"status\":413,\"msg\":\"max number of records exceeded, narrow down the range\"}\n"},
+		{"next cursor without too many fills", &mocks.MockOrderBookService{Fills: []models.Fill{}, NextCursor: "12-0"}, "/fills?startAt=1648230000000", http.StatusOK, "[]\n"},
+		{"no fills", &mocks.MockOrderBookService{Fills: []models.Fill{}}, "/fills", http.StatusOK, "[]\n"},
+		{
+			"fills",
+			&mocks.MockOrderBookService{Fills: []models.Fill{{SwapId: swapId, Symbol: "MATIC-USDC", Side: models.BUY}}},
+			"/fills?startAt=1648230000000&endAt=1648233600000",
+			http.StatusOK,
+			"[{\"orderId\":\"00000000-0000-0000-0000-000000000000\",\"clientOrderId\":\"00000000-0000-0000-0000-000000000000\",\"swapId\":\"00000000-0000-0000-0000-000000000007\",\"side\":\"buy\",\"symbol\":\"MATIC-USDC\",\"mined\":\"0001-01-01T00:00:00Z\",\"resolved\":\"0001-01-01T00:00:00Z\",\"price\":\"0\",\"size\":\"0\",\"orderSize\":\"0\",\"takerFee\":\"0\",\"makerRebate\":\"0\"}]\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := mux.NewRouter()
+
+			h, _ := rest.NewHandler(test.mockService, router)
+
+			req, err := http.NewRequest("GET", test.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.HandleFunc("/fills", h.GetFills).Methods("GET")
+
+			router.ServeHTTP(rr, req.WithContext(ctx))
+
+			assert.Equal(t, test.expectedCode, rr.Code)
+			assert.Equal(t, test.expectedBody, rr.Body.String())
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/orbs-network/order-book/utils"
 	"github.com/orbs-network/order-book/utils/logger"
 	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/shopspring/decimal"
 )
 
 type MarketDepthResponse struct {
@@ -60,7 +61,28 @@ func (h *Handler) GetMarketDepth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	marketDepth, err := h.svc.GetMarketDepth(r.Context(), symbol, limit)
+	// L2 price levels by default, L3 for a row per order
+	mode, err := models.StrToDepthMode(r.URL.Query().Get("mode"))
+	if err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Invalid mode: must be L2 or L3")
+		return
+	}
+
+	// optional tick to group L2 price levels by
+	step := decimal.Zero
+	if stepStr := r.URL.Query().Get("step"); stepStr != "" {
+		if mode == models.DEPTH_L3 {
+			restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Step is not supported in L3 mode")
+			return
+		}
+		step, err = decimal.NewFromString(stepStr)
+		if err != nil || !step.IsPositive() {
+			restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Invalid step: must be a positive number")
+			return
+		}
+	}
+
+	marketDepth, err := h.svc.GetMarketDepth(r.Context(), symbol, models.DepthQuery{Mode: mode, Depth: limit, Step: step})
 
 	if err != nil {
 		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error getting market depth. Try again later")
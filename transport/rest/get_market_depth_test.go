@@ -0,0 +1,68 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/transport/rest"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_GetMarketDepth(t *testing.T) {
+	ctx := mocks.AddUserToCtx(nil)
+
+	md := models.MarketDepth{
+		Asks:   [][]decimal.Decimal{{decimal.NewFromInt(2), decimal.NewFromInt(15), decimal.NewFromInt(2)}},
+		Bids:   [][]decimal.Decimal{{decimal.NewFromInt(1), decimal.NewFromInt(4), decimal.NewFromInt(1)}},
+		Symbol: "MATIC-USDC",
+		Time:   1634567890,
+	}
+
+	tests := []struct {
+		name         string
+		mockService  *mocks.MockOrderBookService
+		url          string
+		expectedCode int
+		expectedBody string
+	}{
+		{"invalid symbol", &mocks.MockOrderBookService{}, "/orderbook/nope", http.StatusBadRequest, "{\"status\":400,\"msg\":\"Invalid symbol\"}\n"},
+		{"invalid limit", &mocks.MockOrderBookService{}, "/orderbook/MATIC-USDC?limit=0", http.StatusBadRequest, "{\"status\":400,\"msg\":\"Invalid limit\"}\n"},
+		{"invalid mode", &mocks.MockOrderBookService{}, "/orderbook/MATIC-USDC?mode=L1", http.StatusBadRequest, "{\"status\":400,\"msg\":\"Invalid mode: must be L2 or L3\"}\n"},
+		{"invalid step", &mocks.MockOrderBookService{}, "/orderbook/MATIC-USDC?step=-0.1", http.StatusBadRequest, "{\"status\":400,\"msg\":\"Invalid step: must be a positive number\"}\n"},
+		{"step in L3 mode", &mocks.MockOrderBookService{}, "/orderbook/MATIC-USDC?mode=L3&step=0.1", http.StatusBadRequest, "{\"status\":400,\"msg\":\"Step is not supported in L3 mode\"}\n"},
+		{"service error", &mocks.MockOrderBookService{Error: assert.AnError}, "/orderbook/MATIC-USDC", http.StatusInternalServerError, "{\"status\":500,\"msg\":\"Error getting market depth. Try again later\"}\n"},
+		{
+			"price levels",
+			&mocks.MockOrderBookService{MarketDepth: md},
+			"/orderbook/MATIC-USDC?limit=5&step=0.5",
+			http.StatusOK,
+			"{\"code\":\"OK\",\"data\":{\"asks\":[[\"2\",\"15\",\"2\"]],\"bids\":[[\"1\",\"4\",\"1\"]],\"symbol\":\"MATIC-USDC\",\"time\":1634567890}}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := mux.NewRouter()
+
+			h, _ := rest.NewHandler(test.mockService, router)
+
+			req, err := http.NewRequest("GET", test.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.HandleFunc("/orderbook/{symbol}", h.GetMarketDepth).Methods("GET")
+
+			router.ServeHTTP(rr, req.WithContext(ctx))
+
+			assert.Equal(t, test.expectedCode, rr.Code)
+			assert.Equal(t, test.expectedBody, rr.Body.String())
+		})
+	}
+}
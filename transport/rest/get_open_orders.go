@@ -67,39 +67,3 @@ func (h *Handler) GetOpenOrders(w http.ResponseWriter, r *http.Request) {
 	}
 
 }
-func (h *Handler) GetSwapFills(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	user := utils.GetUserCtx(ctx)
-	if user == nil {
-		logctx.Error(ctx, "user should be in context")
-		restutils.WriteJSONError(ctx, w, http.StatusUnauthorized, "User not found")
-		return
-	}
-
-	logctx.Debug(r.Context(), "user trying to get their filled orders", logger.String("userId", user.Id.String()))
-
-	symbol := models.Symbol("MATIC-USDC") // TODO: Get From Req and propegate
-	startAt, endAt := getStartEndTime(r)
-	fills, err := h.svc.GetSwapFills(r.Context(), user.Id, symbol, startAt, endAt)
-	if err != nil {
-		logctx.Warn(r.Context(), "failed GetSwapFills", logger.Error(err), logger.String("userId", user.Id.String()))
-		switch err {
-		case models.ErrMaxRecExceeded:
-			// narrow down the time range, 256 exceeded
-			restutils.WriteJSONError(ctx, w, http.StatusRequestEntityTooLarge, err.Error())
-		default:
-			restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error getting swaps. Try again later")
-		}
-		return
-	}
-	jsonData, err := json.Marshal(fills)
-	if err != nil {
-		logctx.Error(r.Context(), "failed to Marshal orders", logger.Error(err), logger.String("userId", user.Id.String()))
-		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error Marshalling swap orders.")
-	}
-
-	if _, err := w.Write(jsonData); err != nil {
-		logctx.Error(r.Context(), "failed to write response", logger.Error(err), logger.String("orderId", user.Id.String()))
-		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error write response.")
-	}
-}
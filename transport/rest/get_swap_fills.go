@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/transport/restutils"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+type SwapFillsResponse struct {
+	Fills []models.Fill `json:"fills"`
+}
+
+// GetSwapFills returns the fills of the user's orders in a resolved swap, including archived ones
+func (h *Handler) GetSwapFills(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := utils.GetUserCtx(ctx)
+	if user == nil {
+		logctx.Error(ctx, "user should be in context")
+		restutils.WriteJSONError(ctx, w, http.StatusUnauthorized, "User not found")
+		return
+	}
+
+	swapId, err := uuid.Parse(mux.Vars(r)["swapId"])
+	if err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Invalid swapId")
+		return
+	}
+
+	logctx.Debug(ctx, "user trying to get their fills of a swap", logger.String("userId", user.Id.String()), logger.String("swapId", swapId.String()))
+
+	fills, err := h.svc.GetSwapFills(ctx, user.Id, swapId)
+	if err == models.ErrNotFound {
+		restutils.WriteJSONError(ctx, w, http.StatusNotFound, "Resolved swap not found")
+		return
+	}
+	if err != nil {
+		logctx.Warn(ctx, "failed GetSwapFills", logger.Error(err), logger.String("userId", user.Id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error getting swap fills. Try again later")
+		return
+	}
+
+	restutils.WriteJSONResponse(ctx, w, http.StatusOK, SwapFillsResponse{Fills: fills}, logger.String("userId", user.Id.String()))
+}
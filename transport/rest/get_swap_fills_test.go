@@ -0,0 +1,59 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/transport/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_GetSwapFills(t *testing.T) {
+	ctx := mocks.AddUserToCtx(nil)
+	swapId := uuid.MustParse("00000000-0000-0000-0000-000000000007")
+
+	tests := []struct {
+		name         string
+		mockService  *mocks.MockOrderBookService
+		url          string
+		expectedCode int
+		expectedBody string
+	}{
+		{"invalid swapId", &mocks.MockOrderBookService{}, "/fills/swap/nope", http.StatusBadRequest, "{\"status\":400,\"msg\":\"Invalid swapId\"}\n"},
+		{"swap not found", &mocks.MockOrderBookService{Error: models.ErrNotFound}, "/fills/swap/" + swapId.String(), http.StatusNotFound, "{\"status\":404,\"msg\":\"Resolved swap not found\"}\n"},
+		{"service error", &mocks.MockOrderBookService{Error: assert.AnError}, "/fills/swap/" + swapId.String(), http.StatusInternalServerError, "{\"status\":500,\"msg\":\"Error getting swap fills. Try again later\"}\n"},
+		{
+			"fills of the swap",
+			&mocks.MockOrderBookService{Fills: []models.Fill{{SwapId: swapId, Symbol: "MATIC-USDC", Side: models.BUY}}},
+			"/fills/swap/" + swapId.String(),
+			http.StatusOK,
+			"{\"fills\":[{\"orderId\":\"00000000-0000-0000-0000-000000000000\",\"clientOrderId\":\"00000000-0000-0000-0000-000000000000\",\"swapId\":\"00000000-0000-0000-0000-000000000007\",\"side\":\"buy\",\"symbol\":\"MATIC-USDC\",\"mined\":\"0001-01-01T00:00:00Z\",\"resolved\":\"0001-01-01T00:00:00Z\",\"price\":\"0\",\"size\":\"0\",\"orderSize\":\"0\",\"takerFee\":\"0\",\"makerRebate\":\"0\"}]}\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := mux.NewRouter()
+
+			h, _ := rest.NewHandler(test.mockService, router)
+
+			req, err := http.NewRequest("GET", test.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.HandleFunc("/fills/swap/{swapId}", h.GetSwapFills).Methods("GET")
+
+			router.ServeHTTP(rr, req.WithContext(ctx))
+
+			assert.Equal(t, test.expectedCode, rr.Code)
+			assert.Equal(t, test.expectedBody, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/transport/restutils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+type TradesResponse struct {
+	Symbol     string         `json:"symbol"`
+	Trades     []models.Trade `json:"trades"`
+	NextCursor string         `json:"nextCursor"`
+}
+
+// GetTrades returns the last trades of a symbol, newest first. Public - no user is required
+// optional query params: side, startAt, endAt (ms), cursor and limit
+func (h *Handler) GetTrades(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	symbol, err := models.StrToSymbol(mux.Vars(r)["symbol"])
+	if err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Invalid symbol")
+		return
+	}
+
+	filter, err := parseHistoryFilter(r)
+	if err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.Symbol = symbol
+
+	trades, nextCursor, err := h.svc.GetTrades(ctx, symbol, filter)
+	if err != nil {
+		logctx.Warn(ctx, "failed GetTrades", logger.Error(err), logger.String("symbol", symbol.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error getting trades. Try again later")
+		return
+	}
+
+	restutils.WriteJSONResponse(ctx, w, http.StatusOK, TradesResponse{Symbol: symbol.String(), Trades: trades, NextCursor: nextCursor}, logger.String("symbol", symbol.String()))
+}
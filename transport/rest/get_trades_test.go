@@ -0,0 +1,84 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/transport/rest"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_GetTrades(t *testing.T) {
+	swapId := uuid.MustParse("00000000-0000-0000-0000-000000000007")
+	mined := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	trade := models.Trade{SwapId: swapId, Symbol: "ETH-USDC", Side: models.BUY, Price: decimal.NewFromFloat(2000.5), Size: decimal.NewFromFloat(1.5), Mined: mined, Resolved: mined}
+
+	tests := []struct {
+		name         string
+		mockService  *mocks.MockOrderBookService
+		url          string
+		expectedCode int
+		expectedBody string
+	}{
+		{"invalid symbol", &mocks.MockOrderBookService{}, "/api/v1/trades/nope", http.StatusBadRequest, "{\"status\":400,\"msg\":\"Invalid symbol\"}\n"},
+		{"invalid limit", &mocks.MockOrderBookService{}, "/api/v1/trades/ETH-USDC?limit=-1", http.StatusBadRequest, "{\"status\":400,\"msg\":\"'limit' must be a positive number\"}\n"},
+		{"service error", &mocks.MockOrderBookService{Error: assert.AnError}, "/api/v1/trades/ETH-USDC", http.StatusInternalServerError, "{\"status\":500,\"msg\":\"Error getting trades. Try again later\"}\n"},
+		{
+			"last trades, no API key required",
+			&mocks.MockOrderBookService{Trades: []models.Trade{trade}},
+			"/api/v1/trades/ETH-USDC?limit=1",
+			http.StatusOK,
+			"{\"symbol\":\"ETH-USDC\",\"trades\":[{\"swapId\":\"00000000-0000-0000-0000-000000000007\",\"symbol\":\"ETH-USDC\",\"side\":\"buy\",\"price\":\"2000.5\",\"size\":\"1.5\",\"mined\":\"2024-03-01T12:00:00Z\",\"resolved\":\"2024-03-01T12:00:00Z\"}],\"nextCursor\":\"\"}\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := mux.NewRouter()
+
+			h, _ := rest.NewHandler(test.mockService, router)
+			h.Init(func(ctx context.Context, apiKey string) (*models.User, error) {
+				return nil, models.ErrNotFound
+			})
+
+			req, err := http.NewRequest("GET", test.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedCode, rr.Code)
+			assert.Equal(t, test.expectedBody, rr.Body.String())
+		})
+	}
+
+	t.Run("maker routes still require an API key", func(t *testing.T) {
+		router := mux.NewRouter()
+
+		h, _ := rest.NewHandler(&mocks.MockOrderBookService{}, router)
+		h.Init(func(ctx context.Context, apiKey string) (*models.User, error) {
+			return nil, models.ErrNotFound
+		})
+
+		req, err := http.NewRequest("GET", "/api/v1/symbols", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -5,15 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
 
 	"github.com/gorilla/mux"
 	"github.com/orbs-network/order-book/featureflags"
 	"github.com/orbs-network/order-book/models"
 	"github.com/orbs-network/order-book/service"
 	"github.com/orbs-network/order-book/transport/middleware"
-	"github.com/orbs-network/order-book/transport/restutils"
 	"github.com/orbs-network/order-book/transport/websocket"
+	"github.com/orbs-network/order-book/utils"
 	"github.com/orbs-network/order-book/utils/logger"
 	"github.com/orbs-network/order-book/utils/logger/logctx"
 )
@@ -25,6 +24,7 @@ type Handler struct {
 	okJson          []byte
 	supportedTokens *service.SupportedTokens
 	reactorAddress  string
+	marketHub       *websocket.MarketHub
 }
 type genRes struct {
 	StatusText string `json:"statusText"`
@@ -32,8 +32,6 @@ type genRes struct {
 }
 
 func NewHandler(svc service.OrderBookService, r *mux.Router) (*Handler, error) {
-	var supportedTokensPath = os.Getenv("SUPPORTED_TOKENS_JSON_FILE_PATH")
-
 	if svc == nil {
 		return nil, fmt.Errorf("svc cannot be nil")
 	}
@@ -54,13 +52,8 @@ func NewHandler(svc service.OrderBookService, r *mux.Router) (*Handler, error) {
 		return nil, err
 	}
 
-	if supportedTokensPath == "" {
-		logctx.Warn(context.Background(), "SUPPORTED_TOKENS_JSON_FILE_PATH env var not set, using default")
-		supportedTokensPath = "supportedTokens.json"
-	}
-
 	// load supported tokens
-	st, err := service.NewSupportedTokens(context.Background(), supportedTokensPath)
+	st, err := service.NewSupportedTokensFromEnv(context.Background())
 	if st == nil {
 		logctx.Error(context.Background(), "failed to load supported tokens", logger.Error(err))
 		return nil, err
@@ -72,15 +65,32 @@ func NewHandler(svc service.OrderBookService, r *mux.Router) (*Handler, error) {
 		pairMngr:        models.NewPairMngr(),
 		okJson:          okJson,
 		supportedTokens: st,
-		reactorAddress:  restutils.GetEnv("REACTOR_ADDRESS", "0x4C4B950432189b3283A5111A6963ee318109695c"),
+		reactorAddress:  utils.GetEnv("REACTOR_ADDRESS", "0x4C4B950432189b3283A5111A6963ee318109695c"),
+		marketHub:       websocket.NewMarketHub(svc),
 	}, nil
 }
 
 func (h *Handler) Init(getUserByApiKey middleware.GetUserByApiKeyFunc) {
+	// public routes are registered first, so they are matched before the authenticated "/api/v1" routes
+	h.initPublicRoutes()
 	h.initMakerRoutes(getUserByApiKey)
 	h.initTakerRoutes(getUserByApiKey)
 }
 
+// Public routes, no API key required
+func (h *Handler) initPublicRoutes() {
+	publicApi := h.Router.PathPrefix("/api/v1").Subrouter()
+
+	// Get last trades of a symbol
+	publicApi.HandleFunc("/trades/{symbol}", h.GetTrades).Methods("GET")
+	// Get OHLCV candles of a symbol
+	publicApi.HandleFunc("/candles/{symbol}", h.GetCandles).Methods("GET")
+
+	// ------- WEBSOCKET -------
+	// Subscribe to book and trades of symbols (websocket)
+	h.Router.HandleFunc("/ws/market", websocket.WebSocketMarketHandler(h.marketHub))
+}
+
 // Market Maker specific routes
 func (h *Handler) initMakerRoutes(getUserByApiKey middleware.GetUserByApiKeyFunc) {
 	mmApi := h.Router.PathPrefix("/api/v1").Subrouter()
@@ -93,14 +103,23 @@ func (h *Handler) initMakerRoutes(getUserByApiKey middleware.GetUserByApiKeyFunc
 	// Only users with write permissions can access these routes
 	createApi := mmApi.Methods("POST").Subrouter()
 	createApi.Use(middleware.CheckUserHasPermsMiddleware([]models.UserType{"MARKET_MAKER", "ADMIN"}))
+	updateApi := mmApi.Methods("PUT").Subrouter()
+	updateApi.Use(middleware.CheckUserHasPermsMiddleware([]models.UserType{"MARKET_MAKER", "ADMIN"}))
 	deleteApi := mmApi.Methods("DELETE").Subrouter()
 	deleteApi.Use(middleware.CheckUserHasPermsMiddleware([]models.UserType{"MARKET_MAKER", "ADMIN"}))
+	// Only admins can access these routes
+	adminGetApi := mmApi.Methods("GET").Subrouter()
+	adminGetApi.Use(middleware.CheckUserHasPermsMiddleware([]models.UserType{"ADMIN"}))
 
 	// ------- CREATE -------
 	// Place multiple orders
 	createApi.HandleFunc("/orders", h.CreateOrders).Methods("POST")
 	// Place a new order
 	createApi.HandleFunc("/order", h.CreateOrder).Methods("POST")
+	// Cancel and place orders of a symbol in a single tx
+	createApi.HandleFunc("/orders/replace", h.ReplaceOrders).Methods("POST")
+	// Register a webhook for the user's events
+	createApi.HandleFunc("/webhooks", h.CreateWebhook).Methods("POST")
 
 	// ------- READ -------
 	// Get an order by client order ID
@@ -109,14 +128,30 @@ func (h *Handler) initMakerRoutes(getUserByApiKey middleware.GetUserByApiKeyFunc
 	getApi.HandleFunc("/order/{orderId}", h.GetOrderById)
 	// Get all open orders for a user
 	getApi.HandleFunc("/orders", middleware.PaginationMiddleware(h.GetOpenOrders))
-	// correct way to Get fills using data in swaps
-	getApi.HandleFunc("/fills", h.GetSwapFills)
+	// Get fills of the user in a time range
+	getApi.HandleFunc("/fills", h.GetFills)
+	// Get fills history of the user, paginated
+	getApi.HandleFunc("/fills/history", h.GetFillsHistory)
+	// Get the user's fills of a resolved swap
+	getApi.HandleFunc("/fills/swap/{swapId}", h.GetSwapFills)
 	// Get all symbols
 	getApi.HandleFunc("/symbols", h.GetSymbols)
 	// Get market depth
 	getApi.HandleFunc("/orderbook/{symbol}", h.GetMarketDepth)
 	// Get supported tokens
 	getApi.HandleFunc("/supported-tokens", h.GetSupportedTokens)
+	// Get the user's webhooks
+	getApi.HandleFunc("/webhooks", h.GetWebhooks)
+	// Get the latest delivery attempts of a webhook
+	getApi.HandleFunc("/webhooks/{webhookId}/deliveries", h.GetWebhookDeliveries)
+	// Get the fees collected per token
+	adminGetApi.HandleFunc("/fees", h.GetFeeTotals)
+
+	// ------- UPDATE -------
+	// Amend an existing order by client order ID
+	updateApi.HandleFunc("/order/client-order/{clientOId}", h.AmendOrderByClientOId).Methods("PUT")
+	// Amend an existing order by order ID
+	updateApi.HandleFunc("/order/{orderId}", h.AmendOrderByOrderId).Methods("PUT")
 
 	// ------- DELETE -------
 	// Cancel an existing order by client order ID
@@ -125,10 +160,12 @@ func (h *Handler) initMakerRoutes(getUserByApiKey middleware.GetUserByApiKeyFunc
 	deleteApi.HandleFunc("/order/{orderId}", h.CancelOrderByOrderId).Methods("DELETE")
 	// Cancel all orders for a user
 	deleteApi.HandleFunc("/orders", h.CancelOrdersForUser).Methods("DELETE")
+	// Delete a webhook
+	deleteApi.HandleFunc("/webhooks/{webhookId}", h.DeleteWebhook).Methods("DELETE")
 
 	// ------- WEBSOCKET -------
-	// Subscribe to order events (websocket)
-	getApi.HandleFunc("/ws/orders", websocket.WebSocketOrderHandler(h.svc, getUserByApiKey))
+	// Subscribe to order events, place and cancel orders (websocket)
+	getApi.HandleFunc("/ws/orders", websocket.WebSocketOrderHandler(h.svc, getUserByApiKey, parseWsOrder))
 }
 
 // Liquidity Hub specific routes
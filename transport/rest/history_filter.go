@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+)
+
+// cursors are the stream ids of the last item of the previous page
+// or, once fills are read from the archive, its resolve time, swap ID and order ID
+var historyCursorRegex = regexp.MustCompile(`^(\d+-\d+|archive:\d+:[0-9a-f-]{36}:[0-9a-f-]{36})$`)
+
+// parseHistoryFilter reads the optional symbol, side, startAt, endAt (ms), cursor and limit query params
+func parseHistoryFilter(r *http.Request) (models.HistoryFilter, error) {
+	q := r.URL.Query()
+	filter := models.HistoryFilter{}
+
+	symbolStr := q.Get("symbol")
+	if symbolStr == "" {
+		symbolStr = q.Get("pair")
+	}
+	if symbolStr != "" {
+		symbol, err := models.StrToSymbol(symbolStr)
+		if err != nil {
+			return filter, fmt.Errorf("'symbol' is not valid")
+		}
+		filter.Symbol = symbol
+	}
+
+	if sideStr := q.Get("side"); sideStr != "" {
+		side, err := models.StrToSide(sideStr)
+		if err != nil {
+			return filter, fmt.Errorf("'side' is not valid")
+		}
+		filter.Side = side
+	}
+
+	if startAt := q.Get("startAt"); startAt != "" {
+		t, err := str2time(startAt)
+		if err != nil {
+			return filter, fmt.Errorf("'startAt' must be a timestamp in ms")
+		}
+		filter.StartAt = t
+	}
+
+	if endAt := q.Get("endAt"); endAt != "" {
+		t, err := str2time(endAt)
+		if err != nil {
+			return filter, fmt.Errorf("'endAt' must be a timestamp in ms")
+		}
+		filter.EndAt = t
+	}
+
+	if cursor := q.Get("cursor"); cursor != "" {
+		if !historyCursorRegex.MatchString(cursor) {
+			return filter, fmt.Errorf("'cursor' is not valid")
+		}
+		filter.Cursor = cursor
+	}
+
+	if limitStr := q.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return filter, fmt.Errorf("'limit' must be a positive number")
+		}
+		if limit > service.MAX_FILLS {
+			limit = service.MAX_FILLS
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,280 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/orbs-network/order-book/transport/restutils"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// max cancels and new orders in a single replace request
+const NUM_OF_REPLACE_ITEMS_LIMIT = 50
+
+type ReplaceOrdersRequest struct {
+	Symbol  string                 `json:"symbol"`
+	Cancels []ReplaceCancelRequest `json:"cancels"`
+	Orders  []CreateOrderRequest   `json:"orders"`
+}
+
+// ReplaceCancelRequest identifies an order to cancel by either orderId or clientOrderId
+type ReplaceCancelRequest struct {
+	OrderId       string `json:"orderId,omitempty"`
+	ClientOrderId string `json:"clientOrderId,omitempty"`
+}
+
+type ReplaceItemResult struct {
+	OrderId       string `json:"orderId,omitempty"`
+	ClientOrderId string `json:"clientOrderId,omitempty"`
+	Status        int    `json:"status"`
+	Msg           string `json:"msg,omitempty"`
+}
+
+type ReplaceOrdersResponse struct {
+	Symbol    string              `json:"symbol"`
+	Cancelled []ReplaceItemResult `json:"cancelled"`
+	Created   []ReplaceItemResult `json:"created"`
+	Status    int                 `json:"status"`
+	Msg       string              `json:"msg"`
+}
+
+// ReplaceOrders cancels and places orders of a symbol in a single tx, results are reported per item in request order
+func (h *Handler) ReplaceOrders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := utils.GetUserCtx(ctx)
+	if user == nil {
+		logctx.Error(ctx, "user should be in context")
+		restutils.WriteJSONError(ctx, w, http.StatusUnauthorized, "User not found")
+		return
+	}
+
+	var args ReplaceOrdersRequest
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+
+	symbol, err := models.StrToSymbol(args.Symbol)
+	if err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "'symbol' is not valid")
+		return
+	}
+
+	if len(args.Cancels) == 0 && len(args.Orders) == 0 {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Cancels and orders lists are empty. Ensure you include 'symbol', 'cancels' and 'orders'")
+		return
+	}
+
+	if len(args.Cancels)+len(args.Orders) > NUM_OF_REPLACE_ITEMS_LIMIT {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, fmt.Sprintf("Maximum %d cancels and orders allowed", NUM_OF_REPLACE_ITEMS_LIMIT))
+		return
+	}
+
+	response := ReplaceOrdersResponse{
+		Symbol:    args.Symbol,
+		Cancelled: make([]ReplaceItemResult, len(args.Cancels)),
+		Created:   make([]ReplaceItemResult, len(args.Orders)),
+	}
+
+	input := service.ReplaceOrdersInput{
+		UserId:     user.Id,
+		UserPubKey: user.PubKey,
+		Symbol:     symbol,
+	}
+	// index of each service item in the request
+	cancelIdx := []int{}
+	orderIdx := []int{}
+
+	for i, cancel := range args.Cancels {
+		response.Cancelled[i] = ReplaceItemResult{OrderId: cancel.OrderId, ClientOrderId: cancel.ClientOrderId}
+		cancelInput, err := parseReplaceCancel(cancel)
+		if err != nil {
+			response.Cancelled[i].Status = http.StatusBadRequest
+			response.Cancelled[i].Msg = err.Error()
+			continue
+		}
+		cancelInput.UserId = user.Id
+		input.Cancels = append(input.Cancels, *cancelInput)
+		cancelIdx = append(cancelIdx, i)
+	}
+
+	for i, order := range args.Orders {
+		response.Created[i] = ReplaceItemResult{ClientOrderId: order.ClientOrderId}
+		orderInput, err := parseReplaceOrder(args.Symbol, order)
+		if err != nil {
+			response.Created[i].Status = http.StatusBadRequest
+			response.Created[i].Msg = err.Error()
+			continue
+		}
+		input.Orders = append(input.Orders, *orderInput)
+		orderIdx = append(orderIdx, i)
+	}
+
+	if len(input.Cancels) > 0 || len(input.Orders) > 0 {
+		res, err := h.svc.ReplaceOrders(ctx, input)
+		if err == models.ErrVersionConflict {
+			logctx.Warn(ctx, "orders kept changing while replacing", logger.String("userId", user.Id.String()))
+			restutils.WriteJSONError(ctx, w, http.StatusConflict, "Orders were changed while replacing. Try again")
+			return
+		}
+		if err != nil {
+			logctx.Error(ctx, "failed to replace orders", logger.Error(err), logger.String("userId", user.Id.String()))
+			restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error replacing orders. Try again later")
+			return
+		}
+
+		for j, item := range res.Cancelled {
+			if j >= len(cancelIdx) {
+				break
+			}
+			setReplaceItemResult(&response.Cancelled[cancelIdx[j]], item, http.StatusOK)
+		}
+		for j, item := range res.Created {
+			if j >= len(orderIdx) {
+				break
+			}
+			setReplaceItemResult(&response.Created[orderIdx[j]], item, http.StatusCreated)
+		}
+	}
+
+	failed := 0
+	for _, item := range response.Cancelled {
+		if item.Status != http.StatusOK {
+			failed++
+		}
+	}
+	for _, item := range response.Created {
+		if item.Status != http.StatusCreated {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		logctx.Warn(ctx, "not all replace items were applied", logger.String("userId", user.Id.String()), logger.Int("failed", failed), logger.Int("numOfCancels", len(args.Cancels)), logger.Int("numOfOrders", len(args.Orders)))
+		response.Status = http.StatusMultiStatus
+		response.Msg = fmt.Sprintf("%d of %d items failed", failed, len(args.Cancels)+len(args.Orders))
+		restutils.WriteJSONResponse(ctx, w, http.StatusMultiStatus, response, logger.String("userId", user.Id.String()))
+		return
+	}
+
+	logctx.Debug(ctx, "user replaced orders", logger.String("userId", user.Id.String()), logger.Int("numOfCancels", len(args.Cancels)), logger.Int("numOfOrders", len(args.Orders)))
+	response.Status = http.StatusOK
+	restutils.WriteJSONResponse(ctx, w, http.StatusOK, response, logger.String("userId", user.Id.String()))
+}
+
+func parseReplaceCancel(cancel ReplaceCancelRequest) (*service.CancelOrderInput, error) {
+	if (cancel.OrderId == "") == (cancel.ClientOrderId == "") {
+		return nil, fmt.Errorf("exactly one of 'orderId' or 'clientOrderId' is required")
+	}
+
+	if cancel.OrderId != "" {
+		orderId, err := uuid.Parse(cancel.OrderId)
+		if err != nil {
+			return nil, fmt.Errorf("'orderId' is not valid")
+		}
+		return &service.CancelOrderInput{Id: orderId}, nil
+	}
+
+	clientOId, err := uuid.Parse(cancel.ClientOrderId)
+	if err != nil {
+		return nil, fmt.Errorf("'clientOrderId' is not valid")
+	}
+	return &service.CancelOrderInput{Id: clientOId, IsClientOId: true}, nil
+}
+
+func parseReplaceOrder(symbol string, order CreateOrderRequest) (*service.CreateOrderInput, error) {
+	if order.Symbol == "" {
+		order.Symbol = symbol
+	}
+
+	if err := handleValidateRequiredFields(hVRFArgs{
+		price:         order.Price,
+		size:          order.Size,
+		symbol:        order.Symbol,
+		side:          order.Side,
+		clientOrderId: order.ClientOrderId,
+		eip712Sig:     order.Eip712Sig,
+		eip712Msg:     &order.Eip712Msg,
+	}); err != nil {
+		return nil, err
+	}
+
+	if order.Symbol != symbol {
+		return nil, fmt.Errorf("symbol in order %q does not match symbol in request %q", order.Symbol, symbol)
+	}
+
+	parsedFields, err := parseFields(nil, pFInput{
+		price:          order.Price,
+		size:           order.Size,
+		symbol:         order.Symbol,
+		side:           order.Side,
+		clientOrderId:  order.ClientOrderId,
+		timeInForce:    order.TimeInForce,
+		expireTime:     order.ExpireTime,
+		cancelAfterSec: order.CancelAfterSec,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	abiFragment, err := restutils.ConvertToAbiFragment(order.Eip712Msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse eip712Msg: %w", err)
+	}
+
+	return &service.CreateOrderInput{
+		Price:         parsedFields.roundedDecPrice,
+		Symbol:        parsedFields.symbol,
+		Size:          parsedFields.decSize,
+		Side:          parsedFields.side,
+		ClientOrderID: parsedFields.clientOrderId,
+		Eip712Sig:     order.Eip712Sig,
+		AbiFragment:   abiFragment,
+		TimeInForce:   parsedFields.timeInForce,
+		ExpiresAt:     parsedFields.expiresAt,
+		CancelAfter:   parsedFields.cancelAfter,
+	}, nil
+}
+
+// setReplaceItemResult maps the service result of a single item to its status and message
+func setReplaceItemResult(result *ReplaceItemResult, item service.ReplaceOrderResult, okStatus int) {
+	if item.Order != nil {
+		result.OrderId = item.Order.Id.String()
+		result.ClientOrderId = item.Order.ClientOId.String()
+	}
+
+	switch item.Err {
+	case nil:
+		result.Status = okStatus
+	case models.ErrNotFound:
+		result.Status = http.StatusNotFound
+		result.Msg = "Order not found"
+	case models.ErrUnauthorized:
+		result.Status = http.StatusUnauthorized
+		result.Msg = "Not authorized"
+	case models.ErrOrderCancelled:
+		result.Status = http.StatusConflict
+		result.Msg = "Order already cancelled"
+	case models.ErrOrderFilled:
+		result.Status = http.StatusConflict
+		result.Msg = "Cannot cancel filled order"
+	case models.ErrClashingClientOrderId:
+		result.Status = http.StatusConflict
+		result.Msg = "Order with this clientOrderId already exists"
+	case models.ErrCrossTrade:
+		result.Status = http.StatusConflict
+		result.Msg = item.Err.Error()
+	case models.ErrInvalidInput, models.ErrInvalidSignature, models.ErrSignedOrderMismatch, models.ErrInvalidTimeInForce:
+		result.Status = http.StatusBadRequest
+		result.Msg = item.Err.Error()
+	default:
+		result.Status = http.StatusInternalServerError
+		result.Msg = "Unexpected error. Try again later"
+	}
+}
@@ -0,0 +1,118 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/orbs-network/order-book/transport/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ReplaceOrders(t *testing.T) {
+	orderId := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	clientOId := uuid.MustParse("a677273e-12de-4acc-a4f8-de7fb5b86e37")
+
+	orderReq := rest.CreateOrderRequest{
+		Price:         "100.0",
+		Size:          "10",
+		Symbol:        "MATIC-USDC",
+		Side:          "sell",
+		ClientOrderId: clientOId.String(),
+		Eip712Sig:     "mock-sig",
+		Eip712Msg:     mocks.MsgData,
+	}
+
+	ctx := mocks.AddUserToCtx(nil)
+
+	serve := func(svc *mocks.MockOrderBookService, body interface{}) (*httptest.ResponseRecorder, rest.ReplaceOrdersResponse) {
+		router := mux.NewRouter()
+		h, _ := rest.NewHandler(svc, router)
+		router.HandleFunc("/orders/replace", h.ReplaceOrders).Methods("POST")
+
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := http.NewRequest("POST", "/orders/replace", bytes.NewBuffer(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req.WithContext(ctx))
+
+		var res rest.ReplaceOrdersResponse
+		_ = json.Unmarshal(rr.Body.Bytes(), &res)
+		return rr, res
+	}
+
+	t.Run("invalid symbol", func(t *testing.T) {
+		rr, _ := serve(&mocks.MockOrderBookService{}, rest.ReplaceOrdersRequest{Symbol: "nope", Orders: []rest.CreateOrderRequest{orderReq}})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("empty request", func(t *testing.T) {
+		rr, _ := serve(&mocks.MockOrderBookService{}, rest.ReplaceOrdersRequest{Symbol: "MATIC-USDC"})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("too many items", func(t *testing.T) {
+		cancels := make([]rest.ReplaceCancelRequest, rest.NUM_OF_REPLACE_ITEMS_LIMIT+1)
+		rr, _ := serve(&mocks.MockOrderBookService{}, rest.ReplaceOrdersRequest{Symbol: "MATIC-USDC", Cancels: cancels})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		rr, _ := serve(&mocks.MockOrderBookService{Error: assert.AnError}, rest.ReplaceOrdersRequest{Symbol: "MATIC-USDC", Orders: []rest.CreateOrderRequest{orderReq}})
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	})
+
+	t.Run("all items applied", func(t *testing.T) {
+		svc := &mocks.MockOrderBookService{ReplaceOrdersRes: service.ReplaceOrdersRes{
+			Cancelled: []service.ReplaceOrderResult{{Id: orderId, Order: &models.Order{Id: orderId}}},
+			Created:   []service.ReplaceOrderResult{{Id: clientOId, Order: &models.Order{Id: uuid.New(), ClientOId: clientOId}}},
+		}}
+		rr, res := serve(svc, rest.ReplaceOrdersRequest{
+			Symbol:  "MATIC-USDC",
+			Cancels: []rest.ReplaceCancelRequest{{OrderId: orderId.String()}},
+			Orders:  []rest.CreateOrderRequest{orderReq},
+		})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, http.StatusOK, res.Cancelled[0].Status)
+		assert.Equal(t, orderId.String(), res.Cancelled[0].OrderId)
+		assert.Equal(t, http.StatusCreated, res.Created[0].Status)
+		assert.Equal(t, clientOId.String(), res.Created[0].ClientOrderId)
+	})
+
+	t.Run("invalid and failed items are reported in request order", func(t *testing.T) {
+		svc := &mocks.MockOrderBookService{ReplaceOrdersRes: service.ReplaceOrdersRes{
+			Cancelled: []service.ReplaceOrderResult{{Id: orderId, Err: models.ErrNotFound}},
+			Created:   []service.ReplaceOrderResult{{Id: clientOId, Err: models.ErrCrossTrade}},
+		}}
+		badOrder := orderReq
+		badOrder.Price = "-1"
+		rr, res := serve(svc, rest.ReplaceOrdersRequest{
+			Symbol:  "MATIC-USDC",
+			Cancels: []rest.ReplaceCancelRequest{{}, {OrderId: orderId.String()}},
+			Orders:  []rest.CreateOrderRequest{badOrder, orderReq},
+		})
+
+		assert.Equal(t, http.StatusMultiStatus, rr.Code)
+		assert.Equal(t, http.StatusBadRequest, res.Cancelled[0].Status)
+		assert.Equal(t, "exactly one of 'orderId' or 'clientOrderId' is required", res.Cancelled[0].Msg)
+		assert.Equal(t, http.StatusNotFound, res.Cancelled[1].Status)
+		assert.Equal(t, http.StatusBadRequest, res.Created[0].Status)
+		assert.Equal(t, "'price' must be positive", res.Created[0].Msg)
+		assert.Equal(t, http.StatusConflict, res.Created[1].Status)
+		assert.Equal(t, "4 of 4 items failed", res.Msg)
+	})
+}
@@ -3,10 +3,12 @@ package rest
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/google/uuid"
@@ -38,6 +40,14 @@ type QuoteReq struct {
 	OutToken        string `json:"outToken"`
 	OutTokenAddress string `json:"outTokenAddress"`
 	MinOutAmount    string `json:"minOutAmount"`
+	// exact output instead of inAmount - quote the inAmount required to get outAmount, up to maxInAmount
+	OutAmount   string `json:"outAmount"`
+	MaxInAmount string `json:"maxInAmount"`
+	// quote only - lock the fragments for ttlSec (default FIRM_QUOTE_TTL_SEC) and return a quoteId
+	Firm   bool `json:"firm"`
+	TtlSec int  `json:"ttlSec"`
+	// swap only - swap the locked fragments of a firm quote instead of quoting again
+	QuoteId string `json:"quoteId"`
 }
 
 type QuoteRes struct {
@@ -49,6 +59,39 @@ type QuoteRes struct {
 	AbiCall   string     `json:"abiCall"`
 	Contract  string     `json:"contract"`
 	Fragments []Fragment `json:"fragments"`
+	// firm quotes only, expiresAt in ms
+	QuoteId   string `json:"quoteId,omitempty"`
+	ExpiresAt int64  `json:"expiresAt,omitempty"`
+	// taker fee already taken from outAmount, in the out token
+	Fee string `json:"fee,omitempty"`
+	// routed quotes only, the symbols of the legs in order
+	Route []string `json:"route,omitempty"`
+	// direct quotes only
+	Analytics *QuoteAnalytics `json:"analytics,omitempty"`
+}
+
+// QuoteAnalytics of the fill on the book, prices and sizes are of the symbol like orders, not in token decimals
+type QuoteAnalytics struct {
+	BestPrice      string `json:"bestPrice"`
+	Vwap           string `json:"vwap"`
+	MidPrice       string `json:"midPrice"`
+	PriceImpactBps string `json:"priceImpactBps"`
+	Levels         int    `json:"levels"`
+	RemainingDepth string `json:"remainingDepth"`
+}
+
+func newQuoteAnalytics(analytics models.QuoteAnalytics) *QuoteAnalytics {
+	if analytics.Levels == 0 {
+		return nil
+	}
+	return &QuoteAnalytics{
+		BestPrice:      analytics.BestPrice.String(),
+		Vwap:           analytics.Vwap.String(),
+		MidPrice:       analytics.MidPrice.String(),
+		PriceImpactBps: analytics.PriceImpactBps.String(),
+		Levels:         analytics.Levels,
+		RemainingDepth: analytics.RemainingDepth.String(),
+	}
 }
 
 func (h *Handler) ToTokenBigInt(ctx context.Context, tokenName string, amount decimal.Decimal) *big.Int {
@@ -139,13 +182,13 @@ func (h *Handler) handleQuote(w http.ResponseWriter, r *http.Request, isSwap boo
 		return nil
 	}
 
-	logFields := []logger.Field{logger.Bool("isSwap", isSwap), logger.String("InToken", req.InToken), logger.String("InTokenAddress", req.InTokenAddress), logger.String("InAmount", req.InAmount), logger.String("OutToken", req.OutToken), logger.String("OutTokenAddress", req.OutTokenAddress), logger.String("MinOutAmount", req.MinOutAmount)}
+	logFields := []logger.Field{logger.Bool("isSwap", isSwap), logger.String("InToken", req.InToken), logger.String("InTokenAddress", req.InTokenAddress), logger.String("InAmount", req.InAmount), logger.String("OutToken", req.OutToken), logger.String("OutTokenAddress", req.OutTokenAddress), logger.String("MinOutAmount", req.MinOutAmount), logger.String("OutAmount", req.OutAmount), logger.String("MaxInAmount", req.MaxInAmount)}
 	logctx.Info(ctx, "handleQuote start", logFields...)
 
 	// ensure token names if only addresses were sent
 	err = h.resolveQuoteTokenNames(&req)
 	// refresh log fields now that name been resolved
-	logFields = []logger.Field{logger.Bool("isSwap", isSwap), logger.String("InToken", req.InToken), logger.String("InTokenAddress", req.InTokenAddress), logger.String("InAmount", req.InAmount), logger.String("OutToken", req.OutToken), logger.String("OutTokenAddress", req.OutTokenAddress), logger.String("MinOutAmount", req.MinOutAmount)}
+	logFields = []logger.Field{logger.Bool("isSwap", isSwap), logger.String("InToken", req.InToken), logger.String("InTokenAddress", req.InTokenAddress), logger.String("InAmount", req.InAmount), logger.String("OutToken", req.OutToken), logger.String("OutTokenAddress", req.OutTokenAddress), logger.String("MinOutAmount", req.MinOutAmount), logger.String("OutAmount", req.OutAmount), logger.String("MaxInAmount", req.MaxInAmount)}
 
 	if err != nil {
 		logctx.Warn(ctx, "handleQuote Failed to resolveQuoteTokenNames", append(logFields, logger.Error(err))...)
@@ -153,7 +196,22 @@ func (h *Handler) handleQuote(w http.ResponseWriter, r *http.Request, isSwap boo
 		return nil
 	}
 
-	inAmount, err := h.convertFromTokenDec(ctx, req.InToken, req.InAmount)
+	if isSwap && req.QuoteId != "" {
+		return h.handleFirmSwap(w, r, req, logFields)
+	}
+
+	isExactOut := req.OutAmount != ""
+	if isExactOut && req.InAmount != "" {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "only one of 'inAmount' and 'outAmount' can be sent", logFields...)
+		return nil
+	}
+
+	// the fixed amount, in the in token or the out token for exact output
+	amountToken, amountStr := req.InToken, req.InAmount
+	if isExactOut {
+		amountToken, amountStr = req.OutToken, req.OutAmount
+	}
+	amount, err := h.convertFromTokenDec(ctx, amountToken, amountStr)
 
 	if err != nil {
 		logctx.Warn(ctx, "handleQuote Failed to convertFromTokenDec", append(logFields, logger.Error(err))...)
@@ -171,28 +229,57 @@ func (h *Handler) handleQuote(w http.ResponseWriter, r *http.Request, isSwap boo
 			minOutAmount = &convMinOutAmount
 		}
 	}
+	// a threshold for max amount in of exact output
+	var maxInAmount *decimal.Decimal = nil
+	if req.MaxInAmount != "" {
+		convMaxInAmount, err := h.convertFromTokenDec(ctx, req.InToken, req.MaxInAmount)
+		if err != nil {
+			restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "'maxInAmount' is not a valid number", logFields...)
+			return nil
+		}
+		maxInAmount = &convMaxInAmount
+	}
 
+	var svcQuoteRes models.QuoteRes
 	pair := h.pairMngr.Resolve(req.InToken, req.OutToken)
 	if pair == nil {
-		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "no suppoerted pair was found for tokens", logFields...)
-		return nil
-	}
-	// add symbol to all log fields
-	logFields = append(logFields, logger.String("symbol", pair.String()))
-
-	// taker's in token to maker's side
-	makerSide := pair.GetMakerSide(req.InToken)
+		// no direct pair - route through an intermediate token
+		routes := h.pairMngr.Routes(req.InToken, req.OutToken)
+		if len(routes) == 0 {
+			restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "no suppoerted pair was found for tokens", logFields...)
+			return nil
+		}
+		if isExactOut {
+			restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "'outAmount' is not supported for tokens with no direct pair", logFields...)
+			return nil
+		}
+		if !h.setRouteMakerInTokens(routes) {
+			restutils.WriteJSONError(ctx, w, http.StatusBadRequest, models.ErrTokenNotsupported.Error(), logFields...)
+			return nil
+		}
+		svcQuoteRes, err = h.svc.GetRouteQuote(r.Context(), routes, amount, minOutAmount)
+	} else {
+		// add symbol to all log fields
+		logFields = append(logFields, logger.String("symbol", pair.String()))
+
+		// taker's in token to maker's side
+		makerSide := pair.GetMakerSide(req.InToken)
+
+		// resolve makerInAddress to verify balance on-chain
+		makerInAdrs := req.OutTokenAddress
+		if makerInAdrs == "" {
+			makerInAdrs = h.supportedTokens.ByName(req.OutToken).Address
+		}
 
-	// resolve makerInAddress to verify balance on-chain
-	makerInAdrs := req.OutTokenAddress
-	if makerInAdrs == "" {
-		makerInAdrs = h.supportedTokens.ByName(req.OutToken).Address
+		// ALWAYS reverese decimals tp meet the makers order's side
+		if isExactOut {
+			svcQuoteRes, err = h.svc.GetQuoteForOut(r.Context(), pair.Symbol(), makerSide, amount, maxInAmount, makerInAdrs)
+		} else {
+			svcQuoteRes, err = h.svc.GetQuote(r.Context(), pair.Symbol(), makerSide, amount, minOutAmount, makerInAdrs)
+		}
 	}
-
-	// ALWAYS reverese decimals tp meet the makers order's side
-	svcQuoteRes, err := h.svc.GetQuote(r.Context(), pair.Symbol(), makerSide, inAmount, minOutAmount, makerInAdrs)
 	if err != nil {
-		if err == models.ErrMinOutAmount {
+		if err == models.ErrMinOutAmount || err == models.ErrMaxInAmount {
 			restutils.WriteJSONError(ctx, w, http.StatusBadRequest, err.Error(), logFields...)
 		} else if err == models.ErrInsufficientBalance {
 			restutils.WriteJSONError(ctx, w, http.StatusConflict, err.Error(), logFields...)
@@ -215,10 +302,46 @@ func (h *Handler) handleQuote(w http.ResponseWriter, r *http.Request, isSwap boo
 		InToken:   req.InToken,
 		//SwapId:    "",
 		Fragments: []Fragment{},
+		Route:     routeSymbols(svcQuoteRes.Legs),
+		Analytics: newQuoteAnalytics(svcQuoteRes.Analytics),
+	}
+	if svcQuoteRes.Fee.IsPositive() {
+		res.Fee = h.ToTokenBigInt(r.Context(), req.OutToken, svcQuoteRes.Fee).String()
+	}
+	if isExactOut {
+		res.OutAmount = req.OutAmount
+		convInAmount := h.ToTokenBigInt(r.Context(), req.InToken, svcQuoteRes.InAmount)
+		if convInAmount == nil {
+			restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "convInAmount return empty string")
+			return nil
+		}
+		res.InAmount = convInAmount.String()
 	}
 
 	logctx.Debug(ctx, "QuoteRes OK", logFields...)
 
+	if req.Firm && !isSwap {
+		if req.TtlSec < 0 {
+			restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "'ttlSec' must be a positive number", logFields...)
+			return nil
+		}
+		// lock liquidity until the quote is swapped or expires
+		firmQuote, err := h.svc.LockQuote(r.Context(), svcQuoteRes, time.Duration(req.TtlSec)*time.Second)
+		if errors.Is(err, models.ErrInvalidInput) {
+			restutils.WriteJSONError(ctx, w, http.StatusBadRequest, err.Error(), logFields...)
+			return nil
+		} else if err == models.ErrSwapInvalid {
+			restutils.WriteJSONError(ctx, w, http.StatusConflict, err.Error(), logFields...)
+			return nil
+		} else if err != nil {
+			restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, err.Error(), logFields...)
+			return nil
+		}
+		res.QuoteId = firmQuote.Id.String()
+		res.ExpiresAt = firmQuote.ExpiresAt.UnixMilli()
+		logctx.Info(ctx, "LockQuote OK", append(logFields, logger.String("quoteId", res.QuoteId))...)
+	}
+
 	if isSwap {
 		logctx.Info(ctx, "BeginSwap", logFields...)
 		// lock liquidity
@@ -228,60 +351,165 @@ func (h *Handler) handleQuote(w http.ResponseWriter, r *http.Request, isSwap boo
 			return nil
 		}
 
-		res.SwapId = swapData.SwapId.String()
-		logFields = append(logFields, logger.String("swapId", res.SwapId))
-		logctx.Info(ctx, "BeginSwap OK", logFields...)
+		if !h.writeSwapFragments(w, r, &res, swapData, logFields) {
+			return nil
+		}
+	}
+
+	restutils.WriteJSONResponse(r.Context(), w, http.StatusOK, res)
+	logctx.Info(ctx, "handleQuote end OK", logFields...)
+	return &res
+}
+
+// setRouteMakerInTokens sets the maker's in token address of each leg, returns false if a token is not supported
+func (h *Handler) setRouteMakerInTokens(routes [][]models.RouteLeg) bool {
+	for _, route := range routes {
+		for i := range route {
+			_, takerOutToken := route[i].TakerTokens()
+			token := h.supportedTokens.ByName(takerOutToken)
+			if token == nil {
+				return false
+			}
+			route[i].MakerInToken = token.Address
+		}
+	}
+	return true
+}
+
+// routeSymbols returns the symbols of a route's legs, nil for a direct quote
+func routeSymbols(legs []models.RouteLeg) []string {
+	if len(legs) < 2 {
+		return nil
+	}
+	symbols := make([]string, 0, len(legs))
+	for _, leg := range legs {
+		symbols = append(symbols, leg.Symbol.String())
+	}
+	return symbols
+}
 
-		signedOrders := []abi.SignedOrder{}
+// writeSwapFragments adds the signed fragments and the abi call of a locked swap to res, returns false if it wrote an error
+// the amounts of each fragment are in the taker's tokens of its order's leg
+func (h *Handler) writeSwapFragments(w http.ResponseWriter, r *http.Request, res *QuoteRes, swapData models.BeginSwapRes, logFields []logger.Field) bool {
+	ctx := r.Context()
 
-		for i := 0; i < len(swapData.Fragments); i++ {
-			// Maker In Amount is Taker's OutAmount!
+	res.SwapId = swapData.SwapId.String()
+	logFields = append(logFields, logger.String("swapId", res.SwapId))
+	logctx.Info(ctx, "BeginSwap OK", logFields...)
 
-			// conver In/Out amount to token decimals
-			// convert to sol's big int and floor (reduce precision here)
-			takerInAmount := h.ToTokenBigInt(r.Context(), req.InToken, swapData.Fragments[i].InSize)
-			takerOutAmount := h.ToTokenBigInt(r.Context(), req.OutToken, swapData.Fragments[i].OutSize)
+	signedOrders := []abi.SignedOrder{}
 
-			abiOrder := swapData.Orders[i].Signature.AbiFragment
-			abiOrder.ExclusivityOverrideBps = big.NewInt(0)
+	for i := 0; i < len(swapData.Fragments); i++ {
+		// Maker In Amount is Taker's OutAmount!
 
-			if len(abiOrder.Outputs) == 0 {
-				restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "abiOrder.Outputs length is 0", logFields...)
-				return nil
-			}
+		// conver In/Out amount to token decimals
+		// convert to sol's big int and floor (reduce precision here)
+		takerInToken, takerOutToken := models.RouteLegsOf(swapData.Orders[i : i+1])[0].TakerTokens()
+		takerInAmount := h.ToTokenBigInt(r.Context(), takerInToken, swapData.Fragments[i].InSize)
+		takerOutAmount := h.ToTokenBigInt(r.Context(), takerOutToken, swapData.Fragments[i].OutSize)
+		if takerInAmount == nil || takerOutAmount == nil {
+			restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, models.ErrTokenNotsupported.Error(), logFields...)
+			return false
+		}
 
-			// create signed order with amount
-			frag := Fragment{
-				Signature:      swapData.Orders[i].Signature.Eip712Sig,
-				AbiOrder:       abiOrder,
-				TakerInAmount:  takerInAmount.String(),
-				TakerOutAmount: takerOutAmount.String(),
-			}
-			res.Fragments = append(res.Fragments, frag)
-			// signed order + out amount from the maker's/order side
-			signedOrder := abi.SignedOrder{
-				OrderWithAmount: abi.OrderWithAmount{
-					Order:  abiOrder,
-					Amount: takerInAmount, // is what the taker requested to swap for this frag
-				},
-				Signature: Signature2Bytes(swapData.Orders[i].Signature.Eip712Sig),
-			}
-			signedOrders = append(signedOrders, signedOrder)
-			// MakerInAmount == takerOutAmount
-			logctx.Debug(ctx, "append swap fragment", logger.String("swapId", res.SwapId), logger.Int("fragIndex", i), logger.String("TakerInAmount", frag.TakerInAmount), logger.String("takerOutAmount", takerOutAmount.String()))
+		abiOrder := swapData.Orders[i].Signature.AbiFragment
+		abiOrder.ExclusivityOverrideBps = big.NewInt(0)
+
+		if len(abiOrder.Outputs) == 0 {
+			restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "abiOrder.Outputs length is 0", logFields...)
+			return false
 		}
-		// abi encode
-		abiCall, err := abi.PackSignedOrders(ctx, signedOrders)
-		if err != nil {
-			restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, err.Error())
-			return nil
+
+		// create signed order with amount
+		frag := Fragment{
+			Signature:      swapData.Orders[i].Signature.Eip712Sig,
+			AbiOrder:       abiOrder,
+			TakerInAmount:  takerInAmount.String(),
+			TakerOutAmount: takerOutAmount.String(),
+		}
+		res.Fragments = append(res.Fragments, frag)
+		// signed order + out amount from the maker's/order side
+		signedOrder := abi.SignedOrder{
+			OrderWithAmount: abi.OrderWithAmount{
+				Order:  abiOrder,
+				Amount: takerInAmount, // is what the taker requested to swap for this frag
+			},
+			Signature: Signature2Bytes(swapData.Orders[i].Signature.Eip712Sig),
 		}
-		res.AbiCall = fmt.Sprintf("0x%x", abiCall)
-		res.Contract = h.reactorAddress
+		signedOrders = append(signedOrders, signedOrder)
+		// MakerInAmount == takerOutAmount
+		logctx.Debug(ctx, "append swap fragment", logger.String("swapId", res.SwapId), logger.Int("fragIndex", i), logger.String("TakerInAmount", frag.TakerInAmount), logger.String("takerOutAmount", takerOutAmount.String()))
 	}
+	// abi encode
+	abiCall, err := abi.PackSignedOrders(ctx, signedOrders)
+	if err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, err.Error())
+		return false
+	}
+	res.AbiCall = fmt.Sprintf("0x%x", abiCall)
+	res.Contract = h.reactorAddress
+	return true
+}
 
-	restutils.WriteJSONResponse(r.Context(), w, http.StatusOK, res)
-	logctx.Info(ctx, "handleQuote end OK", logFields...)
+// handleFirmSwap swaps the locked fragments of a firm quote, the amounts are the quote's
+func (h *Handler) handleFirmSwap(w http.ResponseWriter, r *http.Request, req QuoteReq, logFields []logger.Field) *QuoteRes {
+	ctx := r.Context()
+	logFields = append(logFields, logger.String("quoteId", req.QuoteId))
+
+	quoteId, err := uuid.Parse(req.QuoteId)
+	if err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "invalid quoteId", logFields...)
+		return nil
+	}
+
+	logctx.Info(ctx, "BeginSwapFromQuote", logFields...)
+	swapData, err := h.svc.BeginSwapFromQuote(ctx, quoteId, req.InToken, req.OutToken)
+	switch err {
+	case nil:
+	case models.ErrNotFound:
+		restutils.WriteJSONError(ctx, w, http.StatusNotFound, "quote not found", logFields...)
+		return nil
+	case models.ErrQuoteMismatch:
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, err.Error(), logFields...)
+		return nil
+	case models.ErrQuoteExpired, models.ErrSwapInvalid:
+		restutils.WriteJSONError(ctx, w, http.StatusConflict, err.Error(), logFields...)
+		return nil
+	default:
+		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, err.Error(), logFields...)
+		return nil
+	}
+
+	// the taker's in amount of the quote is the sum of the fragments of its first leg
+	legs := models.RouteLegsOf(swapData.Orders)
+	inAmount := decimal.Zero
+	for i, frag := range swapData.Fragments {
+		if len(legs) > 0 && swapData.Orders[i].Symbol == legs[0].Symbol && swapData.Orders[i].Side == legs[0].MakerSide {
+			inAmount = inAmount.Add(frag.InSize)
+		}
+	}
+	convInAmount := h.ToTokenBigInt(ctx, req.InToken, inAmount)
+	convOutAmount := h.ToTokenBigInt(ctx, req.OutToken, swapData.OutAmount)
+	if convInAmount == nil || convOutAmount == nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "convOutAmount return empty string")
+		return nil
+	}
+
+	res := QuoteRes{
+		OutAmount: convOutAmount.String(),
+		OutToken:  req.OutToken,
+		InAmount:  convInAmount.String(),
+		InToken:   req.InToken,
+		QuoteId:   req.QuoteId,
+		Fragments: []Fragment{},
+		Route:     routeSymbols(legs),
+	}
+	if !h.writeSwapFragments(w, r, &res, swapData, logFields) {
+		return nil
+	}
+
+	restutils.WriteJSONResponse(ctx, w, http.StatusOK, res)
+	logctx.Info(ctx, "handleFirmSwap end OK", logFields...)
 	return &res
 }
 
@@ -0,0 +1,186 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/orbs-network/order-book/transport/restutils"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+type CreateWebhookRequest struct {
+	Url string `json:"url"`
+}
+
+// WebhookResponse leaves out the secret, which is only returned when the webhook is created
+type WebhookResponse struct {
+	Id            string    `json:"id"`
+	Url           string    `json:"url"`
+	Created       time.Time `json:"created"`
+	LastSeq       int64     `json:"lastSeq"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"nextAttemptAt"`
+}
+
+type CreateWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+type WebhooksResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+type WebhookDeliveriesResponse struct {
+	Deliveries []models.WebhookDelivery `json:"deliveries"`
+}
+
+func newWebhookResponse(webhook models.Webhook) WebhookResponse {
+	return WebhookResponse{
+		Id:            webhook.Id.String(),
+		Url:           webhook.Url,
+		Created:       webhook.Created,
+		LastSeq:       webhook.LastSeq,
+		Attempts:      webhook.Attempts,
+		NextAttemptAt: webhook.NextAttemptAt,
+	}
+}
+
+func (h *Handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := utils.GetUserCtx(ctx)
+	if user == nil {
+		logctx.Error(ctx, "user should be in context")
+		restutils.WriteJSONError(ctx, w, http.StatusUnauthorized, "User not found")
+		return
+	}
+
+	var args CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if args.Url == "" {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "missing required field 'url'")
+		return
+	}
+
+	logctx.Debug(ctx, "user trying to create webhook", logger.String("userId", user.Id.String()))
+
+	webhook, err := h.svc.CreateWebhook(ctx, user.Id, args.Url)
+	switch err {
+	case nil:
+		restutils.WriteJSONResponse(ctx, w, http.StatusCreated, CreateWebhookResponse{WebhookResponse: newWebhookResponse(webhook), Secret: webhook.Secret}, logger.String("userId", user.Id.String()))
+	case models.ErrInvalidWebhookUrl, models.ErrTooManyWebhooks:
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, err.Error())
+	default:
+		logctx.Warn(ctx, "failed CreateWebhook", logger.Error(err), logger.String("userId", user.Id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error creating webhook. Try again later")
+	}
+}
+
+func (h *Handler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := utils.GetUserCtx(ctx)
+	if user == nil {
+		logctx.Error(ctx, "user should be in context")
+		restutils.WriteJSONError(ctx, w, http.StatusUnauthorized, "User not found")
+		return
+	}
+
+	webhooks, err := h.svc.GetWebhooks(ctx, user.Id)
+	if err != nil {
+		logctx.Warn(ctx, "failed GetWebhooks", logger.Error(err), logger.String("userId", user.Id.String()))
+		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, "Error getting webhooks. Try again later")
+		return
+	}
+
+	res := WebhooksResponse{Webhooks: make([]WebhookResponse, 0, len(webhooks))}
+	for _, webhook := range webhooks {
+		res.Webhooks = append(res.Webhooks, newWebhookResponse(webhook))
+	}
+	restutils.WriteJSONResponse(ctx, w, http.StatusOK, res, logger.String("userId", user.Id.String()))
+}
+
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := utils.GetUserCtx(ctx)
+	if user == nil {
+		logctx.Error(ctx, "user should be in context")
+		restutils.WriteJSONError(ctx, w, http.StatusUnauthorized, "User not found")
+		return
+	}
+
+	webhookId, err := uuid.Parse(mux.Vars(r)["webhookId"])
+	if err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	err = h.svc.DeleteWebhook(ctx, user.Id, webhookId)
+	if err != nil {
+		h.writeWebhookError(w, r, err, "Error deleting webhook. Try again later")
+		return
+	}
+
+	restutils.WriteJSONResponse(ctx, w, http.StatusOK, WebhookResponse{Id: webhookId.String()}, logger.String("userId", user.Id.String()))
+}
+
+// GetWebhookDeliveries returns the latest delivery attempts of a webhook, newest first
+// optional query param: limit (default 100, max 1000)
+func (h *Handler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := utils.GetUserCtx(ctx)
+	if user == nil {
+		logctx.Error(ctx, "user should be in context")
+		restutils.WriteJSONError(ctx, w, http.StatusUnauthorized, "User not found")
+		return
+	}
+
+	webhookId, err := uuid.Parse(mux.Vars(r)["webhookId"])
+	if err != nil {
+		restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	limit := 100
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > service.MAX_WEBHOOK_DELIVERIES {
+			restutils.WriteJSONError(ctx, w, http.StatusBadRequest, "'limit' must be between 1 and 1000")
+			return
+		}
+	}
+
+	deliveries, err := h.svc.GetWebhookDeliveries(ctx, user.Id, webhookId, limit)
+	if err != nil {
+		h.writeWebhookError(w, r, err, "Error getting webhook deliveries. Try again later")
+		return
+	}
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+
+	restutils.WriteJSONResponse(ctx, w, http.StatusOK, WebhookDeliveriesResponse{Deliveries: deliveries}, logger.String("userId", user.Id.String()))
+}
+
+func (h *Handler) writeWebhookError(w http.ResponseWriter, r *http.Request, err error, internalMsg string) {
+	ctx := r.Context()
+	switch err {
+	case models.ErrNotFound:
+		restutils.WriteJSONError(ctx, w, http.StatusNotFound, "Webhook not found")
+	case models.ErrUnauthorized:
+		restutils.WriteJSONError(ctx, w, http.StatusUnauthorized, "Not authorized")
+	default:
+		logctx.Warn(ctx, "failed webhook request", logger.Error(err))
+		restutils.WriteJSONError(ctx, w, http.StatusInternalServerError, internalMsg)
+	}
+}
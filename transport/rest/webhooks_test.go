@@ -0,0 +1,65 @@
+package rest_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/transport/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Webhooks(t *testing.T) {
+	ctx := mocks.AddUserToCtx(nil)
+	webhook := models.Webhook{Id: uuid.MustParse("00000000-0000-0000-0000-000000000009"), Url: "https://mm.example.com/events", Secret: "abc", LastSeq: 3}
+	webhookJson := "\"id\":\"00000000-0000-0000-0000-000000000009\",\"url\":\"https://mm.example.com/events\",\"created\":\"0001-01-01T00:00:00Z\",\"lastSeq\":3,\"attempts\":0,\"nextAttemptAt\":\"0001-01-01T00:00:00Z\""
+
+	tests := []struct {
+		name         string
+		mockService  *mocks.MockOrderBookService
+		method       string
+		url          string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"create webhook returns the secret", &mocks.MockOrderBookService{Webhook: webhook}, "POST", "/webhooks", `{"url":"https://mm.example.com/events"}`, http.StatusCreated, "{" + webhookJson + ",\"secret\":\"abc\"}\n"},
+		{"create webhook without url", &mocks.MockOrderBookService{}, "POST", "/webhooks", `{}`, http.StatusBadRequest, "{\"status\":400,\"msg\":\"missing required field 'url'\"}\n"},
+		{"create webhook with invalid url", &mocks.MockOrderBookService{Error: models.ErrInvalidWebhookUrl}, "POST", "/webhooks", `{"url":"http://mm"}`, http.StatusBadRequest, "{\"status\":400,\"msg\":\"webhook url must be a valid https url\"}\n"},
+		{"get webhooks leaves out the secret", &mocks.MockOrderBookService{Webhooks: []models.Webhook{webhook}}, "GET", "/webhooks", "", http.StatusOK, "{\"webhooks\":[{" + webhookJson + "}]}\n"},
+		{"delete webhook of another user", &mocks.MockOrderBookService{Error: models.ErrUnauthorized}, "DELETE", "/webhooks/" + webhook.Id.String(), "", http.StatusUnauthorized, "{\"status\":401,\"msg\":\"Not authorized\"}\n"},
+		{"delete webhook with invalid id", &mocks.MockOrderBookService{}, "DELETE", "/webhooks/abc", "", http.StatusBadRequest, "{\"status\":400,\"msg\":\"Invalid webhook ID\"}\n"},
+		{"deliveries of unknown webhook", &mocks.MockOrderBookService{Error: models.ErrNotFound}, "GET", "/webhooks/" + webhook.Id.String() + "/deliveries", "", http.StatusNotFound, "{\"status\":404,\"msg\":\"Webhook not found\"}\n"},
+		{"deliveries with invalid limit", &mocks.MockOrderBookService{}, "GET", "/webhooks/" + webhook.Id.String() + "/deliveries?limit=1001", "", http.StatusBadRequest, "{\"status\":400,\"msg\":\"'limit' must be between 1 and 1000\"}\n"},
+		{"no deliveries", &mocks.MockOrderBookService{}, "GET", "/webhooks/" + webhook.Id.String() + "/deliveries", "", http.StatusOK, "{\"deliveries\":[]}\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := mux.NewRouter()
+
+			h, _ := rest.NewHandler(test.mockService, router)
+
+			req, err := http.NewRequest(test.method, test.url, bytes.NewBufferString(test.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			router.HandleFunc("/webhooks", h.CreateWebhook).Methods("POST")
+			router.HandleFunc("/webhooks", h.GetWebhooks).Methods("GET")
+			router.HandleFunc("/webhooks/{webhookId}", h.DeleteWebhook).Methods("DELETE")
+			router.HandleFunc("/webhooks/{webhookId}/deliveries", h.GetWebhookDeliveries).Methods("GET")
+
+			router.ServeHTTP(rr, req.WithContext(ctx))
+
+			assert.Equal(t, test.expectedCode, rr.Code)
+			assert.Equal(t, test.expectedBody, rr.Body.String())
+		})
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"os"
 
 	"github.com/orbs-network/order-book/utils/logger"
 	"github.com/orbs-network/order-book/utils/logger/logctx"
@@ -57,11 +56,3 @@ func WriteJSONError(ctx context.Context, w http.ResponseWriter, status int, mess
 	logFields = append(logFields, logger.String("status", http.StatusText(status)))
 	logctx.Warn(ctx, "api request not successful", logFields...)
 }
-
-// read os env var with default
-func GetEnv(key, fallback string) string {
-	if value, ok := os.LookupEnv(key); ok {
-		return value
-	}
-	return fallback
-}
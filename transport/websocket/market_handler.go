@@ -0,0 +1,78 @@
+package websocket
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// WebSocketMarketHandler returns a public handler that upgrades the connection to WebSocket and streams book and trades of the symbols the client subscribes to
+// Clients send {"op": "subscribe"|"unsubscribe", "symbol": "ETH-USDC", "channel": "book"|"trades"}
+func WebSocketMarketHandler(hub *MarketHub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Upgrade to WebSocket
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			logctx.Error(ctx, "error upgrading to websocket", logger.Error(err))
+			return
+		}
+		defer conn.Close()
+
+		if err := conn.SetReadDeadline(time.Now().Add(120 * time.Second)); err != nil {
+			logctx.Error(ctx, "error setting initial read deadline", logger.Error(err))
+			return
+		}
+		conn.SetPongHandler(func(appData string) error {
+			if err := conn.SetReadDeadline(time.Now().Add(120 * time.Second)); err != nil {
+				logctx.Error(ctx, "error extending read deadline", logger.Error(err))
+				// Not returning an error here because the connection is still valid
+			}
+			return nil
+		})
+
+		client := hub.NewClient()
+		defer hub.RemoveClient(ctx, client)
+
+		// Read commands until the client disconnects
+		go func() {
+			defer client.Close()
+			for {
+				_, data, err := conn.ReadMessage()
+				if err != nil {
+					logctx.Debug(ctx, "market websocket read ended", logger.Error(err))
+					return
+				}
+				hub.HandleCommand(ctx, client, data)
+			}
+		}()
+
+		ticker := time.NewTicker(60 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case msg := <-client.send:
+				if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+					logctx.Warn(ctx, "unable to write to websocket", logger.Error(err))
+					return
+				}
+			case <-ticker.C:
+				if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+					logctx.Error(ctx, "error sending ping", logger.Error(err))
+					return
+				}
+			case <-client.done:
+				return
+			case <-ctx.Done():
+				logctx.Info(ctx, "request context cancelled")
+				return
+			}
+		}
+	}
+}
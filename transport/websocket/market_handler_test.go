@@ -0,0 +1,108 @@
+package websocket
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+)
+
+func TestWebSocketMarketHandler(t *testing.T) {
+	level := func(price, size, count int64) []decimal.Decimal {
+		return []decimal.Decimal{decimal.NewFromInt(price), decimal.NewFromInt(size), decimal.NewFromInt(count)}
+	}
+
+	dial := func(t *testing.T, svc *mocks.MockOrderBookService) *websocket.Conn {
+		server := httptest.NewServer(WebSocketMarketHandler(NewMarketHub(svc)))
+		t.Cleanup(server.Close)
+
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+server.URL[len("http"):], nil)
+		assert.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	send := func(t *testing.T, conn *websocket.Conn, cmd MarketCommand) {
+		assert.NoError(t, conn.WriteJSON(cmd))
+	}
+	read := func(t *testing.T, conn *websocket.Conn) map[string]interface{} {
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		msg := map[string]interface{}{}
+		assert.NoError(t, conn.ReadJSON(&msg))
+		return msg
+	}
+	publish := func(svc *mocks.MockOrderBookService, event models.MarketEvent) {
+		data, _ := json.Marshal(event)
+		svc.MarketEvents <- data
+	}
+
+	t.Run("should send book snapshot followed by sequenced level updates", func(t *testing.T) {
+		svc := &mocks.MockOrderBookService{
+			MarketEvents: make(chan []byte, 10),
+			MarketDepth:  models.MarketDepth{Symbol: "ETH-USDC", Asks: [][]decimal.Decimal{level(2000, 1, 1)}, Bids: [][]decimal.Decimal{level(1990, 2, 1)}},
+		}
+		conn := dial(t, svc)
+
+		send(t, conn, MarketCommand{Op: "subscribe", Symbol: "ETH-USDC", Channel: MARKET_CHANNEL_BOOK})
+
+		msg := read(t, conn)
+		assert.Equal(t, "subscribed", msg["type"])
+
+		msg = read(t, conn)
+		assert.Equal(t, "snapshot", msg["type"])
+		assert.Equal(t, float64(1), msg["seq"])
+		assert.Equal(t, []interface{}{[]interface{}{"2000", "1", "1"}}, msg["asks"])
+
+		// a second order at the best ask
+		svc.MarketDepth = models.MarketDepth{Symbol: "ETH-USDC", Asks: [][]decimal.Decimal{level(2000, 3, 2)}, Bids: [][]decimal.Decimal{level(1990, 2, 1)}}
+		publish(svc, models.MarketEvent{Event: models.MARKET_EVENT_BOOK_CHANGE, Symbol: "ETH-USDC", Side: models.SELL})
+
+		msg = read(t, conn)
+		assert.Equal(t, "update", msg["type"])
+		assert.Equal(t, float64(2), msg["seq"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"side": "sell", "price": "2000", "size": "3", "count": "2"}}, msg["changes"])
+	})
+
+	t.Run("should forward trades of subscribed symbol", func(t *testing.T) {
+		svc := &mocks.MockOrderBookService{MarketEvents: make(chan []byte, 10)}
+		conn := dial(t, svc)
+
+		send(t, conn, MarketCommand{Op: "subscribe", Symbol: "ETH-USDC", Channel: MARKET_CHANNEL_TRADES})
+		assert.Equal(t, "subscribed", read(t, conn)["type"])
+
+		publish(svc, models.MarketEvent{Event: models.MARKET_EVENT_TRADE, Symbol: "ETH-USDC", Trade: &models.Trade{Symbol: "ETH-USDC", Side: models.BUY, Price: decimal.NewFromInt(2000), Size: decimal.NewFromInt(1)}})
+
+		msg := read(t, conn)
+		assert.Equal(t, "trade", msg["type"])
+		assert.Equal(t, "buy", msg["trade"].(map[string]interface{})["side"])
+
+		send(t, conn, MarketCommand{Op: "unsubscribe", Symbol: "ETH-USDC", Channel: MARKET_CHANNEL_TRADES})
+		assert.Equal(t, "unsubscribed", read(t, conn)["type"])
+	})
+
+	t.Run("should reply with errors to invalid commands", func(t *testing.T) {
+		conn := dial(t, &mocks.MockOrderBookService{MarketEvents: make(chan []byte)})
+
+		send(t, conn, MarketCommand{Op: "subscribe", Symbol: "nope", Channel: MARKET_CHANNEL_BOOK})
+		assert.Equal(t, map[string]interface{}{"type": "error", "channel": "book", "symbol": "nope", "msg": "Invalid symbol"}, read(t, conn))
+
+		send(t, conn, MarketCommand{Op: "subscribe", Symbol: "ETH-USDC", Channel: "orders"})
+		assert.Equal(t, "'channel' must be book or trades", read(t, conn)["msg"])
+
+		send(t, conn, MarketCommand{Op: "list", Symbol: "ETH-USDC", Channel: MARKET_CHANNEL_BOOK})
+		assert.Equal(t, "'op' must be subscribe or unsubscribe", read(t, conn)["msg"])
+	})
+
+	t.Run("should reply with error when subscribing fails", func(t *testing.T) {
+		conn := dial(t, &mocks.MockOrderBookService{Error: assert.AnError})
+
+		send(t, conn, MarketCommand{Op: "subscribe", Symbol: "ETH-USDC", Channel: MARKET_CHANNEL_BOOK})
+		assert.Equal(t, "Error subscribing. Try again later", read(t, conn)["msg"])
+	})
+}
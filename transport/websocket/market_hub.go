@@ -0,0 +1,377 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/orbs-network/order-book/utils"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+	"github.com/shopspring/decimal"
+)
+
+// number of L2 levels per side kept for each symbol, unless set by MARKET_WS_DEPTH
+const DEFAULT_MARKET_DEPTH = 50
+
+// messages buffered per client, a client which falls behind is disconnected rather than sent a gapped book
+const marketClientBufferSize = 256
+
+const (
+	MARKET_CHANNEL_BOOK   = "book"
+	MARKET_CHANNEL_TRADES = "trades"
+)
+
+// MarketCommand is sent by clients of the market websocket
+type MarketCommand struct {
+	// subscribe or unsubscribe
+	Op     string `json:"op"`
+	Symbol string `json:"symbol"`
+	// book or trades
+	Channel string `json:"channel"`
+}
+
+type marketStatusMessage struct {
+	// subscribed, unsubscribed or error
+	Type    string `json:"type"`
+	Channel string `json:"channel,omitempty"`
+	Symbol  string `json:"symbol,omitempty"`
+	Msg     string `json:"msg,omitempty"`
+}
+
+type bookSnapshotMessage struct {
+	Type    string              `json:"type"`
+	Channel string              `json:"channel"`
+	Symbol  string              `json:"symbol"`
+	Seq     uint64              `json:"seq"`
+	Asks    [][]decimal.Decimal `json:"asks"`
+	Bids    [][]decimal.Decimal `json:"bids"`
+}
+
+type bookUpdateMessage struct {
+	Type    string               `json:"type"`
+	Channel string               `json:"channel"`
+	Symbol  string               `json:"symbol"`
+	Seq     uint64               `json:"seq"`
+	Changes []models.DepthChange `json:"changes"`
+}
+
+type tradeMessage struct {
+	Type    string       `json:"type"`
+	Channel string       `json:"channel"`
+	Symbol  string       `json:"symbol"`
+	Trade   models.Trade `json:"trade"`
+}
+
+// MarketClient is a single connection of the market websocket
+type MarketClient struct {
+	send chan []byte
+	done chan struct{}
+	once sync.Once
+}
+
+// Close disconnects the client, it is safe to call more than once
+func (c *MarketClient) Close() {
+	c.once.Do(func() { close(c.done) })
+}
+
+// sendJSON queues a message to the client, a client with a full buffer is closed
+func (c *MarketClient) sendJSON(ctx context.Context, msg interface{}) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal market message", logger.Error(err))
+		return
+	}
+
+	select {
+	case <-c.done:
+	case c.send <- data:
+	default:
+		logctx.Warn(ctx, "market client is too slow, disconnecting", logger.Int("buffer_size", marketClientBufferSize))
+		c.Close()
+	}
+}
+
+// marketFeed holds the book of a single symbol and the clients subscribed to it
+type marketFeed struct {
+	symbol models.Symbol
+	events chan []byte
+	// incremented with every book update
+	seq        uint64
+	book       models.MarketDepth
+	bookSubs   map[*MarketClient]struct{}
+	tradesSubs map[*MarketClient]struct{}
+}
+
+func (f *marketFeed) isEmpty() bool {
+	return len(f.bookSubs) == 0 && len(f.tradesSubs) == 0
+}
+
+// MarketHub shares one market events subscription and L2 book per symbol between all market websocket clients
+type MarketHub struct {
+	svc   service.OrderBookService
+	depth int
+	// feeds outlive the connection which started them
+	ctx   context.Context
+	mu    sync.Mutex
+	feeds map[models.Symbol]*marketFeed
+}
+
+func NewMarketHub(svc service.OrderBookService) *MarketHub {
+	depth, err := strconv.Atoi(utils.GetEnv("MARKET_WS_DEPTH", strconv.Itoa(DEFAULT_MARKET_DEPTH)))
+	if err != nil || depth <= 0 {
+		logctx.Warn(context.Background(), "invalid MARKET_WS_DEPTH, using default", logger.Int("depth", DEFAULT_MARKET_DEPTH))
+		depth = DEFAULT_MARKET_DEPTH
+	}
+
+	return &MarketHub{
+		svc:   svc,
+		depth: depth,
+		ctx:   context.Background(),
+		feeds: make(map[models.Symbol]*marketFeed),
+	}
+}
+
+func (h *MarketHub) NewClient() *MarketClient {
+	return &MarketClient{
+		send: make(chan []byte, marketClientBufferSize),
+		done: make(chan struct{}),
+	}
+}
+
+// HandleCommand applies a subscribe or unsubscribe command and acknowledges it
+func (h *MarketHub) HandleCommand(ctx context.Context, client *MarketClient, data []byte) {
+	var cmd MarketCommand
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		client.sendJSON(ctx, marketStatusMessage{Type: "error", Msg: "Invalid JSON command"})
+		return
+	}
+
+	symbol, err := models.StrToSymbol(cmd.Symbol)
+	if err != nil {
+		client.sendJSON(ctx, marketStatusMessage{Type: "error", Channel: cmd.Channel, Symbol: cmd.Symbol, Msg: "Invalid symbol"})
+		return
+	}
+
+	if cmd.Channel != MARKET_CHANNEL_BOOK && cmd.Channel != MARKET_CHANNEL_TRADES {
+		client.sendJSON(ctx, marketStatusMessage{Type: "error", Channel: cmd.Channel, Symbol: cmd.Symbol, Msg: fmt.Sprintf("'channel' must be %s or %s", MARKET_CHANNEL_BOOK, MARKET_CHANNEL_TRADES)})
+		return
+	}
+
+	switch cmd.Op {
+	case "subscribe":
+		if err := h.Subscribe(ctx, client, symbol, cmd.Channel); err != nil {
+			client.sendJSON(ctx, marketStatusMessage{Type: "error", Channel: cmd.Channel, Symbol: cmd.Symbol, Msg: "Error subscribing. Try again later"})
+		}
+	case "unsubscribe":
+		h.Unsubscribe(ctx, client, symbol, cmd.Channel)
+	default:
+		client.sendJSON(ctx, marketStatusMessage{Type: "error", Channel: cmd.Channel, Symbol: cmd.Symbol, Msg: "'op' must be subscribe or unsubscribe"})
+	}
+}
+
+// Subscribe adds the client to a channel of the symbol
+// book subscribers get a snapshot first, subscribing again gets a fresh snapshot
+func (h *MarketHub) Subscribe(ctx context.Context, client *MarketClient, symbol models.Symbol, channel string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	feed, ok := h.feeds[symbol]
+	if !ok {
+		var err error
+		if feed, err = h.startFeed(ctx, symbol); err != nil {
+			return err
+		}
+	}
+
+	client.sendJSON(ctx, marketStatusMessage{Type: "subscribed", Channel: channel, Symbol: symbol.String()})
+
+	if channel == MARKET_CHANNEL_TRADES {
+		feed.tradesSubs[client] = struct{}{}
+		return nil
+	}
+
+	feed.bookSubs[client] = struct{}{}
+	client.sendJSON(ctx, bookSnapshotMessage{
+		Type:    "snapshot",
+		Channel: MARKET_CHANNEL_BOOK,
+		Symbol:  symbol.String(),
+		Seq:     feed.seq,
+		Asks:    feed.book.Asks,
+		Bids:    feed.book.Bids,
+	})
+	return nil
+}
+
+// Unsubscribe removes the client from a channel of the symbol
+func (h *MarketHub) Unsubscribe(ctx context.Context, client *MarketClient, symbol models.Symbol, channel string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if feed, ok := h.feeds[symbol]; ok {
+		if channel == MARKET_CHANNEL_TRADES {
+			delete(feed.tradesSubs, client)
+		} else {
+			delete(feed.bookSubs, client)
+		}
+		if feed.isEmpty() {
+			h.stopFeed(ctx, feed)
+		}
+	}
+
+	client.sendJSON(ctx, marketStatusMessage{Type: "unsubscribed", Channel: channel, Symbol: symbol.String()})
+}
+
+// RemoveClient closes the client and removes it from all symbols
+func (h *MarketHub) RemoveClient(ctx context.Context, client *MarketClient) {
+	client.Close()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, feed := range h.feeds {
+		delete(feed.bookSubs, client)
+		delete(feed.tradesSubs, client)
+		if feed.isEmpty() {
+			h.stopFeed(ctx, feed)
+		}
+	}
+}
+
+// startFeed subscribes to the symbol's market events and loads its book, must be called with the lock held
+func (h *MarketHub) startFeed(ctx context.Context, symbol models.Symbol) (*marketFeed, error) {
+	events, err := h.svc.SubscribeMarketEvents(h.ctx, symbol)
+	if err != nil {
+		logctx.Error(ctx, "failed to subscribe to market events", logger.Error(err), logger.String("symbol", symbol.String()))
+		return nil, err
+	}
+
+	book, err := h.svc.GetMarketDepth(h.ctx, symbol, models.DepthQuery{Mode: models.DEPTH_L2, Depth: h.depth})
+	if err != nil {
+		logctx.Error(ctx, "failed to get market depth", logger.Error(err), logger.String("symbol", symbol.String()))
+		if err := h.svc.UnsubscribeMarketEvents(h.ctx, symbol, events); err != nil {
+			logctx.Error(ctx, "failed to unsubscribe from market events", logger.Error(err), logger.String("symbol", symbol.String()))
+		}
+		return nil, err
+	}
+
+	feed := &marketFeed{
+		symbol:     symbol,
+		events:     events,
+		seq:        1,
+		book:       book,
+		bookSubs:   make(map[*MarketClient]struct{}),
+		tradesSubs: make(map[*MarketClient]struct{}),
+	}
+	h.feeds[symbol] = feed
+
+	logctx.Info(ctx, "market feed started", logger.String("symbol", symbol.String()))
+	go h.runFeed(feed)
+
+	return feed, nil
+}
+
+// stopFeed unsubscribes from the symbol's market events, must be called with the lock held
+func (h *MarketHub) stopFeed(ctx context.Context, feed *marketFeed) {
+	delete(h.feeds, feed.symbol)
+	if err := h.svc.UnsubscribeMarketEvents(h.ctx, feed.symbol, feed.events); err != nil {
+		logctx.Error(ctx, "failed to unsubscribe from market events", logger.Error(err), logger.String("symbol", feed.symbol.String()))
+	}
+	logctx.Info(ctx, "market feed stopped", logger.String("symbol", feed.symbol.String()))
+}
+
+// isActive checks the feed was not stopped
+func (h *MarketHub) isActive(feed *marketFeed) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.feeds[feed.symbol] == feed
+}
+
+// runFeed forwards trades and turns book changes into level updates until the feed is stopped
+func (h *MarketHub) runFeed(feed *marketFeed) {
+	for {
+		msg, ok := <-feed.events
+		if !ok {
+			return
+		}
+		bookChanged := h.handleMarketEvent(feed, msg)
+
+		// coalesce the book changes already waiting into a single refresh
+	drain:
+		for {
+			select {
+			case msg, ok := <-feed.events:
+				if !ok {
+					break drain
+				}
+				bookChanged = h.handleMarketEvent(feed, msg) || bookChanged
+			default:
+				break drain
+			}
+		}
+
+		if bookChanged {
+			h.refreshBook(feed)
+		}
+		if !h.isActive(feed) {
+			return
+		}
+	}
+}
+
+// handleMarketEvent forwards a trade to its subscribers and returns true for a book change
+func (h *MarketHub) handleMarketEvent(feed *marketFeed, msg []byte) bool {
+	var event models.MarketEvent
+	if err := json.Unmarshal(msg, &event); err != nil {
+		logctx.Error(h.ctx, "failed to unmarshal market event", logger.Error(err), logger.String("symbol", feed.symbol.String()))
+		return false
+	}
+
+	switch event.Event {
+	case models.MARKET_EVENT_BOOK_CHANGE:
+		return true
+	case models.MARKET_EVENT_TRADE:
+		if event.Trade == nil {
+			return false
+		}
+		h.mu.Lock()
+		for client := range feed.tradesSubs {
+			client.sendJSON(h.ctx, tradeMessage{Type: "trade", Channel: MARKET_CHANNEL_TRADES, Symbol: feed.symbol.String(), Trade: *event.Trade})
+		}
+		h.mu.Unlock()
+	default:
+		logctx.Warn(h.ctx, "unknown market event", logger.String("event", event.Event), logger.String("symbol", feed.symbol.String()))
+	}
+	return false
+}
+
+// refreshBook reloads the book and sends the changed levels to book subscribers under the next seq
+func (h *MarketHub) refreshBook(feed *marketFeed) {
+	book, err := h.svc.GetMarketDepth(h.ctx, feed.symbol, models.DepthQuery{Mode: models.DEPTH_L2, Depth: h.depth})
+	if err != nil {
+		logctx.Error(h.ctx, "failed to refresh market depth", logger.Error(err), logger.String("symbol", feed.symbol.String()))
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.feeds[feed.symbol] != feed {
+		return
+	}
+
+	changes := models.DiffDepth(feed.book, book)
+	feed.book = book
+	if len(changes) == 0 {
+		return
+	}
+
+	feed.seq++
+	for client := range feed.bookSubs {
+		client.sendJSON(h.ctx, bookUpdateMessage{Type: "update", Channel: MARKET_CHANNEL_BOOK, Symbol: feed.symbol.String(), Seq: feed.seq, Changes: changes})
+	}
+}
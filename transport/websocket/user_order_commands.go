@@ -0,0 +1,335 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+	"github.com/orbs-network/order-book/utils/logger"
+	"github.com/orbs-network/order-book/utils/logger/logctx"
+)
+
+// replies buffered per connection of the orders websocket
+const orderRepliesBufferSize = 64
+
+// ParseOrderFunc parses an order placed on the orders websocket, the returned error is meant for the client
+type ParseOrderFunc func(user *models.User, data json.RawMessage) (service.CreateOrderInput, error)
+
+// OrderCommand is sent by clients of the orders websocket
+type OrderCommand struct {
+	// optional, echoed in the reply
+	Id string `json:"id,omitempty"`
+	// subscribe, unsubscribe, place or cancel
+	Op string `json:"op"`
+	// subscribe and unsubscribe, none for all
+	Symbols []string `json:"symbols,omitempty"`
+	Events  []string `json:"events,omitempty"`
+	// place, same as the body of POST /order
+	Order json.RawMessage `json:"order,omitempty"`
+	// cancel, by order ID or client order ID
+	OrderId       string `json:"orderId,omitempty"`
+	ClientOrderId string `json:"clientOrderId,omitempty"`
+}
+
+type orderReplyMessage struct {
+	// subscribed, unsubscribed, placed, cancelled or error
+	Type    string   `json:"type"`
+	Id      string   `json:"id,omitempty"`
+	Symbols []string `json:"symbols,omitempty"`
+	Events  []string `json:"events,omitempty"`
+	OrderId string   `json:"orderId,omitempty"`
+	Msg     string   `json:"msg,omitempty"`
+}
+
+// filterSet matches all values but the excluded ones, or only the included ones
+type filterSet struct {
+	all bool
+	// excluded when all, included otherwise
+	values map[string]struct{}
+}
+
+func newFilterSet() filterSet {
+	return filterSet{all: true, values: make(map[string]struct{})}
+}
+
+func (f *filterSet) subscribe(values []string) {
+	if len(values) == 0 {
+		*f = newFilterSet()
+		return
+	}
+	for _, value := range values {
+		if f.all {
+			delete(f.values, value)
+		} else {
+			f.values[value] = struct{}{}
+		}
+	}
+}
+
+func (f *filterSet) unsubscribe(values []string) {
+	if len(values) == 0 {
+		f.all = false
+		f.values = make(map[string]struct{})
+		return
+	}
+	for _, value := range values {
+		if f.all {
+			f.values[value] = struct{}{}
+		} else {
+			delete(f.values, value)
+		}
+	}
+}
+
+func (f *filterSet) isNone() bool {
+	return !f.all && len(f.values) == 0
+}
+
+func (f *filterSet) match(value string) bool {
+	_, found := f.values[value]
+	if f.all {
+		return !found
+	}
+	return found
+}
+
+// userEventFilter selects the user events sent to a connection by symbol and event type, all by default
+type userEventFilter struct {
+	mu      sync.Mutex
+	symbols filterSet
+	events  filterSet
+}
+
+func newUserEventFilter() *userEventFilter {
+	return &userEventFilter{symbols: newFilterSet(), events: newFilterSet()}
+}
+
+// subscribe adds the symbols and the events, a command with neither subscribes to everything
+// when only one is given and nothing of the other is selected, all of the other are selected
+func (f *userEventFilter) subscribe(symbols, events []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(symbols) == 0 && len(events) == 0 {
+		f.symbols.subscribe(nil)
+		f.events.subscribe(nil)
+		return
+	}
+	if len(symbols) > 0 || f.symbols.isNone() {
+		f.symbols.subscribe(symbols)
+	}
+	if len(events) > 0 || f.events.isNone() {
+		f.events.subscribe(events)
+	}
+}
+
+// unsubscribe removes the symbols and the events, a command with neither unsubscribes from everything
+func (f *userEventFilter) unsubscribe(symbols, events []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(symbols) == 0 && len(events) == 0 {
+		f.symbols.unsubscribe(nil)
+		f.events.unsubscribe(nil)
+		return
+	}
+	if len(symbols) > 0 {
+		f.symbols.unsubscribe(symbols)
+	}
+	if len(events) > 0 {
+		f.events.unsubscribe(events)
+	}
+}
+
+// match returns true if the event should be sent. Gaps, and events that fail to parse, are always sent
+func (f *userEventFilter) match(msg []byte) bool {
+	var event struct {
+		Event  string `json:"event"`
+		Symbol string `json:"symbol"`
+	}
+	if err := json.Unmarshal(msg, &event); err != nil || event.Event == models.USER_EVENT_GAP {
+		return true
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if event.Symbol != "" && !f.symbols.match(event.Symbol) {
+		return false
+	}
+	return f.events.match(event.Event)
+}
+
+// orderSession handles the commands of a single orders websocket connection
+type orderSession struct {
+	svc        service.OrderBookService
+	parseOrder ParseOrderFunc
+	user       *models.User
+	filter     *userEventFilter
+	replies    chan []byte
+	done       chan struct{}
+}
+
+func newOrderSession(svc service.OrderBookService, parseOrder ParseOrderFunc, user *models.User) *orderSession {
+	return &orderSession{
+		svc:        svc,
+		parseOrder: parseOrder,
+		user:       user,
+		filter:     newUserEventFilter(),
+		replies:    make(chan []byte, orderRepliesBufferSize),
+		done:       make(chan struct{}),
+	}
+}
+
+func (s *orderSession) handleCommand(ctx context.Context, data []byte) {
+	var cmd OrderCommand
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		s.reply(ctx, orderReplyMessage{Type: "error", Msg: "Invalid command"})
+		return
+	}
+
+	switch cmd.Op {
+	case "subscribe", "unsubscribe":
+		if err := validateFilter(cmd.Symbols, cmd.Events); err != nil {
+			s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: err.Error()})
+			return
+		}
+		if cmd.Op == "subscribe" {
+			s.filter.subscribe(cmd.Symbols, cmd.Events)
+		} else {
+			s.filter.unsubscribe(cmd.Symbols, cmd.Events)
+		}
+		s.reply(ctx, orderReplyMessage{Type: cmd.Op + "d", Id: cmd.Id, Symbols: cmd.Symbols, Events: cmd.Events})
+	case "place":
+		s.placeOrder(ctx, cmd)
+	case "cancel":
+		s.cancelOrder(ctx, cmd)
+	default:
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "Invalid op: must be subscribe, unsubscribe, place or cancel"})
+	}
+}
+
+func validateFilter(symbols, events []string) error {
+	for _, symbol := range symbols {
+		if _, err := models.StrToSymbol(symbol); err != nil {
+			return fmt.Errorf("Invalid symbol: %s", symbol)
+		}
+	}
+	for _, event := range events {
+		valid := false
+		for _, eventType := range models.UserEventTypes {
+			if event == eventType {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("Invalid event: %s", event)
+		}
+	}
+	return nil
+}
+
+// canTrade returns true for users allowed to place and cancel orders, the same as the REST write routes
+func (s *orderSession) canTrade() bool {
+	return s.user.Type == models.MARKET_MAKER || s.user.Type == models.ADMIN
+}
+
+func (s *orderSession) placeOrder(ctx context.Context, cmd OrderCommand) {
+	if !s.canTrade() {
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "user does not have correct permissions"})
+		return
+	}
+	if s.parseOrder == nil || len(cmd.Order) == 0 {
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "missing required field 'order'"})
+		return
+	}
+
+	input, err := s.parseOrder(s.user, cmd.Order)
+	if err != nil {
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: err.Error()})
+		return
+	}
+
+	order, err := s.svc.CreateOrder(ctx, input)
+	switch err {
+	case nil:
+		s.reply(ctx, orderReplyMessage{Type: "placed", Id: cmd.Id, OrderId: order.Id.String()})
+	case models.ErrCrossTrade, models.ErrInvalidSignature, models.ErrSignedOrderMismatch, models.ErrInvalidTimeInForce:
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: err.Error()})
+	case models.ErrClashingOrderId:
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "Clashing order ID. Please retry"})
+	case models.ErrClashingClientOrderId:
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: fmt.Sprintf("Order with clientOrderId %s already exists", input.ClientOrderID)})
+	default:
+		logctx.Error(ctx, "failed to create order", logger.Error(err), logger.String("userId", s.user.Id.String()))
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "Error creating order. Try again later"})
+	}
+}
+
+func (s *orderSession) cancelOrder(ctx context.Context, cmd OrderCommand) {
+	if !s.canTrade() {
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "user does not have correct permissions"})
+		return
+	}
+
+	input := service.CancelOrderInput{UserId: s.user.Id}
+	var err error
+	switch {
+	case cmd.OrderId != "":
+		if input.Id, err = uuid.Parse(cmd.OrderId); err != nil {
+			s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "Invalid order ID"})
+			return
+		}
+	case cmd.ClientOrderId != "":
+		if input.Id, err = uuid.Parse(cmd.ClientOrderId); err != nil {
+			s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "Invalid clientOId"})
+			return
+		}
+		input.IsClientOId = true
+	default:
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "missing required field 'orderId' or 'clientOrderId'"})
+		return
+	}
+
+	cancelledOrderId, err := s.svc.CancelOrder(ctx, input)
+	switch err {
+	case nil:
+		if cancelledOrderId == nil {
+			logctx.Error(ctx, "cancelled order ID is nil", logger.String("id", input.Id.String()))
+			s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "Error cancelling order. Try again later"})
+			return
+		}
+		s.reply(ctx, orderReplyMessage{Type: "cancelled", Id: cmd.Id, OrderId: cancelledOrderId.String()})
+	case models.ErrNotFound:
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "Order not found"})
+	case models.ErrUnauthorized:
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "Not authorized"})
+	case models.ErrOrderCancelled:
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "Order already cancelled"})
+	case models.ErrOrderPending:
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "order is cancelled but some of it's size is pending"})
+	case models.ErrOrderFilled:
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "Cannot cancel filled order"})
+	case models.ErrVersionConflict:
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "Order was changed while cancelling. Try again"})
+	default:
+		logctx.Error(ctx, "failed to cancel order", logger.Error(err), logger.String("id", input.Id.String()))
+		s.reply(ctx, orderReplyMessage{Type: "error", Id: cmd.Id, Msg: "Error cancelling order. Try again later"})
+	}
+}
+
+// reply queues a reply to the client, waiting while the buffer is full
+func (s *orderSession) reply(ctx context.Context, msg orderReplyMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		logctx.Error(ctx, "failed to marshal order websocket reply", logger.Error(err))
+		return
+	}
+
+	select {
+	case s.replies <- data:
+	case <-s.done:
+	}
+}
@@ -0,0 +1,176 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/orbs-network/order-book/mocks"
+	"github.com/orbs-network/order-book/models"
+	"github.com/orbs-network/order-book/service"
+)
+
+func TestUserEventFilter(t *testing.T) {
+	fill := []byte(`{"seq":1,"event":"order-fill","symbol":"ETH-USDC"}`)
+	changed := []byte(`{"seq":2,"event":"order-changed","symbol":"MATIC-USDC"}`)
+	gap := []byte(`{"event":"events-gap","fromSeq":1,"toSeq":2}`)
+
+	t.Run("should match all events by default", func(t *testing.T) {
+		filter := newUserEventFilter()
+		assert.True(t, filter.match(fill))
+		assert.True(t, filter.match(changed))
+	})
+
+	t.Run("should match only subscribed symbols after unsubscribing from all", func(t *testing.T) {
+		filter := newUserEventFilter()
+		filter.unsubscribe(nil, nil)
+		filter.subscribe([]string{"ETH-USDC"}, nil)
+		assert.True(t, filter.match(fill))
+		assert.False(t, filter.match(changed))
+	})
+
+	t.Run("should match only subscribed event types after unsubscribing from all", func(t *testing.T) {
+		filter := newUserEventFilter()
+		filter.unsubscribe(nil, nil)
+		assert.False(t, filter.match(fill))
+
+		filter.subscribe(nil, []string{models.USER_EVENT_ORDER_FILL})
+		assert.True(t, filter.match(fill))
+		assert.False(t, filter.match(changed))
+	})
+
+	t.Run("should exclude unsubscribed event types", func(t *testing.T) {
+		filter := newUserEventFilter()
+		filter.unsubscribe(nil, []string{models.USER_EVENT_ORDER_CHANGED})
+		assert.True(t, filter.match(fill))
+		assert.False(t, filter.match(changed))
+
+		filter.subscribe(nil, []string{models.USER_EVENT_ORDER_CHANGED})
+		assert.True(t, filter.match(changed))
+	})
+
+	t.Run("should always match gaps", func(t *testing.T) {
+		filter := newUserEventFilter()
+		filter.unsubscribe(nil, nil)
+		assert.True(t, filter.match(gap))
+	})
+}
+
+func TestOrderSession_handleCommand(t *testing.T) {
+	ctx := context.Background()
+	maker := &models.User{Id: uuid.New(), Type: models.MARKET_MAKER}
+	orderId := uuid.New()
+
+	parseOrder := func(user *models.User, data json.RawMessage) (service.CreateOrderInput, error) {
+		var args struct {
+			Size string `json:"size"`
+		}
+		if err := json.Unmarshal(data, &args); err != nil || args.Size == "" {
+			return service.CreateOrderInput{}, fmt.Errorf("missing required field 'size'")
+		}
+		return service.CreateOrderInput{UserId: user.Id}, nil
+	}
+
+	tests := []struct {
+		name     string
+		svc      *mocks.MockOrderBookService
+		user     *models.User
+		cmd      string
+		expected orderReplyMessage
+	}{
+		{
+			name:     "subscribe is acknowledged",
+			cmd:      `{"id":"1","op":"subscribe","symbols":["ETH-USDC"],"events":["order-fill"]}`,
+			expected: orderReplyMessage{Type: "subscribed", Id: "1", Symbols: []string{"ETH-USDC"}, Events: []string{"order-fill"}},
+		},
+		{
+			name:     "unsubscribe is acknowledged",
+			cmd:      `{"op":"unsubscribe","events":["order-changed"]}`,
+			expected: orderReplyMessage{Type: "unsubscribed", Events: []string{"order-changed"}},
+		},
+		{
+			name:     "invalid event type",
+			cmd:      `{"id":"1","op":"subscribe","events":["order-eaten"]}`,
+			expected: orderReplyMessage{Type: "error", Id: "1", Msg: "Invalid event: order-eaten"},
+		},
+		{
+			name:     "invalid symbol",
+			cmd:      `{"op":"subscribe","symbols":["NOPE"]}`,
+			expected: orderReplyMessage{Type: "error", Msg: "Invalid symbol: NOPE"},
+		},
+		{
+			name:     "invalid op",
+			cmd:      `{"op":"trade"}`,
+			expected: orderReplyMessage{Type: "error", Msg: "Invalid op: must be subscribe, unsubscribe, place or cancel"},
+		},
+		{
+			name:     "invalid json",
+			cmd:      `{"op":`,
+			expected: orderReplyMessage{Type: "error", Msg: "Invalid command"},
+		},
+		{
+			name:     "place order",
+			svc:      &mocks.MockOrderBookService{Order: &models.Order{Id: orderId}},
+			cmd:      `{"id":"2","op":"place","order":{"size":"1"}}`,
+			expected: orderReplyMessage{Type: "placed", Id: "2", OrderId: orderId.String()},
+		},
+		{
+			name:     "place invalid order",
+			cmd:      `{"id":"2","op":"place","order":{}}`,
+			expected: orderReplyMessage{Type: "error", Id: "2", Msg: "missing required field 'size'"},
+		},
+		{
+			name:     "place crossing order",
+			svc:      &mocks.MockOrderBookService{Order: &models.Order{}, Error: models.ErrCrossTrade},
+			cmd:      `{"id":"2","op":"place","order":{"size":"1"}}`,
+			expected: orderReplyMessage{Type: "error", Id: "2", Msg: models.ErrCrossTrade.Error()},
+		},
+		{
+			name:     "place order without write permissions",
+			user:     &models.User{Id: uuid.New(), Type: models.READ_ONLY},
+			cmd:      `{"id":"2","op":"place","order":{"size":"1"}}`,
+			expected: orderReplyMessage{Type: "error", Id: "2", Msg: "user does not have correct permissions"},
+		},
+		{
+			name:     "cancel order by client order ID",
+			svc:      &mocks.MockOrderBookService{Order: &models.Order{Id: orderId}},
+			cmd:      fmt.Sprintf(`{"id":"3","op":"cancel","clientOrderId":"%s"}`, uuid.New()),
+			expected: orderReplyMessage{Type: "cancelled", Id: "3", OrderId: orderId.String()},
+		},
+		{
+			name:     "cancel order not found",
+			svc:      &mocks.MockOrderBookService{Error: models.ErrNotFound},
+			cmd:      fmt.Sprintf(`{"id":"3","op":"cancel","orderId":"%s"}`, orderId),
+			expected: orderReplyMessage{Type: "error", Id: "3", Msg: "Order not found"},
+		},
+		{
+			name:     "cancel order without ID",
+			cmd:      `{"id":"3","op":"cancel"}`,
+			expected: orderReplyMessage{Type: "error", Id: "3", Msg: "missing required field 'orderId' or 'clientOrderId'"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			svc := test.svc
+			if svc == nil {
+				svc = &mocks.MockOrderBookService{}
+			}
+			user := test.user
+			if user == nil {
+				user = maker
+			}
+			session := newOrderSession(svc, parseOrder, user)
+
+			session.handleCommand(ctx, []byte(test.cmd))
+
+			var reply orderReplyMessage
+			assert.NoError(t, json.Unmarshal(<-session.replies, &reply))
+			assert.Equal(t, test.expected, reply)
+		})
+	}
+}
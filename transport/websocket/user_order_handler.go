@@ -2,6 +2,7 @@ package websocket
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -13,7 +14,9 @@ import (
 
 // WebSocketOrderHandler returns a handler that upgrades the connection to WebSocket and subscribes to order updates for a particular user
 // The user is authenticated using the API key in the request
-func WebSocketOrderHandler(orderSvc service.OrderBookService, getUserByApiKey middleware.GetUserByApiKeyFunc) http.HandlerFunc {
+// Clients may send commands to filter the events by symbol and event type, and to place and cancel orders (see OrderCommand)
+// Optional query param `fromSeq` resumes from the event of that seq, e.g. the last received seq + 1 after a reconnect
+func WebSocketOrderHandler(orderSvc service.OrderBookService, getUserByApiKey middleware.GetUserByApiKeyFunc, parseOrder ParseOrderFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		// Extract API key from query parameters
@@ -32,6 +35,16 @@ func WebSocketOrderHandler(orderSvc service.OrderBookService, getUserByApiKey mi
 			return
 		}
 
+		fromSeq := int64(0)
+		if strFromSeq := r.URL.Query().Get("fromSeq"); strFromSeq != "" {
+			fromSeq, err = strconv.ParseInt(strFromSeq, 10, 64)
+			if err != nil || fromSeq <= 0 {
+				logctx.Warn(ctx, "invalid fromSeq", logger.String("fromSeq", strFromSeq), logger.String("userId", user.Id.String()))
+				http.Error(w, "Invalid fromSeq: must be a positive integer", http.StatusBadRequest)
+				return
+			}
+		}
+
 		// Upgrade to WebSocket
 		upgrader := websocket.Upgrader{}
 		conn, err := upgrader.Upgrade(w, r, nil)
@@ -55,7 +68,7 @@ func WebSocketOrderHandler(orderSvc service.OrderBookService, getUserByApiKey mi
 			return nil
 		})
 
-		messageChan, err := orderSvc.SubscribeUserOrders(ctx, user.Id)
+		messageChan, err := orderSvc.SubscribeUserOrders(ctx, user.Id, fromSeq)
 		if err != nil {
 			logctx.Error(ctx, "error subscribing to user orders", logger.Error(err), logger.String("userId", user.Id.String()))
 			http.Error(w, "Error subscribing to orders", http.StatusInternalServerError)
@@ -70,6 +83,23 @@ func WebSocketOrderHandler(orderSvc service.OrderBookService, getUserByApiKey mi
 			}
 		}()
 
+		session := newOrderSession(orderSvc, parseOrder, user)
+		defer close(session.done)
+
+		// Read commands until the client disconnects
+		disconnected := make(chan struct{})
+		go func() {
+			defer close(disconnected)
+			for {
+				_, data, err := conn.ReadMessage()
+				if err != nil {
+					logctx.Debug(ctx, "orders websocket read ended", logger.Error(err), logger.String("userId", user.Id.String()))
+					return
+				}
+				session.handleCommand(ctx, data)
+			}
+		}()
+
 		ticker := time.NewTicker(60 * time.Second)
 		defer ticker.Stop()
 
@@ -80,15 +110,25 @@ func WebSocketOrderHandler(orderSvc service.OrderBookService, getUserByApiKey mi
 					logctx.Warn(ctx, "message channel closed", logger.String("userId", user.Id.String()))
 					return
 				}
+				if !session.filter.match(msg) {
+					continue
+				}
 				if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
 					logctx.Warn(ctx, "unable to write to websocket", logger.Error(err), logger.String("userId", user.Id.String()))
 					return
 				}
+			case reply := <-session.replies:
+				if err := conn.WriteMessage(websocket.TextMessage, reply); err != nil {
+					logctx.Warn(ctx, "unable to write to websocket", logger.Error(err), logger.String("userId", user.Id.String()))
+					return
+				}
 			case <-ticker.C:
 				if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 					logctx.Error(ctx, "error sending ping", logger.Error(err), logger.String("userId", user.Id.String()))
 					return
 				}
+			case <-disconnected:
+				return
 			case <-ctx.Done():
 				logctx.Info(ctx, "request context cancelled", logger.String("userId", user.Id.String()))
 				return
@@ -18,7 +18,7 @@ func TestWebSocketOrderHandler(t *testing.T) {
 
 	t.Run("Test successful websocket lifecycle", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			WebSocketOrderHandler(&mocks.MockOrderBookService{}, mockGetUserByApiKey)(w, r)
+			WebSocketOrderHandler(&mocks.MockOrderBookService{}, mockGetUserByApiKey, nil)(w, r)
 		}))
 		defer server.Close()
 
@@ -42,7 +42,7 @@ func TestWebSocketOrderHandler(t *testing.T) {
 
 	t.Run("Test invalid API key", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			WebSocketOrderHandler(&mocks.MockOrderBookService{}, mockGetUserByApiKeyError)(w, r)
+			WebSocketOrderHandler(&mocks.MockOrderBookService{}, mockGetUserByApiKeyError, nil)(w, r)
 		}))
 		defer server.Close()
 
@@ -57,9 +57,79 @@ func TestWebSocketOrderHandler(t *testing.T) {
 		assert.Nil(t, conn, "The WebSocket connection should not be established")
 	})
 
+	t.Run("Test invalid fromSeq", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			WebSocketOrderHandler(&mocks.MockOrderBookService{}, mockGetUserByApiKey, nil)(w, r)
+		}))
+		defer server.Close()
+
+		wsURL := "ws" + server.URL[len("http"):] + "?fromSeq=abc"
+
+		dialer := websocket.Dialer{}
+		headers := http.Header{}
+		headers.Set("X-API-KEY", "Bearer mock-api-key")
+
+		conn, res, err := dialer.Dial(wsURL, headers)
+		assert.Error(t, err)
+		assert.Nil(t, conn, "The WebSocket connection should not be established")
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("Test resuming from seq", func(t *testing.T) {
+		events := make(chan []byte, 1)
+		events <- []byte(`{"seq":5,"event":"order-fill"}`)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			WebSocketOrderHandler(&mocks.MockOrderBookService{OrderEvents: events}, mockGetUserByApiKey, nil)(w, r)
+		}))
+		defer server.Close()
+
+		wsURL := "ws" + server.URL[len("http"):] + "?fromSeq=5"
+
+		dialer := websocket.Dialer{}
+		headers := http.Header{}
+		headers.Set("X-API-KEY", "Bearer mock-api-key")
+
+		conn, _, err := dialer.Dial(wsURL, headers)
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		_, msg, err := conn.ReadMessage()
+		assert.NoError(t, err)
+		assert.Equal(t, `{"seq":5,"event":"order-fill"}`, string(msg))
+	})
+
+	t.Run("Test filtering events by symbol", func(t *testing.T) {
+		events := make(chan []byte)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			WebSocketOrderHandler(&mocks.MockOrderBookService{OrderEvents: events}, mockGetUserByApiKey, nil)(w, r)
+		}))
+		defer server.Close()
+
+		wsURL := "ws" + server.URL[len("http"):]
+
+		dialer := websocket.Dialer{}
+		headers := http.Header{}
+		headers.Set("X-API-KEY", "Bearer mock-api-key")
+
+		conn, _, err := dialer.Dial(wsURL, headers)
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"id":"1","op":"unsubscribe","symbols":["MATIC-USDC"]}`)))
+		_, msg, err := conn.ReadMessage()
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"type":"unsubscribed","id":"1","symbols":["MATIC-USDC"]}`, string(msg))
+
+		events <- []byte(`{"seq":1,"event":"order-fill","symbol":"MATIC-USDC"}`)
+		events <- []byte(`{"seq":2,"event":"order-fill","symbol":"ETH-USDC"}`)
+		_, msg, err = conn.ReadMessage()
+		assert.NoError(t, err)
+		assert.Equal(t, `{"seq":2,"event":"order-fill","symbol":"ETH-USDC"}`, string(msg))
+	})
+
 	t.Run("Test error upgrading to WebSocket", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			WebSocketOrderHandler(&mocks.MockOrderBookService{}, mockGetUserByApiKey)(w, r)
+			WebSocketOrderHandler(&mocks.MockOrderBookService{}, mockGetUserByApiKey, nil)(w, r)
 		}))
 		defer server.Close()
 
@@ -81,7 +151,7 @@ func TestWebSocketOrderHandler(t *testing.T) {
 
 	t.Run("Test error subscribing to user orders", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			WebSocketOrderHandler(&mocks.MockOrderBookService{Error: assert.AnError}, mockGetUserByApiKey)(w, r)
+			WebSocketOrderHandler(&mocks.MockOrderBookService{Error: assert.AnError}, mockGetUserByApiKey, nil)(w, r)
 		}))
 		defer server.Close()
 
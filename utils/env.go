@@ -0,0 +1,11 @@
+package utils
+
+import "os"
+
+// read os env var with default
+func GetEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}